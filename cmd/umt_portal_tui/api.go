@@ -1,14 +1,14 @@
 package main
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/cookiejar"
 	"net/url"
-	"os"
 	"slices"
-	"strconv"
 	"strings"
 	"time"
 
@@ -21,29 +21,54 @@ const UMT_COURSES_URL string = "https://online.umt.edu.pk/MyCourses"
 const COURSES_VIEW_ASSESSMENT_URL string = "https://online.umt.edu.pk/MyCourses/ViewAssesments?id="
 const COURSES_VIEW_ATTENDANCE_URL string = "https://online.umt.edu.pk/Attendance/ViewAttendance?id="
 const COURSES_VIEW_ATTENDANCE_ASPX_URL string = "https://online.umt.edu.pk/Reports/Attendance.aspx"
-const COURSES_VIEW_ATTENDANCE_AXD_URL string = "https://online.umt.edu.pk/Reserved.ReportViewerWebControl.axd?"
+const REPORT_VIEWER_AXD_URL string = "https://online.umt.edu.pk/Reserved.ReportViewerWebControl.axd?"
 const TRANSCRIPT_URL string = "https://online.umt.edu.pk/Transcript"
 const TRANSCRIPT_ASPX_URL string = "https://online.umt.edu.pk/Reports/Transcript.aspx"
 
-func (s *Session) loginAPI(credentials Credentials) ([]*http.Cookie, ErrorCode, string) {
+var retryDelay = time.Second * 2
+
+// loginAPI logs in with securityCode answering the captcha from the previous
+// attempt. Without one, a fresh login page is loaded; if it carries a
+// captcha, the image is kept in s.captcha and ErrCaptchaRequired returned.
+func (s *Session) loginAPI(credentials Credentials, securityCode string) ([]*http.Cookie, ErrorCode, string) {
 	if credentials.StudentID == "" || credentials.Password == "" {
 		return nil, ErrInvalidCredentials, ""
 	}
 
-	jar, _ := cookiejar.New(nil)
-	client := &http.Client{Jar: jar}
+	client := s.httpClient(OP_LOGIN)
+	if securityCode != "" && s.captcha != nil {
+		client.Jar = s.captcha.jar
+	} else {
+		securityCode = DEFAULT_SECURITY_CODE
+		client.Jar, _ = cookiejar.New(nil)
 
-	resp, err := client.Get(UMT_LOGIN_URL)
-	if err != nil {
-		return nil, ErrNetworkIssue, err.Error()
+		resp, err := client.Get(UMT_LOGIN_URL)
+		if err != nil {
+			return nil, ErrNetworkIssue, err.Error()
+		}
+		doc, err := goquery.NewDocumentFromReader(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, ErrNetworkIssue, err.Error()
+		}
+
+		if src := findCaptchaImage(doc); src != "" {
+			img, err := fetchCaptchaImage(client, src)
+			if err != nil {
+				return nil, ErrParsingError, err.Error()
+			}
+			s.captcha = &loginCaptcha{Image: img, jar: client.Jar}
+			return nil, ErrCaptchaRequired, ""
+		}
 	}
-	resp.Body.Close()
+	s.captcha = nil
+	jar := client.Jar
 
 	form := url.Values{}
 	form.Set("student_id", credentials.StudentID)
 	form.Set("Password", credentials.Password)
-	form.Set("SecurityCode", "abcde")
-	form.Set("SecurityCodeText", "abcde")
+	form.Set("SecurityCode", securityCode)
+	form.Set("SecurityCodeText", securityCode)
 
 	req, err := http.NewRequest("POST", UMT_LOGIN_URL, strings.NewReader(form.Encode()))
 	if err != nil {
@@ -51,7 +76,7 @@ func (s *Session) loginAPI(credentials Credentials) ([]*http.Cookie, ErrorCode,
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	resp, err = client.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
 		return nil, ErrNetworkIssue, err.Error()
 	}
@@ -80,7 +105,7 @@ func (s *Session) fetchUserData() error {
 		return fmt.Errorf("no cookies found during fetching user data")
 	}
 
-	client := &http.Client{}
+	client := s.httpClient(OP_LOGIN)
 	req, err := http.NewRequest("GET", UMT_DATA_URL, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create CourseRequest: %w", err)
@@ -101,6 +126,9 @@ func (s *Session) fetchUserData() error {
 		return fmt.Errorf("failed to parse CourseRequest HTML: %w", err)
 	}
 
+	s.studentMu.Lock()
+	defer s.studentMu.Unlock()
+
 	doc.Find(".widget-numbers.text-primary").Each(func(i int, sel *goquery.Selection) {
 		text := strings.Join(strings.Fields(sel.Text()), " ")
 		switch i {
@@ -155,76 +183,19 @@ func (s *Session) fetchUserCourses() error {
 		return fmt.Errorf("no cookies found during fetching user courses")
 	}
 
+	s.studentMu.Lock()
 	s.Student.Courses = nil
+	s.studentMu.Unlock()
 
-	client := &http.Client{}
-	req, err := http.NewRequest("GET", UMT_COURSES_URL, nil)
+	courses, err := fetchPage(s, OP_COURSES, UMT_COURSES_URL, "courses page", parseCoursesHTML)
 	if err != nil {
-		return fmt.Errorf("failed to create courses request: %w", err)
+		return err
 	}
-
-	for _, cookie := range s.Cookies {
-		req.AddCookie(cookie)
-	}
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to get courses page: %w", err)
-	}
-	defer resp.Body.Close()
-
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to parse courses HTML: %w", err)
-	}
-
-	doc.Find(".table tr").Each(func(rowIndex int, row *goquery.Selection) {
-		if row.Find("th").Length() > 0 {
-			return
-		}
-
-		var rowData []string
-		var assignedID string
-
-		row.Find("td").Each(func(cellIndex int, cell *goquery.Selection) {
-			if emailLink := cell.Find("a.__cf_email__"); emailLink.Length() > 0 {
-				if encodedEmail, exists := emailLink.Attr("data-cfemail"); exists {
-					decodedEmail := decodeFacultyEmail(encodedEmail)
-					if decodedEmail != "" {
-						rowData = append(rowData, decodedEmail)
-					} else {
-						rowData = append(rowData, "[email protected]")
-					}
-				} else {
-					rowData = append(rowData, strings.TrimSpace(cell.Text()))
-				}
-			} else if assignedLink := cell.Find("a.assesment"); assignedLink.Length() > 0 {
-				if id, exists := assignedLink.Attr("data-assigned-id"); exists {
-					assignedID = id
-				}
-				rowData = append(rowData, "")
-			} else {
-				cellText := strings.TrimSpace(cell.Text())
-				rowData = append(rowData, cellText)
-			}
-		})
-
-		if len(rowData) >= 9 {
-			course := Course{
-				ID:           assignedID,
-				Code:         rowData[0],
-				Title:        rowData[1],
-				CreditHours:  rowData[2],
-				CourseType:   rowData[3],
-				FacultyName:  rowData[4],
-				FacultyEmail: rowData[5],
-				Mode:         rowData[6],
-				Section:      rowData[7],
-				Semester:     rowData[8],
-			}
-			s.Student.Courses = append(s.Student.Courses, course)
-		}
-	})
+	// The cached copy stays as parsed; attendance and assessments are
+	// filled into the session's own.
+	s.studentMu.Lock()
+	s.Student.Courses = slices.Clone(courses)
+	s.studentMu.Unlock()
 
 	return nil
 }
@@ -234,12 +205,15 @@ func (s *Session) fetchCourseAssessments(courseId string) error {
 		return fmt.Errorf("no cookies found during fetching course assessments")
 	}
 
-	maxRetries := 10
-	for range maxRetries {
-		client := &http.Client{}
+	policy := appConfig.requestPolicy(OP_ASSESSMENTS)
+	task := assessmentsTask(courseId)
+	var lastErr error
+	for attempt := range policy.MaxRetries {
+		client := s.httpClient(OP_ASSESSMENTS)
 		req, err := http.NewRequest("GET", COURSES_VIEW_ASSESSMENT_URL+courseId, nil)
 		if err != nil {
-			time.Sleep(time.Second * 2)
+			lastErr = err
+			s.retryPause(task, attempt, policy.MaxRetries, err)
 			continue
 		}
 
@@ -250,111 +224,57 @@ func (s *Session) fetchCourseAssessments(courseId string) error {
 		resp, err := client.Do(req)
 
 		if err != nil {
-			time.Sleep(time.Second * 2)
+			lastErr = err
+			s.retryPause(task, attempt, policy.MaxRetries, err)
 			continue
 		}
 		defer resp.Body.Close()
 
 		bodyBytes, err := io.ReadAll(resp.Body)
 		if err != nil {
-			time.Sleep(time.Second * 2)
+			lastErr = err
+			s.retryPause(task, attempt, policy.MaxRetries, err)
 			continue
 		}
 
-		doc, err := goquery.NewDocumentFromReader(strings.NewReader(string(bodyBytes)))
+		assessmentRecords, foundTable, err := parseAssessmentsHTML(bytes.NewReader(bodyBytes))
 		if err != nil {
-			time.Sleep(time.Second * 2)
+			lastErr = err
+			s.retryPause(task, attempt, policy.MaxRetries, err)
 			continue
 		}
 
-		index := getCourseIndex(s, courseId)
-		if index == -1 {
-			return fmt.Errorf("course not found")
-		}
-
-		course := &s.Student.Courses[index]
-		var assessmentRecords []Assessment
-		foundTable := false
-
-		doc.Find("table").Each(func(tableIndex int, table *goquery.Selection) {
-			headerRow := table.Find("tr").First()
-			hasNameHeader := false
-			hasTotalMarksHeader := false
-			hasObtainedMarksHeader := false
-			hasAssignedDateHeader := false
-
-			headerRow.Find("th").Each(func(i int, th *goquery.Selection) {
-				headerText := strings.ToLower(strings.TrimSpace(th.Text()))
-				if strings.Contains(headerText, "name") {
-					hasNameHeader = true
-				}
-				if strings.Contains(headerText, "total marks") {
-					hasTotalMarksHeader = true
-				}
-				if strings.Contains(headerText, "obtained marks") {
-					hasObtainedMarksHeader = true
-				}
-				if strings.Contains(headerText, "assigned date") {
-					hasAssignedDateHeader = true
-				}
-			})
-
-			if hasNameHeader && hasTotalMarksHeader && hasObtainedMarksHeader && hasAssignedDateHeader {
-				foundTable = true
-				table.Find("tr").Each(func(rowIndex int, row *goquery.Selection) {
-					if rowIndex == 0 {
-						return
-					}
-
-					cells := row.Find("td")
-					if cells.Length() >= 4 {
-						name := strings.TrimSpace(cells.Eq(0).Text())
-						totalMarksStr := strings.TrimSpace(cells.Eq(1).Text())
-						obtainedMarksStr := strings.TrimSpace(cells.Eq(2).Text())
-						assignedDate := strings.TrimSpace(cells.Eq(3).Text())
-
-						totalMarks := 0.0
-						if totalMarksFloat, err := strconv.ParseFloat(totalMarksStr, 64); err == nil {
-							totalMarks = totalMarksFloat
-						}
-
-						obtainedMarks := 0.0
-						if obtainedMarksFloat, err := strconv.ParseFloat(obtainedMarksStr, 64); err == nil {
-							obtainedMarks = obtainedMarksFloat
-						}
-
-						if name != "" {
-							assessmentRecords = append(assessmentRecords, Assessment{
-								name:          name,
-								obtainedMarks: float32(obtainedMarks),
-								totalMarks:    float32(totalMarks),
-								assignedDate:  assignedDate,
-							})
-						}
-					}
-				})
-			}
-		})
-
 		if len(assessmentRecords) == 0 {
-			if foundTable {
-				// Table found but no records -> Legitimately empty
-				course.Assessment = []Assessment{}
-				return nil
+			if !foundTable {
+				// If we got no assessments and no table, maybe the page load failed or was incomplete
+				// Wait and retry unless it's the last attempt
+				lastErr = fmt.Errorf("no assessments table found: %w", errReportIncomplete)
+				s.retryPause(task, attempt, policy.MaxRetries, lastErr)
+				continue
 			}
-			// If we got no assessments and no table, maybe the page load failed or was incomplete
-			// Wait and retry unless it's the last attempt
-			time.Sleep(time.Second * 2)
-			continue
+			// Table found but no records -> Legitimately empty
+			assessmentRecords = []Assessment{}
 		}
 
-		course.Assessment = assessmentRecords
+		s.studentMu.Lock()
+		defer s.studentMu.Unlock()
+		index := getCourseIndex(s, courseId)
+		if index == -1 {
+			return fmt.Errorf("course not found")
+		}
+		s.Student.Courses[index].Assessment = assessmentRecords
 		return nil
 	}
 
-	// If we exhausted retries but got empty assessments, return success but with warning/empty list
-	// because maybe the student really has no assessments
-	return nil
+	return retriesExhausted("course assessments", policy.MaxRetries, lastErr)
+}
+
+// retriesExhausted is the error of a fetch whose every attempt failed: the
+// last attempt's, which keeps deciding the exit code, so a network failure
+// exits with EXIT_NETWORK_FAILURE and incomplete pages with
+// EXIT_PARSE_FAILURE.
+func retriesExhausted(what string, attempts int, lastErr error) error {
+	return fmt.Errorf("failed to fetch %s after %d attempts: %w", what, attempts, lastErr)
 }
 
 func (s *Session) fetchCourseAttendance(refresh bool, courseId string) error {
@@ -365,231 +285,205 @@ func (s *Session) fetchCourseAttendance(refresh bool, courseId string) error {
 
 	// Cache
 	if !refresh {
-		index := getCourseIndex(s, courseId)
-		if index == -1 {
+		course, ok := s.courseSnapshot(courseId)
+		if !ok {
 			return fmt.Errorf("course not found")
 		}
-		if len(s.Student.Courses[index].Attendance) > 0 {
+		if len(course.Attendance) > 0 {
 			return nil
 		}
 	}
 
-	maxRetries := 10
-	for range maxRetries {
-		client := &http.Client{}
-
-		req, err := http.NewRequest("GET", COURSES_VIEW_ATTENDANCE_URL+courseId, nil)
-		if err != nil {
-			time.Sleep(time.Second * 2)
-			continue
-		}
-
-		for _, cookie := range s.Cookies {
-			req.AddCookie(cookie)
-		}
-
-		resp, err := client.Do(req)
-		if err != nil {
-			time.Sleep(time.Second * 2)
-			continue
+	policy := appConfig.requestPolicy(OP_ATTENDANCE)
+	task := attendanceTask(courseId)
+	var lastErr error
+	for attempt := range policy.MaxRetries {
+		progress := func(stage string, step int) {
+			s.reportProgress(FetchProgress{Task: task, Stage: stage, Step: step, Steps: ATTENDANCE_STAGES, Attempt: attempt + 1, MaxAttempts: policy.MaxRetries})
 		}
-		resp.Body.Close()
 
-		req, err = http.NewRequest("GET", COURSES_VIEW_ATTENDANCE_ASPX_URL, nil)
+		// The ReportViewer sometimes returns an empty payload before the
+		// report is ready (errReportIncomplete), so retry rather than
+		// caching nothing. A report cut short is shown meanwhile.
+		report, err := s.attendanceAttempt(courseId, progress)
 		if err != nil {
-			time.Sleep(time.Second * 2)
+			lastErr = err
+			if errors.Is(err, errReportIncomplete) && len(report.Records) > 0 {
+				s.reportProgress(FetchProgress{Task: task, Attempt: attempt + 1, MaxAttempts: policy.MaxRetries, Partial: &report})
+			}
+			s.retryPause(task, attempt, policy.MaxRetries, err)
 			continue
 		}
 
-		for _, cookie := range s.Cookies {
-			req.AddCookie(cookie)
-		}
-
-		resp, err = client.Do(req)
-		if err != nil {
-			time.Sleep(time.Second * 2)
-			continue
+		s.studentMu.Lock()
+		defer s.studentMu.Unlock()
+		index := getCourseIndex(s, courseId)
+		if index == -1 {
+			return fmt.Errorf("course not found")
 		}
-		defer resp.Body.Close()
 
-		bodyBytes, err := io.ReadAll(resp.Body)
-		if err != nil {
-			time.Sleep(time.Second * 2)
-			continue
-		}
+		course := &s.Student.Courses[index]
+		course.TotalLectures = report.TotalLectures
+		course.AttendancePercentage = report.AttendancePercentage
+		course.Attendance = report.Records
+		return nil
+	}
 
-		bodyString := string(bodyBytes)
-		doc, err := goquery.NewDocumentFromReader(strings.NewReader(bodyString))
-		if err != nil {
-			time.Sleep(time.Second * 2)
-			continue
-		}
+	return retriesExhausted("course attendance", policy.MaxRetries, lastErr)
+}
 
-		var viewState, viewStateGen, eventValidation string
+// attendanceForm is the attendance report page of a course, opened and
+// waiting to be posted back.
+type attendanceForm struct {
+	courseID        string
+	viewState       string
+	viewStateGen    string
+	eventValidation string
+	openedAt        time.Time
+}
 
-		doc.Find("input[name='__VIEWSTATE']").Each(func(i int, sel *goquery.Selection) {
-			if val, exists := sel.Attr("value"); exists {
-				viewState = val
-			}
-		})
+// ATTENDANCE_FORM_TTL is how long a warmed-up attendance form is trusted;
+// the portal's session state may have moved on after that.
+const ATTENDANCE_FORM_TTL = 2 * time.Minute
 
-		doc.Find("input[name='__VIEWSTATEGENERATOR']").Each(func(i int, sel *goquery.Selection) {
-			if val, exists := sel.Attr("value"); exists {
-				viewStateGen = val
-			}
-		})
+// openAttendanceForm selects the course on the portal and opens its
+// attendance report, which carries the viewstate to post back.
+func (s *Session) openAttendanceForm(client *http.Client, courseId string, progress func(string, int)) (*attendanceForm, error) {
+	progress(STAGE_SELECT_COURSE, 1)
+	req, err := http.NewRequest("GET", COURSES_VIEW_ATTENDANCE_URL+courseId, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, cookie := range s.Cookies {
+		req.AddCookie(cookie)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
 
-		doc.Find("input[name='__EVENTVALIDATION']").Each(func(i int, sel *goquery.Selection) {
-			if val, exists := sel.Attr("value"); exists {
-				eventValidation = val
-			}
-		})
+	progress(STAGE_OPEN_REPORT, 2)
+	req, err = http.NewRequest("GET", COURSES_VIEW_ATTENDANCE_ASPX_URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, cookie := range s.Cookies {
+		req.AddCookie(cookie)
+	}
+	resp, err = client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := goquery.NewDocumentFromReader(limitReport(resp.Body))
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
 
-		if viewState == "" || viewStateGen == "" || eventValidation == "" {
-			time.Sleep(time.Second * 2)
-			continue
+	form := &attendanceForm{courseID: courseId, openedAt: time.Now()}
+	doc.Find("input[name='__VIEWSTATE']").Each(func(i int, sel *goquery.Selection) {
+		if val, exists := sel.Attr("value"); exists {
+			form.viewState = val
 		}
-
-		data := url.Values{}
-		data.Set("__VIEWSTATE", viewState)
-		data.Set("__VIEWSTATEGENERATOR", viewStateGen)
-		data.Set("__EVENTVALIDATION", eventValidation)
-		data.Set("__EVENTTARGET", "Attendance_Report$ctl13$Reserved_AsyncLoadTarget")
-		data.Set("__EVENTARGUMENT", "")
-		data.Set("Attendance_Report$ctl03$ctl00", "")
-		data.Set("Attendance_Report$ctl03$ctl01", "")
-		data.Set("Attendance_Report$isReportViewerInVs", "")
-		data.Set("Attendance_Report$ctl14", "")
-		data.Set("Attendance_Report$ctl15", "standards")
-		data.Set("Attendance_Report$AsyncWait$HiddenCancelField", "False")
-		data.Set("Attendance_Report$ToggleParam$store", "")
-		data.Set("Attendance_Report$ToggleParam$collapse", "false")
-		data.Set("Attendance_Report$ctl12$ClientClickedId", "")
-		data.Set("Attendance_Report$ctl11$store", "")
-		data.Set("Attendance_Report$ctl11$collapse", "false")
-		data.Set("Attendance_Report$ctl13$VisibilityState$ctl00", "None")
-		data.Set("Attendance_Report$ctl13$ScrollPosition", "")
-		data.Set("Attendance_Report$ctl13$ReportControl$ctl02", "")
-		data.Set("Attendance_Report$ctl13$ReportControl$ctl03", "")
-		data.Set("Attendance_Report$ctl13$ReportControl$ctl04", "100")
-
-		req, err = http.NewRequest("POST", COURSES_VIEW_ATTENDANCE_ASPX_URL, strings.NewReader(data.Encode()))
-		if err != nil {
-			time.Sleep(time.Second * 2)
-			continue
+	})
+	doc.Find("input[name='__VIEWSTATEGENERATOR']").Each(func(i int, sel *goquery.Selection) {
+		if val, exists := sel.Attr("value"); exists {
+			form.viewStateGen = val
 		}
-
-		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-		req.Header.Set("Referer", "https://online.umt.edu.pk/Reports/Attendance.aspx")
-
-		for _, cookie := range s.Cookies {
-			req.AddCookie(cookie)
+	})
+	doc.Find("input[name='__EVENTVALIDATION']").Each(func(i int, sel *goquery.Selection) {
+		if val, exists := sel.Attr("value"); exists {
+			form.eventValidation = val
 		}
+	})
+	if form.viewState == "" || form.viewStateGen == "" || form.eventValidation == "" {
+		return nil, fmt.Errorf("attendance report has no viewstate")
+	}
+	return form, nil
+}
 
-		resp, err = client.Do(req)
-		if err != nil {
-			time.Sleep(time.Second * 2)
-			continue
+// attendanceAttempt runs the attendance report round trips once. A form
+// warmed up for the course spares the two GETs; it is only good for one
+// POST. The portal remembers one selected course per session, so report
+// round trips never overlap.
+func (s *Session) attendanceAttempt(courseId string, progress func(string, int)) (AttendanceReport, error) {
+	s.attendanceMu.Lock()
+	defer s.attendanceMu.Unlock()
+
+	client := s.httpClient(OP_ATTENDANCE)
+	form := s.warmForm
+	s.warmForm = nil
+	if form == nil || form.courseID != courseId || time.Since(form.openedAt) > ATTENDANCE_FORM_TTL {
+		var err error
+		if form, err = s.openAttendanceForm(client, courseId, progress); err != nil {
+			return AttendanceReport{}, err
 		}
-		defer resp.Body.Close()
+	}
 
-		finalBodyBytes, err := io.ReadAll(resp.Body)
-		if err != nil {
-			time.Sleep(time.Second * 2)
-			continue
-		}
+	data := url.Values{}
+	data.Set("__VIEWSTATE", form.viewState)
+	data.Set("__VIEWSTATEGENERATOR", form.viewStateGen)
+	data.Set("__EVENTVALIDATION", form.eventValidation)
+	data.Set("__EVENTTARGET", "Attendance_Report$ctl13$Reserved_AsyncLoadTarget")
+	data.Set("__EVENTARGUMENT", "")
+	data.Set("Attendance_Report$ctl03$ctl00", "")
+	data.Set("Attendance_Report$ctl03$ctl01", "")
+	data.Set("Attendance_Report$isReportViewerInVs", "")
+	data.Set("Attendance_Report$ctl14", "")
+	data.Set("Attendance_Report$ctl15", "standards")
+	data.Set("Attendance_Report$AsyncWait$HiddenCancelField", "False")
+	data.Set("Attendance_Report$ToggleParam$store", "")
+	data.Set("Attendance_Report$ToggleParam$collapse", "false")
+	data.Set("Attendance_Report$ctl12$ClientClickedId", "")
+	data.Set("Attendance_Report$ctl11$store", "")
+	data.Set("Attendance_Report$ctl11$collapse", "false")
+	data.Set("Attendance_Report$ctl13$VisibilityState$ctl00", "None")
+	data.Set("Attendance_Report$ctl13$ScrollPosition", "")
+	data.Set("Attendance_Report$ctl13$ReportControl$ctl02", "")
+	data.Set("Attendance_Report$ctl13$ReportControl$ctl03", "")
+	data.Set("Attendance_Report$ctl13$ReportControl$ctl04", "100")
+
+	progress(STAGE_RENDER_REPORT, 3)
+	req, err := http.NewRequest("POST", COURSES_VIEW_ATTENDANCE_ASPX_URL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return AttendanceReport{}, err
+	}
 
-		if len(finalBodyBytes) < 30000 {
-			time.Sleep(time.Second * 2)
-			continue
-		}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Referer", "https://online.umt.edu.pk/Reports/Attendance.aspx")
 
-		doc, err = goquery.NewDocumentFromReader(strings.NewReader(string(finalBodyBytes)))
-		if err != nil {
-			continue
-		}
+	for _, cookie := range s.Cookies {
+		req.AddCookie(cookie)
+	}
 
-		var extractedData []string
-		doc.Find("div.canGrowTextBoxInTablix.cannotShrinkTextBoxInTablix").Each(func(i int, s *goquery.Selection) {
-			currentText := strings.TrimSpace(s.Text())
-			if currentText != "" && !strings.Contains(currentText, "canGrowTextBoxInTablix") {
-				extractedData = append(extractedData, currentText)
-			}
-			sibling := s.Next()
-			if sibling.Length() > 0 {
-				siblingText := strings.TrimSpace(sibling.Text())
-				if siblingText != "" {
-					extractedData = append(extractedData, siblingText)
-				}
-			}
-		})
+	resp, err := client.Do(req)
+	if err != nil {
+		return AttendanceReport{}, err
+	}
+	defer resp.Body.Close()
 
-		index := getCourseIndex(s, courseId)
-		if index == -1 {
-			return fmt.Errorf("course not found")
-		} else {
-			course := &s.Student.Courses[index]
-
-			if len(extractedData) < 6 {
-				// If no data found, retry (unless it's the 10th try)
-				// But maybe the course just has no attendance yet?
-				// The retry logic is good if we suspect flaky server returns empty payload
-				time.Sleep(time.Second * 2)
-				continue
-			} else {
-				var attendanceRecords []Attendance
-
-				startIndex := 4
-				endIndex := len(extractedData) - 2
-
-				for i := startIndex; i < endIndex; i += 4 {
-					if i+3 >= endIndex {
-						break
-					}
-
-					lectureNumStr := strings.TrimPrefix(extractedData[i], "Lecture No. ")
-					lectureNum, err := strconv.Atoi(lectureNumStr)
-					if err != nil {
-						continue
-					}
-
-					date := extractedData[i+1]
-					present := strings.EqualFold(extractedData[i+2], "Present")
-					faculty := extractedData[i+3]
-
-					attendanceRecords = append(attendanceRecords, Attendance{
-						LectureNumber: lectureNum,
-						LectureDate:   date,
-						Attendance:    present,
-						Faculty:       faculty,
-					})
-				}
-
-				totalLecturesStr := strings.TrimPrefix(extractedData[len(extractedData)-2], "Total Lectures : ")
-				totalLectures, err := strconv.Atoi(totalLecturesStr)
-				if err != nil {
-					totalLectures = 0
-				}
-
-				percentageStr := extractedData[len(extractedData)-1]
-				percentageStr = strings.TrimSuffix(percentageStr, " % Attandence")
-				percentageStr = strings.TrimSuffix(percentageStr, " % Attendance")
-				attendancePercentage, err := strconv.Atoi(strings.TrimSpace(percentageStr))
-				if err != nil {
-					attendancePercentage = 0
-				}
-
-				course.TotalLectures = totalLectures
-				course.AttendancePercentage = attendancePercentage
-				course.Attendance = attendanceRecords
+	progress(STAGE_READ_ATTENDANCE, 4)
+	return s.readAttendanceReport(resp.Body)
+}
 
-			}
-		}
-		return nil
+// warmAttendance opens the attendance report of a course ahead of time, so
+// fetching it takes only the POST.
+func (s *Session) warmAttendance(courseId string) error {
+	if len(s.Cookies) == 0 {
+		return fmt.Errorf("no cookies found during warming up attendance")
 	}
+	s.attendanceMu.Lock()
+	defer s.attendanceMu.Unlock()
 
-	// If failed after retries, just return success with empty data to avoid crashing app
-	// This mirrors assessment logic
+	if f := s.warmForm; f != nil && f.courseID == courseId && time.Since(f.openedAt) < ATTENDANCE_FORM_TTL {
+		return nil
+	}
+	form, err := s.openAttendanceForm(s.httpClient(OP_ATTENDANCE), courseId, func(string, int) {})
+	if err != nil {
+		return fmt.Errorf("failed to warm up attendance: %w", err)
+	}
+	s.warmForm = form
 	return nil
 }
 
@@ -603,10 +497,15 @@ func (s *Session) fetchTranscript(refresh bool) error {
 	if len(s.Cookies) == 0 {
 		return fmt.Errorf("no cookies found during fetching user transcript")
 	}
-	maxRetries := 10
+	policy := appConfig.requestPolicy(OP_TRANSCRIPT)
 	var lastErr error
-	for range maxRetries {
-		client := &http.Client{}
+	for attempt := range policy.MaxRetries {
+		client := s.httpClient(OP_TRANSCRIPT)
+		progress := func(stage string, step int) {
+			s.reportProgress(FetchProgress{Task: TASK_TRANSCRIPT, Stage: stage, Step: step, Steps: TRANSCRIPT_STAGES, Attempt: attempt + 1, MaxAttempts: policy.MaxRetries})
+		}
+
+		progress(STAGE_OPEN_TRANSCRIPT, 1)
 		req, err := http.NewRequest("GET", TRANSCRIPT_URL, nil)
 		if err != nil {
 			lastErr = fmt.Errorf("failed to create request: %w", err)
@@ -618,18 +517,16 @@ func (s *Session) fetchTranscript(refresh bool) error {
 		resp, err := client.Do(req)
 		if err != nil {
 			lastErr = fmt.Errorf("failed to get transcript page: %w", err)
+			s.retryPause(TASK_TRANSCRIPT, attempt, policy.MaxRetries, err)
 			continue
 		}
-		bodyBytes, err := io.ReadAll(resp.Body)
+		_, err = io.Copy(io.Discard, resp.Body)
 		resp.Body.Close()
 		if err != nil {
 			lastErr = fmt.Errorf("failed to read response body: %w", err)
 			continue
 		}
-		if err := os.WriteFile("transcript_initial.txt", bodyBytes, 0644); err != nil {
-			lastErr = fmt.Errorf("failed to write initial transcript file: %w", err)
-			continue
-		}
+		progress(STAGE_LOAD_TRANSCRIPT, 2)
 		req2, err := http.NewRequest("GET", TRANSCRIPT_ASPX_URL, nil)
 		if err != nil {
 			lastErr = fmt.Errorf("failed to create transcript ASPX request: %w", err)
@@ -641,58 +538,19 @@ func (s *Session) fetchTranscript(refresh bool) error {
 		resp2, err := client.Do(req2)
 		if err != nil {
 			lastErr = fmt.Errorf("failed to get transcript ASPX page: %w", err)
+			s.retryPause(TASK_TRANSCRIPT, attempt, policy.MaxRetries, err)
 			continue
 		}
-		bodyBytes2, err := io.ReadAll(resp2.Body)
+		progress(STAGE_READ_TRANSCRIPT, 3)
+		transcript, err := s.readTranscriptReport(resp2.Body)
 		resp2.Body.Close()
 		if err != nil {
-			lastErr = fmt.Errorf("failed to read transcript ASPX response: %w", err)
-			continue
-		}
-		if len(bodyBytes2) < 30000 {
-			lastErr = fmt.Errorf("response too small: %d bytes", len(bodyBytes2))
-			continue
-		}
-		doc, err := goquery.NewDocumentFromReader(strings.NewReader(string(bodyBytes2)))
-		if err != nil {
-			lastErr = fmt.Errorf("failed to parse HTML document: %w", err)
+			lastErr = err
 			continue
 		}
-
-		spans := []string{}
-		doc.Find("span").Each(func(i int, s *goquery.Selection) {
-			text := strings.TrimSpace(s.Text())
-			spans = append(spans, text)
-		})
-
-		if err := parseSpanData(s, spans); err != nil {
-			lastErr = fmt.Errorf("failed to parse span data: %w", err)
-			continue
-		}
-
-		var extractedData []string
-		doc.Find("div.canGrowTextBoxInTablix.cannotShrinkTextBoxInTablix").Each(func(i int, s *goquery.Selection) {
-			currentText := strings.TrimSpace(s.Text())
-			if currentText != "" && !strings.Contains(currentText, "canGrowTextBoxInTablix") {
-				extractedData = append(extractedData, currentText)
-			}
-			sibling := s.Next()
-			if sibling.Length() > 0 {
-				siblingText := strings.TrimSpace(sibling.Text())
-				if siblingText != "" {
-					extractedData = append(extractedData, siblingText)
-				}
-			}
-		})
-
-		if len(extractedData) == 0 {
-			lastErr = fmt.Errorf("no transcript data found in response")
-			continue
-		}
-		err = parseTranscript(s, extractedData)
-		if err != nil {
-			lastErr = fmt.Errorf("failed to parse transcript: %w", err)
-			continue
+		s.setTranscript(transcript)
+		if cacheMode == CACHE_OFF {
+			return nil
 		}
 		if err := saveTranscriptCache(s); err != nil {
 			fmt.Printf("Warning: failed to save transcript cache: %v\n", err)
@@ -700,168 +558,7 @@ func (s *Session) fetchTranscript(refresh bool) error {
 		return nil
 	}
 	if lastErr != nil {
-		return fmt.Errorf("failed to fetch transcript after %d attempts: %w", maxRetries, lastErr)
-	}
-	return fmt.Errorf("failed to fetch transcript after %d attempts", maxRetries)
-}
-
-func parseSpanData(s *Session, spans []string) error {
-	for i, span := range spans {
-		span = strings.TrimSpace(span)
-
-		if span == "Credit Hours Earned :" && i+1 < len(spans) {
-			s.Student.Transcript.CreditHoursEarned = strings.TrimSpace(spans[i+1])
-		}
-
-		if span == "Credit Hours for GPA :" && i+1 < len(spans) {
-			s.Student.Transcript.CreditHoursForGPA = strings.TrimSpace(spans[i+1])
-		}
-
-		if span == "Total Grade Points :" && i+1 < len(spans) {
-			s.Student.Transcript.TotalGradePoints = strings.TrimSpace(spans[i+1])
-		}
-
-		if span == "CGPA :" && i+1 < len(spans) {
-			cgpaValue := strings.TrimSpace(spans[i+1])
-			if parts := strings.Split(cgpaValue, " /"); len(parts) > 0 {
-				s.Student.Transcript.TotalCGPA = strings.TrimSpace(parts[0])
-			} else {
-				s.Student.Transcript.TotalCGPA = cgpaValue
-			}
-		}
-	}
-	return nil
-}
-
-func parseTranscript(s *Session, extractedData []string) error {
-	semesterData := make(map[Semester][]TranscriptCourse)
-	var currentSemester Semester
-	var courses []TranscriptCourse
-	var totalCreditHours int
-	var totalCreditHoursForGrade int
-	var totalGradePoints float32
-
-	isZeroGradePointGrade := func(grade string) bool {
-		zeroGrades := []string{"P", "I", "W", "SA", "S", "NC", "F"}
-		return slices.Contains(zeroGrades, grade)
-	}
-
-	i := 0
-	for i < len(extractedData) {
-		line := strings.TrimSpace(extractedData[i])
-
-		if line == "Course Code" || line == "Course Title" || line == "Cr. Hrs" || line == "Grade" || line == "G.P." {
-			i++
-			continue
-		}
-
-		if strings.Contains(line, "Fall") || strings.Contains(line, "Spring") || strings.Contains(line, "Summer") {
-			if currentSemester.Name != "" && len(courses) > 0 {
-				semesterData[currentSemester] = courses
-			}
-
-			currentSemester = Semester{Name: line}
-			courses = []TranscriptCourse{}
-			i++
-			continue
-		}
-
-		if strings.Contains(line, "Cr. Hrs. Earned:") {
-			parts := strings.Split(line, "CGPA:")
-			if len(parts) >= 2 {
-				creditHoursPart := strings.TrimSpace(strings.Replace(parts[0], "Cr. Hrs. Earned:", "", 1))
-				if creditHours, err := strconv.Atoi(creditHoursPart); err == nil {
-					currentSemester.CreditHoursEarned = creditHours
-					totalCreditHours += creditHours
-				}
-
-				cgpaStr := strings.TrimSpace(parts[1])
-				if cgpa, err := strconv.ParseFloat(cgpaStr, 32); err == nil {
-					currentSemester.CGPA = float32(cgpa)
-				}
-			}
-			i++
-			continue
-		}
-
-		if strings.Contains(line, "SGPA:") {
-			sgpaStr := strings.TrimSpace(strings.Replace(line, "SGPA:", "", 1))
-			if sgpa, err := strconv.ParseFloat(sgpaStr, 32); err == nil {
-				currentSemester.SGPA = float32(sgpa)
-			}
-			i++
-			continue
-		}
-
-		if i+3 < len(extractedData) {
-			code := strings.TrimSpace(line)
-			title := strings.TrimSpace(extractedData[i+1])
-			creditHoursStr := strings.TrimSpace(extractedData[i+2])
-			grade := strings.TrimSpace(extractedData[i+3])
-
-			if creditHours, err := strconv.Atoi(creditHoursStr); err == nil {
-				var gradePoint float32
-				fieldsToSkip := 4 // code, title, credit hours, grade
-
-				if isZeroGradePointGrade(grade) {
-					gradePoint = 0.0
-					if grade == "F" && !strings.Contains(title, "[R]") {
-						totalCreditHoursForGrade += creditHours
-					}
-				} else {
-					if i+4 < len(extractedData) {
-						gradePointStr := strings.TrimSpace(extractedData[i+4])
-
-						if gp, err := strconv.ParseFloat(gradePointStr, 32); err == nil &&
-							!strings.Contains(gradePointStr, "Cr. Hrs. Earned:") &&
-							!strings.Contains(gradePointStr, "Fall") &&
-							!strings.Contains(gradePointStr, "Spring") &&
-							!strings.Contains(gradePointStr, "Summer") &&
-							!strings.Contains(gradePointStr, "Course Code") {
-							gradePoint = float32(gp)
-							if gradePoint != 0 {
-								totalCreditHoursForGrade += creditHours
-							}
-							fieldsToSkip = 5
-						} else {
-							gradePoint = 0.0
-							fieldsToSkip = 4
-						}
-					} else {
-						gradePoint = 0.0
-					}
-				}
-
-				course := TranscriptCourse{
-					Code:        code,
-					Title:       title,
-					CreditHours: creditHours,
-					Grade:       grade,
-					GradePoint:  gradePoint,
-				}
-				courses = append(courses, course)
-
-				if !isZeroGradePointGrade(grade) && gradePoint > 0 {
-					totalGradePoints += gradePoint
-				}
-
-				i += fieldsToSkip
-				continue
-			}
-		}
-		i++
+		return fmt.Errorf("failed to fetch transcript after %d attempts: %w", policy.MaxRetries, lastErr)
 	}
-
-	if currentSemester.Name != "" && len(courses) > 0 {
-		semesterData[currentSemester] = courses
-	}
-
-	semesterKeys := parseAndSortSemesters(semesterData)
-
-	s.Student.Transcript.Semester = make(map[Semester][]TranscriptCourse)
-	for _, key := range semesterKeys {
-		s.Student.Transcript.Semester[key.semester] = semesterData[key.semester]
-	}
-
-	return nil
+	return fmt.Errorf("failed to fetch transcript after %d attempts", policy.MaxRetries)
 }
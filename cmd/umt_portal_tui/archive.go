@@ -0,0 +1,210 @@
+package main
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	DEFAULT_EXPORT_FILE = "umt_data.zip"
+	ARCHIVE_VERSION     = 1
+	// Archives only ever hold a few JSON files; anything bigger is not ours.
+	MAX_ARCHIVE_ENTRY_SIZE = 16 << 20
+)
+
+// archiveManifest describes an export. Saved credentials are never part of
+// an archive.
+type archiveManifest struct {
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exported_at"`
+	AppVersion string    `json:"app_version"`
+	StudentID  string    `json:"student_id"`
+}
+
+// exportPath is bound to export's --out flag.
+var exportPath string
+
+func exportFlags(fs *flag.FlagSet) {
+	fs.StringVar(&exportPath, "out", DEFAULT_EXPORT_FILE, "archive to write")
+}
+
+func runExport(s *Session, args []string) (Output, error) {
+	if len(args) > 0 {
+		return Output{}, withExitCode(EXIT_USAGE, fmt.Errorf("unexpected arguments; use --out to choose the file"))
+	}
+
+	data, dataErr := readDataCache()
+	transcript, transcriptErr := readCacheFile(transcriptCachePath())
+	if dataErr != nil && transcriptErr != nil {
+		return Output{}, fmt.Errorf("no cached data to export; run the TUI or a data command such as '%s courses' first", commandName())
+	}
+
+	manifest := archiveManifest{
+		Version:    ARCHIVE_VERSION,
+		ExportedAt: time.Now(),
+		AppVersion: readBuildInfo().Version,
+		StudentID:  data.Student.ID,
+	}
+
+	f, err := os.Create(exportPath)
+	if err != nil {
+		return Output{}, fmt.Errorf("failed to create archive: %w", err)
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	if err := writeArchiveJSON(zw, "manifest.json", manifest); err != nil {
+		return Output{}, err
+	}
+	if dataErr == nil {
+		if err := writeArchiveJSON(zw, "data.json", data); err != nil {
+			return Output{}, err
+		}
+	}
+	if transcriptErr == nil {
+		w, err := zw.Create("transcript.json")
+		if err != nil {
+			return Output{}, fmt.Errorf("failed to write archive: %w", err)
+		}
+		if _, err := w.Write(transcript); err != nil {
+			return Output{}, fmt.Errorf("failed to write archive: %w", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return Output{}, fmt.Errorf("failed to write archive: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return Output{}, fmt.Errorf("failed to write archive: %w", err)
+	}
+
+	return Output{
+		Header: []string{"archive", "student_id", "courses", "transcript"},
+		Rows:   [][]string{{exportPath, manifest.StudentID, strconv.Itoa(len(data.Courses)), strconv.FormatBool(transcriptErr == nil)}},
+		Value:  map[string]any{"archive": exportPath, "manifest": manifest},
+		Record: true,
+	}, nil
+}
+
+func runImport(s *Session, args []string) (Output, error) {
+	if len(args) != 1 {
+		return Output{}, withExitCode(EXIT_USAGE, fmt.Errorf("expected the archive to import"))
+	}
+	if cacheMode == CACHE_OFF {
+		return Output{}, withExitCode(EXIT_USAGE, fmt.Errorf("import only writes the cache, which --no-cache, --record and --replay leave alone"))
+	}
+
+	zr, err := zip.OpenReader(args[0])
+	if err != nil {
+		return Output{}, fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer zr.Close()
+
+	var (
+		manifest   archiveManifest
+		data       SerializableData
+		transcript SerializableTranscript
+	)
+	if err := readArchiveJSON(&zr.Reader, "manifest.json", &manifest); err != nil {
+		return Output{}, fmt.Errorf("%s is not an export archive: %w", args[0], err)
+	}
+	if manifest.Version > ARCHIVE_VERSION {
+		return Output{}, fmt.Errorf("archive version %d is newer than this build supports; update with '%s update'", manifest.Version, commandName())
+	}
+	dataErr := readArchiveJSON(&zr.Reader, "data.json", &data)
+	transcriptErr := readArchiveJSON(&zr.Reader, "transcript.json", &transcript)
+	for _, err := range []error{dataErr, transcriptErr} {
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return Output{}, err
+		}
+	}
+
+	// The archive's student replaces the cached one, data and transcript
+	// alike, so neither is left next to another student's.
+	student := manifest.StudentID
+	if dataErr == nil {
+		student = data.Student.ID
+	}
+	var notes []string
+	if previous, err := readDataCache(); err == nil && previous.Student.ID != "" && previous.Student.ID != student {
+		if dataErr == nil {
+			notes = append(notes, fmt.Sprintf("Replaced cached data of %s.", previous.Student.ID))
+		} else {
+			notes = append(notes, fmt.Sprintf("Removed cached data of %s, whose transcript this is not.", previous.Student.ID))
+		}
+		for _, remove := range []func() error{deleteDataCache, deleteTranscriptCache} {
+			if err := remove(); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return Output{}, fmt.Errorf("failed to remove the cached data of %s: %w", previous.Student.ID, err)
+			}
+		}
+	}
+	if dataErr == nil {
+		if err := writeDataCache(data); err != nil {
+			return Output{}, err
+		}
+	}
+	if transcriptErr == nil {
+		imported := NewSession()
+		imported.Student.Transcript = transcript.ToTranscript()
+		if err := saveTranscriptCache(imported); err != nil {
+			return Output{}, err
+		}
+	}
+
+	return Output{
+		Header: []string{"archive", "student_id", "exported_at", "courses", "transcript"},
+		Rows: [][]string{{args[0], manifest.StudentID, manifest.ExportedAt.Format(time.DateTime),
+			strconv.Itoa(len(data.Courses)), strconv.FormatBool(transcriptErr == nil)}},
+		Value:  manifest,
+		Notes:  notes,
+		Record: true,
+	}, nil
+}
+
+func writeArchiveJSON(zw *zip.Writer, name string, v any) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("failed to write %s to archive: %w", name, err)
+	}
+	return nil
+}
+
+// readArchiveJSON decodes the named entry, returning an error wrapping
+// os.ErrNotExist when the archive does not have it.
+func readArchiveJSON(zr *zip.Reader, name string, v any) error {
+	f, err := zr.Open(name)
+	if err != nil {
+		return fmt.Errorf("archive has no %s: %w", name, err)
+	}
+	defer f.Close()
+
+	raw, err := io.ReadAll(io.LimitReader(f, MAX_ARCHIVE_ENTRY_SIZE+1))
+	if err != nil {
+		return fmt.Errorf("failed to read %s from archive: %w", name, err)
+	}
+	if len(raw) > MAX_ARCHIVE_ENTRY_SIZE {
+		return fmt.Errorf("%s in archive is too large", name)
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return fmt.Errorf("invalid %s in archive: %w", name, err)
+	}
+	return nil
+}
+
+func readCacheFile(path string, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(path)
+}
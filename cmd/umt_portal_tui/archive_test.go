@@ -0,0 +1,156 @@
+package main
+
+import (
+	"archive/zip"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExportImportRoundTrip(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	useMockPortal(t, portal)
+	withTerminal(t, false, "")
+	t.Setenv("UMT_STUDENT_ID", "F2023000000")
+	t.Setenv("UMT_PASSWORD", "hunter2")
+
+	for _, args := range [][]string{{"attendance", "cc2042"}, {"assessments", "cs3051"}, {"transcript"}} {
+		c, _ := findCommand(args[0])
+		if err := runCommand(c, args[1:], strings.NewReader(""), io.Discard); err != nil {
+			t.Fatalf("%s: %v", args[0], err)
+		}
+	}
+	if err := SaveCreds(Credentials{StudentID: "F2023000000", Password: "hunter2"}); err != nil {
+		t.Fatal(err)
+	}
+	before, err := readDataCache()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	archive := filepath.Join(t.TempDir(), "data.zip")
+	export, _ := findCommand("export")
+	if err := runCommand(export, []string{"--out", archive}, strings.NewReader(""), io.Discard); err != nil {
+		t.Fatal(err)
+	}
+
+	zr, err := zip.OpenReader(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	zr.Close()
+	if want := []string{"manifest.json", "data.json", "transcript.json"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("archive has %v, want %v", names, want)
+	}
+
+	deleteCaches()
+	importCmd, _ := findCommand("import")
+	if err := runCommand(importCmd, []string{archive}, strings.NewReader(""), io.Discard); err != nil {
+		t.Fatal(err)
+	}
+
+	after, err := readDataCache()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(before.Courses, after.Courses) || !before.SavedAt.Equal(after.SavedAt) {
+		t.Error("imported data differs from the export")
+	}
+	s := NewSession()
+	if err := loadTranscriptCache(s); err != nil || s.Student.Transcript.TotalCGPA == "" {
+		t.Errorf("transcript not restored: %v", err)
+	}
+}
+
+func TestImportOtherStudent(t *testing.T) {
+	useMockPortal(t, newMockPortal(t, "F2023000000", "hunter2"))
+
+	archive := writeTestArchive(t, "F2023111111", map[string]any{
+		"data.json": SerializableData{Student: SerializableStudent{ID: "F2023111111"}},
+	})
+
+	if err := writeDataCache(SerializableData{Student: SerializableStudent{ID: "F2023000000"}}); err != nil {
+		t.Fatal(err)
+	}
+	cached := NewSession()
+	cached.Student.Transcript.TotalCGPA = "3.31"
+	if err := saveTranscriptCache(cached); err != nil {
+		t.Fatal(err)
+	}
+
+	importCmd, _ := findCommand("import")
+	prevMode := cacheMode
+	t.Cleanup(func() { cacheMode = prevMode })
+	cacheMode = CACHE_OFF
+	if err := runCommand(importCmd, []string{archive}, strings.NewReader(""), io.Discard); exitCode(err) != EXIT_USAGE {
+		t.Errorf("import with --no-cache: %v, want a usage error", err)
+	}
+	if data, err := readDataCache(); err != nil || data.Student.ID != "F2023000000" {
+		t.Fatalf("import with --no-cache wrote the cache: %+v, %v", data.Student, err)
+	}
+
+	cacheMode = CACHE_NORMAL
+	if err := runCommand(importCmd, []string{archive}, strings.NewReader(""), io.Discard); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := readDataCache(); err != nil || data.Student.ID != "F2023111111" {
+		t.Errorf("data not imported: %+v, %v", data.Student, err)
+	}
+	if err := loadTranscriptCache(NewSession()); err == nil {
+		t.Error("the previous student's transcript is still cached")
+	}
+}
+
+func TestImportOtherStudentTranscript(t *testing.T) {
+	useMockPortal(t, newMockPortal(t, "F2023000000", "hunter2"))
+	archive := writeTestArchive(t, "F2023111111", map[string]any{
+		"transcript.json": SerializableTranscript{SchemaVersion: SCHEMA_VERSION, TotalCGPA: "2.90"},
+	})
+	if err := writeDataCache(SerializableData{Student: SerializableStudent{ID: "F2023000000"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	importCmd, _ := findCommand("import")
+	if err := runCommand(importCmd, []string{archive}, strings.NewReader(""), io.Discard); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := readDataCache(); err == nil {
+		t.Errorf("another student's transcript was imported next to the cached data of %s", data.Student.ID)
+	}
+	s := NewSession()
+	if err := loadTranscriptCache(s); err != nil || s.Student.Transcript.TotalCGPA != "2.90" {
+		t.Errorf("transcript not imported: %v", err)
+	}
+}
+
+// writeTestArchive writes an export archive of the student with the given
+// entries besides the manifest.
+func writeTestArchive(t *testing.T, studentID string, entries map[string]any) string {
+	t.Helper()
+	archive := filepath.Join(t.TempDir(), "data.zip")
+	f, err := os.Create(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zw := zip.NewWriter(f)
+	if err := writeArchiveJSON(zw, "manifest.json", archiveManifest{Version: ARCHIVE_VERSION, StudentID: studentID}); err != nil {
+		t.Fatal(err)
+	}
+	for name, v := range entries {
+		if err := writeArchiveJSON(zw, name, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return archive
+}
@@ -0,0 +1,93 @@
+package main
+
+import "strings"
+
+// Assessment categories, inferred from the names instructors give them.
+// CATEGORY_ORDER is the order AssessmentView groups them in.
+const (
+	CATEGORY_QUIZ       = "quiz"
+	CATEGORY_ASSIGNMENT = "assignment"
+	CATEGORY_MIDTERM    = "midterm"
+	CATEGORY_FINAL      = "final"
+	CATEGORY_PROJECT    = "project"
+	CATEGORY_OTHER      = "other"
+)
+
+var CATEGORY_ORDER = []string{CATEGORY_QUIZ, CATEGORY_ASSIGNMENT, CATEGORY_MIDTERM, CATEGORY_FINAL, CATEGORY_PROJECT, CATEGORY_OTHER}
+
+// categoryKeywords are matched against the lowercased name, in order, so
+// "Final Project" is a project and "Mid Term Quiz" a midterm.
+var categoryKeywords = []struct {
+	category string
+	words    []string
+}{
+	{CATEGORY_PROJECT, []string{"project", "proj"}},
+	{CATEGORY_MIDTERM, []string{"mid term", "midterm", "mid-term", "mids", "mid "}},
+	{CATEGORY_FINAL, []string{"final", "terminal"}},
+	{CATEGORY_QUIZ, []string{"quiz", "qz"}},
+	{CATEGORY_ASSIGNMENT, []string{"assignment", "assign", "homework", "hw", "lab task"}},
+}
+
+func assessmentCategory(name string) string {
+	name = strings.ToLower(name) + " "
+	for _, c := range categoryKeywords {
+		for _, word := range c.words {
+			if strings.Contains(name, word) {
+				return c.category
+			}
+		}
+	}
+	return CATEGORY_OTHER
+}
+
+type assessmentGroup struct {
+	Category    string
+	Assessments []Assessment
+	Obtained    float32
+	Total       float32
+}
+
+func groupAssessments(assessments []Assessment) []assessmentGroup {
+	byCategory := map[string]*assessmentGroup{}
+	for _, a := range assessments {
+		category := assessmentCategory(a.name)
+		g := byCategory[category]
+		if g == nil {
+			g = &assessmentGroup{Category: category}
+			byCategory[category] = g
+		}
+		g.Assessments = append(g.Assessments, a)
+		g.Obtained += a.obtainedMarks
+		g.Total += a.totalMarks
+	}
+
+	var groups []assessmentGroup
+	for _, category := range CATEGORY_ORDER {
+		if g := byCategory[category]; g != nil {
+			groups = append(groups, *g)
+		}
+	}
+	return groups
+}
+
+// assessmentRow is a line of the grouped assessment table: a category
+// subtotal, or one assessment under it. dropped marks an assessment left out
+// by the category's best-of-N policy.
+type assessmentRow struct {
+	group      *assessmentGroup
+	assessment Assessment
+	dropped    bool
+}
+
+func assessmentRows(assessments []Assessment, grading CourseGrading) []assessmentRow {
+	var rows []assessmentRow
+	groups := grading.groups(assessments)
+	for i := range groups {
+		rows = append(rows, assessmentRow{group: &groups[i]})
+		drop := grading.dropped(groups[i].Category, groups[i].Assessments)
+		for j, a := range groups[i].Assessments {
+			rows = append(rows, assessmentRow{assessment: a, dropped: drop[j]})
+		}
+	}
+	return rows
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// The assessment exports are in English whatever the UI language, like the
+// CLI, since they are pasted where others read them.
+
+type assessmentExport struct {
+	Title    string
+	Header   []string
+	Rows     [][]string
+	Total    []string
+	Weighted string
+}
+
+func buildAssessmentExport(course Course, grading CourseGrading) assessmentExport {
+	e := assessmentExport{
+		Title:  fmt.Sprintf("%s %s: assessments", course.Code, course.Title),
+		Header: []string{"Assessment", "Category", "Obtained", "Total", "%", "Date"},
+	}
+	class := hasClassStats(course.Assessment)
+	if class {
+		e.Header = append(e.Header, "Class avg", "Top")
+	}
+	marks := func(m float32) string {
+		if m <= 0 {
+			return "-"
+		}
+		return fmt.Sprintf("%.1f", m)
+	}
+	percent := func(obtained, total float32) string {
+		if total <= 0 {
+			return "-"
+		}
+		return fmt.Sprintf("%.1f%%", obtained/total*100)
+	}
+
+	var obtained, total float32
+	for _, group := range grading.groups(course.Assessment) {
+		drop := grading.dropped(group.Category, group.Assessments)
+		for i, a := range group.Assessments {
+			name := a.name
+			if drop[i] {
+				name += " (dropped)"
+			}
+			row := []string{name, group.Category, fmt.Sprintf("%.1f", a.obtainedMarks), fmt.Sprintf("%.1f", a.totalMarks), percent(a.obtainedMarks, a.totalMarks), a.assignedDate}
+			if class {
+				row = append(row, marks(a.classAverage), marks(a.classHighest))
+			}
+			e.Rows = append(e.Rows, row)
+			obtained += a.obtainedMarks
+			total += a.totalMarks
+		}
+	}
+	e.Total = []string{"Total", "", fmt.Sprintf("%.1f", obtained), fmt.Sprintf("%.1f", total), percent(obtained, total), ""}
+	if class {
+		e.Total = append(e.Total, "", "")
+	}
+
+	if p, ok := grading.project(course.Assessment); ok {
+		e.Weighted = fmt.Sprintf("Weighted: %.1f of %.0f%% assessed so far, projected %.1f%%", p.Earned, p.Assessed, p.Percentage())
+		if letter, ok := grading.letterGrade(p.Percentage()); ok {
+			e.Weighted += " (" + letter + ")"
+		}
+	}
+	return e
+}
+
+var markdownCellReplacer = strings.NewReplacer("|", `\|`, "\n", " ")
+
+func (e assessmentExport) Markdown() string {
+	var b strings.Builder
+	row := func(cells []string) {
+		b.WriteString("|")
+		for _, cell := range cells {
+			b.WriteString(" " + markdownCellReplacer.Replace(cell) + " |")
+		}
+		b.WriteString("\n")
+	}
+
+	fmt.Fprintf(&b, "### %s\n\n", e.Title)
+	row(e.Header)
+	b.WriteString("|")
+	for i := range e.Header {
+		if numericColumn(i) {
+			b.WriteString("---:|")
+		} else {
+			b.WriteString("---|")
+		}
+	}
+	b.WriteString("\n")
+	for _, r := range e.Rows {
+		row(r)
+	}
+	bold := make([]string, len(e.Total))
+	for i, cell := range e.Total {
+		if cell != "" {
+			bold[i] = "**" + cell + "**"
+		}
+	}
+	row(bold)
+	if e.Weighted != "" {
+		fmt.Fprintf(&b, "\n%s\n", e.Weighted)
+	}
+	return b.String()
+}
+
+// numericColumn reports whether column i of the export holds marks, which
+// are right-aligned.
+func numericColumn(i int) bool {
+	return i >= 2 && i <= 4 || i >= 6
+}
+
+var assessmentHTMLTemplate = template.Must(template.New("assessments").Funcs(template.FuncMap{"numeric": numericColumn}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2rem; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 0.3rem 0.6rem; }
+th { background: #1e3a8a; color: #fff; }
+td.num { text-align: right; }
+tfoot td { font-weight: bold; }
+</style>
+</head>
+<body>
+<h3>{{.Title}}</h3>
+<table>
+<thead><tr>{{range .Header}}<th>{{.}}</th>{{end}}</tr></thead>
+<tbody>
+{{- range .Rows}}
+<tr>{{range $i, $cell := .}}<td{{if numeric $i}} class="num"{{end}}>{{$cell}}</td>{{end}}</tr>
+{{- end}}
+</tbody>
+<tfoot><tr>{{range $i, $cell := .Total}}<td{{if numeric $i}} class="num"{{end}}>{{$cell}}</td>{{end}}</tr></tfoot>
+</table>
+{{- if .Weighted}}
+<p>{{.Weighted}}</p>
+{{- end}}
+</body>
+</html>
+`))
+
+func (e assessmentExport) HTML() (string, error) {
+	var b bytes.Buffer
+	if err := assessmentHTMLTemplate.Execute(&b, e); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// saveAssessmentsHTML writes the HTML export of a course's assessments
+// into dir and returns its path.
+func saveAssessmentsHTML(course Course, grading CourseGrading, dir string) (string, error) {
+	page, err := buildAssessmentExport(course, grading).HTML()
+	if err != nil {
+		return "", fmt.Errorf("failed to render assessments: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create download dir: %w", err)
+	}
+	name := strings.NewReplacer("/", "_", `\`, "_").Replace(course.Code) + "_assessments.html"
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(page), 0644); err != nil {
+		return "", fmt.Errorf("failed to save assessments: %w", err)
+	}
+	return path, nil
+}
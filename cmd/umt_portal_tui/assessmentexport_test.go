@@ -0,0 +1,37 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAssessmentExport(t *testing.T) {
+	course := Course{Code: "CS1001", Title: "Programming Fundamentals", Assessment: []Assessment{
+		{name: "Quiz 1", obtainedMarks: 8, totalMarks: 10, assignedDate: "05-Sep-2025", classAverage: 6.5, classHighest: 9.5},
+		{name: "Quiz 2", obtainedMarks: 4, totalMarks: 10, assignedDate: "19-Sep-2025"},
+		{name: "Assignment 1 | Arrays", obtainedMarks: 17, totalMarks: 20, assignedDate: "12-Sep-2025"},
+		{name: "Mid Term Exam", obtainedMarks: 22, totalMarks: 30, assignedDate: "10-Oct-2025"},
+	}}
+	grading := CourseGrading{
+		Weights: map[string]float64{CATEGORY_QUIZ: 10, CATEGORY_ASSIGNMENT: 10, CATEGORY_MIDTERM: 30, CATEGORY_FINAL: 50},
+		Best:    map[string]int{CATEGORY_QUIZ: 1},
+	}
+	export := buildAssessmentExport(course, grading)
+	assertGoldenText(t, filepath.Join("testdata", "assessments_export.golden"), export.Markdown())
+
+	path, err := saveAssessmentsHTML(course, grading, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	page, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"<td>Assignment 1 | Arrays</td>", `<td class="num">6.5</td>`, "<td>Quiz 2 (dropped)</td>", "projected 77.0%"} {
+		if !strings.Contains(string(page), want) {
+			t.Errorf("HTML export is missing %q:\n%s", want, page)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// The portal writes lecture dates as 02-Sep-2025; the filter bar also takes
+// ISO and day/month/year dates.
+const LECTURE_DATE_LAYOUT = "02-Jan-2006"
+
+var filterDateLayouts = []string{LECTURE_DATE_LAYOUT, "2-Jan-2006", "2006-01-02", "02/01/2006", "2/1/2006", "2 Jan 2006", "2 January 2006"}
+
+var filterMonthLayouts = []string{"Jan 2006", "January 2006", "Jan-2006", "2006-01", "01/2006"}
+
+var errFilterDate = errors.New("not a date, month or range")
+
+// attendanceFilter narrows the attendance report to absences, to lectures
+// between from and to (inclusive, zero for unbounded), or both.
+type attendanceFilter struct {
+	absentOnly bool
+	from, to   time.Time
+	// label describes the date range; input is what was typed for it.
+	label string
+	input string
+}
+
+func (f attendanceFilter) active() bool {
+	return f.absentOnly || !f.from.IsZero() || !f.to.IsZero()
+}
+
+func (f attendanceFilter) apply(records []Attendance) []Attendance {
+	if !f.active() {
+		return records
+	}
+	var matched []Attendance
+	for _, a := range records {
+		if f.absentOnly && a.Attendance {
+			continue
+		}
+		if !f.from.IsZero() || !f.to.IsZero() {
+			date, err := parseLectureDate(a.LectureDate)
+			if err != nil || !f.from.IsZero() && date.Before(f.from) || !f.to.IsZero() && date.After(f.to) {
+				continue
+			}
+		}
+		matched = append(matched, a)
+	}
+	return matched
+}
+
+func (f attendanceFilter) describe() string {
+	var parts []string
+	if f.absentOnly {
+		parts = append(parts, T("attendance.filter_absent"))
+	}
+	if f.label != "" {
+		parts = append(parts, f.label)
+	}
+	return strings.Join(parts, " • ")
+}
+
+func parseLectureDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range filterDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, errFilterDate
+}
+
+// parseDateSpan reads a day or a month as the first and last day it covers.
+func parseDateSpan(s string) (from, to time.Time, err error) {
+	s = strings.TrimSpace(s)
+	if t, err := parseLectureDate(s); err == nil {
+		return t, t, nil
+	}
+	for _, layout := range filterMonthLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, t.AddDate(0, 1, -1), nil
+		}
+	}
+	return time.Time{}, time.Time{}, fmt.Errorf("%q: %w", s, errFilterDate)
+}
+
+// parseDateFilter reads the filter bar: a day, a month, or a range of them
+// separated by "..", " to " or " - ". Either end of a range may be left
+// out. Empty input clears the range.
+func parseDateFilter(input string) (from, to time.Time, label string, err error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return
+	}
+	for _, sep := range []string{"..", " to ", " - "} {
+		start, end, ok := strings.Cut(input, sep)
+		if !ok {
+			continue
+		}
+		if strings.TrimSpace(start) != "" {
+			if from, _, err = parseDateSpan(start); err != nil {
+				return
+			}
+		}
+		if strings.TrimSpace(end) != "" {
+			if _, to, err = parseDateSpan(end); err != nil {
+				return
+			}
+		}
+		if from.IsZero() && to.IsZero() {
+			return from, to, "", fmt.Errorf("%q: %w", input, errFilterDate)
+		}
+		if !from.IsZero() && !to.IsZero() && to.Before(from) {
+			from, to = to, from
+		}
+		return from, to, formatDateRange(from, to), nil
+	}
+
+	if from, to, err = parseDateSpan(input); err != nil {
+		return
+	}
+	return from, to, formatDateRange(from, to), nil
+}
+
+func formatDateRange(from, to time.Time) string {
+	const day = "2 Jan 2006"
+	switch {
+	case from.Equal(to):
+		return from.Format(day)
+	case !from.IsZero() && from.Day() == 1 && to.Equal(from.AddDate(0, 1, -1)):
+		return from.Format("Jan 2006")
+	case from.IsZero():
+		return "… – " + to.Format(day)
+	case to.IsZero():
+		return from.Format(day) + " – …"
+	}
+	return from.Format(day) + " – " + to.Format(day)
+}
+
+// attendanceMonths lists the months records fall in, in the order they
+// first appear.
+func attendanceMonths(records []Attendance) []time.Time {
+	var months []time.Time
+	seen := map[time.Time]bool{}
+	for _, a := range records {
+		date, err := parseLectureDate(a.LectureDate)
+		if err != nil {
+			continue
+		}
+		month := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+		if !seen[month] {
+			seen[month] = true
+			months = append(months, month)
+		}
+	}
+	return months
+}
+
+// nextMonth moves the filter to the month after the one it shows, starting
+// from the first and clearing the range after the last.
+func (f attendanceFilter) nextMonth(records []Attendance) attendanceFilter {
+	months := attendanceMonths(records)
+	next := 0
+	for i, month := range months {
+		if f.from.Equal(month) && f.to.Equal(month.AddDate(0, 1, -1)) {
+			next = i + 1
+		}
+	}
+	f.input = ""
+	if next >= len(months) {
+		f.from, f.to, f.label = time.Time{}, time.Time{}, ""
+		return f
+	}
+	f.from, f.to = months[next], months[next].AddDate(0, 1, -1)
+	f.label = formatDateRange(f.from, f.to)
+	return f
+}
+
+func (m model) filteredAttendance() []Attendance {
+	if len(m.courses) == 0 || m.selectedCourse >= len(m.courses) {
+		return nil
+	}
+	return m.attendanceFilter.apply(m.courses[m.selectedCourse].Attendance)
+}
+
+func (m model) handleAttendanceFilterKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		if !m.rememberMe {
+			deleteCaches()
+		}
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.editingFilter = false
+		m.filterError = ""
+	case tea.KeyEnter:
+		from, to, label, err := parseDateFilter(m.filterInput)
+		if err != nil {
+			m.filterError = T("attendance.filter_invalid", m.filterInput)
+			break
+		}
+		m.attendanceFilter.from, m.attendanceFilter.to = from, to
+		m.attendanceFilter.label, m.attendanceFilter.input = label, strings.TrimSpace(m.filterInput)
+		m.editingFilter = false
+		m.filterError = ""
+		m.currentAttendancePage = 0
+	case tea.KeyBackspace:
+		if runes := []rune(m.filterInput); len(runes) > 0 {
+			m.filterInput = string(runes[:len(runes)-1])
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.filterInput += string(msg.Runes)
+	}
+	return m, nil
+}
+
+// renderAttendanceFilter is the filter bar: the input while it is edited,
+// otherwise what the report is narrowed to.
+func (m model) renderAttendanceFilter(shown, total int) string {
+	labelStyle := lipgloss.NewStyle().
+		Foreground(ACCENT).
+		Bold(true)
+
+	inputStyle := lipgloss.NewStyle().
+		Foreground(WHITE)
+
+	hintStyle := lipgloss.NewStyle().
+		Foreground(GREY)
+
+	errorStyle := lipgloss.NewStyle().
+		Foreground(DANGER)
+
+	if m.editingFilter {
+		lines := []string{
+			labelStyle.Render(T("attendance.filter")) + " " + inputStyle.Render(m.filterInput+"█"),
+			hintStyle.Render(T("attendance.filter_hint")),
+		}
+		if m.filterError != "" {
+			lines = append(lines, errorStyle.Render(m.filterError))
+		}
+		return lipgloss.JoinVertical(lipgloss.Center, lines...)
+	}
+	if !m.attendanceFilter.active() {
+		return ""
+	}
+	return labelStyle.Render(T("attendance.filter")) + " " +
+		inputStyle.Render(m.attendanceFilter.describe()) + " " +
+		hintStyle.Render(T("attendance.filter_count", shown, total))
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muesli/termenv"
+)
+
+const (
+	ATTENTION_BELL   = "bell"
+	ATTENTION_NOTIFY = "notify"
+	ATTENTION_OFF    = "off"
+
+	// ATTENTION_AFTER is how long a fetch has to take before its end is
+	// signalled.
+	ATTENTION_AFTER = 5 * time.Second
+)
+
+func validateAttention(mode string) error {
+	switch mode {
+	case "", ATTENTION_BELL, ATTENTION_NOTIFY, ATTENTION_OFF:
+		return nil
+	}
+	return fmt.Errorf("attention %q is not %s, %s or %s", mode, ATTENTION_BELL, ATTENTION_NOTIFY, ATTENTION_OFF)
+}
+
+// signalAttention rings the terminal bell or, with "notify", sends an
+// OSC 777 desktop notification; tests replace it.
+var signalAttention = func(mode, title, body string) {
+	if mode == ATTENTION_NOTIFY {
+		termenv.Notify(title, body)
+		return
+	}
+	fmt.Fprint(os.Stdout, "\a")
+}
+
+// attentionCmd signals that a fetch ended, unless attention is off.
+func attentionCmd(elapsed time.Duration) tea.Cmd {
+	mode := appConfig.Attention
+	if mode == ATTENTION_OFF {
+		return nil
+	}
+	return func() tea.Msg {
+		signalAttention(mode, "UMT Portal", T("attention.done", formatElapsed(elapsed)))
+		return nil
+	}
+}
+
+// awaitingAttention reports whether the end of the current fetch should be
+// signalled: it has been loading for a while in a terminal that reported
+// losing focus. Terminals without focus reporting are never signalled.
+func (m model) awaitingAttention() bool {
+	return m.unfocused && m.currentView == LoadingView && !m.loadingSince.IsZero() && time.Since(m.loadingSince) >= ATTENTION_AFTER
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestAttentionAfterLongFetch(t *testing.T) {
+	var signals []string
+	prev := signalAttention
+	t.Cleanup(func() { signalAttention = prev })
+	signalAttention = func(mode, title, body string) {
+		signals = append(signals, body)
+	}
+
+	fetch := func(since time.Duration, focus tea.Msg) model {
+		t.Helper()
+		signals = nil
+		m := model{currentView: LoadingView, loadingSince: time.Now().Add(-since), width: 80, height: 24}
+		next, _ := m.Update(focus)
+		next, cmd := next.(model).Update(ResultsLoadedMsg{})
+		m = next.(model)
+		if m.currentView != ProvisionalResultView {
+			t.Fatalf("still in view %v", m.currentView)
+		}
+		if cmd == nil {
+			return m
+		}
+		msg := cmd()
+		batch, ok := msg.(tea.BatchMsg)
+		if !ok {
+			batch = tea.BatchMsg{func() tea.Msg { return msg }}
+		}
+		for _, c := range batch {
+			if c != nil {
+				c()
+			}
+		}
+		return m
+	}
+
+	m := fetch(10*time.Second, tea.BlurMsg{})
+	if len(signals) != 1 || !strings.Contains(signals[0], "10s") {
+		t.Errorf("long fetch in the background signalled %q", signals)
+	}
+	if !m.unfocused {
+		t.Error("focus regained by the fetch ending")
+	}
+	if fetch(10*time.Second, tea.FocusMsg{}); len(signals) != 0 {
+		t.Errorf("focused terminal signalled %q", signals)
+	}
+	if fetch(time.Second, tea.BlurMsg{}); len(signals) != 0 {
+		t.Errorf("quick fetch signalled %q", signals)
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// MAX_RETRY_AFTER caps how long a Retry-After hint can hold a fetch up.
+	MAX_RETRY_AFTER = 5 * time.Minute
+	// MAX_BACKOFF caps the doubling delay after error pages sent without a
+	// hint.
+	MAX_BACKOFF = time.Minute
+)
+
+// portalBusyError is a 429 or 5xx response: the portal is rate limiting or
+// struggling under load. RetryAfter is its Retry-After hint, zero without
+// one.
+type portalBusyError struct {
+	Status     int
+	RetryAfter time.Duration
+}
+
+func (e *portalBusyError) Error() string {
+	msg := fmt.Sprintf("portal busy (%d %s)", e.Status, http.StatusText(e.Status))
+	if e.RetryAfter > 0 {
+		msg += ", asked to retry in " + e.RetryAfter.String()
+	}
+	return msg
+}
+
+// busyTransport turns 429 and 5xx responses into a portalBusyError, so
+// callers back off instead of parsing the error page.
+type busyTransport struct {
+	next http.RoundTripper
+}
+
+func (t *busyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500 {
+		return resp, err
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+	return nil, &portalBusyError{Status: resp.StatusCode, RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())}
+}
+
+// parseRetryAfter reads a Retry-After header, in seconds or as an HTTP date,
+// capped at MAX_RETRY_AFTER. It is zero when the header is missing or
+// invalid.
+func parseRetryAfter(header string, now time.Time) time.Duration {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return 0
+	}
+	var d time.Duration
+	if seconds, err := strconv.Atoi(header); err == nil {
+		d = time.Duration(seconds) * time.Second
+	} else if at, err := http.ParseTime(header); err == nil {
+		d = at.Sub(now)
+	}
+	return min(max(d, 0), MAX_RETRY_AFTER)
+}
+
+// retryPause waits before the next attempt of a fetch. After err from a
+// busy portal it waits as long as the portal asked, or twice as long as
+// the attempt before when it gave no hint, and reports when the fetch
+// resumes; otherwise it waits retryDelay.
+func (s *Session) retryPause(task string, attempt, maxAttempts int, err error) {
+	var busy *portalBusyError
+	if !errors.As(err, &busy) {
+		time.Sleep(retryDelay)
+		return
+	}
+	delay := busy.RetryAfter
+	if delay == 0 {
+		delay = min(retryDelay<<min(attempt, 16), MAX_BACKOFF)
+	}
+	s.reportProgress(FetchProgress{Task: task, Attempt: attempt + 1, MaxAttempts: maxAttempts, RetryAt: time.Now().Add(delay)})
+	time.Sleep(delay)
+}
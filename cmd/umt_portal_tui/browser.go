@@ -0,0 +1,62 @@
+package main
+
+import (
+	"os/exec"
+	"runtime"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type BrowserOpenedMsg struct {
+	URL   string
+	Error error
+}
+
+// openURL starts the default browser on url without waiting for it; tests
+// replace it.
+var openURL = func(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	case "darwin":
+		cmd = exec.Command("open", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
+
+// portalURL is the portal page behind the current view, or "" if it has
+// none. The browser has its own portal session, so it may ask to log in.
+func (m model) portalURL() string {
+	switch m.currentView {
+	case CoursesView:
+		return UMT_COURSES_URL
+	case AttendanceView, AssessmentView:
+		if m.selectedCourse >= len(m.courses) {
+			return ""
+		}
+		if m.currentView == AttendanceView {
+			return COURSES_VIEW_ATTENDANCE_URL + m.courses[m.selectedCourse].ID
+		}
+		return COURSES_VIEW_ASSESSMENT_URL + m.courses[m.selectedCourse].ID
+	case TranscriptView:
+		return TRANSCRIPT_URL
+	case ProvisionalResultView:
+		return RESULTS_URL
+	case FeesView:
+		return FEES_URL
+	}
+	return ""
+}
+
+func openBrowserCmd(url string) tea.Cmd {
+	return func() tea.Msg {
+		return BrowserOpenedMsg{URL: url, Error: openURL(url)}
+	}
+}
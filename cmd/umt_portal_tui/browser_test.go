@@ -0,0 +1,50 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestOpenInBrowser(t *testing.T) {
+	var opened []string
+	prev := openURL
+	t.Cleanup(func() { openURL = prev })
+	openURL = func(url string) error {
+		opened = append(opened, url)
+		if strings.HasPrefix(url, FEES_URL) {
+			return errors.New("no browser")
+		}
+		return nil
+	}
+
+	o := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("o")}
+	m := model{courses: []Course{{ID: "42", Code: "CC1021"}}, currentView: AttendanceView}
+	next, cmd := m.handleKeyPress(o)
+	if cmd == nil {
+		t.Fatal("o did nothing in the attendance view")
+	}
+	next, _ = next.(model).Update(cmd())
+	if want := COURSES_VIEW_ATTENDANCE_URL + "42"; len(opened) != 1 || opened[0] != want {
+		t.Fatalf("opened %v, want %s", opened, want)
+	}
+	if got := next.(model).header(); !strings.Contains(got, T("browser.opened", opened[0])) {
+		t.Errorf("header does not report the opened page:\n%s", got)
+	}
+
+	m.currentView = FeesView
+	next, cmd = m.handleKeyPress(o)
+	next, _ = next.(model).Update(cmd())
+	if got := next.(model).browserNotice; got != T("browser.failed", "no browser", FEES_URL) {
+		t.Errorf("failure reported as %q", got)
+	}
+
+	m.currentView = CourseDetailView
+	if _, cmd := m.handleKeyPress(o); cmd != nil {
+		if _, ok := cmd().(BrowserOpenedMsg); ok {
+			t.Error("o opened the browser instead of the outline in course details")
+		}
+	}
+}
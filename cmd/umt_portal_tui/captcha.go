@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// The login form has always carried a fixed SecurityCode that the portal
+// never checked. If it starts serving a real captcha, the image is shown to
+// the user and the code they type is sent instead.
+const DEFAULT_SECURITY_CODE = "abcde"
+
+const CAPTCHA_WIDTH = 60
+
+// loginCaptcha is a captcha served with the login page. The cookie jar ties
+// it to the portal session the code has to be submitted from.
+type loginCaptcha struct {
+	Image image.Image
+	jar   http.CookieJar
+}
+
+// findCaptchaImage returns the src of a captcha image on the login page, or
+// "" when the page has none.
+func findCaptchaImage(doc *goquery.Document) string {
+	var src string
+	doc.Find("form img").EachWithBreak(func(_ int, img *goquery.Selection) bool {
+		s, _ := img.Attr("src")
+		id, _ := img.Attr("id")
+		alt, _ := img.Attr("alt")
+		text := strings.ToLower(s + " " + id + " " + alt)
+		if strings.Contains(text, "captcha") || strings.Contains(text, "securitycode") {
+			src = s
+			return false
+		}
+		return true
+	})
+	return src
+}
+
+func fetchCaptchaImage(client *http.Client, src string) (image.Image, error) {
+	base, _ := url.Parse(UMT_LOGIN_URL)
+	ref, err := url.Parse(src)
+	if err != nil {
+		return nil, fmt.Errorf("invalid captcha link %q: %w", src, err)
+	}
+
+	resp, err := client.Get(base.ResolveReference(ref).String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to download captcha: %w", err)
+	}
+	defer resp.Body.Close()
+
+	img, _, err := image.Decode(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode captcha image: %w", err)
+	}
+	return img, nil
+}
+
+// captchaRamp runs from light to dark, as captchas are dark text on a light
+// background.
+const captchaRamp = " .:-=+*#%@"
+
+// renderCaptcha draws img at most width cells wide. With color, each cell is
+// an upper half block covering two pixel rows; without, a character from
+// captchaRamp stands for the cell's brightness.
+func renderCaptcha(img image.Image, width int, color bool) string {
+	bounds := img.Bounds()
+	if bounds.Dx() == 0 || bounds.Dy() == 0 {
+		return ""
+	}
+	width = min(width, bounds.Dx())
+	height := max(bounds.Dy()*width/bounds.Dx()/2, 1)
+
+	pixel := func(col, row int) (r, g, b uint32) {
+		x := bounds.Min.X + col*bounds.Dx()/width
+		y := bounds.Min.Y + min(row*bounds.Dy()/(height*2), bounds.Dy()-1)
+		r, g, b, _ = img.At(x, y).RGBA()
+		return r >> 8, g >> 8, b >> 8
+	}
+
+	var out strings.Builder
+	for row := range height {
+		for col := range width {
+			tr, tg, tb := pixel(col, row*2)
+			br, bg, bb := pixel(col, row*2+1)
+			if color {
+				out.WriteString(lipgloss.NewStyle().
+					Foreground(lipgloss.Color(fmt.Sprintf("#%02x%02x%02x", tr, tg, tb))).
+					Background(lipgloss.Color(fmt.Sprintf("#%02x%02x%02x", br, bg, bb))).
+					Render("▀"))
+				continue
+			}
+			luma := (299*(tr+br) + 587*(tg+bg) + 114*(tb+bb)) / 2000
+			out.WriteByte(captchaRamp[(255-luma)*uint32(len(captchaRamp)-1)/255])
+		}
+		if row < height-1 {
+			out.WriteByte('\n')
+		}
+	}
+	return out.String()
+}
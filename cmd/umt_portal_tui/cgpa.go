@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+func (c Config) validateCGPAFloor() error {
+	if c.CGPAFloor < 0 || c.CGPAFloor > 4 {
+		return fmt.Errorf("cgpa_floor %v is not between 0 and 4", c.CGPAFloor)
+	}
+	return nil
+}
+
+// projectedCGPA is the CGPA with the provisional result's posted grades
+// added to the transcript, counting only the latest attempt of a retaken
+// course as the transcript does. A result already on the transcript is not
+// counted twice. It is zero without any credit hours for GPA.
+func projectedCGPA(t Transcript, r ProvisionalResult) float64 {
+	semesters := make(map[Semester][]TranscriptCourse, len(t.Semester)+1)
+	posted := r.Posted()
+	for sem, courses := range t.Semester {
+		semesters[sem] = slices.Clone(courses)
+		if strings.EqualFold(sem.Name, r.Semester) {
+			posted = false
+		}
+	}
+	if posted {
+		var graded []TranscriptCourse
+		for _, c := range r.Courses {
+			if c.Grade != "" {
+				graded = append(graded, c)
+			}
+		}
+		semesters[Semester{Name: r.Semester}] = graded
+	}
+
+	projected := Transcript{Semester: semesters}
+	applyRepeatPolicy(&projected)
+	creditHours, points := projected.gpaTotals()
+	if creditHours == 0 {
+		return 0
+	}
+	return points / float64(creditHours)
+}
+
+// belowCGPAFloor reports the projected CGPA and whether it is under the
+// configured floor.
+func (c Config) belowCGPAFloor(t Transcript, r ProvisionalResult) (float64, bool) {
+	cgpa := projectedCGPA(t, r)
+	return cgpa, c.CGPAFloor > 0 && cgpa > 0 && cgpa < c.CGPAFloor
+}
+
+// updateCGPAWarning sets or clears the CGPA warning after the transcript or
+// the provisional result changes.
+func (m *model) updateCGPAWarning(t Transcript) {
+	if cgpa, below := appConfig.belowCGPAFloor(t, m.provisionalResult); below {
+		m.cgpaWarning = T("cgpa.below_floor", cgpa, appConfig.CGPAFloor)
+	} else {
+		m.cgpaWarning = ""
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestProjectedCGPA(t *testing.T) {
+	transcript := Transcript{Semester: map[Semester][]TranscriptCourse{
+		{Name: "Fall 2024"}: {
+			{Code: "CC1021", CreditHours: 3, Grade: "A", GradePoint: 4},
+			{Code: "MA1011", CreditHours: 3, Grade: "D", GradePoint: 1},
+		},
+	}}
+	result := ProvisionalResult{Semester: "Spring 2025", Courses: []TranscriptCourse{
+		{Code: "MA1011", CreditHours: 3, Grade: "B", GradePoint: 3},
+		{Code: "CC2042", CreditHours: 3, Grade: "C", GradePoint: 2},
+		{Code: "SS1012", CreditHours: 2},
+	}}
+
+	// The retake of MA1011 replaces the D.
+	if got, want := projectedCGPA(transcript, result), 3.0; math.Abs(got-want) > 1e-9 {
+		t.Errorf("projected CGPA %v, want %v", got, want)
+	}
+	if got := transcript.Semester[Semester{Name: "Fall 2024"}][1]; got.Superseded {
+		t.Error("projecting changed the transcript")
+	}
+	if got, want := projectedCGPA(transcript, ProvisionalResult{}), 2.5; got != want {
+		t.Errorf("CGPA without a result %v, want %v", got, want)
+	}
+
+	prev := appConfig
+	t.Cleanup(func() { appConfig = prev })
+	appConfig = Config{CGPAFloor: 2.75}
+	m := model{provisionalResult: result}
+	m.updateCGPAWarning(transcript)
+	if m.cgpaWarning != "" {
+		t.Errorf("warned at a projected 3.00: %q", m.cgpaWarning)
+	}
+	m.provisionalResult = ProvisionalResult{}
+	m.setTranscriptTable(transcript)
+	if !strings.Contains(m.header(), T("cgpa.below_floor", 2.5, 2.75)) {
+		t.Errorf("no warning at 2.50 in the header:\n%s", m.header())
+	}
+	if err := (Config{CGPAFloor: 5}).validateCGPAFloor(); err == nil {
+		t.Error("expected a floor above 4 to be rejected")
+	}
+}
+
+func TestCheckCGPAFloor(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	useMockPortal(t, portal)
+	withTerminal(t, false, "")
+	t.Setenv("UMT_STUDENT_ID", "F2023000000")
+	t.Setenv("UMT_PASSWORD", "hunter2")
+	prev := appConfig
+	t.Cleanup(func() { appConfig = prev })
+
+	appConfig.CGPAFloor = 4
+	c, _ := findCommand("check")
+	var out bytes.Buffer
+	err := runCommand(c, []string{"--format", "json", "cgpa"}, strings.NewReader(""), &out)
+	if exitCode(err) != EXIT_BELOW_THRESHOLD {
+		t.Fatalf("expected exit code %d, got %d (%v)", EXIT_BELOW_THRESHOLD, exitCode(err), err)
+	}
+	var result checkResult
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+	}
+	if len(result.Breaches) != 1 || result.Breaches[0].Kind != CHECK_CGPA {
+		t.Errorf("unexpected breaches: %+v", result.Breaches)
+	}
+
+	appConfig.CGPAFloor = 1
+	out.Reset()
+	if err := runCommand(c, []string{"cgpa"}, strings.NewReader(""), &out); err != nil {
+		t.Errorf("expected no alert above the floor: %v\n%s", err, out.String())
+	}
+}
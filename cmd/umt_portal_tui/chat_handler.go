@@ -11,90 +11,46 @@ import (
 
 func (m model) handleIntent(msg NLPClassificationMsg) (tea.Model, tea.Cmd) {
 	if m.session == nil || !m.session.loggedIn {
-		m.chatHistory = append(m.chatHistory, "⚠️ Please log in first")
+		m.chatHistory = append(m.chatHistory, T("chat.login_first"))
 		return m, nil
 	}
 
 	switch msg.Intent {
 	case "check_cgpa":
-		m.chatHistory = append(m.chatHistory, fmt.Sprintf("📊 Your CGPA is: %s", m.session.Student.CgpaEarned))
+		m.chatHistory = append(m.chatHistory, T("chat.cgpa", m.session.GetStudent().CgpaEarned))
 
 	case "attendance":
 		if msg.ExtractedCourse != nil {
 			selectedCourse := *msg.ExtractedCourse
-			m.chatHistory = append(m.chatHistory, fmt.Sprintf("🎯 Found course: %s", selectedCourse.Code))
-			m.chatHistory = append(m.chatHistory, fmt.Sprintf("🔄 Fetching attendance for %s...", selectedCourse.Code))
-
-			m.setLoadingState(fmt.Sprintf("📊 Getting attendance for %s...", selectedCourse.Code), "Fetching attendance records", "• Esc: Back to chat • Q: Cancel and quit")
-			m.currentView = LoadingView
-			m.lastView = ChatView
-			return m, tea.Batch(
-				m.spinner.Tick,
-				func() tea.Msg {
-					err := m.session.GetCourseAttendance(false, selectedCourse.ID)
-					if err != nil {
-						return CourseActionMsg{
-							Action:   "attendance",
-							CourseID: selectedCourse.ID,
-							Error:    err,
-							Success:  false,
-						}
-					}
-					for i, c := range m.session.Student.Courses {
-						if c.ID == selectedCourse.ID {
-							m.selectedCourse = i
-							break
-						}
-					}
-					return CourseActionMsg{
-						Action:         "attendance",
-						CourseID:       selectedCourse.ID,
-						Error:          nil,
-						Success:        true,
-						UpdatedCourses: m.session.Student.Courses,
-					}
-				},
-			)
+			m.chatHistory = append(m.chatHistory, T("chat.found_course", selectedCourse.Code))
+			m.chatHistory = append(m.chatHistory, T("chat.fetch_attendance", selectedCourse.Code))
+
+			m.setLoadingState(T("loading.attendance", selectedCourse.Code), T("loading.attendance_help"), T("loading.help_back_chat"))
+			m.loadingTask = attendanceTask(selectedCourse.ID)
+			m.pushView(LoadingView)
+			return m, tea.Batch(m.spinner.Tick, attendanceCmd(m.session, selectedCourse.ID))
 		} else {
-			m.chatHistory = append(m.chatHistory, "🔢 Please select a course by number:")
+			m.chatHistory = append(m.chatHistory, T("chat.select_course"))
 			m.awaitingCourseSelection = true
 			m.pendingAction = "attendance"
 		}
 
 	case "greeting":
-		m.chatHistory = append(m.chatHistory, "👋 Hello! I'm the UMT Portal AI Assistant, created by Sunbreeze.")
-		m.chatHistory = append(m.chatHistory, "I can help you check your marks, attendance, and transcript. How can I help you today?")
+		m.chatHistory = append(m.chatHistory, T("chat.greeting"))
+		m.chatHistory = append(m.chatHistory, T("chat.greeting_help"))
 
 	case "identity":
-		m.chatHistory = append(m.chatHistory, "🤖 I am the UMT Portal TUI Assistant.")
-		m.chatHistory = append(m.chatHistory, "I was created by Sunbreeze to help students access their portal data easily via the terminal.")
+		m.chatHistory = append(m.chatHistory, T("chat.identity"))
+		m.chatHistory = append(m.chatHistory, T("chat.identity_detail"))
 
 	case "transcript":
 		if msg.ExtractedSemester > 0 || msg.SpecificQuery != "" {
-			if m.session.Student.Transcript.TotalCGPA == "" {
-				m.chatHistory = append(m.chatHistory, "🔄 Fetching transcript data first...")
-				return m, func() tea.Msg {
-					err := m.session.GetTranscript(false)
-					if err != nil {
-						return NLPClassificationMsg{
-							Query:             msg.Query,
-							Intent:            msg.Intent,
-							Error:             fmt.Errorf("failed to fetch transcript"),
-							ExtractedSemester: msg.ExtractedSemester,
-							SpecificQuery:     msg.SpecificQuery,
-						}
-					}
-					return NLPClassificationMsg{
-						Query:             msg.Query,
-						Intent:            msg.Intent,
-						Confidence:        msg.Confidence,
-						ExtractedSemester: msg.ExtractedSemester,
-						SpecificQuery:     msg.SpecificQuery,
-					}
-				}
+			transcript := m.session.GetStudent().Transcript
+			if transcript.TotalCGPA == "" {
+				m.chatHistory = append(m.chatHistory, T("chat.fetch_transcript"))
+				return m, chatTranscriptCmd(m.session, msg)
 			}
 
-			transcript := m.session.Student.Transcript
 			semesters := parseAndSortSemesters(transcript.Semester)
 
 			var targetSem *SemesterKey
@@ -102,7 +58,7 @@ func (m model) handleIntent(msg NLPClassificationMsg) (tea.Model, tea.Cmd) {
 				if msg.ExtractedSemester <= len(semesters) {
 					targetSem = &semesters[msg.ExtractedSemester-1]
 				} else {
-					m.chatHistory = append(m.chatHistory, fmt.Sprintf("❌ Semester %d not found in your transcript.", msg.ExtractedSemester))
+					m.chatHistory = append(m.chatHistory, T("chat.semester_not_found", msg.ExtractedSemester))
 					return m, nil
 				}
 			}
@@ -111,41 +67,31 @@ func (m model) handleIntent(msg NLPClassificationMsg) (tea.Model, tea.Cmd) {
 				semData := transcript.Semester[targetSem.semester]
 				switch msg.SpecificQuery {
 				case "sgpa":
-					m.chatHistory = append(m.chatHistory, fmt.Sprintf("📄 Semester %s SGPA: %.2f", targetSem.semester.Name, targetSem.semester.SGPA))
+					m.chatHistory = append(m.chatHistory, T("chat.semester_sgpa", targetSem.semester.Name, targetSem.semester.SGPA))
 				case "cgpa":
-					m.chatHistory = append(m.chatHistory, fmt.Sprintf("📈 Semester %s CGPA: %.2f", targetSem.semester.Name, targetSem.semester.CGPA))
+					m.chatHistory = append(m.chatHistory, T("chat.semester_cgpa", targetSem.semester.Name, targetSem.semester.CGPA))
 				case "courses":
-					m.chatHistory = append(m.chatHistory, fmt.Sprintf("📚 Courses in %s:", targetSem.semester.Name))
+					m.chatHistory = append(m.chatHistory, T("chat.semester_courses", targetSem.semester.Name))
 					for _, c := range semData {
 						m.chatHistory = append(m.chatHistory, fmt.Sprintf("  • %s: %s (%s)", c.Code, c.Title, c.Grade))
 					}
 				default:
-					m.chatHistory = append(m.chatHistory, fmt.Sprintf("📄 %s Summary:", targetSem.semester.Name))
-					m.chatHistory = append(m.chatHistory, fmt.Sprintf("  SGPA: %.2f | CGPA: %.2f | Cr. Hrs: %d", targetSem.semester.SGPA, targetSem.semester.CGPA, targetSem.semester.CreditHoursEarned))
+					m.chatHistory = append(m.chatHistory, T("chat.semester_summary", targetSem.semester.Name))
+					m.chatHistory = append(m.chatHistory, T("chat.semester_stats", targetSem.semester.SGPA, targetSem.semester.CGPA, targetSem.semester.CreditHoursEarned))
 				}
 				return m, nil
 			}
 		}
 
-		m.setLoadingState("📄 Getting transcript, please wait", "Fetching your complete academic transcript", "• Esc: Back to chat • Q: Cancel and quit")
-		m.currentView = LoadingView
-		m.lastView = ChatView
-		return m, tea.Batch(
-			m.spinner.Tick,
-			func() tea.Msg {
-				err := m.session.GetTranscript(false)
-				if err != nil {
-					m.session.Student.CgpaEarned = m.session.Student.Transcript.TotalCGPA
-					return CourseActionMsg{Action: "transcript", Error: err, Success: false}
-				}
-				return CourseActionMsg{Action: "transcript", Error: nil, Success: true}
-			},
-		)
+		m.setLoadingState(T("loading.transcript"), T("loading.transcript_help"), T("loading.help_back_chat"))
+		m.loadingTask = TASK_TRANSCRIPT
+		m.pushView(LoadingView)
+		return m, tea.Batch(m.spinner.Tick, transcriptCmd(m.session))
 
 	case "course_details":
 		if msg.ExtractedCourse != nil {
 			selectedCourse := *msg.ExtractedCourse
-			m.chatHistory = append(m.chatHistory, fmt.Sprintf("🎯 Found course: %s", selectedCourse.Code))
+			m.chatHistory = append(m.chatHistory, T("chat.found_course", selectedCourse.Code))
 
 			for i, c := range m.courses {
 				if c.ID == selectedCourse.ID {
@@ -153,58 +99,30 @@ func (m model) handleIntent(msg NLPClassificationMsg) (tea.Model, tea.Cmd) {
 					break
 				}
 			}
-			m.currentView = CourseDetailView
-			m.lastView = ChatView
-			m.chatHistory = append(m.chatHistory, fmt.Sprintf("📖 Showing details for %s...", selectedCourse.Code))
+			m.pushView(CourseDetailView)
+			m.chatHistory = append(m.chatHistory, T("chat.showing_details", selectedCourse.Code))
 		} else {
-			m.currentView = CoursesView
-			m.chatHistory = append(m.chatHistory, fmt.Sprintf("📚 You have %d enrolled courses. Select one to view details.", len(m.courses)))
+			m.pushView(CoursesView)
+			m.chatHistory = append(m.chatHistory, T("chat.course_count", len(m.courses)))
 		}
 
 	case "assessment":
 		if msg.ExtractedCourse != nil {
 			selectedCourse := *msg.ExtractedCourse
-			m.chatHistory = append(m.chatHistory, fmt.Sprintf("🎯 Found course: %s", selectedCourse.Code))
-			m.chatHistory = append(m.chatHistory, fmt.Sprintf("🔄 Fetching assessments for %s...", selectedCourse.Code))
-
-			m.setLoadingState(fmt.Sprintf("📝 Getting assessments for %s...", selectedCourse.Code), "Fetching detailed assessment information", "• Esc: Back to chat • Q: Cancel and quit")
-			m.currentView = LoadingView
-			m.lastView = ChatView
-			return m, tea.Batch(
-				m.spinner.Tick,
-				func() tea.Msg {
-					err := m.session.GetCourseAssessments(selectedCourse.ID)
-					if err != nil {
-						return CourseActionMsg{
-							Action:   "assessments",
-							CourseID: selectedCourse.ID,
-							Error:    err,
-							Success:  false,
-						}
-					}
-					for i, c := range m.session.Student.Courses {
-						if c.ID == selectedCourse.ID {
-							m.selectedCourse = i
-							break
-						}
-					}
-					return CourseActionMsg{
-						Action:         "assessments",
-						CourseID:       selectedCourse.ID,
-						Error:          nil,
-						Success:        true,
-						UpdatedCourses: m.session.Student.Courses,
-					}
-				},
-			)
+			m.chatHistory = append(m.chatHistory, T("chat.found_course", selectedCourse.Code))
+			m.chatHistory = append(m.chatHistory, T("chat.fetch_assessments", selectedCourse.Code))
+
+			m.setLoadingState(T("loading.assessments", selectedCourse.Code), T("loading.assessments_help"), T("loading.help_back_chat"))
+			m.pushView(LoadingView)
+			return m, tea.Batch(m.spinner.Tick, assessmentsCmd(m.session, selectedCourse.ID))
 		} else {
-			m.chatHistory = append(m.chatHistory, "🔢 Please select a course by number:")
+			m.chatHistory = append(m.chatHistory, T("chat.select_course"))
 			m.awaitingCourseSelection = true
 			m.pendingAction = "assessment"
 		}
 
 	default:
-		m.chatHistory = append(m.chatHistory, fmt.Sprintf("❓ Unknown intent: %s", msg.Intent))
+		m.chatHistory = append(m.chatHistory, T("chat.unknown_intent", msg.Intent))
 	}
 
 	return m, nil
@@ -214,7 +132,7 @@ func (m model) handleChatKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "ctrl+c":
 		if !m.rememberMe {
-			deleteTranscriptCache()
+			deleteCaches()
 		}
 		return m, tea.Quit
 
@@ -222,12 +140,12 @@ func (m model) handleChatKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if m.awaitingCourseSelection {
 			m.awaitingCourseSelection = false
 			m.pendingAction = ""
-			m.chatHistory = append(m.chatHistory, "❌ Selection cancelled")
+			m.chatHistory = append(m.chatHistory, T("chat.selection_cancelled"))
 			return m, nil
 		}
 
 		if m.session != nil && m.session.loggedIn {
-			m.currentView = CoursesView
+			m.goBack()
 		}
 
 	case "enter":
@@ -238,7 +156,7 @@ func (m model) handleChatKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if m.awaitingCourseSelection {
 			courseNum, err := strconv.Atoi(strings.TrimSpace(m.chatInput))
 			if err != nil || courseNum < 1 || courseNum > len(m.courses) {
-				m.chatHistory = append(m.chatHistory, fmt.Sprintf("❌ Invalid selection. Please enter a number between 1 and %d", len(m.courses)))
+				m.chatHistory = append(m.chatHistory, T("chat.invalid_selection", len(m.courses)))
 				m.chatInput = ""
 				return m, nil
 			}
@@ -248,69 +166,16 @@ func (m model) handleChatKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.chatInput = ""
 
 			if m.pendingAction == "attendance" {
-				m.chatHistory = append(m.chatHistory, fmt.Sprintf("🔄 Fetching attendance for %s...", selectedCourse.Code))
-				m.setLoadingState(fmt.Sprintf("📊 Getting attendance for %s...", selectedCourse.Code), "Fetching attendance records", "• Esc: Back to chat • Q: Cancel and quit")
-				m.currentView = LoadingView
-				m.lastView = ChatView
-				return m, tea.Batch(
-					m.spinner.Tick,
-					func() tea.Msg {
-						err := m.session.GetCourseAttendance(false, selectedCourse.ID)
-						if err != nil {
-							return CourseActionMsg{
-								Action:   "attendance",
-								CourseID: selectedCourse.ID,
-								Error:    err,
-								Success:  false,
-							}
-						}
-						for i, c := range m.session.Student.Courses {
-							if c.ID == selectedCourse.ID {
-								m.selectedCourse = i
-								break
-							}
-						}
-						return CourseActionMsg{
-							Action:         "attendance",
-							CourseID:       selectedCourse.ID,
-							Error:          nil,
-							Success:        true,
-							UpdatedCourses: m.session.Student.Courses, // <- Add this
-						}
-					},
-				)
+				m.chatHistory = append(m.chatHistory, T("chat.fetch_attendance", selectedCourse.Code))
+				m.setLoadingState(T("loading.attendance", selectedCourse.Code), T("loading.attendance_help"), T("loading.help_back_chat"))
+				m.loadingTask = attendanceTask(selectedCourse.ID)
+				m.pushView(LoadingView)
+				return m, tea.Batch(m.spinner.Tick, attendanceCmd(m.session, selectedCourse.ID))
 			} else if m.pendingAction == "assessment" {
-				m.chatHistory = append(m.chatHistory, fmt.Sprintf("🔄 Fetching assessments for %s...", selectedCourse.Code))
-				m.setLoadingState(fmt.Sprintf("📝 Getting assessments for %s...", selectedCourse.Code), "Fetching detailed assessment information", "• Esc: Back to chat • Q: Cancel and quit")
-				m.currentView = LoadingView
-				m.lastView = ChatView
-				return m, tea.Batch(
-					m.spinner.Tick,
-					func() tea.Msg {
-						err := m.session.GetCourseAssessments(selectedCourse.ID)
-						if err != nil {
-							return CourseActionMsg{
-								Action:   "assessments",
-								CourseID: selectedCourse.ID,
-								Error:    err,
-								Success:  false,
-							}
-						}
-						for i, c := range m.session.Student.Courses {
-							if c.ID == selectedCourse.ID {
-								m.selectedCourse = i
-								break
-							}
-						}
-						return CourseActionMsg{
-							Action:         "assessments",
-							CourseID:       selectedCourse.ID,
-							Error:          nil,
-							Success:        true,
-							UpdatedCourses: m.session.Student.Courses,
-						}
-					},
-				)
+				m.chatHistory = append(m.chatHistory, T("chat.fetch_assessments", selectedCourse.Code))
+				m.setLoadingState(T("loading.assessments", selectedCourse.Code), T("loading.assessments_help"), T("loading.help_back_chat"))
+				m.pushView(LoadingView)
+				return m, tea.Batch(m.spinner.Tick, assessmentsCmd(m.session, selectedCourse.ID))
 			}
 
 			m.pendingAction = ""
@@ -318,7 +183,7 @@ func (m model) handleChatKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 
 		if m.matcher == nil {
-			m.chatHistory = append(m.chatHistory, "❌ Intent matcher not available")
+			m.chatHistory = append(m.chatHistory, T("chat.no_matcher"))
 			m.chatInput = ""
 			return m, nil
 		}
@@ -357,7 +222,7 @@ func (m model) handleChatKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 func (m model) renderChat() string {
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(LIGHT_BLUE).
+		Foreground(ACCENT).
 		MarginBottom(1)
 
 	historyStyle := lipgloss.NewStyle().
@@ -385,7 +250,7 @@ func (m model) renderChat() string {
 	botMsgStyle := lipgloss.NewStyle().
 		Foreground(TURQUOISE)
 
-	title := titleStyle.Render("🤖 AI Assistant")
+	title := titleStyle.Render(T("chat.title"))
 
 	displayHistory := m.chatHistory
 	if len(displayHistory) > 15 {
@@ -395,11 +260,11 @@ func (m model) renderChat() string {
 	var historyText string
 	if len(displayHistory) == 0 {
 		welcomeStyle := lipgloss.NewStyle().Foreground(SILVER).Italic(true)
-		historyText = welcomeStyle.Render("👋 Hi! Ask me about your CGPA, grades, courses, attendance, or transcript!\n\nExamples:\n• What's my CGPA?\n• Show me my attendance\n• Check my grades\n• Who are you?")
+		historyText = welcomeStyle.Render(T("chat.welcome"))
 	} else {
 		var styledMessages []string
 		for _, msg := range displayHistory {
-			if strings.HasPrefix(msg, "🧑 You:") {
+			if strings.HasPrefix(msg, T("chat.you")) {
 				// User message
 				styledMessages = append(styledMessages, userMsgStyle.Render(msg))
 			} else {
@@ -410,7 +275,7 @@ func (m model) renderChat() string {
 
 		if m.awaitingCourseSelection && len(m.courses) > 0 {
 			historyText += "\n\n"
-			courseListStyle := lipgloss.NewStyle().Foreground(YELLOW)
+			courseListStyle := lipgloss.NewStyle().Foreground(WARN)
 			var courseLines []string
 			for i, course := range m.courses {
 				line := fmt.Sprintf("%d. %s - %s", i+1, course.Code, course.Title)
@@ -425,13 +290,13 @@ func (m model) renderChat() string {
 	inputDisplay := m.chatInput + "│"
 	input := inputStyle.Render(inputDisplay)
 
-	helpText := helpStyle.Render("• Type your query and press Enter • Esc: Back to courses • Q: Quit")
+	helpText := helpStyle.Render(T("chat.help"))
 
 	content := lipgloss.JoinVertical(lipgloss.Center,
 		title,
 		history,
 		"",
-		lipgloss.NewStyle().Bold(true).Foreground(WHITE).Render("Your query:"),
+		lipgloss.NewStyle().Bold(true).Foreground(WHITE).Render(T("chat.query")),
 		input,
 		helpText,
 	)
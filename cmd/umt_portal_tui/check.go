@@ -0,0 +1,219 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	CHECK_ATTENDANCE  = "attendance"
+	CHECK_ASSESSMENTS = "assessments"
+	CHECK_FEES        = "fees"
+	CHECK_CGPA        = "cgpa"
+)
+
+type checkChange struct {
+	Course string `json:"course"`
+	Kind   string `json:"kind"`
+	Detail string `json:"detail"`
+}
+
+type checkResult struct {
+	Baseline bool          `json:"baseline"`
+	Changes  []checkChange `json:"changes"`
+	Breaches []checkChange `json:"below_threshold"`
+	// Reminders are fee installments due soon or overdue.
+	Reminders []checkChange `json:"reminders"`
+}
+
+// checkKinds picks what check looks at: the named kinds, or by default
+// attendance, assessments and, when configured, fees and the CGPA.
+func checkKinds(args []string) (map[string]bool, error) {
+	if len(args) == 0 {
+		return map[string]bool{
+			CHECK_ATTENDANCE:  true,
+			CHECK_ASSESSMENTS: true,
+			CHECK_FEES:        len(appConfig.Installments) > 0,
+			CHECK_CGPA:        appConfig.CGPAFloor > 0,
+		}, nil
+	}
+	kinds := map[string]bool{}
+	for _, arg := range args {
+		switch arg {
+		case CHECK_ATTENDANCE, CHECK_ASSESSMENTS, CHECK_FEES, CHECK_CGPA:
+			kinds[arg] = true
+		default:
+			return nil, withExitCode(EXIT_USAGE, fmt.Errorf("unknown check %q (want %s, %s, %s or %s)", arg, CHECK_ATTENDANCE, CHECK_ASSESSMENTS, CHECK_FEES, CHECK_CGPA))
+		}
+	}
+	return kinds, nil
+}
+
+func diffAttendance(code string, old, new []Attendance) []checkChange {
+	var changes []checkChange
+	previous := make(map[int]Attendance, len(old))
+	for _, a := range old {
+		previous[a.LectureNumber] = a
+	}
+	status := func(present bool) string {
+		if present {
+			return "present"
+		}
+		return "absent"
+	}
+	for _, a := range new {
+		before, ok := previous[a.LectureNumber]
+		switch {
+		case !ok:
+			changes = append(changes, checkChange{code, CHECK_ATTENDANCE, fmt.Sprintf("lecture %d (%s) marked %s", a.LectureNumber, a.LectureDate, status(a.Attendance))})
+		case before.Attendance != a.Attendance:
+			changes = append(changes, checkChange{code, CHECK_ATTENDANCE, fmt.Sprintf("lecture %d (%s) changed from %s to %s", a.LectureNumber, a.LectureDate, status(before.Attendance), status(a.Attendance))})
+		}
+	}
+	return changes
+}
+
+func diffAssessments(code string, old []SerializableAssessment, new []Assessment) []checkChange {
+	var changes []checkChange
+	previous := make(map[string]SerializableAssessment, len(old))
+	for _, a := range old {
+		previous[a.Name] = a
+	}
+	for _, a := range new {
+		before, ok := previous[a.name]
+		switch {
+		case !ok:
+			changes = append(changes, checkChange{code, CHECK_ASSESSMENTS, fmt.Sprintf("new: %s %.1f/%.1f", a.name, a.obtainedMarks, a.totalMarks)})
+		case before.ObtainedMarks != a.obtainedMarks || before.TotalMarks != a.totalMarks:
+			changes = append(changes, checkChange{code, CHECK_ASSESSMENTS, fmt.Sprintf("%s changed from %.1f/%.1f to %.1f/%.1f", a.name, before.ObtainedMarks, before.TotalMarks, a.obtainedMarks, a.totalMarks)})
+		}
+	}
+	return changes
+}
+
+// collectChecks refetches attendance and/or assessments for every course
+// and compares them with the cached snapshot. Courses the cache has no data
+// for are taken as the baseline rather than reported as changed, so the
+// first run is quiet. Fees and the CGPA are checked against the config.
+func collectChecks(s *Session, kinds map[string]bool) (checkResult, error) {
+	checkAttendance, checkAssessments := kinds[CHECK_ATTENDANCE], kinds[CHECK_ASSESSMENTS]
+	result := checkResult{Baseline: checkAttendance || checkAssessments, Changes: []checkChange{}, Breaches: []checkChange{}, Reminders: []checkChange{}}
+
+	courses, err := s.GetCourses()
+	if err != nil {
+		return result, err
+	}
+	// GetCourses has just rewritten the cache, but it carries the per-course
+	// records over from the previous snapshot, which is what we diff against.
+	previous, err := readDataCache()
+	if err != nil || previous.Student.ID != s.Student.ID {
+		previous = SerializableData{}
+	}
+	old := make(map[string]SerializableCourse, len(previous.Courses))
+	for _, c := range previous.Courses {
+		old[c.ID] = c
+	}
+
+	for _, c := range courses {
+		before := old[c.ID]
+		if checkAttendance && len(before.Attendance) > 0 || checkAssessments && len(before.Assessments) > 0 {
+			result.Baseline = false
+		}
+		if checkAttendance {
+			if err := s.GetCourseAttendance(true, c.ID); err != nil {
+				return result, err
+			}
+			course := s.Student.Courses[getCourseIndex(s, c.ID)]
+			if len(before.Attendance) > 0 {
+				result.Changes = append(result.Changes, diffAttendance(c.Code, before.Attendance, course.Attendance)...)
+			}
+			if appConfig.belowAttendanceThreshold(course) {
+				result.Breaches = append(result.Breaches, checkChange{c.Code, CHECK_ATTENDANCE, fmt.Sprintf("attendance %.1f%% is below %.0f%%", course.AttendancePercentage, appConfig.attendanceThreshold(c.Code))})
+			}
+		}
+		if checkAssessments {
+			if err := s.GetCourseAssessments(c.ID); err != nil {
+				return result, err
+			}
+			course := s.Student.Courses[getCourseIndex(s, c.ID)]
+			if len(before.Assessments) > 0 {
+				result.Changes = append(result.Changes, diffAssessments(c.Code, before.Assessments, course.Assessment)...)
+			}
+		}
+	}
+
+	if kinds[CHECK_FEES] {
+		challans, err := s.GetFees()
+		if err != nil {
+			return result, err
+		}
+		installments := splitInstallments(outstandingDues(challans), appConfig.installmentDates(), time.Now())
+		result.Reminders = append(result.Reminders, installmentReminders(installments, appConfig.installmentReminderDays(), time.Now())...)
+	}
+
+	if kinds[CHECK_CGPA] {
+		if err := s.GetTranscript(true); err != nil {
+			return result, err
+		}
+		// Before any grade is posted the results page may have no table.
+		provisional, _ := s.GetResults()
+		if cgpa, below := appConfig.belowCGPAFloor(s.Student.Transcript, provisional); below {
+			result.Breaches = append(result.Breaches, checkChange{Kind: CHECK_CGPA, Detail: fmt.Sprintf("CGPA %.2f is below the floor of %.2f", cgpa, appConfig.CGPAFloor)})
+		}
+	}
+	return result, nil
+}
+
+// runCheck prints what collectChecks found, exiting non-zero when there is
+// anything to act on.
+func runCheck(s *Session, args []string) (Output, error) {
+	kinds, err := checkKinds(args)
+	if err != nil {
+		return Output{}, err
+	}
+	result, err := collectChecks(s, kinds)
+	if err != nil {
+		return Output{}, err
+	}
+
+	out := Output{Header: []string{"course", "kind", "change"}, Value: result}
+	for _, c := range slices.Concat(result.Breaches, result.Reminders, result.Changes) {
+		out.Rows = append(out.Rows, []string{c.Course, c.Kind, c.Detail})
+	}
+
+	var summary []string
+	if len(result.Changes) > 0 {
+		summary = append(summary, fmt.Sprintf("%d change(s)", len(result.Changes)))
+	}
+	lowAttendance := 0
+	for _, b := range result.Breaches {
+		if b.Kind == CHECK_ATTENDANCE {
+			lowAttendance++
+		}
+	}
+	if lowAttendance > 0 {
+		summary = append(summary, fmt.Sprintf("%d course(s) below the attendance threshold", lowAttendance))
+	}
+	if lowAttendance < len(result.Breaches) {
+		summary = append(summary, "CGPA below the floor")
+	}
+	if len(result.Reminders) > 0 {
+		summary = append(summary, fmt.Sprintf("%d fee installment(s) due", len(result.Reminders)))
+	}
+	switch {
+	case len(result.Breaches) > 0:
+		return out, withExitCode(EXIT_BELOW_THRESHOLD, errors.New(strings.Join(summary, ", ")))
+	case len(result.Reminders) > 0:
+		return out, withExitCode(EXIT_PAYMENT_DUE, errors.New(strings.Join(summary, ", ")))
+	case len(result.Changes) > 0:
+		return out, withExitCode(EXIT_CHANGED, errors.New(strings.Join(summary, ", ")))
+	case result.Baseline:
+		out.Notes = []string{"No previous data; saved a baseline for the next check."}
+	default:
+		out.Notes = []string{"No changes."}
+	}
+	return out, nil
+}
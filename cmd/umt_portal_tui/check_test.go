@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestCheckReportsChanges(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	useMockPortal(t, portal)
+	withTerminal(t, false, "")
+	t.Setenv("UMT_STUDENT_ID", "F2023000000")
+	t.Setenv("UMT_PASSWORD", "hunter2")
+
+	c, _ := findCommand("check")
+	var out bytes.Buffer
+	if err := runCommand(c, nil, strings.NewReader(""), &out); err != nil {
+		t.Fatalf("first check should only save a baseline: %v\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "baseline") {
+		t.Errorf("expected baseline note, got:\n%s", out.String())
+	}
+
+	// Pretend the last lecture and a mark change happened since the last run.
+	data, err := readDataCache()
+	if err != nil {
+		t.Fatal(err)
+	}
+	course := &data.Courses[0]
+	if len(course.Attendance) == 0 || len(course.Assessments) == 0 {
+		t.Fatalf("cache is missing per-course data: %+v", course)
+	}
+	lecture := course.Attendance[len(course.Attendance)-1]
+	course.Attendance = course.Attendance[:len(course.Attendance)-1]
+	course.Assessments[0].ObtainedMarks--
+	raw, _ := json.Marshal(data)
+	path, _ := dataCachePath()
+	if err := os.WriteFile(path, raw, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	out.Reset()
+	err = runCommand(c, []string{"--format", "json"}, strings.NewReader(""), &out)
+	if exitCode(err) != EXIT_CHANGED {
+		t.Fatalf("expected exit code %d, got %d (%v)", EXIT_CHANGED, exitCode(err), err)
+	}
+	var result checkResult
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+	}
+	if len(result.Changes) != 2 || result.Changes[0].Kind != CHECK_ATTENDANCE || result.Changes[1].Kind != CHECK_ASSESSMENTS {
+		t.Fatalf("unexpected changes: %+v", result.Changes)
+	}
+	if result.Changes[0].Course != course.Code || !strings.Contains(result.Changes[0].Detail, lecture.LectureDate) {
+		t.Errorf("unexpected attendance change: %+v", result.Changes[0])
+	}
+
+	out.Reset()
+	if err := runCommand(c, []string{"attendance"}, strings.NewReader(""), &out); err != nil {
+		t.Fatalf("expected no changes on rerun: %v\n%s", err, out.String())
+	}
+	if _, err := checkKinds([]string{"grades"}); exitCode(err) != EXIT_USAGE {
+		t.Errorf("expected usage error for unknown check, got %v", err)
+	}
+}
+
+func TestCheckAttendanceThresholds(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	useMockPortal(t, portal)
+	withTerminal(t, false, "")
+	t.Setenv("UMT_STUDENT_ID", "F2023000000")
+	t.Setenv("UMT_PASSWORD", "hunter2")
+
+	// Every fixture course is at 87.5%.
+	appConfig.AttendanceThreshold = 75
+	appConfig.CourseThresholds = map[string]float64{"cc2042": 90}
+	if got := appConfig.attendanceThreshold("CC2042"); got != 90 {
+		t.Errorf("override threshold = %v, want 90", got)
+	}
+	if got := appConfig.attendanceThreshold("CS3051"); got != 75 {
+		t.Errorf("global threshold = %v, want 75", got)
+	}
+	if got := (Config{}).attendanceThreshold("CS3051"); got != DEFAULT_ATTENDANCE_THRESHOLD {
+		t.Errorf("default threshold = %v, want %v", got, DEFAULT_ATTENDANCE_THRESHOLD)
+	}
+
+	c, _ := findCommand("check")
+	var out bytes.Buffer
+	err := runCommand(c, []string{"--format", "json", "attendance"}, strings.NewReader(""), &out)
+	if exitCode(err) != EXIT_BELOW_THRESHOLD {
+		t.Fatalf("expected exit code %d, got %d (%v)", EXIT_BELOW_THRESHOLD, exitCode(err), err)
+	}
+	var result checkResult
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+	}
+	if len(result.Breaches) != 1 || result.Breaches[0].Course != "CC2042" {
+		t.Errorf("unexpected breaches: %+v", result.Breaches)
+	}
+
+	if err := (Config{CourseThresholds: map[string]float64{"CC2042": 120}}).validateThresholds(); err == nil {
+		t.Error("expected out-of-range threshold to be rejected")
+	}
+}
+
+func TestCheckFailingAttendance(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	useMockPortal(t, portal)
+	withTerminal(t, false, "")
+	t.Setenv("UMT_STUDENT_ID", "F2023000000")
+	t.Setenv("UMT_PASSWORD", "hunter2")
+
+	c, _ := findCommand("check")
+	if err := runCommand(c, nil, strings.NewReader(""), io.Discard); err != nil {
+		t.Fatal(err)
+	}
+
+	// Attendance the portal fails to send is not attendance that vanished.
+	portal.Fail("GET /Attendance/ViewAttendance")
+	err := runCommand(c, []string{"attendance"}, strings.NewReader(""), io.Discard)
+	if got := exitCode(err); got == EXIT_OK || got == EXIT_CHANGED {
+		t.Fatalf("exit code %d (%v), want a failure other than %d", got, err, EXIT_CHANGED)
+	}
+}
+
+func TestCheckEmailKeepsExitCode(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	useMockPortal(t, portal)
+	withTerminal(t, false, "")
+	t.Setenv("UMT_STUDENT_ID", "F2023000000")
+	t.Setenv("UMT_PASSWORD", "hunter2")
+
+	// Every fixture course is at 87.5%, and no SMTP server is configured.
+	appConfig.AttendanceThreshold = 90
+	c, _ := findCommand("check")
+	err := runCommand(c, []string{"--email", "attendance"}, strings.NewReader(""), io.Discard)
+	if got := exitCode(err); got != EXIT_BELOW_THRESHOLD {
+		t.Fatalf("exit code %d (%v), want %d", got, err, EXIT_BELOW_THRESHOLD)
+	}
+	if !errors.Is(err, errSMTPNotConfigured) {
+		t.Errorf("email failure not reported: %v", err)
+	}
+}
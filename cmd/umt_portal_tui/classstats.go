@@ -0,0 +1,75 @@
+package main
+
+import (
+	"math"
+	"strings"
+)
+
+// CLASS_BAR_WIDTH is the width of the bar comparing a mark with the class.
+const CLASS_BAR_WIDTH = 10
+
+func hasClassStats(assessments []Assessment) bool {
+	for _, a := range assessments {
+		if a.classAverage > 0 || a.classHighest > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// classBar draws the marks from 0 to the total: filled up to the student's
+// mark, with ┃ at the class average and ◆ at the highest mark.
+func classBar(a Assessment, width int) string {
+	if a.totalMarks <= 0 || width < 2 {
+		return strings.Repeat(" ", max(width, 0))
+	}
+	at := func(marks float32) int {
+		return int(math.Round(float64(min(max(marks/a.totalMarks, 0), 1)) * float64(width-1)))
+	}
+
+	bar := []rune(strings.Repeat("─", width))
+	for i := range at(a.obtainedMarks) + 1 {
+		bar[i] = '━'
+	}
+	if a.classAverage > 0 {
+		bar[at(a.classAverage)] = '┃'
+	}
+	if a.classHighest > 0 {
+		bar[at(a.classHighest)] = '◆'
+	}
+	return string(bar)
+}
+
+// classComparison describes the student's mark against the class: how far
+// above or below the average it is, and the highest mark.
+func classComparison(a Assessment) string {
+	var parts []string
+	if a.classAverage > 0 {
+		parts = append(parts, T("assessment.vs_average", a.obtainedMarks-a.classAverage))
+	}
+	if a.classHighest > 0 {
+		parts = append(parts, T("assessment.highest", a.classHighest))
+	}
+	return strings.Join(parts, " ")
+}
+
+// classGrading fills in the class average of a relative grading scheme
+// from the class averages the portal shows, when every weighted assessment
+// has one.
+func classGrading(g CourseGrading, assessments []Assessment) CourseGrading {
+	if g.Scheme != SCHEME_RELATIVE || g.ClassAverage > 0 || len(assessments) == 0 {
+		return g
+	}
+	class := make([]Assessment, len(assessments))
+	for i, a := range assessments {
+		if a.classAverage <= 0 {
+			return g
+		}
+		a.obtainedMarks = a.classAverage
+		class[i] = a
+	}
+	if p, ok := g.project(class); ok {
+		g.ClassAverage = p.Percentage()
+	}
+	return g
+}
@@ -0,0 +1,528 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+var (
+	errNoCredentials = errors.New("no credentials available")
+	// errUsage is returned after the flag package has already reported a bad
+	// command line.
+	errUsage = errors.New("invalid usage")
+)
+
+// cliOptions are the flags shared by the non-interactive subcommands.
+type cliOptions struct {
+	studentID     string
+	passwordStdin bool
+	remember      bool
+	email         bool
+	format        formatFlag
+}
+
+type command struct {
+	name    string
+	args    string
+	summary string
+	run     func(s *Session, args []string) (Output, error)
+	// offline commands run on cached data and never log in.
+	offline bool
+	// local commands neither log in nor need cached data.
+	local bool
+	// flags registers flags specific to the command.
+	flags func(fs *flag.FlagSet)
+}
+
+var commands = []command{
+	{name: "profile", summary: "show student profile and credit hours", run: runProfile},
+	{name: "courses", summary: "list enrolled courses", run: runCourses},
+	{name: "attendance", args: "<course>", summary: "show lecture-by-lecture attendance for a course", run: runAttendance},
+	{name: "assessments", args: "<course>", summary: "show assessment marks for a course", run: runAssessments},
+	{name: "transcript", summary: "show the full transcript, or download the official PDF", run: runTranscript, flags: transcriptFlags},
+	{name: "results", summary: "show the provisional result of the current semester", run: runResults},
+	{name: "fees", summary: "show fee challans and payment history", run: runFees, flags: feesFlags},
+	{name: "check", args: "[attendance] [assessments] [fees] [cgpa]", summary: "refetch data, print what changed since the last run and exit non-zero on changes, low attendance or CGPA, or fee installments due", run: runCheck},
+	{name: "report", args: "[file]", summary: "write cached data to a self-contained HTML report (default " + DEFAULT_REPORT_FILE + ")", run: runReport, offline: true},
+	{name: "dashboard", summary: "fetch everything and write a static HTML/JS dashboard of GPA, attendance and upcoming LMS deadlines to a directory (default " + DEFAULT_DASHBOARD_DIR + ")", run: runDashboard, flags: dashboardFlags},
+	{name: "export", summary: "bundle cached courses, attendance, assessments and the transcript into a zip archive (credentials are never included)", run: runExport, local: true, flags: exportFlags},
+	{name: "import", args: "<archive>", summary: "restore cached data from an archive written by export", run: runImport, local: true},
+	{name: "doctor", summary: "check DNS and TLS reachability of the portal, local directories, saved credentials and login time", run: runDoctor, local: true},
+	{name: "diagnose", summary: "fetch each kind of portal page once and report whether it parsed; --bundle zips the anonymized results for an issue", run: runDiagnose, flags: diagnoseFlags},
+	{name: "daemon", args: "[install]", summary: "stay running and send a digest of changes, alerts and dues every day at the configured time; --once sends one now, install sets it up as a user service (systemd, launchd or a scheduled task)", run: runDaemon, local: true, flags: daemonFlags},
+	{name: "schema", summary: "print the JSON Schema of the JSON that profile, courses, attendance, assessments and transcript print and export archives hold", run: runSchema, local: true},
+}
+
+func findCommand(name string) (command, bool) {
+	for _, c := range commands {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return command{}, false
+}
+
+func (c command) flagSet(opts *cliOptions) *flag.FlagSet {
+	fs := flag.NewFlagSet(c.name, flag.ContinueOnError)
+	fs.StringVar(&opts.studentID, "student-id", "", "student ID (default $UMT_STUDENT_ID)")
+	fs.BoolVar(&opts.passwordStdin, "password-stdin", false, "read the password from the first line of stdin (default $UMT_PASSWORD)")
+	opts.format = FORMAT_TABLE
+	fs.Var(&opts.format, "format", "output format: "+strings.Join(outputFormats, ", "))
+	fs.BoolVar(&opts.remember, "remember", false, "save prompted credentials for later runs, like the TUI's \"Remember me\"")
+	fs.BoolVar(&opts.email, "email", false, "also email the output to the recipients in the smtp config")
+	if c.flags != nil {
+		c.flags(fs)
+	}
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s %s [flags] %s\n\n%s.\n\nFlags:\n", commandName(), c.name, c.args, c.summary)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// resolveCredentials picks the credentials for a CLI run. Flags and the
+// UMT_STUDENT_ID/UMT_PASSWORD environment variables take precedence and, when
+// any of them is given, the stored credentials are neither read nor written.
+// Anything still missing is prompted for when stdin is a terminal; prompted
+// reports whether that happened.
+func resolveCredentials(opts cliOptions, stdin io.Reader, prompt io.Writer) (creds Credentials, prompted bool, err error) {
+	creds = Credentials{
+		StudentID: opts.studentID,
+		Password:  os.Getenv("UMT_PASSWORD"),
+	}
+	if creds.StudentID == "" {
+		creds.StudentID = os.Getenv("UMT_STUDENT_ID")
+	}
+	if opts.passwordStdin {
+		line, err := bufio.NewReader(stdin).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return creds, false, fmt.Errorf("failed to read password from stdin: %w", err)
+		}
+		creds.Password = strings.TrimRight(line, "\r\n")
+	}
+
+	explicit := creds.StudentID != "" || creds.Password != ""
+	if !explicit {
+		stored, err := LoadCreds()
+		if err == nil && stored.StudentID != "" && stored.Password != "" {
+			return stored, false, nil
+		}
+	}
+
+	if (creds.StudentID == "" || creds.Password == "") && !opts.passwordStdin && stdinIsTerminal() {
+		creds, err = promptCredentials(creds, stdin, prompt)
+		return creds, true, err
+	}
+
+	switch {
+	case explicit && creds.StudentID == "":
+		return creds, false, fmt.Errorf("%w: password given without --student-id or UMT_STUDENT_ID", errNoCredentials)
+	case explicit && creds.Password == "":
+		return creds, false, fmt.Errorf("%w: student ID given without --password-stdin or UMT_PASSWORD", errNoCredentials)
+	case !explicit:
+		return creds, false, fmt.Errorf("%w: set UMT_STUDENT_ID and UMT_PASSWORD, pass --student-id with --password-stdin, or run from a terminal to be prompted", errNoCredentials)
+	}
+	return creds, false, nil
+}
+
+var (
+	stdinIsTerminal = func() bool { return term.IsTerminal(int(os.Stdin.Fd())) }
+	readPassword    = func() ([]byte, error) { return term.ReadPassword(int(os.Stdin.Fd())) }
+)
+
+// promptCredentials asks for whichever of the student ID and password is
+// missing. The password is read without echo.
+func promptCredentials(creds Credentials, stdin io.Reader, prompt io.Writer) (Credentials, error) {
+	if creds.StudentID == "" {
+		fmt.Fprint(prompt, "Student ID: ")
+		line, err := bufio.NewReader(stdin).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return creds, fmt.Errorf("failed to read student ID: %w", err)
+		}
+		creds.StudentID = strings.TrimSpace(line)
+	}
+	if creds.Password == "" {
+		fmt.Fprint(prompt, "Password: ")
+		password, err := readPassword()
+		fmt.Fprintln(prompt)
+		if err != nil {
+			return creds, fmt.Errorf("failed to read password: %w", err)
+		}
+		creds.Password = string(password)
+	}
+	if creds.StudentID == "" || creds.Password == "" {
+		return creds, fmt.Errorf("%w: student ID and password are required", errNoCredentials)
+	}
+	return creds, nil
+}
+
+func cliLogin(creds Credentials, stdin io.Reader, prompt io.Writer) (*Session, error) {
+	session := NewSession()
+	code, text := session.Login(creds, false)
+	if code == ErrCaptchaRequired {
+		if !stdinIsTerminal() {
+			return nil, errors.New("the portal is asking for a security code (captcha); run from a terminal to enter it")
+		}
+		securityCode, err := promptCaptcha(session, stdin, prompt)
+		if err != nil {
+			return nil, err
+		}
+		code, text = session.LoginWithCaptcha(creds, securityCode, false)
+	}
+	switch code {
+	case ErrNone:
+		return session, nil
+	case ErrInvalidCredentials:
+		return nil, withExitCode(EXIT_INVALID_CREDENTIALS, errors.New("invalid credentials"))
+	case ErrLockedOut:
+		return nil, withExitCode(EXIT_INVALID_CREDENTIALS, fmt.Errorf("too many failed logins for %s; saved password removed, try again in %s", creds.StudentID, text))
+	case ErrNetworkIssue:
+		return nil, withExitCode(EXIT_NETWORK_FAILURE, fmt.Errorf("network issue: %s", text))
+	default:
+		return nil, withExitCode(EXIT_PARSE_FAILURE, fmt.Errorf("failed to parse portal response: %s", text))
+	}
+}
+
+// promptCaptcha draws the login captcha as text and reads the code.
+func promptCaptcha(session *Session, stdin io.Reader, prompt io.Writer) (string, error) {
+	if img := session.Captcha(); img != nil {
+		fmt.Fprintln(prompt, renderCaptcha(img, CAPTCHA_WIDTH, false))
+	}
+	fmt.Fprint(prompt, "Security code: ")
+	line, err := bufio.NewReader(stdin).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("failed to read security code: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// cachedSession restores a session from the data and transcript caches.
+func cachedSession() (*Session, error) {
+	if cacheMode != CACHE_NORMAL {
+		return nil, fmt.Errorf("this command only reads cached data: %w", errCacheSkipped)
+	}
+	s := NewSession()
+	dataErr := loadDataCache(s)
+	transcriptErr := loadTranscriptCache(s)
+	if dataErr != nil && transcriptErr != nil {
+		return nil, fmt.Errorf("no cached data; run the TUI or a data command such as '%s courses' first: %w", commandName(), dataErr)
+	}
+	return s, nil
+}
+
+// runCommand parses the subcommand's flags, logs in and runs it.
+func runCommand(c command, args []string, stdin io.Reader, out io.Writer) error {
+	var opts cliOptions
+	fs := c.flagSet(&opts)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+
+	var session *Session
+	switch {
+	case c.local:
+		session = NewSession()
+	case c.offline:
+		var err error
+		if session, err = cachedSession(); err != nil {
+			return err
+		}
+	default:
+		creds, prompted, err := resolveCredentials(opts, stdin, os.Stderr)
+		if err != nil {
+			return err
+		}
+		if session, err = cliLogin(creds, stdin, os.Stderr); err != nil {
+			return err
+		}
+		if prompted && opts.remember {
+			if err := SaveCreds(creds); err != nil {
+				fmt.Fprintln(os.Stderr, "warning: failed to save credentials:", err)
+			}
+		}
+	}
+	// A command can fail with output, as check does to signal changes
+	// through its exit code; that output is still written.
+	output, runErr := c.run(session, fs.Args())
+	if runErr != nil && output.Header == nil {
+		return runErr
+	}
+	if err := writeOutput(out, string(opts.format), output); err != nil {
+		return err
+	}
+	if opts.email && len(output.Rows) > 0 {
+		// A failed email is reported without hiding the command's own exit
+		// code, such as check's for a change.
+		if err := emailOutput(c, session, output); err != nil {
+			return errors.Join(runErr, err)
+		}
+	}
+	return runErr
+}
+
+// emailOutput mails the table rendering of output, with its HTML rendering
+// as an alternative when the command provides one. Output without rows, such
+// as a check that found nothing, is not mailed.
+func emailOutput(c command, s *Session, output Output) error {
+	var text bytes.Buffer
+	if err := writeOutput(&text, FORMAT_TABLE, output); err != nil {
+		return err
+	}
+	subject := "UMT Portal " + c.name
+	if s.Student.Name != "" {
+		subject += " for " + s.Student.Name
+	}
+	if err := sendMail(appConfig.SMTP, mailMessage{Subject: subject, Text: text.String(), HTML: output.HTML}); err != nil {
+		return fmt.Errorf("failed to email %s: %w", c.name, err)
+	}
+	return nil
+}
+
+// findCourse matches a course by code, ID or a case-insensitive title prefix.
+func findCourse(courses []Course, query string) (Course, error) {
+	q := strings.ToLower(strings.TrimSpace(query))
+	for _, c := range courses {
+		if strings.ToLower(c.Code) == q || c.ID == query {
+			return c, nil
+		}
+	}
+	for _, c := range courses {
+		if q != "" && strings.HasPrefix(strings.ToLower(c.Title), q) {
+			return c, nil
+		}
+	}
+	return Course{}, fmt.Errorf("no enrolled course matches %q", query)
+}
+
+func courseArg(s *Session, args []string) (Course, error) {
+	if len(args) != 1 {
+		return Course{}, withExitCode(EXIT_USAGE, errors.New("expected exactly one course code, ID or title"))
+	}
+	courses, err := s.GetCourses()
+	if err != nil {
+		return Course{}, err
+	}
+	return findCourse(courses, args[0])
+}
+
+type profileRecord struct {
+	SchemaVersion         int    `json:"schema_version"`
+	Name                  string `json:"name"`
+	ID                    string `json:"id"`
+	Program               string `json:"program"`
+	Email                 string `json:"email"`
+	CGPA                  string `json:"cgpa"`
+	RequestedCreditHours  string `json:"requested_credit_hours"`
+	MaxAllowedCreditHours string `json:"max_allowed_credit_hours"`
+	CompletedCreditHours  string `json:"completed_credit_hours"`
+	RequiredCreditHours   string `json:"required_credit_hours"`
+}
+
+type courseRecord struct {
+	ID           string `json:"id"`
+	Code         string `json:"code"`
+	Title        string `json:"title"`
+	CreditHours  string `json:"credit_hours"`
+	Type         string `json:"type"`
+	Section      string `json:"section"`
+	Mode         string `json:"mode"`
+	Semester     string `json:"semester"`
+	FacultyName  string `json:"faculty_name"`
+	FacultyEmail string `json:"faculty_email"`
+}
+
+type attendanceRecord struct {
+	Lecture int    `json:"lecture"`
+	Date    string `json:"date"`
+	Present bool   `json:"present"`
+	Faculty string `json:"faculty"`
+}
+
+type courseAttendanceRecord struct {
+	SchemaVersion        int                `json:"schema_version"`
+	Course               string             `json:"course"`
+	TotalLectures        int                `json:"total_lectures"`
+	AttendancePercentage float64            `json:"attendance_percentage"`
+	Threshold            float64            `json:"threshold"`
+	BelowThreshold       bool               `json:"below_threshold"`
+	Lectures             []attendanceRecord `json:"lectures"`
+}
+
+type assessmentRecord struct {
+	Name     string  `json:"name"`
+	Obtained float32 `json:"obtained"`
+	Total    float32 `json:"total"`
+	Date     string  `json:"date"`
+}
+
+type courseAssessmentsRecord struct {
+	SchemaVersion int                `json:"schema_version"`
+	Course        string             `json:"course"`
+	Assessments   []assessmentRecord `json:"assessments"`
+}
+
+type courseListRecord struct {
+	SchemaVersion int            `json:"schema_version"`
+	Courses       []courseRecord `json:"courses"`
+}
+
+func toCourseRecord(c Course) courseRecord {
+	return courseRecord{
+		ID:           c.ID,
+		Code:         c.Code,
+		Title:        c.Title,
+		CreditHours:  c.CreditHours,
+		Type:         c.CourseType,
+		Section:      c.Section,
+		Mode:         c.Mode,
+		Semester:     c.Semester,
+		FacultyName:  c.FacultyName,
+		FacultyEmail: c.FacultyEmail,
+	}
+}
+
+func runProfile(s *Session, args []string) (Output, error) {
+	st := s.GetStudent()
+	p := profileRecord{
+		SchemaVersion:         SCHEMA_VERSION,
+		Name:                  st.Name,
+		ID:                    st.ID,
+		Program:               st.Program,
+		Email:                 st.Email,
+		CGPA:                  st.CgpaEarned,
+		RequestedCreditHours:  st.RequestedCreditHours,
+		MaxAllowedCreditHours: st.MaxAllowedCreditHours,
+		CompletedCreditHours:  st.CompletedCreditHours,
+		RequiredCreditHours:   st.RequiredCreditHours,
+	}
+	return Output{
+		Header: []string{"name", "id", "program", "email", "cgpa", "requested_credit_hours", "max_allowed_credit_hours", "completed_credit_hours", "required_credit_hours"},
+		Rows:   [][]string{{p.Name, p.ID, p.Program, p.Email, p.CGPA, p.RequestedCreditHours, p.MaxAllowedCreditHours, p.CompletedCreditHours, p.RequiredCreditHours}},
+		Value:  p,
+		Record: true,
+	}, nil
+}
+
+func runCourses(s *Session, args []string) (Output, error) {
+	courses, err := s.GetCourses()
+	if err != nil {
+		return Output{}, err
+	}
+
+	out := Output{Header: []string{"code", "title", "credit_hours", "section", "faculty"}}
+	records := []courseRecord{}
+	for _, c := range courses {
+		out.Rows = append(out.Rows, []string{c.Code, c.Title, c.CreditHours, c.Section, c.FacultyName})
+		records = append(records, toCourseRecord(c))
+	}
+	out.Value = courseListRecord{SCHEMA_VERSION, records}
+	return out, nil
+}
+
+func runAttendance(s *Session, args []string) (Output, error) {
+	course, err := courseArg(s, args)
+	if err != nil {
+		return Output{}, err
+	}
+	if err := s.GetCourseAttendance(true, course.ID); err != nil {
+		return Output{}, err
+	}
+	course = s.Student.Courses[getCourseIndex(s, course.ID)]
+
+	out := Output{Header: []string{"lecture", "date", "status", "faculty"}}
+	record := courseAttendanceRecord{
+		SchemaVersion:        SCHEMA_VERSION,
+		Course:               course.Code,
+		TotalLectures:        course.TotalLectures,
+		AttendancePercentage: course.AttendancePercentage,
+		Threshold:            appConfig.attendanceThreshold(course.Code),
+		BelowThreshold:       appConfig.belowAttendanceThreshold(course),
+		Lectures:             []attendanceRecord{},
+	}
+	for _, a := range course.Attendance {
+		status := "Absent"
+		if a.Attendance {
+			status = "Present"
+		}
+		out.Rows = append(out.Rows, []string{strconv.Itoa(a.LectureNumber), a.LectureDate, status, a.Faculty})
+		record.Lectures = append(record.Lectures, attendanceRecord{a.LectureNumber, a.LectureDate, a.Attendance, a.Faculty})
+	}
+	out.Value = record
+	out.Notes = []string{fmt.Sprintf("%s: %d lectures, %.1f%% attendance", course.Code, course.TotalLectures, course.AttendancePercentage)}
+	if record.BelowThreshold {
+		out.Notes = append(out.Notes, fmt.Sprintf("Warning: below the %.0f%% attendance threshold", record.Threshold))
+	}
+	return out, nil
+}
+
+func runAssessments(s *Session, args []string) (Output, error) {
+	course, err := courseArg(s, args)
+	if err != nil {
+		return Output{}, err
+	}
+	if err := s.GetCourseAssessments(course.ID); err != nil {
+		return Output{}, err
+	}
+	course = s.Student.Courses[getCourseIndex(s, course.ID)]
+
+	out := Output{Header: []string{"name", "obtained", "total", "date"}}
+	record := courseAssessmentsRecord{SchemaVersion: SCHEMA_VERSION, Course: course.Code, Assessments: []assessmentRecord{}}
+	for _, a := range course.Assessment {
+		out.Rows = append(out.Rows, []string{a.name, fmt.Sprintf("%.1f", a.obtainedMarks), fmt.Sprintf("%.1f", a.totalMarks), a.assignedDate})
+		record.Assessments = append(record.Assessments, assessmentRecord{a.name, a.obtainedMarks, a.totalMarks, a.assignedDate})
+	}
+	out.Value = record
+	return out, nil
+}
+
+// transcriptPDF and transcriptAsText are bound to transcript's --pdf and
+// --text flags.
+var transcriptPDF, transcriptAsText bool
+
+func transcriptFlags(fs *flag.FlagSet) {
+	fs.BoolVar(&transcriptPDF, "pdf", false, "download the official transcript PDF into the download directory")
+	fs.BoolVar(&transcriptAsText, "text", false, "print the transcript as aligned plain text for printing or email")
+}
+
+func runTranscript(s *Session, args []string) (Output, error) {
+	if transcriptPDF {
+		path, err := s.DownloadTranscriptPDF(downloadDir())
+		if err != nil {
+			return Output{}, err
+		}
+		return Output{Header: []string{"path"}, Rows: [][]string{{path}}, Value: map[string]string{"path": path}, Record: true}, nil
+	}
+	if err := s.GetTranscript(false); err != nil {
+		return Output{}, err
+	}
+	t := s.Student.Transcript
+
+	out := Output{
+		Header: []string{"semester", "code", "title", "credit_hours", "grade", "grade_point"},
+		Value:  t.ToSerializable(),
+	}
+	for _, sk := range parseAndSortSemesters(t.Semester) {
+		sem := sk.semester
+		for _, c := range t.Semester[sem] {
+			out.Rows = append(out.Rows, []string{sem.Name, c.Code, c.Title, strconv.Itoa(c.CreditHours), c.Grade, fmt.Sprintf("%.2f", c.GradePoint)})
+		}
+		out.Notes = append(out.Notes, fmt.Sprintf("%s: SGPA %.2f, CGPA %.2f, %d credit hours", sem.Name, sem.SGPA, sem.CGPA, sem.CreditHoursEarned))
+	}
+	out.Notes = append(out.Notes, fmt.Sprintf("Total: CGPA %s, %s credit hours earned", t.TotalCGPA, t.CreditHoursEarned))
+	if transcriptAsText {
+		out.Text = transcriptText(s.Student, t)
+	}
+	return out, nil
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestCLICredentialsFromEnvironment(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	useMockPortal(t, portal)
+	withTerminal(t, false, "")
+	if err := SaveCreds(Credentials{StudentID: "F2023999999", Password: "stale"}); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("UMT_STUDENT_ID", "F2023000000")
+	t.Setenv("UMT_PASSWORD", "hunter2")
+
+	c, _ := findCommand("courses")
+	var out bytes.Buffer
+	if err := runCommand(c, nil, strings.NewReader(""), &out); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(out.String(), "\n"); lines != 4 {
+		t.Errorf("got %d lines, want header and 3 courses:\n%s", lines, out.String())
+	}
+
+	stored, err := LoadCreds()
+	if err != nil || stored.StudentID != "F2023999999" {
+		t.Errorf("stored credentials were modified: %+v, %v", stored, err)
+	}
+}
+
+// withTerminal makes stdin look like a terminal (or not) and feeds
+// password prompts from password.
+func withTerminal(t *testing.T, isTerminal bool, password string) {
+	t.Helper()
+	prevIsTerminal, prevReadPassword := stdinIsTerminal, readPassword
+	t.Cleanup(func() { stdinIsTerminal, readPassword = prevIsTerminal, prevReadPassword })
+
+	stdinIsTerminal = func() bool { return isTerminal }
+	readPassword = func() ([]byte, error) { return []byte(password), nil }
+}
+
+func TestCLIPasswordFromStdin(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	useMockPortal(t, portal)
+	withTerminal(t, false, "")
+	t.Setenv("UMT_STUDENT_ID", "")
+	t.Setenv("UMT_PASSWORD", "")
+
+	c, _ := findCommand("profile")
+	var out bytes.Buffer
+	err := runCommand(c, []string{"--student-id", "F2023000000", "--password-stdin"}, strings.NewReader("hunter2\r\n"), &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "TEST STUDENT") {
+		t.Errorf("profile output missing student name:\n%s", out.String())
+	}
+
+	err = runCommand(c, []string{"--student-id", "F2023000000"}, strings.NewReader(""), &out)
+	if !errors.Is(err, errNoCredentials) {
+		t.Errorf("got %v, want errNoCredentials for a missing password", err)
+	}
+}
+
+func TestCLIPromptsForMissingCredentials(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	useMockPortal(t, portal)
+	withTerminal(t, true, "hunter2")
+	t.Setenv("UMT_STUDENT_ID", "")
+	t.Setenv("UMT_PASSWORD", "")
+
+	creds, prompted, err := resolveCredentials(cliOptions{}, strings.NewReader("F2023000000\n"), io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !prompted || creds.StudentID != "F2023000000" || creds.Password != "hunter2" {
+		t.Errorf("got %+v (prompted %v), want prompted credentials", creds, prompted)
+	}
+
+	c, _ := findCommand("profile")
+	var out bytes.Buffer
+	if err := runCommand(c, []string{"--remember"}, strings.NewReader("F2023000000\n"), &out); err != nil {
+		t.Fatal(err)
+	}
+	if stored, err := LoadCreds(); err != nil || stored != creds {
+		t.Errorf("--remember saved %+v, %v", stored, err)
+	}
+}
+
+func TestCLIExitCodes(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	useMockPortal(t, portal)
+	withTerminal(t, false, "")
+	c, _ := findCommand("courses")
+
+	t.Setenv("UMT_STUDENT_ID", "F2023000000")
+	t.Setenv("UMT_PASSWORD", "wrong")
+	err := runCommand(c, nil, strings.NewReader(""), io.Discard)
+	if got := exitCode(err); got != EXIT_INVALID_CREDENTIALS {
+		t.Errorf("invalid credentials: exit code %d (%v), want %d", got, err, EXIT_INVALID_CREDENTIALS)
+	}
+
+	portal.Close()
+	t.Setenv("UMT_PASSWORD", "hunter2")
+	err = runCommand(c, nil, strings.NewReader(""), io.Discard)
+	if got := exitCode(err); got != EXIT_NETWORK_FAILURE {
+		t.Errorf("portal down: exit code %d (%v), want %d", got, err, EXIT_NETWORK_FAILURE)
+	}
+
+	c, _ = findCommand("attendance")
+	err = runCommand(c, []string{"--bogus"}, strings.NewReader(""), io.Discard)
+	if got := exitCode(err); got != EXIT_USAGE {
+		t.Errorf("bad flag: exit code %d (%v), want %d", got, err, EXIT_USAGE)
+	}
+}
+
+func TestCLIAttendanceFailureExitCode(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	useMockPortal(t, portal)
+	withTerminal(t, false, "")
+	t.Setenv("UMT_STUDENT_ID", "F2023000000")
+	t.Setenv("UMT_PASSWORD", "hunter2")
+	portal.Fail("GET /Attendance/ViewAttendance")
+
+	c, _ := findCommand("attendance")
+	var out bytes.Buffer
+	err := runCommand(c, []string{"cc2042"}, strings.NewReader(""), &out)
+	if got := exitCode(err); got != EXIT_NETWORK_FAILURE {
+		t.Fatalf("exit code %d (%v), want %d", got, err, EXIT_NETWORK_FAILURE)
+	}
+	if out.Len() > 0 {
+		t.Errorf("printed attendance the portal never sent:\n%s", out.String())
+	}
+
+	if got := exitCode(retriesExhausted("course attendance", 3, errors.New("course not found"))); got != EXIT_FAILURE {
+		t.Errorf("exhausted retries of a non-network failure: exit code %d, want %d", got, EXIT_FAILURE)
+	}
+	if got := exitCode(retriesExhausted("course attendance", 3, errReportIncomplete)); got != EXIT_PARSE_FAILURE {
+		t.Errorf("exhausted retries of incomplete reports: exit code %d, want %d", got, EXIT_PARSE_FAILURE)
+	}
+}
+
+func TestCLIJSONOutput(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	useMockPortal(t, portal)
+	withTerminal(t, false, "")
+	t.Setenv("UMT_STUDENT_ID", "F2023000000")
+	t.Setenv("UMT_PASSWORD", "hunter2")
+
+	c, _ := findCommand("attendance")
+	var out bytes.Buffer
+	if err := runCommand(c, []string{"--format", "json", "cc2042"}, strings.NewReader(""), &out); err != nil {
+		t.Fatal(err)
+	}
+
+	var got courseAttendanceRecord
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+	}
+	if got.Course != "CC2042" || got.TotalLectures != 8 || len(got.Lectures) != 8 || got.AttendancePercentage != 87.5 {
+		t.Errorf("unexpected attendance JSON: %+v", got)
+	}
+}
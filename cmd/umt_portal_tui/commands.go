@@ -0,0 +1,185 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Everything that talks to the portal runs as one of the commands below.
+// They take what they need as arguments instead of closing over the model,
+// so Update only decides which command to run and the result always comes
+// back as a message; tests can run them directly and feed the result to
+// Update.
+
+func loginCmd(creds Credentials, remember, cached bool) tea.Cmd {
+	return recoverable(func() tea.Msg {
+		session := NewSession()
+		if cached {
+			loadTranscriptCache(session)
+		}
+		code, str := session.Login(creds, remember)
+		return LoginResultMsg{Code: code, Text: str, Session: session}
+	})
+}
+
+func captchaLoginCmd(session *Session, creds Credentials, code string, remember bool) tea.Cmd {
+	return func() tea.Msg {
+		code, str := session.LoginWithCaptcha(creds, code, remember)
+		return LoginResultMsg{Code: code, Text: str, Session: session}
+	}
+}
+
+func keepAliveCmd(session *Session, id int) tea.Cmd {
+	return func() tea.Msg {
+		return KeepAliveMsg{ID: id, Error: session.keepAlive()}
+	}
+}
+
+func renewSessionCmd(session *Session, creds Credentials, id int) tea.Cmd {
+	return func() tea.Msg {
+		code, _ := session.Login(creds, false)
+		return SessionRenewedMsg{ID: id, Code: code}
+	}
+}
+
+func coursesCmd(session *Session) tea.Cmd {
+	return recoverable(func() tea.Msg {
+		courses, err := session.GetCourses()
+		return CoursesLoadedMsg{Courses: courses, Error: err}
+	})
+}
+
+// prefetchCoursesCmd and prefetchTranscriptCmd run concurrently right after
+// login, so the dashboard is usually ready by the time it is opened. The
+// session's studentMu keeps their writes to Student apart. The portal's
+// timetable is not read yet, so there is none to prefetch.
+func prefetchCoursesCmd(session *Session) tea.Cmd {
+	return recoverable(func() tea.Msg {
+		courses, err := session.GetCourses()
+		return CoursesLoadedMsg{Courses: courses, Error: err, Prefetch: true}
+	})
+}
+
+func prefetchTranscriptCmd(session *Session) tea.Cmd {
+	return recoverable(func() tea.Msg {
+		return TranscriptPrefetchedMsg{Error: session.GetTranscript(false)}
+	})
+}
+
+func transcriptCmd(session *Session) tea.Cmd {
+	return recoverable(func() tea.Msg {
+		if err := session.GetTranscript(false); err != nil {
+			return CourseActionMsg{Action: "transcript", Error: err}
+		}
+		return CourseActionMsg{Action: "transcript", Success: true, UpdatedCourses: session.GetStudent().Courses}
+	})
+}
+
+// chatTranscriptCmd fetches the transcript for a chat question about it and
+// asks the question again once it is there.
+func chatTranscriptCmd(session *Session, msg NLPClassificationMsg) tea.Cmd {
+	return func() tea.Msg {
+		if err := session.GetTranscript(false); err != nil {
+			msg.Error = fmt.Errorf("failed to fetch transcript")
+			msg.Confidence = 0
+		}
+		return msg
+	}
+}
+
+// attendanceCmd and assessmentsCmd open the course's view even when the
+// portal kept sending incomplete reports, showing the records empty rather
+// than an error. Any other failure is reported.
+func attendanceCmd(session *Session, courseID string) tea.Cmd {
+	return recoverable(func() tea.Msg {
+		if err := session.GetCourseAttendance(false, courseID); err != nil && !errors.Is(err, errReportIncomplete) {
+			return CourseActionMsg{Action: "attendance", CourseID: courseID, Error: err}
+		}
+		return CourseActionMsg{Action: "attendance", CourseID: courseID, Success: true, UpdatedCourses: session.GetStudent().Courses}
+	})
+}
+
+func assessmentsCmd(session *Session, courseID string) tea.Cmd {
+	return recoverable(func() tea.Msg {
+		if err := session.GetCourseAssessments(courseID); err != nil && !errors.Is(err, errReportIncomplete) {
+			return CourseActionMsg{Action: "assessments", CourseID: courseID, Error: err}
+		}
+		return CourseActionMsg{Action: "assessments", CourseID: courseID, Success: true, UpdatedCourses: session.GetStudent().Courses}
+	})
+}
+
+// outlineCmd downloads a course outline and either extracts its text for
+// OutlineView or saves it to the download directory.
+func outlineCmd(session *Session, courseID string, save bool) tea.Cmd {
+	return recoverable(func() tea.Msg {
+		outline, err := session.GetCourseOutline(courseID)
+		if err != nil {
+			return OutlineLoadedMsg{CourseID: courseID, Error: err}
+		}
+		if save {
+			path, err := saveCourseOutline(outline, downloadDir())
+			return OutlineLoadedMsg{CourseID: courseID, Outline: outline, SavedPath: path, Error: err}
+		}
+		text, err := outline.Text()
+		return OutlineLoadedMsg{CourseID: courseID, Outline: outline, Text: text, Error: err}
+	})
+}
+
+func resultsCmd(session *Session) tea.Cmd {
+	return recoverable(func() tea.Msg {
+		result, err := session.GetResults()
+		return ResultsLoadedMsg{Result: result, Error: err}
+	})
+}
+
+func feesCmd(session *Session) tea.Cmd {
+	return recoverable(func() tea.Msg {
+		challans, err := session.GetFees()
+		return FeesLoadedMsg{Challans: challans, Error: err}
+	})
+}
+
+func challanCmd(session *Session, challan FeeChallan, dir string) tea.Cmd {
+	return func() tea.Msg {
+		path, err := session.DownloadChallan(challan, dir)
+		return ChallanSavedMsg{Path: path, Error: err}
+	}
+}
+
+func transcriptPDFCmd(session *Session, dir string) tea.Cmd {
+	return func() tea.Msg {
+		path, err := session.DownloadTranscriptPDF(dir)
+		return TranscriptPDFSavedMsg{Path: path, Error: err}
+	}
+}
+
+// warmAttendanceCmd opens a course's attendance report ahead of time. It
+// only saves time, so failures go unreported.
+func warmAttendanceCmd(session *Session, courseID string) tea.Cmd {
+	return func() tea.Msg {
+		return AttendanceWarmedMsg{CourseID: courseID, Error: session.WarmAttendance(courseID)}
+	}
+}
+
+func assessmentsHTMLCmd(course Course, dir string) tea.Cmd {
+	return func() tea.Msg {
+		path, err := saveAssessmentsHTML(course, classGrading(appConfig.courseGrading(course.Code), course.Assessment), dir)
+		return AssessmentsSavedMsg{Path: path, Error: err}
+	}
+}
+
+func lmsDeadlinesCmd(courses []Course) tea.Cmd {
+	return func() tea.Msg {
+		deadlines, err := fetchLMSDeadlines(appConfig.LMS, courses, time.Now())
+		return LMSDeadlinesMsg{Deadlines: deadlines, Error: err}
+	}
+}
+
+func waitForProgress(ch chan FetchProgress) tea.Cmd {
+	return func() tea.Msg {
+		return ProgressMsg(<-ch)
+	}
+}
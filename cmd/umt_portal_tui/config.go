@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type Config struct {
+	ProxyURL           string     `json:"proxy_url"`
+	CACertFile         string     `json:"ca_cert_file"`
+	InsecureSkipVerify bool       `json:"insecure_skip_verify"`
+	RequestsPerMinute  int        `json:"requests_per_minute"`
+	Language           string     `json:"language"`
+	CheckForUpdates    bool       `json:"check_for_updates"`
+	SMTP               SMTPConfig `json:"smtp"`
+	DownloadDir        string     `json:"download_dir"`
+	LMS                LMSConfig  `json:"lms"`
+	// AttendanceThreshold is the minimum attendance percentage; courses
+	// below it are highlighted and alerted on. CourseThresholds overrides it
+	// per course code.
+	AttendanceThreshold float64            `json:"attendance_threshold"`
+	CourseThresholds    map[string]float64 `json:"course_thresholds"`
+	// CGPAFloor is the lowest CGPA to accept; a transcript or provisional
+	// result below it is warned about. Zero turns the warning off.
+	CGPAFloor float64 `json:"cgpa_floor"`
+	// Grading weighs assessment categories per course code, for the
+	// weighted grade shown with the assessments.
+	Grading map[string]CourseGrading `json:"grading"`
+	// GradeScale replaces the minimum percentage of each letter grade for
+	// courses without cutoffs of their own.
+	GradeScale map[string]float64 `json:"grade_scale"`
+	// Requests overrides the timeout and retries of portal operations.
+	Requests map[string]RequestPolicy `json:"requests"`
+	// Installments are the dates (YYYY-MM-DD) to split the outstanding
+	// dues over; check reminds of each InstallmentReminderDays ahead.
+	Installments            []string `json:"installments"`
+	InstallmentReminderDays int      `json:"installment_reminder_days"`
+	// Digest is when and where the daemon sends its daily digest.
+	Digest DigestConfig `json:"digest"`
+	// Recheck is the format of assessment recheck requests.
+	Recheck RecheckConfig `json:"recheck"`
+	// Credentials selects where saved credentials are read from.
+	Credentials CredentialsConfig `json:"credentials"`
+	// Hooks are shell commands run when a fetch finds new data.
+	Hooks HooksConfig `json:"hooks"`
+	// Palette is the preset the TUI's color roles are taken from.
+	Palette string `json:"palette"`
+	// Attention is how the end of a long fetch is signalled while the
+	// terminal is unfocused: bell, notify or off.
+	Attention string `json:"attention"`
+}
+
+const DEFAULT_ATTENDANCE_THRESHOLD = 80.0
+
+var appConfig Config
+
+func configPath() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.json"), nil
+}
+
+// LoadConfig reads the user config file. A missing file is not an error and
+// yields the zero Config.
+func LoadConfig() (Config, error) {
+	var cfg Config
+
+	path, err := configPath()
+	if err != nil {
+		return cfg, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	if err := cfg.validateThresholds(); err != nil {
+		return cfg, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	if err := cfg.validateRequests(); err != nil {
+		return cfg, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	if err := cfg.validateInstallments(); err != nil {
+		return cfg, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	if err := cfg.validateCGPAFloor(); err != nil {
+		return cfg, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	if err := cfg.Digest.validate(); err != nil {
+		return cfg, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	if err := cfg.Recheck.validate(); err != nil {
+		return cfg, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	if err := cfg.Credentials.validate(); err != nil {
+		return cfg, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	if err := validatePalette(cfg.Palette); err != nil {
+		return cfg, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	if err := validateAttention(cfg.Attention); err != nil {
+		return cfg, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	if err := (CourseGrading{Cutoffs: cfg.GradeScale}).validate(); err != nil {
+		return cfg, fmt.Errorf("invalid config file %s: grade_scale: %w", path, err)
+	}
+	for code, grading := range cfg.Grading {
+		if err := grading.validate(); err != nil {
+			return cfg, fmt.Errorf("invalid config file %s: grading[%s]: %w", path, code, err)
+		}
+	}
+
+	return cfg, nil
+}
+
+func (c Config) validateThresholds() error {
+	if c.AttendanceThreshold < 0 || c.AttendanceThreshold > 100 {
+		return fmt.Errorf("attendance_threshold %v is not between 0 and 100", c.AttendanceThreshold)
+	}
+	for code, threshold := range c.CourseThresholds {
+		if threshold < 0 || threshold > 100 {
+			return fmt.Errorf("course_thresholds[%s] %v is not between 0 and 100", code, threshold)
+		}
+	}
+	return nil
+}
+
+// attendanceThreshold returns the threshold for a course, matching
+// course_thresholds keys case-insensitively.
+func (c Config) attendanceThreshold(courseCode string) float64 {
+	for code, threshold := range c.CourseThresholds {
+		if strings.EqualFold(code, courseCode) {
+			return threshold
+		}
+	}
+	if c.AttendanceThreshold > 0 {
+		return c.AttendanceThreshold
+	}
+	return DEFAULT_ATTENDANCE_THRESHOLD
+}
+
+// belowAttendanceThreshold reports whether a course with fetched attendance
+// is under its threshold.
+func (c Config) belowAttendanceThreshold(course Course) bool {
+	return course.TotalLectures > 0 && course.AttendancePercentage < c.attendanceThreshold(course.Code)
+}
@@ -0,0 +1,285 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+const (
+	DIGEST_TIME_FORMAT  = "15:04"
+	DEFAULT_DIGEST_TIME = "20:00"
+	WEBHOOK_TIMEOUT     = 30 * time.Second
+	// DAEMON_POLL_INTERVAL bounds each sleep, so a digest still goes out
+	// on time after the machine wakes from suspend.
+	DAEMON_POLL_INTERVAL = time.Minute
+)
+
+// DigestConfig schedules the daemon's daily digest and where it goes. With
+// neither Desktop nor Webhook it is only printed.
+type DigestConfig struct {
+	// Time is the local time of day, HH:MM.
+	Time    string `json:"time"`
+	Desktop bool   `json:"desktop"`
+	Webhook string `json:"webhook"`
+	// Weekly is the weekday, e.g. "sunday", on which the daily digest is
+	// followed by a weekly summary email through the smtp config.
+	Weekly string `json:"weekly"`
+}
+
+func (c DigestConfig) validate() error {
+	if c.Time != "" {
+		if _, err := time.Parse(DIGEST_TIME_FORMAT, c.Time); err != nil {
+			return fmt.Errorf("digest time %q is not HH:MM", c.Time)
+		}
+	}
+	if _, ok := c.weeklyDay(); c.Weekly != "" && !ok {
+		return fmt.Errorf("digest weekly %q is not a weekday", c.Weekly)
+	}
+	return nil
+}
+
+// daemonOnce and daemonMetricsAddr are bound to daemon's --once and
+// --metrics flags.
+var (
+	daemonOnce        bool
+	daemonMetricsAddr string
+)
+
+func daemonFlags(fs *flag.FlagSet) {
+	fs.BoolVar(&daemonOnce, "once", false, "send one digest now and exit")
+	fs.StringVar(&daemonMetricsAddr, "metrics", "", "serve Prometheus metrics at http://`addr`"+METRICS_PATH+", e.g. localhost:9464")
+}
+
+// daemonLog is where the daemon reports its runs; tests replace it.
+var daemonLog io.Writer = os.Stderr
+
+// digest is what changed since the last digest, what needs attention and
+// what is still owed.
+type digest struct {
+	Date    time.Time     `json:"date"`
+	Changes []checkChange `json:"changes"`
+	Alerts  []checkChange `json:"alerts"`
+	Dues    float64       `json:"dues"`
+}
+
+func (d digest) title() string {
+	return "UMT Portal digest for " + d.Date.Format("Mon 2 Jan")
+}
+
+func (d digest) body() string {
+	var lines []string
+	line := func(mark string, c checkChange) {
+		if c.Course != "" {
+			lines = append(lines, fmt.Sprintf("%s %s: %s", mark, c.Course, c.Detail))
+		} else {
+			lines = append(lines, mark+" "+c.Detail)
+		}
+	}
+	for _, c := range d.Alerts {
+		line("!", c)
+	}
+	for _, c := range d.Changes {
+		line("•", c)
+	}
+	if d.Dues > 0 {
+		lines = append(lines, "Outstanding dues: "+formatAmount(d.Dues))
+	}
+	if len(lines) == 0 {
+		return "Nothing new since the last check."
+	}
+	return strings.Join(lines, "\n")
+}
+
+// daemonCredentials are UMT_STUDENT_ID and UMT_PASSWORD, else the
+// credentials saved with "Remember me". The daemon never prompts.
+func daemonCredentials() (Credentials, error) {
+	creds := Credentials{StudentID: os.Getenv("UMT_STUDENT_ID"), Password: os.Getenv("UMT_PASSWORD")}
+	if creds.StudentID != "" && creds.Password != "" {
+		return creds, nil
+	}
+	saved, err := LoadCreds()
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return creds, fmt.Errorf("%w: saved credentials are unreadable: %v", errNoCredentials, err)
+	}
+	if saved.StudentID == "" || saved.Password == "" {
+		return creds, fmt.Errorf("%w: set UMT_STUDENT_ID and UMT_PASSWORD or save them in the TUI with \"Remember me\"", errNoCredentials)
+	}
+	return saved, nil
+}
+
+// buildDigest logs in afresh, since the portal drops idle sessions long
+// before the next digest, and collects what check would report along with
+// the outstanding dues.
+func buildDigest(creds Credentials, now time.Time) (*Session, digest, error) {
+	d := digest{Date: now}
+	s, err := cliLogin(creds, strings.NewReader(""), io.Discard)
+	if err != nil {
+		return nil, d, err
+	}
+	kinds, _ := checkKinds(nil)
+	result, err := collectChecks(s, kinds)
+	if err != nil {
+		return s, d, err
+	}
+	d.Changes = result.Changes
+	d.Alerts = append(result.Breaches, result.Reminders...)
+
+	challans, err := s.GetFees()
+	if err != nil {
+		return s, d, err
+	}
+	d.Dues = outstandingDues(challans)
+	return s, d, nil
+}
+
+// notifyDesktop shows a desktop notification; tests replace it.
+var notifyDesktop = func(title, body string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("osascript", "-e", fmt.Sprintf("display notification %q with title %q", body, title))
+	case "windows":
+		cmd = exec.Command("powershell", "-NoProfile", "-Command", `[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null
+$t = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$x = $t.GetElementsByTagName('text')
+$x.Item(0).AppendChild($t.CreateTextNode($env:UMT_NOTIFY_TITLE)) > $null
+$x.Item(1).AppendChild($t.CreateTextNode($env:UMT_NOTIFY_BODY)) > $null
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('UMT Portal').Show([Windows.UI.Notifications.ToastNotification]::new($t))`)
+		cmd.Env = append(os.Environ(), "UMT_NOTIFY_TITLE="+title, "UMT_NOTIFY_BODY="+body)
+	default:
+		cmd = exec.Command("notify-send", "--app-name", "UMT Portal", title, body)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("desktop notification failed: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// postWebhook sends the digest as JSON. Chat services such as Slack show
+// its text; the digest itself is there for anything else.
+func postWebhook(url string, d digest) error {
+	body, err := json.Marshal(struct {
+		Text   string `json:"text"`
+		Digest digest `json:"digest"`
+	}{d.title() + "\n" + d.body(), d})
+	if err != nil {
+		return fmt.Errorf("failed to encode digest: %w", err)
+	}
+	client := &http.Client{Transport: sharedTransport, Timeout: WEBHOOK_TIMEOUT}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("webhook failed: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook failed: %s", resp.Status)
+	}
+	return nil
+}
+
+func deliverDigest(cfg DigestConfig, d digest) error {
+	var errs []error
+	if cfg.Desktop {
+		errs = append(errs, notifyDesktop(d.title(), d.body()))
+	}
+	if cfg.Webhook != "" {
+		errs = append(errs, postWebhook(cfg.Webhook, d))
+	}
+	if !cfg.Desktop && cfg.Webhook == "" {
+		fmt.Fprintf(daemonLog, "%s\n%s\n", d.title(), d.body())
+	}
+	return errors.Join(errs...)
+}
+
+// sendDigest delivers the daily digest and, on the configured weekday, the
+// weekly email.
+func sendDigest(s *Session, cfg DigestConfig, d digest) error {
+	err := deliverDigest(cfg, d)
+	if day, ok := cfg.weeklyDay(); ok && d.Date.Weekday() == day {
+		err = errors.Join(err, sendWeeklyDigest(s, d.Date))
+	}
+	return err
+}
+
+// nextDigest is the first time of day at after now.
+func nextDigest(now time.Time, at string) time.Time {
+	if at == "" {
+		at = DEFAULT_DIGEST_TIME
+	}
+	t, _ := time.Parse(DIGEST_TIME_FORMAT, at)
+	next := time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, now.Location())
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// runDaemon sends a digest every day at the configured time until it is
+// stopped. A failed digest is reported and retried the next day. "daemon
+// install" instead sets it up as a service.
+func runDaemon(_ *Session, args []string) (Output, error) {
+	if len(args) > 0 && args[0] == "install" {
+		return installDaemon(args[1:])
+	}
+	if len(args) > 0 {
+		return Output{}, withExitCode(EXIT_USAGE, fmt.Errorf("unexpected arguments"))
+	}
+	creds, err := daemonCredentials()
+	if err != nil {
+		return Output{}, err
+	}
+	cfg := appConfig.Digest
+
+	if daemonOnce {
+		s, d, err := buildDigest(creds, time.Now())
+		if err != nil {
+			return Output{}, err
+		}
+		out := Output{Header: []string{"kind", "course", "detail"}, Value: d}
+		for _, c := range append(d.Alerts, d.Changes...) {
+			out.Rows = append(out.Rows, []string{c.Kind, c.Course, c.Detail})
+		}
+		return out, sendDigest(s, cfg, d)
+	}
+
+	if daemonMetricsAddr != "" {
+		portalMetrics = newMetricsRegistry()
+		portalMetrics.add("umt_digests_total", "", 0)
+		portalMetrics.add("umt_digest_failures_total", "", 0)
+		ln, err := serveMetrics(daemonMetricsAddr, portalMetrics)
+		if err != nil {
+			return Output{}, err
+		}
+		defer ln.Close()
+		fmt.Fprintf(daemonLog, "serving metrics at http://%s%s\n", ln.Addr(), METRICS_PATH)
+	}
+
+	for {
+		next := nextDigest(time.Now(), cfg.Time)
+		fmt.Fprintf(daemonLog, "next digest at %s\n", next.Format("Mon 2 Jan 15:04"))
+		for now := time.Now(); now.Before(next); now = time.Now() {
+			time.Sleep(min(next.Sub(now), DAEMON_POLL_INTERVAL))
+		}
+		s, d, err := buildDigest(creds, time.Now())
+		if err == nil {
+			err = sendDigest(s, cfg, d)
+		}
+		if portalMetrics != nil {
+			portalMetrics.recordDigest(s, d, err)
+		}
+		if err != nil {
+			fmt.Fprintf(daemonLog, "digest failed: %v\n", err)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDaemonOnceDelivers(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	useMockPortal(t, portal)
+	withTerminal(t, false, "")
+	t.Setenv("UMT_STUDENT_ID", "F2023000000")
+	t.Setenv("UMT_PASSWORD", "hunter2")
+
+	var posted struct {
+		Text   string `json:"text"`
+		Digest digest `json:"digest"`
+	}
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&posted); err != nil {
+			t.Errorf("invalid webhook body: %v", err)
+		}
+	}))
+	defer hook.Close()
+	// The webhook is not on the portal, so it bypasses the mock portal's
+	// rewriting.
+	sharedTransport = hookTransport{hook: hook.URL, next: sharedTransport}
+
+	var notified []string
+	saved, savedNotify, savedLog := appConfig, notifyDesktop, daemonLog
+	t.Cleanup(func() { appConfig, notifyDesktop, daemonLog = saved, savedNotify, savedLog })
+	notifyDesktop = func(title, body string) error {
+		notified = append(notified, title, body)
+		return nil
+	}
+	daemonLog = io.Discard
+	appConfig.Digest = DigestConfig{Desktop: true, Webhook: hook.URL}
+
+	c, _ := findCommand("daemon")
+	var out bytes.Buffer
+	if err := runCommand(c, []string{"--once"}, strings.NewReader(""), &out); err != nil {
+		t.Fatalf("daemon --once failed: %v\n%s", err, out.String())
+	}
+	if len(notified) != 2 || !strings.HasPrefix(notified[0], "UMT Portal digest for ") {
+		t.Fatalf("expected one desktop notification, got %q", notified)
+	}
+	if posted.Text != notified[0]+"\n"+notified[1] {
+		t.Errorf("webhook text %q does not match the notification %q", posted.Text, notified)
+	}
+}
+
+type hookTransport struct {
+	hook string
+	next http.RoundTripper
+}
+
+func (t hookTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if strings.HasPrefix(req.URL.String(), t.hook) {
+		return http.DefaultTransport.RoundTrip(req)
+	}
+	return t.next.RoundTrip(req)
+}
+
+func TestNextDigest(t *testing.T) {
+	now := time.Date(2026, 3, 9, 18, 30, 0, 0, time.UTC)
+	if got, want := nextDigest(now, "20:00"), time.Date(2026, 3, 9, 20, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("later today: got %v, want %v", got, want)
+	}
+	if got, want := nextDigest(now, "18:30"), time.Date(2026, 3, 10, 18, 30, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("now is already sent: got %v, want %v", got, want)
+	}
+	if got, want := nextDigest(now, ""), time.Date(2026, 3, 9, 20, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("default time: got %v, want %v", got, want)
+	}
+	if err := (DigestConfig{Time: "8pm"}).validate(); err == nil {
+		t.Error("expected an error for a time that is not HH:MM")
+	}
+}
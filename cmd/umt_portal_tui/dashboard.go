@@ -0,0 +1,196 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+const DEFAULT_DASHBOARD_DIR = "umt_dashboard"
+
+// The dashboard page and its script; data.js, written next to them, holds
+// the data. A script rather than a fetched JSON file, so the page also
+// works opened straight from disk.
+var (
+	//go:embed dashboard.html
+	dashboardHTML []byte
+	//go:embed dashboard.js
+	dashboardJS []byte
+)
+
+type dashboardSemester struct {
+	Name        string  `json:"name"`
+	SGPA        float32 `json:"sgpa"`
+	CGPA        float32 `json:"cgpa"`
+	CreditHours int     `json:"credit_hours"`
+}
+
+type dashboardCourse struct {
+	Code                 string  `json:"code"`
+	Title                string  `json:"title"`
+	Lectures             int     `json:"lectures"`
+	Absences             int     `json:"absences"`
+	AttendancePercentage float64 `json:"attendance_percentage"`
+	Threshold            float64 `json:"threshold"`
+	BelowThreshold       bool    `json:"below_threshold"`
+	AssessmentPercentage float64 `json:"assessment_percentage"`
+	Assessments          int     `json:"assessments"`
+}
+
+// dashboardEvent is an upcoming LMS activity. The portal publishes no exam
+// schedule, so quizzes and assignments on the LMS are what the dashboard
+// can count down to.
+type dashboardEvent struct {
+	Name   string    `json:"name"`
+	Kind   string    `json:"kind"`
+	Course string    `json:"course"`
+	Due    time.Time `json:"due"`
+	URL    string    `json:"url,omitempty"`
+}
+
+type dashboardData struct {
+	GeneratedAt time.Time           `json:"generated_at"`
+	Student     string              `json:"student"`
+	ID          string              `json:"id"`
+	Program     string              `json:"program"`
+	CGPA        string              `json:"cgpa"`
+	CreditHours string              `json:"credit_hours"`
+	Semesters   []dashboardSemester `json:"semesters"`
+	Courses     []dashboardCourse   `json:"courses"`
+	Upcoming    []dashboardEvent    `json:"upcoming"`
+	// LMS says why Upcoming is empty: the LMS is not configured or failed.
+	LMS string `json:"lms,omitempty"`
+}
+
+// dashboardDir is bound to dashboard's --out flag.
+var dashboardDir string
+
+func dashboardFlags(fs *flag.FlagSet) {
+	fs.StringVar(&dashboardDir, "out", DEFAULT_DASHBOARD_DIR, "directory to write the dashboard to")
+}
+
+func buildDashboardData(s *Session, deadlines []LMSDeadline, now time.Time) dashboardData {
+	report := buildReportData(s, now)
+	data := dashboardData{
+		GeneratedAt: now,
+		Student:     s.Student.Name,
+		ID:          s.Student.ID,
+		Program:     s.Student.Program,
+		CGPA:        s.Student.CgpaEarned,
+		CreditHours: s.Student.CompletedCreditHours,
+		Semesters:   []dashboardSemester{},
+		Courses:     []dashboardCourse{},
+		Upcoming:    []dashboardEvent{},
+	}
+	if s.Student.Transcript.TotalCGPA != "" {
+		data.CGPA = s.Student.Transcript.TotalCGPA
+	}
+	for _, sem := range report.Semesters {
+		data.Semesters = append(data.Semesters, dashboardSemester{Name: sem.Name, SGPA: sem.SGPA, CGPA: sem.CGPA, CreditHours: sem.CreditHoursEarned})
+	}
+	for _, c := range report.Courses {
+		course := dashboardCourse{
+			Code:                 c.Code,
+			Title:                c.Title,
+			Lectures:             len(c.Attendance),
+			AttendancePercentage: c.AttendancePercentage,
+			Threshold:            c.Threshold,
+			BelowThreshold:       c.BelowThreshold,
+			AssessmentPercentage: c.AssessmentPercent,
+			Assessments:          len(c.Assessment),
+		}
+		for _, a := range c.Attendance {
+			if !a.Attendance {
+				course.Absences++
+			}
+		}
+		data.Courses = append(data.Courses, course)
+	}
+	for _, d := range deadlines {
+		course := d.CourseCode
+		if course == "" {
+			course = d.Course
+		}
+		data.Upcoming = append(data.Upcoming, dashboardEvent{Name: d.Name, Kind: d.Module, Course: course, Due: d.Due, URL: d.URL})
+	}
+	return data
+}
+
+// writeDashboard writes the page, its script and the data into dir.
+func writeDashboard(dir string, data dashboardData) error {
+	raw, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode dashboard data: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create dashboard directory: %w", err)
+	}
+	files := map[string][]byte{
+		"index.html":   dashboardHTML,
+		"dashboard.js": dashboardJS,
+		"data.js":      append(append([]byte("window.UMT_DASHBOARD = "), raw...), ";\n"...),
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), content, 0644); err != nil {
+			return fmt.Errorf("failed to write dashboard: %w", err)
+		}
+	}
+	return nil
+}
+
+// runDashboard fetches the attendance and assessments of every course, the
+// transcript and, when configured, the LMS deadlines, and writes a static
+// dashboard of them that can be opened locally or served from any web
+// server.
+func runDashboard(s *Session, args []string) (Output, error) {
+	if len(args) > 0 {
+		return Output{}, withExitCode(EXIT_USAGE, fmt.Errorf("unexpected arguments; use --out to choose the directory"))
+	}
+
+	courses, err := s.GetCourses()
+	if err != nil {
+		return Output{}, err
+	}
+	for _, c := range courses {
+		if err := s.GetCourseAttendance(true, c.ID); err != nil {
+			return Output{}, err
+		}
+		if err := s.GetCourseAssessments(c.ID); err != nil {
+			return Output{}, err
+		}
+	}
+	if err := s.GetTranscript(true); err != nil {
+		return Output{}, err
+	}
+
+	var notes []string
+	var deadlines []LMSDeadline
+	lms := "not configured"
+	if appConfig.LMS.configured() {
+		lms = ""
+		if deadlines, err = fetchLMSDeadlines(appConfig.LMS, s.Student.Courses, time.Now()); err != nil {
+			lms = "unavailable"
+			notes = append(notes, fmt.Sprintf("LMS deadlines left out: %v", err))
+		}
+	}
+
+	data := buildDashboardData(s, deadlines, time.Now())
+	data.LMS = lms
+	if err := writeDashboard(dashboardDir, data); err != nil {
+		return Output{}, err
+	}
+
+	index := filepath.Join(dashboardDir, "index.html")
+	return Output{
+		Header: []string{"dashboard", "courses", "semesters", "upcoming"},
+		Rows:   [][]string{{index, strconv.Itoa(len(data.Courses)), strconv.Itoa(len(data.Semesters)), strconv.Itoa(len(data.Upcoming))}},
+		Value:  map[string]any{"dashboard": index, "data": data},
+		Notes:  notes,
+		Record: true,
+	}, nil
+}
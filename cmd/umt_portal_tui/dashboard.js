@@ -0,0 +1,132 @@
+// Draws the dashboard from window.UMT_DASHBOARD, which data.js sets.
+(function () {
+  "use strict";
+
+  var data = window.UMT_DASHBOARD;
+  var SVG = "http://www.w3.org/2000/svg";
+
+  function el(tag, attrs, text) {
+    var node = tag.indexOf("svg:") === 0 ? document.createElementNS(SVG, tag.slice(4)) : document.createElement(tag);
+    Object.keys(attrs || {}).forEach(function (k) { node.setAttribute(k, attrs[k]); });
+    if (text !== undefined) node.textContent = text;
+    return node;
+  }
+
+  function scoreClass(percentage) {
+    if (percentage >= 85) return "good";
+    if (percentage >= 70) return "warn";
+    return "bad";
+  }
+
+  function empty(target, text) {
+    target.appendChild(el("p", { "class": "meta" }, text));
+  }
+
+  function header() {
+    document.title = "UMT Dashboard – " + data.student;
+    document.getElementById("student").textContent = data.student;
+    document.getElementById("meta").textContent = [data.id, data.program, "generated " + new Date(data.generated_at).toLocaleString()].join(" · ");
+    var cards = document.getElementById("cards");
+    [["CGPA", data.cgpa], ["Credit hours earned", data.credit_hours], ["Courses", data.courses.length], ["Upcoming", data.upcoming.length]].forEach(function (c) {
+      var card = el("div", { "class": "card" }, c[0]);
+      card.appendChild(el("b", {}, String(c[1] || "–")));
+      cards.appendChild(card);
+    });
+  }
+
+  function gpaChart() {
+    var target = document.getElementById("gpa");
+    var sems = data.semesters;
+    if (!sems.length) return empty(target, "No transcript yet.");
+
+    var width = 480, height = 220, pad = 32;
+    var step = sems.length > 1 ? (width - 2 * pad) / (sems.length - 1) : 0;
+    var x = function (i) { return pad + i * step; };
+    var y = function (gpa) { return height - pad - gpa / 4 * (height - 2 * pad); };
+    var svg = el("svg:svg", { viewBox: "0 0 " + width + " " + height, role: "img", "aria-label": "SGPA and CGPA by semester" });
+    for (var g = 0; g <= 4; g++) {
+      svg.appendChild(el("svg:line", { "class": "gridline", x1: pad, x2: width - pad, y1: y(g), y2: y(g) }));
+      svg.appendChild(el("svg:text", { x: 4, y: y(g) + 4 }, g + ".0"));
+    }
+    ["sgpa", "cgpa"].forEach(function (series) {
+      var points = sems.map(function (s, i) { return x(i) + "," + y(s[series]); });
+      svg.appendChild(el("svg:polyline", { "class": series, points: points.join(" ") }));
+      sems.forEach(function (s, i) {
+        var dot = el("svg:circle", { "class": series, cx: x(i), cy: y(s[series]), r: 3 });
+        dot.appendChild(el("svg:title", {}, s.name + " " + series.toUpperCase() + " " + s[series].toFixed(2)));
+        svg.appendChild(dot);
+      });
+    });
+    sems.forEach(function (s, i) {
+      svg.appendChild(el("svg:text", { x: x(i), y: height - 8, "text-anchor": "middle" }, s.name));
+    });
+    target.appendChild(svg);
+  }
+
+  function attendanceChart() {
+    var target = document.getElementById("attendance");
+    var courses = data.courses.filter(function (c) { return c.lectures > 0; });
+    if (!courses.length) return empty(target, "No attendance recorded yet.");
+
+    var width = 480, row = 28, label = 80, value = 110;
+    var bar = width - label - value;
+    var svg = el("svg:svg", { viewBox: "0 0 " + width + " " + (courses.length * row + 4), role: "img", "aria-label": "Attendance by course" });
+    courses.forEach(function (c, i) {
+      var top = i * row + 4;
+      var cls = c.below_threshold ? "bad" : scoreClass(c.attendance_percentage);
+      svg.appendChild(el("svg:text", { x: 0, y: top + 14 }, c.code));
+      svg.appendChild(el("svg:rect", { x: label, y: top, width: bar, height: 18, rx: 4, fill: "#eee" }));
+      var filled = el("svg:rect", { "class": cls, x: label, y: top, width: bar * c.attendance_percentage / 100, height: 18, rx: 4 });
+      filled.appendChild(el("svg:title", {}, c.title + ": " + c.absences + " absence(s) in " + c.lectures + " lectures"));
+      svg.appendChild(filled);
+      var mark = label + bar * c.threshold / 100;
+      svg.appendChild(el("svg:line", { "class": "threshold", x1: mark, x2: mark, y1: top - 2, y2: top + 20 }));
+      svg.appendChild(el("svg:text", { "class": cls, x: label + bar + 8, y: top + 14 }, c.attendance_percentage.toFixed(1) + "%" + (c.below_threshold ? " ⚠" : "")));
+    });
+    target.appendChild(svg);
+  }
+
+  function upcoming() {
+    var target = document.getElementById("upcoming");
+    if (!data.upcoming.length) {
+      var why = {
+        "not configured": "Connect the LMS (lms in the config) to list upcoming quizzes and assignments; the portal publishes no exam schedule.",
+        "unavailable": "The LMS could not be reached when the dashboard was generated."
+      };
+      return empty(target, why[data.lms] || "Nothing due.");
+    }
+
+    var table = el("table");
+    var head = el("tr");
+    ["Due", "Course", "Activity", ""].forEach(function (h) { head.appendChild(el("th", {}, h)); });
+    table.appendChild(head);
+    var now = Date.now();
+    data.upcoming.forEach(function (e) {
+      var due = new Date(e.due);
+      var days = Math.ceil((due - now) / 86400000);
+      var tr = el("tr");
+      tr.appendChild(el("td", {}, due.toLocaleString()));
+      tr.appendChild(el("td", {}, e.course));
+      var name = el("td");
+      if (/^https?:\/\//.test(e.url || "")) {
+        name.appendChild(el("a", { href: e.url }, e.name));
+      } else {
+        name.textContent = e.name;
+      }
+      if (e.kind) name.appendChild(el("span", { "class": "meta" }, " (" + e.kind + ")"));
+      tr.appendChild(name);
+      tr.appendChild(el("td", { "class": days <= 2 ? "soon" : "meta" }, days < 0 ? "past" : days === 0 ? "today" : "in " + days + " day(s)"));
+      table.appendChild(tr);
+    });
+    target.appendChild(table);
+  }
+
+  if (!data) {
+    document.body.textContent = "data.js is missing; run the dashboard command again.";
+    return;
+  }
+  header();
+  gpaChart();
+  attendanceChart();
+  upcoming();
+})();
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDashboard(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	useMockPortal(t, portal)
+	withTerminal(t, false, "")
+	t.Setenv("UMT_STUDENT_ID", "F2023000000")
+	t.Setenv("UMT_PASSWORD", "hunter2")
+
+	dir := filepath.Join(t.TempDir(), "site")
+	dashboard, _ := findCommand("dashboard")
+	if err := runCommand(dashboard, []string{"--out", dir}, strings.NewReader(""), io.Discard); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"index.html", "dashboard.js"} {
+		raw, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(raw), "src=\"http") || strings.Contains(string(raw), "fetch(") {
+			t.Errorf("%s loads something from elsewhere", name)
+		}
+	}
+	raw, err := os.ReadFile(filepath.Join(dir, "data.js"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	script := strings.TrimSpace(string(raw))
+	if !strings.HasPrefix(script, "window.UMT_DASHBOARD = ") || !strings.HasSuffix(script, ";") {
+		t.Fatalf("data.js does not set the data:\n%s", script)
+	}
+	var data dashboardData
+	if err := json.Unmarshal([]byte(strings.TrimSuffix(strings.TrimPrefix(script, "window.UMT_DASHBOARD = "), ";")), &data); err != nil {
+		t.Fatal(err)
+	}
+	if data.Student != "TEST STUDENT" || len(data.Semesters) == 0 || data.LMS != "not configured" {
+		t.Errorf("student %q, %d semesters, lms %q", data.Student, len(data.Semesters), data.LMS)
+	}
+	var found bool
+	for _, c := range data.Courses {
+		if c.Code == "CC2042" {
+			found = c.Lectures > 0 && c.Assessments > 0
+		}
+	}
+	if !found {
+		t.Errorf("CC2042 attendance or assessments missing: %+v", data.Courses)
+	}
+}
@@ -0,0 +1,288 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Cache modes, set for one run by the --refresh and --no-cache flags.
+const (
+	CACHE_NORMAL = iota
+	// CACHE_REFRESH ignores the cached data, fetching everything from the
+	// portal, and saves what it fetches as usual.
+	CACHE_REFRESH
+	// CACHE_OFF neither reads nor writes any local cache, and sends no
+	// validators for pages fetched before.
+	CACHE_OFF
+)
+
+var cacheMode = CACHE_NORMAL
+
+var errCacheSkipped = errors.New("cached data skipped by --refresh or --no-cache")
+
+// SerializableData is the on-disk snapshot of everything fetched besides the
+// transcript: the profile and the enrolled courses with their attendance and
+// assessments. It backs offline commands such as report.
+type SerializableData struct {
+	SchemaVersion int                  `json:"schema_version"`
+	SavedAt       time.Time            `json:"saved_at"`
+	Student       SerializableStudent  `json:"student"`
+	Courses       []SerializableCourse `json:"courses"`
+}
+
+type SerializableStudent struct {
+	Name                  string `json:"name"`
+	Batch                 string `json:"batch"`
+	ID                    string `json:"id"`
+	Program               string `json:"program"`
+	ProgramLevel          string `json:"program_level"`
+	Email                 string `json:"email"`
+	CurrentSemester       string `json:"current_semester"`
+	CgpaEarned            string `json:"cgpa_earned"`
+	MaxAllowedCreditHours string `json:"max_allowed_credit_hours"`
+	RequestedCreditHours  string `json:"requested_credit_hours"`
+	CompletedCreditHours  string `json:"completed_credit_hours"`
+	RequiredCreditHours   string `json:"required_credit_hours"`
+}
+
+type SerializableCourse struct {
+	ID                   string                   `json:"id"`
+	Code                 string                   `json:"code"`
+	Title                string                   `json:"title"`
+	CreditHours          string                   `json:"credit_hours"`
+	CourseType           string                   `json:"course_type"`
+	FacultyName          string                   `json:"faculty_name"`
+	FacultyEmail         string                   `json:"faculty_email"`
+	Mode                 string                   `json:"mode"`
+	Section              string                   `json:"section"`
+	Semester             string                   `json:"semester"`
+	OutlineURL           string                   `json:"outline_url,omitempty"`
+	TotalLectures        int                      `json:"total_lectures"`
+	AttendancePercentage float64                  `json:"attendance_percentage"`
+	Attendance           []Attendance             `json:"attendance"`
+	Assessments          []SerializableAssessment `json:"assessments"`
+}
+
+type SerializableAssessment struct {
+	Name          string  `json:"name"`
+	ObtainedMarks float32 `json:"obtained_marks"`
+	TotalMarks    float32 `json:"total_marks"`
+	AssignedDate  string  `json:"assigned_date"`
+	ClassAverage  float32 `json:"class_average,omitempty"`
+	ClassHighest  float32 `json:"class_highest,omitempty"`
+}
+
+func (st *Student) ToSerializable() SerializableData {
+	data := SerializableData{
+		SchemaVersion: SCHEMA_VERSION,
+		Student: SerializableStudent{
+			Name:                  st.Name,
+			Batch:                 st.Batch,
+			ID:                    st.ID,
+			Program:               st.Program,
+			ProgramLevel:          st.ProgramLevel,
+			Email:                 st.Email,
+			CurrentSemester:       st.CurrentSemester,
+			CgpaEarned:            st.CgpaEarned,
+			MaxAllowedCreditHours: st.MaxAllowedCreditHours,
+			RequestedCreditHours:  st.RequestedCreditHours,
+			CompletedCreditHours:  st.CompletedCreditHours,
+			RequiredCreditHours:   st.RequiredCreditHours,
+		},
+	}
+	for _, c := range st.Courses {
+		sc := SerializableCourse{
+			ID:                   c.ID,
+			Code:                 c.Code,
+			Title:                c.Title,
+			CreditHours:          c.CreditHours,
+			CourseType:           c.CourseType,
+			FacultyName:          c.FacultyName,
+			FacultyEmail:         c.FacultyEmail,
+			Mode:                 c.Mode,
+			Section:              c.Section,
+			Semester:             c.Semester,
+			OutlineURL:           c.OutlineURL,
+			TotalLectures:        c.TotalLectures,
+			AttendancePercentage: c.AttendancePercentage,
+			Attendance:           c.Attendance,
+		}
+		for _, a := range c.Assessment {
+			sc.Assessments = append(sc.Assessments, SerializableAssessment{
+				Name:          a.name,
+				ObtainedMarks: a.obtainedMarks,
+				TotalMarks:    a.totalMarks,
+				AssignedDate:  a.assignedDate,
+				ClassAverage:  a.classAverage,
+				ClassHighest:  a.classHighest,
+			})
+		}
+		data.Courses = append(data.Courses, sc)
+	}
+	return data
+}
+
+// ToStudent restores the profile and courses. The transcript is cached
+// separately and left empty.
+func (d *SerializableData) ToStudent() Student {
+	ss := d.Student
+	st := Student{
+		Name:                  ss.Name,
+		Batch:                 ss.Batch,
+		ID:                    ss.ID,
+		Program:               ss.Program,
+		ProgramLevel:          ss.ProgramLevel,
+		Email:                 ss.Email,
+		CurrentSemester:       ss.CurrentSemester,
+		CgpaEarned:            ss.CgpaEarned,
+		MaxAllowedCreditHours: ss.MaxAllowedCreditHours,
+		RequestedCreditHours:  ss.RequestedCreditHours,
+		CompletedCreditHours:  ss.CompletedCreditHours,
+		RequiredCreditHours:   ss.RequiredCreditHours,
+	}
+	for _, sc := range d.Courses {
+		c := Course{
+			ID:                   sc.ID,
+			Code:                 sc.Code,
+			Title:                sc.Title,
+			CreditHours:          sc.CreditHours,
+			CourseType:           sc.CourseType,
+			FacultyName:          sc.FacultyName,
+			FacultyEmail:         sc.FacultyEmail,
+			Mode:                 sc.Mode,
+			Section:              sc.Section,
+			Semester:             sc.Semester,
+			OutlineURL:           sc.OutlineURL,
+			TotalLectures:        sc.TotalLectures,
+			AttendancePercentage: sc.AttendancePercentage,
+			Attendance:           sc.Attendance,
+		}
+		for _, a := range sc.Assessments {
+			c.Assessment = append(c.Assessment, Assessment{
+				name:          a.Name,
+				obtainedMarks: a.ObtainedMarks,
+				totalMarks:    a.TotalMarks,
+				assignedDate:  a.AssignedDate,
+				classAverage:  a.ClassAverage,
+				classHighest:  a.ClassHighest,
+			})
+		}
+		st.Courses = append(st.Courses, c)
+	}
+	return st
+}
+
+func dataCachePath() (string, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "data.json"), nil
+}
+
+func readDataCache() (SerializableData, error) {
+	var data SerializableData
+
+	cacheFile, err := dataCachePath()
+	if err != nil {
+		return data, err
+	}
+	raw, err := os.ReadFile(cacheFile)
+	if err != nil {
+		return data, fmt.Errorf("failed to read cache file: %w", err)
+	}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return data, fmt.Errorf("failed to unmarshal cached data: %w", err)
+	}
+	return data, nil
+}
+
+// saveDataCache writes the session's profile and courses. Attendance and
+// assessments are fetched per course on demand, so records the session has
+// not loaded are kept from the previous snapshot of the same student.
+func saveDataCache(s *Session) error {
+	if cacheMode == CACHE_OFF {
+		return nil
+	}
+	s.studentMu.Lock()
+	data := s.Student.ToSerializable()
+	s.studentMu.Unlock()
+	data.SavedAt = time.Now()
+
+	if previous, err := readDataCache(); err == nil && previous.Student.ID == data.Student.ID {
+		for i := range data.Courses {
+			c := &data.Courses[i]
+			for _, old := range previous.Courses {
+				if old.ID != c.ID {
+					continue
+				}
+				if len(c.Attendance) == 0 {
+					c.TotalLectures = old.TotalLectures
+					c.AttendancePercentage = old.AttendancePercentage
+					c.Attendance = old.Attendance
+				}
+				if len(c.Assessments) == 0 {
+					c.Assessments = old.Assessments
+				}
+			}
+		}
+	}
+	return writeDataCache(data)
+}
+
+func writeDataCache(data SerializableData) error {
+	cacheFile, err := dataCachePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(cacheFile), 0755); err != nil {
+		return fmt.Errorf("failed to create app cache dir: %w", err)
+	}
+
+	raw, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal cached data: %w", err)
+	}
+	if err := os.WriteFile(cacheFile, raw, 0600); err != nil {
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	return nil
+}
+
+// loadDataCache fills the session's profile and courses from the snapshot,
+// keeping any transcript already loaded.
+func loadDataCache(s *Session) error {
+	if cacheMode != CACHE_NORMAL {
+		return errCacheSkipped
+	}
+	data, err := readDataCache()
+	if err != nil {
+		return err
+	}
+	s.studentMu.Lock()
+	transcript := s.Student.Transcript
+	s.Student = data.ToStudent()
+	s.Student.Transcript = transcript
+	s.studentMu.Unlock()
+	s.cachedAt = data.SavedAt
+	return nil
+}
+
+func deleteDataCache() error {
+	cacheFile, err := dataCachePath()
+	if err != nil {
+		return err
+	}
+	return os.Remove(cacheFile)
+}
+
+// deleteCaches removes every cached portal file, as on logout or when
+// quitting without "Remember me".
+func deleteCaches() {
+	deleteTranscriptCache()
+	deleteDataCache()
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// debugTransport saves the body of every portal response into dir, for
+// reporting parser breakage. It is only installed by --debug-artifacts.
+// Bodies are scrubbed of personal data unless --include-pii; binary ones,
+// which can't be, are left out.
+type debugTransport struct {
+	dir  string
+	next http.RoundTripper
+	seq  atomic.Int64
+}
+
+func enableDebugArtifacts(dir string) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create debug artifact dir: %w", err)
+	}
+	sharedTransport = &debugTransport{dir: dir, next: sharedTransport}
+	return nil
+}
+
+func (t *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return resp, err
+	}
+
+	name := fmt.Sprintf("%s_%04d_%s_%s.html",
+		time.Now().Format("20060102T150405.000"), t.seq.Add(1), req.Method, artifactName(req.URL.Path))
+	if !includePII {
+		if isTextResponse(resp) {
+			body = []byte(scrubPII(string(body), cookieValues(req, resp)...))
+		} else {
+			body = fmt.Appendf(nil, "%s response of %d bytes left out; run with --include-pii to keep it\n", resp.Header.Get("Content-Type"), len(body))
+		}
+	}
+	if err := os.WriteFile(filepath.Join(t.dir, name), body, 0600); err != nil {
+		fmt.Fprintln(os.Stderr, "warning: failed to save debug artifact:", err)
+	}
+	return resp, nil
+}
+
+func isTextResponse(resp *http.Response) bool {
+	mediaType, _, _ := strings.Cut(resp.Header.Get("Content-Type"), ";")
+	mediaType = strings.TrimSpace(mediaType)
+	return mediaType == "" || strings.HasPrefix(mediaType, "text/") || strings.HasSuffix(mediaType, "json") || strings.HasSuffix(mediaType, "xml") || mediaType == "application/javascript"
+}
+
+// artifactName turns a URL path into a short file name component.
+func artifactName(path string) string {
+	name := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '-' {
+			return r
+		}
+		return '_'
+	}, strings.Trim(path, "/"))
+	if name == "" {
+		name = "index"
+	}
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return name
+}
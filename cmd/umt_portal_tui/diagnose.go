@@ -0,0 +1,261 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DEFAULT_BUNDLE_FILE = "umt_diagnose.zip"
+	// MAX_SNIPPET_SIZE is how much of a failing page goes into a bundle.
+	MAX_SNIPPET_SIZE = 256 << 10
+)
+
+var (
+	// The ASP.NET form state is large and can encode anything on the page.
+	formStatePattern = regexp.MustCompile(`(name="__[A-Z]+"[^>]*value=")[^"]*`)
+	emailPattern     = regexp.MustCompile(`[\w.+-]+@[\w-]+(\.[\w-]+)+`)
+)
+
+// diagnoseBundle and diagnoseOut are bound to diagnose's flags.
+var (
+	diagnoseBundle bool
+	diagnoseOut    string
+)
+
+func diagnoseFlags(fs *flag.FlagSet) {
+	fs.BoolVar(&diagnoseBundle, "bundle", false, "also write an anonymized zip of the results, request log and failing pages to attach to an issue")
+	fs.StringVar(&diagnoseOut, "out", DEFAULT_BUNDLE_FILE, "bundle to write")
+}
+
+// capturedResponse is a portal response kept for the bundle, cut at
+// MAX_SNIPPET_SIZE.
+type capturedResponse struct {
+	Method string
+	URL    string
+	Path   string
+	Status int
+	Body   []byte
+}
+
+// captureTransport keeps the text responses of a diagnose run, so the
+// pages a failing step fetched can go into the bundle.
+type captureTransport struct {
+	next      http.RoundTripper
+	mu        sync.Mutex
+	responses []capturedResponse
+}
+
+func (t *captureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || !isTextResponse(resp) {
+		return resp, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return resp, err
+	}
+	t.mu.Lock()
+	t.responses = append(t.responses, capturedResponse{Method: req.Method, URL: req.URL.String(), Path: req.URL.Path, Status: resp.StatusCode, Body: body[:min(len(body), MAX_SNIPPET_SIZE)]})
+	t.mu.Unlock()
+	return resp, nil
+}
+
+func (t *captureTransport) mark() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.responses)
+}
+
+func (t *captureTransport) since(mark int) []capturedResponse {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]capturedResponse(nil), t.responses[mark:]...)
+}
+
+// Step statuses. Fetches give up on a page that never parses by returning
+// nothing rather than an error, so a step that parsed nothing is suspect too.
+const (
+	STEP_OK     = "ok"
+	STEP_EMPTY  = "empty"
+	STEP_FAILED = "failed"
+)
+
+// diagnoseStep is the outcome of one fetch. Summary counts what was parsed
+// rather than showing it.
+type diagnoseStep struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Summary string `json:"summary,omitempty"`
+	Error   string `json:"error,omitempty"`
+
+	pages []capturedResponse
+}
+
+type bundleManifest struct {
+	CreatedAt  time.Time `json:"created_at"`
+	AppVersion string    `json:"app_version"`
+	OS         string    `json:"os"`
+	Arch       string    `json:"arch"`
+	Language   string    `json:"language"`
+}
+
+// runDiagnose fetches each kind of portal page once and reports whether it
+// parsed, optionally bundling the results for an issue.
+func runDiagnose(s *Session, args []string) (Output, error) {
+	if len(args) > 0 {
+		return Output{}, withExitCode(EXIT_USAGE, fmt.Errorf("unexpected arguments; use --out to choose the bundle"))
+	}
+
+	capture := &captureTransport{next: sharedTransport}
+	prevTransport := sharedTransport
+	sharedTransport = capture
+	defer func() { sharedTransport = prevTransport }()
+
+	var (
+		steps    []diagnoseStep
+		firstErr error
+	)
+	// fetch returns how many items it parsed and a summary of them.
+	step := func(name string, fetch func() (int, string, error)) {
+		mark := capture.mark()
+		n, summary, err := fetch()
+		st := diagnoseStep{Name: name, Status: STEP_OK, Summary: summary}
+		switch {
+		case err != nil:
+			st.Status, st.Summary, st.Error = STEP_FAILED, "", anonymize(err.Error(), s.Student)
+			if firstErr == nil {
+				firstErr = fmt.Errorf("%s: %w", name, err)
+			}
+		case n == 0:
+			st.Status = STEP_EMPTY
+		}
+		if st.Status != STEP_OK {
+			st.pages = capture.since(mark)
+		}
+		steps = append(steps, st)
+	}
+
+	var courses []Course
+	step("courses", func() (int, string, error) {
+		var err error
+		courses, err = s.GetCourses()
+		return len(courses), fmt.Sprintf("%d courses", len(courses)), err
+	})
+	if len(courses) > 0 {
+		course := courses[0]
+		step("attendance "+course.Code, func() (int, string, error) {
+			err := s.GetCourseAttendance(true, course.ID)
+			c := s.Student.Courses[getCourseIndex(s, course.ID)]
+			return len(c.Attendance), fmt.Sprintf("%d lectures, %d total, %.1f%%", len(c.Attendance), c.TotalLectures, c.AttendancePercentage), err
+		})
+		step("assessments "+course.Code, func() (int, string, error) {
+			err := s.GetCourseAssessments(course.ID)
+			c := s.Student.Courses[getCourseIndex(s, course.ID)]
+			return len(c.Assessment), fmt.Sprintf("%d assessments", len(c.Assessment)), err
+		})
+	}
+	step("transcript", func() (int, string, error) {
+		err := s.GetTranscript(true)
+		var n int
+		for _, semester := range s.Student.Transcript.Semester {
+			n += len(semester)
+		}
+		return n, fmt.Sprintf("%d semesters, %d courses", len(s.Student.Transcript.Semester), n), err
+	})
+
+	out := Output{Header: []string{"step", "status", "summary", "error"}, Value: steps}
+	for _, st := range steps {
+		out.Rows = append(out.Rows, []string{st.Name, st.Status, st.Summary, st.Error})
+	}
+	if diagnoseBundle {
+		if err := writeDiagnoseBundle(diagnoseOut, s, steps); err != nil {
+			return Output{}, err
+		}
+		out.Notes = append(out.Notes, fmt.Sprintf("Wrote %s; check it before attaching it to an issue.", diagnoseOut))
+	}
+	return out, firstErr
+}
+
+// anonymize strips the student's name, ID and email, every email address,
+// the ASP.NET form state and whatever stripPII removes from text.
+func anonymize(text string, st Student) string {
+	text = formStatePattern.ReplaceAllString(text, "${1}"+REDACTED)
+	for _, v := range []string{st.Name, st.ID, st.Email} {
+		if strings.TrimSpace(v) != "" {
+			text = regexp.MustCompile(`(?i)`+regexp.QuoteMeta(v)).ReplaceAllString(text, REDACTED)
+		}
+	}
+	text = emailPattern.ReplaceAllString(text, REDACTED)
+	return stripPII(text)
+}
+
+// writeDiagnoseBundle zips the diagnose results, the session's request log
+// and the pages of the steps that failed or came back empty, all
+// anonymized.
+func writeDiagnoseBundle(path string, s *Session, steps []diagnoseStep) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create bundle: %w", err)
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	manifest := bundleManifest{
+		CreatedAt:  time.Now(),
+		AppVersion: readBuildInfo().Version,
+		OS:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+		Language:   currentLanguage,
+	}
+	if err := writeArchiveJSON(zw, "manifest.json", manifest); err != nil {
+		return err
+	}
+	if err := writeArchiveJSON(zw, "steps.json", steps); err != nil {
+		return err
+	}
+
+	var log strings.Builder
+	for _, r := range s.requests.recent(MAX_REQUEST_LOG) {
+		outcome := fmt.Sprint(r.Status)
+		if r.Err != nil {
+			outcome = r.Err.Error()
+		}
+		fmt.Fprintf(&log, "%s %-4s %s -> %s (%s)\n", r.Time.Format(time.RFC3339), r.Method, r.URL, outcome, r.Duration.Round(time.Millisecond))
+	}
+	files := [][2]string{{"requests.log", log.String()}}
+	for i, st := range steps {
+		for j, page := range st.pages {
+			name := fmt.Sprintf("pages/%02d_%02d_%s_%s.html", i+1, j+1, page.Method, artifactName(page.Path))
+			files = append(files, [2]string{name, fmt.Sprintf("<!-- %s: %s %s -> %d -->\n%s", st.Name, page.Method, page.URL, page.Status, page.Body)})
+		}
+	}
+	for _, file := range files {
+		w, err := zw.Create(file[0])
+		if err != nil {
+			return fmt.Errorf("failed to write bundle: %w", err)
+		}
+		if _, err := io.WriteString(w, anonymize(file[1], s.Student)); err != nil {
+			return fmt.Errorf("failed to write bundle: %w", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"archive/zip"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDiagnoseBundle(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	portal.incompleteReports = 100
+	useMockPortal(t, portal)
+	withTerminal(t, false, "")
+	t.Setenv("UMT_STUDENT_ID", "F2023000000")
+	t.Setenv("UMT_PASSWORD", "hunter2")
+
+	bundle := filepath.Join(t.TempDir(), "bundle.zip")
+	c, _ := findCommand("diagnose")
+	var out strings.Builder
+	err := runCommand(c, []string{"--bundle", "--out", bundle, "--format", "csv"}, strings.NewReader(""), &out)
+	if got := exitCode(err); got != EXIT_PARSE_FAILURE {
+		t.Fatalf("exit code %d (%v), want %d", got, err, EXIT_PARSE_FAILURE)
+	}
+	if !strings.Contains(out.String(), "attendance CC2042,"+STEP_FAILED) || !strings.Contains(out.String(), "transcript,"+STEP_OK) {
+		t.Errorf("attendance report that never loads not flagged:\n%s", out.String())
+	}
+
+	zr, err := zip.OpenReader(bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zr.Close()
+	names := map[string]bool{}
+	var pages int
+	for _, f := range zr.File {
+		names[f.Name] = true
+		if strings.HasPrefix(f.Name, "pages/") {
+			pages++
+		}
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(r)
+		r.Close()
+		for _, pii := range []string{"F2023000000", "hunter2"} {
+			if strings.Contains(string(data), pii) {
+				t.Errorf("%s contains %q", f.Name, pii)
+			}
+		}
+	}
+	for _, want := range []string{"manifest.json", "steps.json", "requests.log"} {
+		if !names[want] {
+			t.Errorf("bundle has no %s", want)
+		}
+	}
+	if pages == 0 {
+		t.Error("bundle has no pages of the failed step")
+	}
+
+	page := `<input type="hidden" name="__VIEWSTATE" id="__VIEWSTATE" value="dDwtMTA4"><td>ALI RAZA</td><td>ali.raza@umt.edu.pk</td>`
+	want := `<input type="hidden" name="__VIEWSTATE" id="__VIEWSTATE" value="[redacted]"><td>[redacted]</td><td>[redacted]</td>`
+	if got := anonymize(page, Student{Name: "Ali Raza"}); got != want {
+		t.Errorf("anonymize =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,278 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// Each session remembers its last MAX_REQUEST_LOG portal requests, so a
+// failure can be reported with what led up to it; DIAGNOSTICS_HISTORY of
+// them go into the report.
+const (
+	MAX_REQUEST_LOG     = 50
+	DIAGNOSTICS_HISTORY = 15
+)
+
+type requestRecord struct {
+	Time     time.Time
+	Method   string
+	URL      string
+	Status   int
+	Duration time.Duration
+	Err      error
+}
+
+func (r requestRecord) failed() bool {
+	return r.Err != nil || r.Status >= http.StatusBadRequest
+}
+
+// requestLog is a bounded history of requests, safe for the concurrent
+// fetches of one session. A nil log records nothing.
+type requestLog struct {
+	mu      sync.Mutex
+	records []requestRecord
+}
+
+func (l *requestLog) add(r requestRecord) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.records = append(l.records, r)
+	if len(l.records) > MAX_REQUEST_LOG {
+		l.records = l.records[len(l.records)-MAX_REQUEST_LOG:]
+	}
+}
+
+func (l *requestLog) recent(n int) []requestRecord {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]requestRecord(nil), l.records[max(len(l.records)-n, 0):]...)
+}
+
+type loggingTransport struct {
+	log  *requestLog
+	next http.RoundTripper
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	r := requestRecord{Time: start, Method: req.Method, URL: scrubPII(req.URL.String()), Duration: time.Since(start), Err: err}
+	if resp != nil {
+		r.Status = resp.StatusCode
+	}
+	t.log.add(r)
+	return resp, err
+}
+
+// failedResult is implemented by messages that carry the outcome of a
+// fetch; failure returns what was being fetched and the error, if any.
+type failedResult interface {
+	failure() (action string, err error)
+}
+
+func (msg LoginResultMsg) failure() (string, error) {
+	switch msg.Code {
+	case ErrNetworkIssue, ErrParsingError:
+		return "login", errors.New(strings.TrimSpace(msg.Text))
+	}
+	return "login", nil
+}
+
+func (msg CoursesLoadedMsg) failure() (string, error) { return "courses", msg.Error }
+func (msg CourseActionMsg) failure() (string, error)  { return msg.Action, msg.Error }
+func (msg ResultsLoadedMsg) failure() (string, error) { return "results", msg.Error }
+func (msg FeesLoadedMsg) failure() (string, error)    { return "fees", msg.Error }
+func (msg ChallanSavedMsg) failure() (string, error)  { return "challan", msg.Error }
+func (msg OutlineLoadedMsg) failure() (string, error) { return "outline", msg.Error }
+
+func (msg TranscriptPrefetchedMsg) failure() (string, error) { return "transcript", msg.Error }
+func (msg TranscriptPDFSavedMsg) failure() (string, error)   { return "transcript PDF", msg.Error }
+
+func (msg NetworkLostMsg) failure() (string, error) {
+	if r, ok := msg.Msg.(failedResult); ok {
+		action, _ := r.failure()
+		return action, msg.Error
+	}
+	return "", msg.Error
+}
+
+// Diagnostics describes the last failure: what failed, the error with
+// everything it wraps, and the requests made up to it.
+type Diagnostics struct {
+	Time     time.Time
+	Action   string
+	Err      error
+	Requests []requestRecord
+	// Attempt is how many times the network recovery had retried the fetch.
+	Attempt int
+}
+
+func newDiagnostics(action string, err error, s *Session) *Diagnostics {
+	d := &Diagnostics{Time: time.Now(), Action: action, Err: err}
+	if s != nil {
+		d.Requests = s.requests.recent(DIAGNOSTICS_HISTORY)
+	}
+	return d
+}
+
+// errorChain lists err and everything it wraps, outermost first.
+func errorChain(err error) []string {
+	var chain []string
+	for ; err != nil; err = errors.Unwrap(err) {
+		chain = append(chain, fmt.Sprintf("%s (%T)", err, err))
+	}
+	return chain
+}
+
+// failedRequest is the request the failure came from: the one named by the
+// error if it wraps a *url.Error, otherwise the last failed request, or else
+// the last request made.
+func (d Diagnostics) failedRequest() (requestRecord, bool) {
+	var urlErr *url.Error
+	if errors.As(d.Err, &urlErr) {
+		for i := len(d.Requests) - 1; i >= 0; i-- {
+			if d.Requests[i].URL == scrubPII(urlErr.URL) {
+				return d.Requests[i], true
+			}
+		}
+		return requestRecord{Method: urlErr.Op, URL: urlErr.URL, Err: urlErr.Err}, true
+	}
+	for i := len(d.Requests) - 1; i >= 0; i-- {
+		if d.Requests[i].failed() {
+			return d.Requests[i], true
+		}
+	}
+	if len(d.Requests) > 0 {
+		return d.Requests[len(d.Requests)-1], true
+	}
+	return requestRecord{}, false
+}
+
+// Report formats d as plain text for an issue. It is in English whatever
+// the UI language, for whoever reads the issue.
+func (d Diagnostics) Report() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Action:  %s\n", d.Action)
+	fmt.Fprintf(&b, "Time:    %s\n", d.Time.Format(time.RFC3339))
+	fmt.Fprintf(&b, "Version: %s\n", readBuildInfo())
+	if d.Attempt > 0 {
+		fmt.Fprintf(&b, "Network retries: %d\n", d.Attempt)
+	}
+
+	b.WriteString("\nError:\n")
+	for i, e := range errorChain(d.Err) {
+		fmt.Fprintf(&b, "%s%s\n", strings.Repeat("  ", i+1), e)
+	}
+
+	if r, ok := d.failedRequest(); ok {
+		b.WriteString("\nRequest:\n")
+		fmt.Fprintf(&b, "  %s %s\n", r.Method, r.URL)
+		if r.Status != 0 {
+			fmt.Fprintf(&b, "  Status: %d %s\n", r.Status, http.StatusText(r.Status))
+		}
+	}
+
+	if len(d.Requests) > 0 {
+		b.WriteString("\nHistory:\n")
+		for _, r := range d.Requests {
+			outcome := fmt.Sprint(r.Status)
+			if r.Err != nil {
+				outcome = r.Err.Error()
+			}
+			fmt.Fprintf(&b, "  %s %-4s %s -> %s (%s)\n", r.Time.Format("15:04:05"), r.Method, r.URL, outcome, r.Duration.Round(time.Millisecond))
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// diagnosticsReport is the report of the last failure with the student's
+// credentials and identity removed, unless --include-pii.
+func (m model) diagnosticsReport() string {
+	if m.diagnostics == nil {
+		return ""
+	}
+	secrets := []string{m.Credentials.Password, m.Credentials.StudentID}
+	if m.session != nil {
+		st := m.session.GetStudent()
+		secrets = append(secrets, st.ID, st.Name, st.Email)
+	}
+	return scrubPII(m.diagnostics.Report(), secrets...)
+}
+
+type DiagnosticsCopiedMsg struct{}
+
+// copyToClipboard sets the terminal's clipboard with OSC 52, which also
+// works over SSH; terminals that don't support it ignore the sequence. done
+// is the message sent once it is set.
+func copyToClipboard(text string, done tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		termenv.Copy(text)
+		return done
+	}
+}
+
+// recordFailure keeps the diagnostics of msg if it reports a failure.
+// A later success of the same action clears them.
+func (m *model) recordFailure(msg failedResult) {
+	action, err := msg.failure()
+	if err == nil {
+		if m.diagnostics != nil && m.diagnostics.Action == action {
+			m.diagnostics, m.showDiagnostics = nil, false
+		}
+		return
+	}
+	s := m.session
+	if login, ok := msg.(LoginResultMsg); ok {
+		s = login.Session
+	}
+	m.diagnostics = newDiagnostics(action, err, s)
+	if m.recovery != nil {
+		m.diagnostics.Attempt = m.recovery.attempt
+	}
+	m.diagnosticsCopied = false
+}
+
+// renderDiagnostics is the one-line hint about the last failure, or the
+// full report once expanded.
+func (m model) renderDiagnostics() string {
+	d := m.diagnostics
+	if d == nil {
+		return ""
+	}
+	if !m.showDiagnostics {
+		return lipgloss.NewStyle().Foreground(DANGER).Render(T("diag.hint", d.Action, d.Err))
+	}
+
+	titleStyle := lipgloss.NewStyle().Foreground(DANGER).Bold(true)
+	paneStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(DANGER).
+		Padding(0, 1).
+		Width(max(min(m.width-4, 110), 20))
+	helpStyle := lipgloss.NewStyle().Foreground(GREY)
+
+	help := T("diag.help")
+	if m.diagnosticsCopied {
+		help = T("diag.copied") + " " + help
+	}
+	return paneStyle.Render(lipgloss.JoinVertical(lipgloss.Left,
+		titleStyle.Render(T("diag.title")),
+		m.diagnosticsReport(),
+		helpStyle.Render(help),
+	))
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestDiagnostics(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	useMockPortal(t, portal)
+
+	s := NewSession()
+	if code, text := s.Login(Credentials{StudentID: "F2023000000", Password: "hunter2"}, false); code != ErrNone {
+		t.Fatalf("login failed: %v %s", code, text)
+	}
+	portal.Close()
+
+	m := model{session: s, currentView: LoadingView, viewStack: []ViewType{CoursesView}, width: 120, height: 40}
+	next, _ := m.Update(feesCmd(s)())
+	m = next.(model)
+	if m.diagnostics == nil || m.diagnostics.Action != "fees" {
+		t.Fatalf("failure was not recorded: %+v", m.diagnostics)
+	}
+	if view := m.View(); !strings.Contains(view, "fees failed") {
+		t.Errorf("no diagnostics hint:\n%s", view)
+	}
+
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlE})
+	m = next.(model)
+	view := m.View()
+	for _, want := range []string{T("diag.title"), "GET " + FEES_URL, "*url.Error", "POST"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("diagnostics pane is missing %q:\n%s", want, view)
+		}
+	}
+
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m = next.(model); m.showDiagnostics || m.currentView != LoadingView {
+		t.Error("esc did not just close the pane")
+	}
+
+	// The next successful fetch of the same thing clears it.
+	next, _ = m.Update(FeesLoadedMsg{})
+	if next.(model).diagnostics != nil {
+		t.Error("diagnostics outlived a successful retry")
+	}
+}
+
+func TestRedact(t *testing.T) {
+	got := redact("GET https://portal/x?id=7&access_token=abc (F2023000000, Ali Raza)", "F2023000000", "Ali Raza", "")
+	want := "GET https://portal/x?access_token=%5Bredacted%5D&id=7 ([redacted], [redacted])"
+	if got != want {
+		t.Errorf("redact =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestScrubPII(t *testing.T) {
+	registerSecrets("s3cret-pass")
+	text := "id F2023000000 pw s3cret-pass cnic 35202-1234567-1 or 3520212345671 at https://portal/x?ASP.NET_SessionId=abc"
+	want := "id [redacted] pw [redacted] cnic [redacted] or [redacted] at https://portal/x?ASP.NET_SessionId=%5Bredacted%5D"
+	if got := scrubPII(text); got != want {
+		t.Errorf("scrubPII =\n%s\nwant\n%s", got, want)
+	}
+
+	includePII = true
+	t.Cleanup(func() { includePII = false })
+	if got := scrubPII(text); got != text {
+		t.Errorf("scrubPII with --include-pii = %s", got)
+	}
+}
+
+func TestDebugArtifactsRedacted(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: ".ASPXAUTH", Value: "auth-cookie-value"})
+		if r.URL.Path == "/challan.pdf" {
+			w.Header().Set("Content-Type", "application/pdf")
+			fmt.Fprint(w, "%PDF F2023000000")
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, "<span>F2023000000</span><span>35202-1234567-1</span><input value=\"auth-cookie-value\">")
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	client := &http.Client{Transport: &debugTransport{dir: dir, next: http.DefaultTransport}}
+	for _, path := range []string{"/profile", "/challan.pdf"} {
+		resp, err := client.Get(server.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+
+	saved, _ := filepath.Glob(filepath.Join(dir, "*"))
+	if len(saved) != 2 {
+		t.Fatalf("saved %d artifacts, want 2", len(saved))
+	}
+	for _, path := range saved {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, pii := range []string{"F2023000000", "35202-1234567-1", "auth-cookie-value"} {
+			if strings.Contains(string(data), pii) {
+				t.Errorf("%s contains %q:\n%s", filepath.Base(path), pii, data)
+			}
+		}
+	}
+}
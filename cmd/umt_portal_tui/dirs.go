@@ -0,0 +1,123 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// APP_DIR is the directory the app keeps its files in under each of the
+// user's config, cache and state directories.
+const APP_DIR = "umt_tui"
+
+// CACHE_DIR_ENV overrides the cache directory, like --cache-dir.
+const CACHE_DIR_ENV = "UMT_TUI_CACHE_DIR"
+
+// STATE_FILES are kept in the state directory. They used to be kept with
+// the caches, and are moved on the first run that finds them there.
+var STATE_FILES = []string{"creds.gob", "ui_state.json", "login_failures.json"}
+
+// cacheDirOverride is the --cache-dir flag.
+var cacheDirOverride string
+
+func configDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user config dir: %w", err)
+	}
+	return filepath.Join(dir, APP_DIR), nil
+}
+
+// cacheDir holds the cached portal data, which can always be fetched
+// again: --cache-dir, else $UMT_TUI_CACHE_DIR, else the user cache
+// directory.
+func cacheDir() (string, error) {
+	if cacheDirOverride != "" {
+		return cacheDirOverride, nil
+	}
+	if dir := os.Getenv(CACHE_DIR_ENV); dir != "" {
+		return dir, nil
+	}
+	return defaultCacheDir()
+}
+
+func defaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user cache dir: %w", err)
+	}
+	return filepath.Join(dir, APP_DIR), nil
+}
+
+// stateDir holds what should outlive a cleared cache: saved credentials,
+// the UI state and the login lockout. Where the XDG spec applies that is
+// $XDG_STATE_HOME, by default ~/.local/state; elsewhere there is no such
+// directory and it is the default cache directory.
+func stateDir() (string, error) {
+	switch runtime.GOOS {
+	case "windows", "darwin", "ios", "plan9":
+		return defaultCacheDir()
+	}
+	if dir := os.Getenv("XDG_STATE_HOME"); filepath.IsAbs(dir) {
+		return filepath.Join(dir, APP_DIR), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user state dir: %w", err)
+	}
+	return filepath.Join(home, ".local", "state", APP_DIR), nil
+}
+
+// migrateStateFiles moves the STATE_FILES left in the default cache
+// directory by older versions into the state directory. A file already in
+// the state directory wins.
+func migrateStateFiles() error {
+	from, err := defaultCacheDir()
+	if err != nil {
+		return err
+	}
+	to, err := stateDir()
+	if err != nil || from == to {
+		return err
+	}
+	for _, name := range STATE_FILES {
+		old := filepath.Join(from, name)
+		if _, err := os.Stat(old); err != nil {
+			continue
+		}
+		path := filepath.Join(to, name)
+		if _, err := os.Stat(path); !errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err := os.MkdirAll(to, 0700); err != nil {
+			return fmt.Errorf("failed to create state dir: %w", err)
+		}
+		if err := moveFile(old, path); err != nil {
+			return fmt.Errorf("failed to move %s to %s: %w", name, to, err)
+		}
+	}
+	return nil
+}
+
+// moveFile renames old to path, copying it when they are on different
+// file systems.
+func moveFile(old, path string) error {
+	if err := os.Rename(old, path); err == nil {
+		return nil
+	}
+	info, err := os.Stat(old)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(old)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, info.Mode().Perm()); err != nil {
+		return err
+	}
+	return os.Remove(old)
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestCacheDirOverride(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(dir, "cache"))
+	t.Setenv(CACHE_DIR_ENV, filepath.Join(dir, "env"))
+	prev := cacheDirOverride
+	t.Cleanup(func() { cacheDirOverride = prev })
+
+	cacheDirOverride = ""
+	if got, _ := dataCachePath(); got != filepath.Join(dir, "env", "data.json") {
+		t.Errorf("with %s: data cache at %s", CACHE_DIR_ENV, got)
+	}
+	cacheDirOverride = filepath.Join(dir, "flag")
+	if got, _ := transcriptCachePath(); got != filepath.Join(dir, "flag", "transcript.json") {
+		t.Errorf("with --cache-dir: transcript cache at %s", got)
+	}
+	if got, _ := uiStatePath(); filepath.Dir(got) == cacheDirOverride {
+		t.Errorf("UI state moved with the cache to %s", got)
+	}
+}
+
+func TestMigrateStateFiles(t *testing.T) {
+	switch runtime.GOOS {
+	case "windows", "darwin", "ios", "plan9":
+		t.Skip("no separate state directory on " + runtime.GOOS)
+	}
+	dir := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(dir, "cache"))
+	t.Setenv("XDG_STATE_HOME", filepath.Join(dir, "state"))
+
+	old := filepath.Join(dir, "cache", APP_DIR)
+	if err := os.MkdirAll(old, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(old, "creds.gob"), []byte("creds"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(old, "data.json"), []byte("{}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := migrateStateFiles(); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "state", APP_DIR, "creds.gob")); err != nil || string(data) != "creds" {
+		t.Errorf("credentials not moved to the state dir: %q, %v", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(old, "creds.gob")); err == nil {
+		t.Error("credentials left in the cache dir")
+	}
+	if _, err := os.Stat(filepath.Join(old, "data.json")); err != nil {
+		t.Errorf("cached data moved: %v", err)
+	}
+	if err := migrateStateFiles(); err != nil {
+		t.Errorf("second run: %v", err)
+	}
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// DISPUTE_MAX_ROWS is how many absences the dispute picker shows at a time.
+const DISPUTE_MAX_ROWS = 10
+
+// disputeDraft is an email asking a course's faculty to correct an absence
+// that should have been marked present, or to recheck an assessment.
+type disputeDraft struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// DisputeDraftMsg reports a draft, of an attendance dispute or an
+// assessment recheck, opened in the mail client or copied to the
+// clipboard. Text is the draft, copied instead when Error is set.
+type DisputeDraftMsg struct {
+	Text   string
+	Copied bool
+	Error  error
+}
+
+// facultyGreeting is who a draft to the course's faculty is addressed to.
+func facultyGreeting(c Course) string {
+	if c.FacultyName == "" {
+		return "Sir/Madam"
+	}
+	return c.FacultyName
+}
+
+func newDisputeDraft(st Student, c Course, a Attendance) disputeDraft {
+	course := c.Code + " " + c.Title
+	if c.Section != "" {
+		course += ", section " + c.Section
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Dear %s,\n\n", facultyGreeting(c))
+	fmt.Fprintf(&b, "I have been marked absent for lecture %d of %s on %s, but I attended that class. ", a.LectureNumber, course, a.LectureDate)
+	b.WriteString("Could you please check the record and correct my attendance?\n\n")
+	fmt.Fprintf(&b, "Regards,\n%s\n%s\n", st.Name, st.ID)
+
+	return disputeDraft{
+		To:      c.FacultyEmail,
+		Subject: fmt.Sprintf("Attendance correction: %s lecture %d (%s)", c.Code, a.LectureNumber, a.LectureDate),
+		Body:    b.String(),
+	}
+}
+
+// mailto is the draft as a mailto: link, which opens it in the mail
+// client. Spaces are sent as %20, as some clients show a "+" literally.
+func (d disputeDraft) mailto() string {
+	query := url.Values{"subject": {d.Subject}, "body": {d.Body}}.Encode()
+	return "mailto:" + d.To + "?" + strings.ReplaceAll(query, "+", "%20")
+}
+
+func (d disputeDraft) String() string {
+	return fmt.Sprintf("To: %s\nSubject: %s\n\n%s", d.To, d.Subject, d.Body)
+}
+
+// absences are the absences of the selected course that the attendance
+// filter shows.
+func (m model) absences() []Attendance {
+	var absent []Attendance
+	for _, a := range m.filteredAttendance() {
+		if !a.Attendance {
+			absent = append(absent, a)
+		}
+	}
+	return absent
+}
+
+func (m *model) openDisputePicker() {
+	m.attendanceStatus = ""
+	if len(m.absences()) == 0 {
+		m.attendanceStatus = T("dispute.none")
+		return
+	}
+	m.disputePicker = true
+	m.pickerAbsence = 0
+}
+
+// selectedDispute drafts the email for the highlighted absence.
+func (m model) selectedDispute() (disputeDraft, bool) {
+	absent := m.absences()
+	if m.pickerAbsence >= len(absent) || m.selectedCourse >= len(m.courses) {
+		return disputeDraft{}, false
+	}
+	var st Student
+	if m.session != nil {
+		st = m.session.GetStudent()
+	}
+	return newDisputeDraft(st, m.courses[m.selectedCourse], absent[m.pickerAbsence]), true
+}
+
+func (m model) handleDisputeKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	last := len(m.absences()) - 1
+	switch msg.String() {
+	case "ctrl+c":
+		if !m.rememberMe {
+			deleteCaches()
+		}
+		return m, tea.Quit
+	case "esc", "d", "q":
+		m.disputePicker = false
+	case "up", "k":
+		if m.pickerAbsence > 0 {
+			m.pickerAbsence--
+		}
+	case "down", "j":
+		if m.pickerAbsence < last {
+			m.pickerAbsence++
+		}
+	case "enter":
+		if draft, ok := m.selectedDispute(); ok {
+			m.disputePicker = false
+			return m, func() tea.Msg {
+				return DisputeDraftMsg{Text: draft.String(), Error: openURL(draft.mailto())}
+			}
+		}
+	case "c":
+		if draft, ok := m.selectedDispute(); ok {
+			m.disputePicker = false
+			return m, copyToClipboard(draft.String(), DisputeDraftMsg{Copied: true})
+		}
+	}
+	return m, nil
+}
+
+func (m model) renderDisputePicker() string {
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(ACCENT).
+		MarginBottom(1)
+
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(WHITE).
+		Background(BLUE)
+
+	normalStyle := lipgloss.NewStyle().
+		Foreground(SILVER)
+
+	moreStyle := lipgloss.NewStyle().
+		Foreground(GREY)
+
+	draftStyle := lipgloss.NewStyle().
+		Foreground(SILVER).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(GREY).
+		Padding(0, 1).
+		MarginTop(1)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(GREY).
+		MarginTop(1)
+
+	absent := m.absences()
+	rows := DISPUTE_MAX_ROWS
+	if height := m.bodyHeight(); height > 0 {
+		rows = min(max(height-16, 3), rows)
+	}
+	start, end := pickerWindow(m.pickerAbsence, len(absent), rows)
+
+	var lines []string
+	if start > 0 {
+		lines = append(lines, moreStyle.Render(T("picker.more_above", start)))
+	}
+	for i := start; i < end; i++ {
+		line := T("dispute.lecture", absent[i].LectureNumber, absent[i].LectureDate)
+		if i == m.pickerAbsence {
+			lines = append(lines, selectedStyle.Render("→ "+line))
+		} else {
+			lines = append(lines, normalStyle.Render("  "+line))
+		}
+	}
+	if end < len(absent) {
+		lines = append(lines, moreStyle.Render(T("picker.more_below", len(absent)-end)))
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Center,
+		titleStyle.Render(T("dispute.title", m.courses[m.selectedCourse].Code)),
+		lipgloss.JoinVertical(lipgloss.Left, strings.Join(lines, "\n")),
+	)
+	if draft, ok := m.selectedDispute(); ok {
+		to := draft.To
+		if to == "" {
+			to = T("dispute.no_email")
+		}
+		width := min(max(m.width-8, 30), 80)
+		preview := fitText(T("dispute.to", to), width) + "\n" + fitText(T("dispute.subject", draft.Subject), width)
+		content = lipgloss.JoinVertical(lipgloss.Center, content, draftStyle.Render(preview))
+	}
+	content = lipgloss.JoinVertical(lipgloss.Center, content, helpStyle.Render(wrapHelp(T("dispute.help"), m.width)))
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, content)
+}
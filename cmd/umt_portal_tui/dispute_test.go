@@ -0,0 +1,84 @@
+package main
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestDisputeAbsence(t *testing.T) {
+	var opened []string
+	prev := openURL
+	t.Cleanup(func() { openURL = prev })
+	openURL = func(link string) error {
+		opened = append(opened, link)
+		return nil
+	}
+
+	s := NewSession()
+	s.Student = Student{Name: "Ali Raza", ID: "F2023000000"}
+	course := Course{ID: "42", Code: "CC2042", Title: "Database Systems", Section: "B",
+		FacultyName: "Dr. Ayesha Khan", FacultyEmail: "ayesha.khan@umt.edu.pk",
+		Attendance: []Attendance{
+			{LectureNumber: 1, LectureDate: "02-Sep-2025", Attendance: false},
+			{LectureNumber: 2, LectureDate: "04-Sep-2025", Attendance: true},
+			{LectureNumber: 3, LectureDate: "09-Sep-2025", Attendance: false},
+		},
+	}
+	m := model{session: s, courses: []Course{course}, currentView: AttendanceView, width: 120, height: 40}
+	press := func(key string) tea.Cmd {
+		t.Helper()
+		next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		m = next.(model)
+		return cmd
+	}
+
+	press("d")
+	if !m.disputePicker || !strings.Contains(m.View(), "Lecture 3  09-Sep-2025") {
+		t.Fatalf("d did not list the absences:\n%s", m.View())
+	}
+	press("j")
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = next.(model)
+	if cmd == nil {
+		t.Fatal("enter drafted nothing")
+	}
+	next, _ = m.Update(cmd())
+	m = next.(model)
+
+	if len(opened) != 1 {
+		t.Fatalf("opened %v", opened)
+	}
+	link, err := url.Parse(opened[0])
+	if err != nil || link.Scheme != "mailto" || link.Opaque != "ayesha.khan@umt.edu.pk" {
+		t.Fatalf("not a mailto link to the faculty: %s", opened[0])
+	}
+	if strings.Contains(link.RawQuery, "+") {
+		t.Errorf("spaces sent as +: %s", link.RawQuery)
+	}
+	query := link.Query()
+	if got, want := query.Get("subject"), "Attendance correction: CC2042 lecture 3 (09-Sep-2025)"; got != want {
+		t.Errorf("subject %q, want %q", got, want)
+	}
+	body := query.Get("body")
+	for _, want := range []string{"Dear Dr. Ayesha Khan", "lecture 3 of CC2042 Database Systems, section B on 09-Sep-2025", "Ali Raza\nF2023000000"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body lacks %q:\n%s", want, body)
+		}
+	}
+	if m.disputePicker || m.attendanceStatus != T("dispute.opened") {
+		t.Errorf("picker %v, status %q", m.disputePicker, m.attendanceStatus)
+	}
+
+	// Without a mail client the draft goes to the clipboard.
+	openURL = func(string) error { return errors.New("no mail client") }
+	press("d")
+	next, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	next, _ = next.(model).Update(cmd())
+	if got := next.(model).attendanceStatus; got != T("dispute.open_failed", "no mail client") {
+		t.Errorf("status %q", got)
+	}
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/url"
+	"os"
+	"time"
+)
+
+// Check results of doctor.
+const (
+	CHECK_PASS = "pass"
+	CHECK_FAIL = "fail"
+	CHECK_SKIP = "skip"
+)
+
+// portalEndpoint is a portal page the app depends on.
+type portalEndpoint struct {
+	Name string
+	URL  string
+}
+
+var PORTAL_ENDPOINTS = []portalEndpoint{
+	{"login", UMT_LOGIN_URL},
+	{"MyCourses", UMT_COURSES_URL},
+	{"Attendance.aspx", COURSES_VIEW_ATTENDANCE_ASPX_URL},
+	{"Transcript.aspx", TRANSCRIPT_ASPX_URL},
+}
+
+// lookupHost resolves portal hosts; tests replace it.
+var lookupHost = net.DefaultResolver.LookupHost
+
+type doctorCheck struct {
+	Check  string `json:"check"`
+	Result string `json:"result"`
+	Detail string `json:"detail"`
+}
+
+// runDoctor checks what the app needs from the machine and the network and
+// exits non-zero when any check fails.
+func runDoctor(s *Session, args []string) (Output, error) {
+	if len(args) > 0 {
+		return Output{}, withExitCode(EXIT_USAGE, fmt.Errorf("unexpected arguments"))
+	}
+
+	var checks []doctorCheck
+	add := func(check, result, detail string) {
+		checks = append(checks, doctorCheck{check, result, detail})
+	}
+
+	resolved := map[string]bool{}
+	for _, e := range PORTAL_ENDPOINTS {
+		u, err := url.Parse(e.URL)
+		if err != nil || resolved[u.Hostname()] {
+			continue
+		}
+		resolved[u.Hostname()] = true
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		addrs, err := lookupHost(ctx, u.Hostname())
+		cancel()
+		if err != nil {
+			add("dns "+u.Hostname(), CHECK_FAIL, err.Error())
+		} else {
+			add("dns "+u.Hostname(), CHECK_PASS, fmt.Sprint(addrs))
+		}
+	}
+
+	for _, e := range PORTAL_ENDPOINTS {
+		result, detail := checkEndpoint(s, e)
+		add("reach "+e.Name, result, detail)
+	}
+
+	for _, dir := range []struct {
+		name string
+		path func() (string, error)
+	}{{"cache dir", cacheDir}, {"state dir", stateDir}, {"config dir", configDir}} {
+		path, err := dir.path()
+		if err == nil {
+			err = checkWritable(path)
+		}
+		if err != nil {
+			add(dir.name, CHECK_FAIL, err.Error())
+		} else {
+			add(dir.name, CHECK_PASS, path)
+		}
+	}
+
+	creds, err := LoadCreds()
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		add("saved credentials", CHECK_SKIP, "none saved")
+	case err != nil && appConfig.Credentials.usesPass():
+		add("saved credentials", CHECK_FAIL, err.Error())
+	case err != nil:
+		add("saved credentials", CHECK_FAIL, fmt.Sprintf("unreadable, log in with \"Remember me\" again: %v", err))
+	case !validStudentID.MatchString(creds.StudentID):
+		add("saved credentials", CHECK_FAIL, fmt.Sprintf("student ID %q is not F or S, a year and 6 digits", creds.StudentID))
+	case creds.Password == "":
+		add("saved credentials", CHECK_FAIL, "no password")
+	default:
+		add("saved credentials", CHECK_PASS, creds.StudentID)
+	}
+
+	if env := (Credentials{StudentID: os.Getenv("UMT_STUDENT_ID"), Password: os.Getenv("UMT_PASSWORD")}); env.StudentID != "" && env.Password != "" {
+		creds, err = env, nil
+	}
+	if err != nil || creds.StudentID == "" || creds.Password == "" {
+		add("login", CHECK_SKIP, "no credentials; set UMT_STUDENT_ID and UMT_PASSWORD or save them in the TUI")
+	} else {
+		start := time.Now()
+		code, text := s.Login(creds, false)
+		elapsed := time.Since(start).Round(time.Millisecond)
+		switch code {
+		case ErrNone:
+			add("login", CHECK_PASS, elapsed.String())
+		case ErrCaptchaRequired:
+			add("login", CHECK_SKIP, "the portal asks for a captcha; log in from the TUI")
+		case ErrInvalidCredentials:
+			add("login", CHECK_FAIL, "invalid credentials")
+		default:
+			add("login", CHECK_FAIL, fmt.Sprintf("%s after %s", text, elapsed))
+		}
+	}
+
+	out := Output{Header: []string{"check", "result", "detail"}, Value: checks}
+	failed := 0
+	for _, c := range checks {
+		out.Rows = append(out.Rows, []string{c.Check, c.Result, c.Detail})
+		if c.Result == CHECK_FAIL {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return out, fmt.Errorf("%d of %d checks failed", failed, len(checks))
+	}
+	return out, nil
+}
+
+// checkEndpoint requests e without logging in or following redirects; any
+// answer short of a server error means the portal is reachable over TLS.
+func checkEndpoint(s *Session, e portalEndpoint) (string, string) {
+	p := probeEndpoint(s, e)
+	if p.Err != nil {
+		var certErr *tls.CertificateVerificationError
+		if errors.As(p.Err, &certErr) {
+			return CHECK_FAIL, fmt.Sprintf("TLS certificate rejected: %v (see ca_cert_file in the config)", certErr.Err)
+		}
+		return CHECK_FAIL, p.Err.Error()
+	}
+	detail := fmt.Sprintf("%d in %s (connect %s, server %s)", p.Status,
+		p.Duration.Round(time.Millisecond), p.Connect.Round(time.Millisecond), p.Wait.Round(time.Millisecond))
+	if v := p.tlsDetail(); v != "" {
+		detail += ", " + v
+	}
+	return CHECK_PASS, detail
+}
+
+// checkWritable creates dir if needed and writes and removes a file in it.
+func checkWritable(dir string) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(dir, ".doctor-*")
+	if err != nil {
+		return err
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestDoctor(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	useMockPortal(t, portal)
+	t.Setenv("UMT_STUDENT_ID", "")
+	t.Setenv("UMT_PASSWORD", "")
+	prevLookup := lookupHost
+	t.Cleanup(func() { lookupHost = prevLookup })
+	lookupHost = func(ctx context.Context, host string) ([]string, error) { return []string{"127.0.0.1"}, nil }
+
+	c, _ := findCommand("doctor")
+	run := func() (string, error) {
+		var out strings.Builder
+		err := runCommand(c, []string{"--format", "csv"}, strings.NewReader(""), &out)
+		return out.String(), err
+	}
+
+	out, err := run()
+	if err != nil {
+		t.Fatalf("doctor failed without saved credentials: %v\n%s", err, out)
+	}
+	for _, want := range []string{"dns online.umt.edu.pk,pass", "reach Transcript.aspx,pass", "state dir,pass", "saved credentials,skip", "login,skip"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("no %q in:\n%s", want, out)
+		}
+	}
+
+	if err := SaveCreds(Credentials{StudentID: "F2023000000", Password: "hunter2"}); err != nil {
+		t.Fatal(err)
+	}
+	if out, err = run(); err != nil || !strings.Contains(out, "login,pass") {
+		t.Errorf("doctor with saved credentials: %v\n%s", err, out)
+	}
+
+	if err := SaveCreds(Credentials{StudentID: "2023000000", Password: "hunter2"}); err != nil {
+		t.Fatal(err)
+	}
+	if out, err = run(); err == nil || !strings.Contains(out, "saved credentials,fail") {
+		t.Errorf("malformed student ID passed: %v\n%s", err, out)
+	}
+	if err := runCommand(c, []string{"extra"}, strings.NewReader(""), io.Discard); err == nil {
+		t.Error("doctor accepted arguments")
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"errors"
+	"net"
+)
+
+// Process exit codes for the CLI subcommands, so scripts can branch on the
+// kind of failure.
+const (
+	EXIT_OK                  = 0
+	EXIT_FAILURE             = 1
+	EXIT_INVALID_CREDENTIALS = 2
+	EXIT_NETWORK_FAILURE     = 3
+	EXIT_PARSE_FAILURE       = 4
+	EXIT_BELOW_THRESHOLD     = 5
+	EXIT_CHANGED             = 6
+	EXIT_PAYMENT_DUE         = 7
+	EXIT_USAGE               = 64
+)
+
+// exitError attaches an exit code to an error.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+func (e *exitError) Unwrap() error { return e.err }
+
+func withExitCode(code int, err error) error {
+	return &exitError{code: code, err: err}
+}
+
+// exitCode maps an error returned by a subcommand to its exit code.
+func exitCode(err error) int {
+	var ee *exitError
+	var netErr net.Error
+	switch {
+	case err == nil:
+		return EXIT_OK
+	case errors.As(err, &ee):
+		return ee.code
+	case errors.Is(err, errUsage):
+		return EXIT_USAGE
+	case errors.Is(err, errNoCredentials):
+		return EXIT_INVALID_CREDENTIALS
+	case errors.As(err, &netErr):
+		return EXIT_NETWORK_FAILURE
+	case errors.Is(err, errReportIncomplete):
+		return EXIT_PARSE_FAILURE
+	default:
+		return EXIT_FAILURE
+	}
+}
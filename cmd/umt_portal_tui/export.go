@@ -0,0 +1,363 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// A rendered ReportViewer page carries the URL its export menu uses, under
+// Reserved.ReportViewerWebControl.axd. The CSV export has one line per
+// detail row with a column per textbox, named after the textbox, which is
+// steadier than walking the HTML rendering's tablix cells by position. When
+// a page has no export URL or the CSV lacks a required column, the fetches
+// fall back to scraping the HTML.
+
+var exportURLPattern = regexp.MustCompile(`"ExportUrlBase":("(?:[^"\\]|\\.)*")`)
+
+var errNoExport = errors.New("report has no export URL")
+
+// errExportFormat is returned for an export that doesn't have the expected
+// columns.
+var errExportFormat = errors.New("unexpected report export format")
+
+// reportExportURL returns the URL exporting the report rendered in doc as
+// format, e.g. "CSV" or "PDF".
+func reportExportURL(doc *goquery.Document, format string) (string, error) {
+	match := exportURLPattern.FindStringSubmatch(doc.Find("script").Text())
+	if match == nil {
+		return "", errNoExport
+	}
+	var base string
+	if err := json.Unmarshal([]byte(match[1]), &base); err != nil {
+		return "", fmt.Errorf("invalid export URL: %w", err)
+	}
+	_, query, ok := strings.Cut(base, "/Reserved.ReportViewerWebControl.axd?")
+	if !ok || !strings.HasSuffix(query, "Format=") {
+		return "", fmt.Errorf("unexpected export URL %q", base)
+	}
+	return REPORT_VIEWER_AXD_URL + query + format, nil
+}
+
+// exportReport requests the export of the report rendered in doc. The
+// caller closes the response body.
+func (s *Session) exportReport(doc *goquery.Document, format string) (*http.Response, error) {
+	exportURL, err := reportExportURL(doc, format)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest("GET", exportURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create export request: %w", err)
+	}
+	for _, cookie := range s.Cookies {
+		req.AddCookie(cookie)
+	}
+	resp, err := s.httpClient(OP_OTHER).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export report: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to export report: %s", resp.Status)
+	}
+	return resp, nil
+}
+
+// csvTable is a CSV export with its columns looked up by name.
+type csvTable struct {
+	columns map[string]int
+	rows    [][]string
+}
+
+// exportColumnName normalizes a textbox name: "Cr. Hrs" and "CrHrs" are the
+// same column.
+func exportColumnName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, strings.ToLower(name))
+}
+
+func readCSVTable(r io.Reader) (csvTable, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return csvTable{}, fmt.Errorf("failed to read report export: %w", err)
+	}
+	if len(records) == 0 {
+		return csvTable{}, errExportFormat
+	}
+
+	table := csvTable{columns: map[string]int{}, rows: records[1:]}
+	for i, name := range records[0] {
+		table.columns[exportColumnName(strings.TrimPrefix(name, "\ufeff"))] = i
+	}
+	return table, nil
+}
+
+// column returns the index of the first of names the export has, or -1.
+func (t csvTable) column(names ...string) int {
+	for _, name := range names {
+		if i, ok := t.columns[name]; ok {
+			return i
+		}
+	}
+	return -1
+}
+
+func csvCell(row []string, i int) string {
+	if i < 0 || i >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[i])
+}
+
+func parseAttendanceCSV(r io.Reader) (AttendanceReport, error) {
+	var report AttendanceReport
+	table, err := readCSVTable(r)
+	if err != nil {
+		return report, err
+	}
+
+	lecture := table.column("lectureno", "lecture", "lecturenumber")
+	status := table.column("attendance", "status")
+	if lecture < 0 || status < 0 {
+		return report, errExportFormat
+	}
+	date := table.column("lecturedate", "date")
+	faculty := table.column("faculty", "facultyname", "teacher")
+	total := table.column("totallectures")
+	percentage := table.column("attendancepercentage", "percentage")
+
+	present := 0
+	for _, row := range table.rows {
+		number, err := strconv.Atoi(strings.TrimPrefix(csvCell(row, lecture), "Lecture No. "))
+		if err != nil {
+			continue
+		}
+		a := Attendance{
+			LectureNumber: number,
+			LectureDate:   csvCell(row, date),
+			Attendance:    strings.EqualFold(csvCell(row, status), "Present"),
+			Faculty:       csvCell(row, faculty),
+		}
+		if a.Attendance {
+			present++
+		}
+		report.Records = append(report.Records, a)
+
+		if n, err := strconv.Atoi(attendancePercentagePattern.FindString(csvCell(row, total))); err == nil {
+			report.TotalLectures = n
+		}
+		if p, err := strconv.ParseFloat(attendancePercentagePattern.FindString(csvCell(row, percentage)), 64); err == nil {
+			report.AttendancePercentage = p
+		}
+	}
+	if len(report.Records) == 0 {
+		return report, errReportIncomplete
+	}
+
+	if total < 0 {
+		report.TotalLectures = len(report.Records)
+	}
+	if percentage < 0 {
+		report.AttendancePercentage = float64(present) * 100 / float64(len(report.Records))
+	}
+	return report, nil
+}
+
+func parseTranscriptCSV(r io.Reader) (Transcript, error) {
+	var transcript Transcript
+	table, err := readCSVTable(r)
+	if err != nil {
+		return transcript, err
+	}
+
+	semester := table.column("semester", "semestername")
+	code := table.column("coursecode", "code")
+	title := table.column("coursetitle", "title")
+	credits := table.column("crhrs", "credithours")
+	grade := table.column("grade")
+	if semester < 0 || code < 0 || title < 0 || credits < 0 || grade < 0 {
+		return transcript, errExportFormat
+	}
+	gradePoint := table.column("gp", "gradepoint")
+	semEarned := table.column("semcrhrsearned", "semestercredithoursearned")
+	semCGPA := table.column("semcgpa", "semestercgpa")
+	sgpa := table.column("sgpa")
+	earned := table.column("crhrsearned", "credithoursearned")
+	forGPA := table.column("crhrsforgpa", "credithoursforgpa")
+	totalGP := table.column("totalgradepoints", "totalgp")
+	cgpa := table.column("cgpa")
+
+	float := func(s string) float32 {
+		f, _ := strconv.ParseFloat(s, 32)
+		return float32(f)
+	}
+
+	// Rows of one semester repeat its stats; the first row of each has them.
+	var order []string
+	stats := map[string]Semester{}
+	courses := map[string][]TranscriptCourse{}
+	for _, row := range table.rows {
+		name := csvCell(row, semester)
+		hours, err := strconv.Atoi(csvCell(row, credits))
+		if name == "" || err != nil {
+			continue
+		}
+		if _, ok := stats[name]; !ok {
+			order = append(order, name)
+			earnedHours, _ := strconv.Atoi(csvCell(row, semEarned))
+			stats[name] = Semester{Name: name, CreditHoursEarned: earnedHours, CGPA: float(csvCell(row, semCGPA)), SGPA: float(csvCell(row, sgpa))}
+		}
+
+		c := TranscriptCourse{Code: csvCell(row, code), Title: csvCell(row, title), CreditHours: hours, Grade: csvCell(row, grade)}
+		if !isZeroGradePointGrade(c.Grade) {
+			c.GradePoint = float(csvCell(row, gradePoint))
+		}
+		courses[name] = append(courses[name], c)
+
+		transcript.CreditHoursEarned = csvCell(row, earned)
+		transcript.CreditHoursForGPA = csvCell(row, forGPA)
+		transcript.TotalGradePoints = csvCell(row, totalGP)
+		if total, _, _ := strings.Cut(csvCell(row, cgpa), " /"); total != "" {
+			transcript.TotalCGPA = strings.TrimSpace(total)
+		}
+	}
+	if len(order) == 0 {
+		return transcript, fmt.Errorf("no transcript data found in export: %w", errReportIncomplete)
+	}
+
+	transcript.Semester = make(map[Semester][]TranscriptCourse, len(order))
+	for _, name := range order {
+		transcript.Semester[stats[name]] = courses[name]
+	}
+	if transcript.CreditHoursEarned == "" {
+		earned := 0
+		for _, sem := range stats {
+			earned += sem.CreditHoursEarned
+		}
+		transcript.CreditHoursEarned = strconv.Itoa(earned)
+	}
+	applyRepeatPolicy(&transcript)
+	transcript.fillTotals()
+	return transcript, nil
+}
+
+// readAttendanceReport reads a rendered attendance report from its CSV
+// export, or from its HTML when it can't be exported.
+func (s *Session) readAttendanceReport(page io.Reader) (AttendanceReport, error) {
+	doc, err := goquery.NewDocumentFromReader(limitReport(page))
+	if err != nil {
+		return AttendanceReport{}, fmt.Errorf("failed to parse attendance report HTML: %w", err)
+	}
+	if resp, err := s.exportReport(doc, "CSV"); err == nil {
+		report, err := parseAttendanceCSV(limitReport(resp.Body))
+		resp.Body.Close()
+		if err == nil {
+			return report, nil
+		}
+	}
+	return attendanceFromDoc(doc)
+}
+
+// readTranscriptReport is readAttendanceReport for the transcript.
+func (s *Session) readTranscriptReport(page io.Reader) (Transcript, error) {
+	doc, err := goquery.NewDocumentFromReader(limitReport(page))
+	if err != nil {
+		return Transcript{}, fmt.Errorf("failed to parse HTML document: %w", err)
+	}
+	if resp, err := s.exportReport(doc, "CSV"); err == nil {
+		transcript, err := parseTranscriptCSV(limitReport(resp.Body))
+		resp.Body.Close()
+		if err == nil {
+			return transcript, nil
+		}
+	}
+	return transcriptFromDoc(doc)
+}
+
+// downloadTranscriptPDF saves the official transcript, the PDF export of the
+// transcript report with the university's stamp, into dir and returns its
+// path.
+func (s *Session) downloadTranscriptPDF(dir string) (string, error) {
+	if len(s.Cookies) == 0 {
+		return "", fmt.Errorf("no cookies found during downloading transcript")
+	}
+	client := s.httpClient(OP_OTHER)
+	get := func(pageURL string) (*http.Response, error) {
+		req, err := http.NewRequest("GET", pageURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, cookie := range s.Cookies {
+			req.AddCookie(cookie)
+		}
+		return client.Do(req)
+	}
+
+	resp, err := get(TRANSCRIPT_URL)
+	if err != nil {
+		return "", fmt.Errorf("failed to get transcript page: %w", err)
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	resp, err = get(TRANSCRIPT_ASPX_URL)
+	if err != nil {
+		return "", fmt.Errorf("failed to get transcript ASPX page: %w", err)
+	}
+	doc, err := goquery.NewDocumentFromReader(limitReport(resp.Body))
+	resp.Body.Close()
+	if err != nil {
+		return "", fmt.Errorf("failed to parse HTML document: %w", err)
+	}
+
+	resp, err = s.exportReport(doc, "PDF")
+	if errors.Is(err, errNoExport) {
+		return "", fmt.Errorf("the transcript report has no PDF export: %w", err)
+	}
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(limitReport(resp.Body))
+	if err != nil {
+		return "", fmt.Errorf("failed to read transcript PDF: %w", err)
+	}
+	if !strings.HasPrefix(string(data), "%PDF") {
+		return "", fmt.Errorf("the portal did not return a PDF for the transcript")
+	}
+
+	name := "transcript_" + s.Student.ID + ".pdf"
+	if s.Student.ID == "" {
+		name = "transcript.pdf"
+	}
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil && params["filename"] != "" {
+		name = filepath.Base(params["filename"])
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create download dir: %w", err)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to save transcript: %w", err)
+	}
+	return path, nil
+}
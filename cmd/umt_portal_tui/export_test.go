@@ -0,0 +1,85 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestReportExport reads the reports through their CSV export, which must
+// come out the same as scraping their HTML.
+func TestReportExport(t *testing.T) {
+	wantAttendance, err := parseAttendanceReport(openFixture(t, "attendance_report.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantTranscript, err := parseTranscriptReport(openFixture(t, "transcript_report.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	portal.exports = true
+	useMockPortal(t, portal)
+
+	s := NewSession()
+	if code, text := s.Login(Credentials{StudentID: "F2023000000", Password: "hunter2"}, false); code != ErrNone {
+		t.Fatalf("login failed: %v %s", code, text)
+	}
+	courses, err := s.GetCourses()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.GetCourseAttendance(true, courses[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	course := s.Student.Courses[0]
+	got := AttendanceReport{TotalLectures: course.TotalLectures, AttendancePercentage: course.AttendancePercentage, Records: course.Attendance}
+	if !reflect.DeepEqual(got, wantAttendance) {
+		t.Errorf("exported attendance\n%+v\nwant\n%+v", got, wantAttendance)
+	}
+
+	if err := s.GetTranscript(true); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(s.Student.Transcript, wantTranscript) {
+		t.Errorf("exported transcript\n%+v\nwant\n%+v", s.Student.Transcript, wantTranscript)
+	}
+
+	// A request without the session's cookies is sent to the login page
+	// and the HTML is scraped instead, with the same result; only exports
+	// the portal actually served count.
+	if got := portal.Exported(); got != 2 {
+		t.Errorf("exported %d reports, want 2", got)
+	}
+}
+
+func TestTranscriptPDF(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	useMockPortal(t, portal)
+
+	s := NewSession()
+	if code, text := s.Login(Credentials{StudentID: "F2023000000", Password: "hunter2"}, false); code != ErrNone {
+		t.Fatalf("login failed: %v %s", code, text)
+	}
+	dir := t.TempDir()
+	if _, err := s.DownloadTranscriptPDF(dir); !errors.Is(err, errNoExport) {
+		t.Errorf("download without an export = %v, want errNoExport", err)
+	}
+
+	portal.exports = true
+	path, err := s.DownloadTranscriptPDF(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != filepath.Join(dir, "transcript.pdf") {
+		t.Errorf("saved to %s", path)
+	}
+	if data, err := os.ReadFile(path); err != nil || !strings.HasPrefix(string(data), "%PDF") {
+		t.Errorf("saved %q, %v", data, err)
+	}
+}
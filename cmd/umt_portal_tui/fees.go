@@ -0,0 +1,263 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+const FEES_URL string = "https://online.umt.edu.pk/Payment"
+const CHALLAN_URL string = "https://online.umt.edu.pk/Payment/Payment_Voucher?id="
+
+// FeeChallan is one row of the payment history. Unpaid rows are the
+// outstanding dues.
+type FeeChallan struct {
+	Number      string  `json:"number"`
+	Semester    string  `json:"semester"`
+	Description string  `json:"description"`
+	Amount      float64 `json:"amount"`
+	DueDate     string  `json:"due_date"`
+	PaidDate    string  `json:"paid_date"`
+	Status      string  `json:"status"`
+	VoucherURL  string  `json:"voucher_url,omitempty"`
+	// Reference is the 1Link or consumer number banks take, when listed.
+	Reference string `json:"reference,omitempty"`
+}
+
+func (c FeeChallan) Paid() bool {
+	status := strings.ToLower(c.Status)
+	return strings.Contains(status, "paid") && !strings.Contains(status, "unpaid")
+}
+
+// outstandingDues totals the unpaid challans.
+func outstandingDues(challans []FeeChallan) float64 {
+	var total float64
+	for _, c := range challans {
+		if !c.Paid() {
+			total += c.Amount
+		}
+	}
+	return total
+}
+
+// parseAmount reads amounts such as "Rs. 98,500" or "PKR 1,250.50".
+func parseAmount(s string) float64 {
+	var digits strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' || r == '.' {
+			digits.WriteRune(r)
+		}
+	}
+	amount, _ := strconv.ParseFloat(strings.Trim(digits.String(), "."), 64)
+	return amount
+}
+
+// parsePaymentsHTML reads the payment history table, locating columns by
+// their headers.
+func parsePaymentsHTML(r io.Reader) ([]FeeChallan, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse payments HTML: %w", err)
+	}
+
+	table := doc.Find("table.table").First()
+	if table.Length() == 0 {
+		return nil, errReportIncomplete
+	}
+
+	columns := map[string]int{}
+	table.Find("th").Each(func(i int, th *goquery.Selection) {
+		header := strings.ToLower(strings.TrimSpace(th.Text()))
+		switch {
+		case strings.Contains(header, "1link") || strings.Contains(header, "consumer") || strings.Contains(header, "reference"):
+			columns["reference"] = i
+		case strings.Contains(header, "challan") || strings.Contains(header, "voucher no"):
+			columns["number"] = i
+		case strings.Contains(header, "semester"):
+			columns["semester"] = i
+		case strings.Contains(header, "description") || strings.Contains(header, "fee type"):
+			columns["description"] = i
+		case strings.Contains(header, "amount"):
+			columns["amount"] = i
+		case strings.Contains(header, "due"):
+			columns["due_date"] = i
+		case strings.Contains(header, "paid") || strings.Contains(header, "payment date"):
+			columns["paid_date"] = i
+		case strings.Contains(header, "status"):
+			columns["status"] = i
+		}
+	})
+	if _, ok := columns["number"]; !ok {
+		return nil, fmt.Errorf("payments table has no challan number column")
+	}
+
+	var challans []FeeChallan
+	table.Find("tbody tr").Each(func(_ int, row *goquery.Selection) {
+		cells := row.Find("td").Map(func(_ int, td *goquery.Selection) string {
+			return strings.TrimSpace(td.Text())
+		})
+		cell := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(cells) {
+				return cells[i]
+			}
+			return ""
+		}
+		if cell("number") == "" {
+			return
+		}
+
+		challan := FeeChallan{
+			Number:      cell("number"),
+			Semester:    cell("semester"),
+			Description: cell("description"),
+			Amount:      parseAmount(cell("amount")),
+			DueDate:     cell("due_date"),
+			PaidDate:    cell("paid_date"),
+			Status:      cell("status"),
+			Reference:   cell("reference"),
+		}
+		row.Find("a[href]").EachWithBreak(func(_ int, link *goquery.Selection) bool {
+			href, _ := link.Attr("href")
+			if strings.Contains(strings.ToLower(href), "voucher") {
+				challan.VoucherURL = href
+				return false
+			}
+			return true
+		})
+		challans = append(challans, challan)
+	})
+
+	return challans, nil
+}
+
+func (s *Session) fetchFees() ([]FeeChallan, error) {
+	if len(s.Cookies) == 0 {
+		return nil, fmt.Errorf("no cookies found during fetching fees")
+	}
+
+	return fetchPage(s, OP_OTHER, FEES_URL, "fees page", parsePaymentsHTML)
+}
+
+// downloadChallan saves the challan PDF into dir and returns its path. The
+// portal generates vouchers on demand, so the response is checked to really
+// be a PDF rather than an error page.
+func (s *Session) downloadChallan(challan FeeChallan, dir string) (string, error) {
+	if len(s.Cookies) == 0 {
+		return "", fmt.Errorf("no cookies found during downloading challan")
+	}
+
+	link := challan.VoucherURL
+	if link == "" {
+		link = CHALLAN_URL + url.QueryEscape(challan.Number)
+	}
+	base, _ := url.Parse(FEES_URL)
+	ref, err := url.Parse(link)
+	if err != nil {
+		return "", fmt.Errorf("invalid voucher link %q: %w", link, err)
+	}
+
+	req, err := http.NewRequest("GET", base.ResolveReference(ref).String(), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create challan request: %w", err)
+	}
+	for _, cookie := range s.Cookies {
+		req.AddCookie(cookie)
+	}
+
+	resp, err := s.httpClient(OP_OTHER).Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to download challan: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to download challan: %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read challan: %w", err)
+	}
+	if !strings.HasPrefix(string(data), "%PDF") {
+		return "", fmt.Errorf("the portal did not return a PDF for challan %s", challan.Number)
+	}
+
+	name := "challan_" + challan.Number + ".pdf"
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil && params["filename"] != "" {
+		name = params["filename"]
+	}
+	// Both the challan number and the suggested name come from the portal;
+	// neither may lead out of dir.
+	name = filepath.Base(name)
+	if name == "." || name == ".." || name == string(filepath.Separator) {
+		return "", fmt.Errorf("the portal gave challan %s no usable file name", challan.Number)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create download dir: %w", err)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to save challan: %w", err)
+	}
+	return path, nil
+}
+
+// formatAmount writes amounts as PKR with thousands separators, showing
+// paisa only when there are any.
+func formatAmount(amount float64) string {
+	sign := ""
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	text := strconv.FormatFloat(amount, 'f', 2, 64)
+	whole, fraction, _ := strings.Cut(text, ".")
+	var b strings.Builder
+	for i, r := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(r)
+	}
+	if fraction != "00" {
+		b.WriteString("." + fraction)
+	}
+	return sign + "PKR " + b.String()
+}
+
+func runFees(s *Session, args []string) (Output, error) {
+	challans, err := s.GetFees()
+	if err != nil {
+		return Output{}, err
+	}
+
+	out := Output{Header: []string{"challan", "semester", "description", "amount", "due_date", "paid_date", "status"}, Value: challans}
+	for _, c := range challans {
+		out.Rows = append(out.Rows, []string{c.Number, c.Semester, c.Description, strconv.FormatFloat(c.Amount, 'f', -1, 64), c.DueDate, c.PaidDate, c.Status})
+	}
+	dates := appConfig.installmentDates()
+	if feesInstallments != "" {
+		if dates, err = parseInstallmentDates(strings.Split(feesInstallments, ",")); err != nil {
+			return Output{}, withExitCode(EXIT_USAGE, err)
+		}
+	}
+	dues := outstandingDues(challans)
+	if dues <= 0 {
+		out.Notes = []string{"No outstanding dues."}
+		return out, nil
+	}
+	out.Notes = []string{"Outstanding dues: " + formatAmount(dues)}
+	installments := splitInstallments(dues, dates, time.Now())
+	for i, in := range installments {
+		out.Notes = append(out.Notes, fmt.Sprintf("Installment %d of %d: %s by %s", i+1, len(installments), formatAmount(in.Amount), in.Due.Format(INSTALLMENT_DATE_FORMAT)))
+	}
+	return out, nil
+}
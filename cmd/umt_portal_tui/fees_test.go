@@ -0,0 +1,96 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestDownloadChallan(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	useMockPortal(t, portal)
+
+	s := NewSession()
+	if code, text := s.Login(Credentials{StudentID: "F2023000000", Password: "hunter2"}, false); code != ErrNone {
+		t.Fatalf("login failed: %v %s", code, text)
+	}
+	challans, err := s.GetFees()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := filepath.Join(t.TempDir(), "challans")
+	for _, c := range []FeeChallan{challans[0], challans[2]} {
+		path, err := s.DownloadChallan(c, dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if filepath.Base(path) != "challan_"+c.Number+".pdf" {
+			t.Errorf("saved as %q", path)
+		}
+		data, _ := os.ReadFile(path)
+		if !strings.HasPrefix(string(data), "%PDF") || !strings.Contains(string(data), c.Number) {
+			t.Errorf("unexpected challan contents %q", data)
+		}
+	}
+	// A scraped challan number is never a path.
+	portal.noDisposition = true
+	escape := challans[0]
+	escape.Number, escape.VoucherURL = "../../../escape", ""
+	path, err := s.DownloadChallan(escape, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Dir(path) != dir {
+		t.Errorf("challan %q saved outside %s as %s", escape.Number, dir, path)
+	}
+}
+
+func TestPaymentQR(t *testing.T) {
+	challans, err := parsePaymentsHTML(strings.NewReader(`<table class="table"><thead><tr>
+		<th>Challan No.</th><th>1Link Consumer No.</th><th>Amount</th><th>Status</th></tr></thead>
+		<tbody><tr><td>100231</td><td></td><td>Rs. 5,000</td><td>Paid</td></tr>
+		<tr><td>100245</td><td>1001234567890</td><td>Rs. 98,500</td><td>Unpaid</td></tr></tbody></table>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := challans[0].PaymentReference(); got != "100231" {
+		t.Errorf("reference without a 1Link number is %q", got)
+	}
+	if got := challans[1].PaymentReference(); got != "1001234567890" {
+		t.Errorf("reference is %q, want the 1Link number", got)
+	}
+
+	code, err := renderQR(challans[1].PaymentReference())
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(code, "\n")
+	if want := (21 + 2*QR_QUIET_ZONE + 1) / 2; len(lines) != want {
+		t.Errorf("QR code is %d lines, want %d", len(lines), want)
+	}
+	for _, line := range lines {
+		if w := len([]rune(line)); w != 21+2*QR_QUIET_ZONE {
+			t.Errorf("QR line is %d wide: %q", w, line)
+		}
+	}
+	if lines[0] != strings.Repeat("█", 21+2*QR_QUIET_ZONE) {
+		t.Errorf("no quiet zone above the code: %q", lines[0])
+	}
+
+	p := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")}
+	m := model{currentView: FeesView, fees: challans, width: 120, height: 60}
+	next, _ := m.handleFeesKeys(p)
+	if m = next.(model); m.showQR || m.feeStatus != T("fees.qr_paid", "100231") {
+		t.Errorf("paid challan: showQR %v, status %q", m.showQR, m.feeStatus)
+	}
+	next, _ = m.handleFeesKeys(tea.KeyMsg{Type: tea.KeyDown})
+	next, _ = next.(model).handleFeesKeys(p)
+	m = next.(model)
+	if view := m.View(); !strings.Contains(view, lines[1]) || !strings.Contains(view, T("fees.qr_caption", "1001234567890", formatAmount(98500))) {
+		t.Errorf("QR code not shown for the unpaid challan:\n%s", view)
+	}
+}
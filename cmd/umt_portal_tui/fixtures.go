@@ -0,0 +1,233 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// fixtureHeaders are the response headers a fixture keeps; the rest only
+// describe the connection.
+var fixtureHeaders = []string{"Content-Type", "Content-Disposition", "Location", "Set-Cookie", "ETag", "Last-Modified"}
+
+// fixtureExchange is one recorded request and its response, saved as
+// NNNN_METHOD_path.json with the body next to it in Body.
+type fixtureExchange struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	// Request identifies the request's form, less the login fields, so
+	// postbacks to the same page replay the right response.
+	Request string      `json:"request"`
+	Status  int         `json:"status"`
+	Header  http.Header `json:"header"`
+	Body    string      `json:"body"`
+	Note    string      `json:"note,omitempty"`
+}
+
+// recordTransport saves every portal exchange into dir, for --replay to
+// answer from later. Like debug artifacts, bodies and cookies are scrubbed
+// of personal data unless --include-pii, and binary bodies are left out.
+type recordTransport struct {
+	dir  string
+	next http.RoundTripper
+	seq  atomic.Int64
+}
+
+func enableFixtureRecording(dir string) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create fixture dir: %w", err)
+	}
+	sharedTransport = &recordTransport{dir: dir, next: sharedTransport}
+	return nil
+}
+
+// readRequestBody reads req's body and gives req a fresh copy of it.
+func readRequestBody(req *http.Request) (*http.Request, []byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return req, nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return req, nil, err
+	}
+	req = req.Clone(req.Context())
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return req, body, nil
+}
+
+// fixtureKey identifies a request to replay regardless of who logs in: the
+// values of the login form are dropped and the rest scrubbed as the
+// recorded responses were.
+func fixtureKey(method, rawURL string, body []byte) string {
+	form, err := url.ParseQuery(string(body))
+	if err == nil {
+		for k := range form {
+			key := strings.ToLower(k)
+			if strings.Contains(key, "pass") || strings.Contains(key, "student") || strings.Contains(key, "security") || strings.Contains(key, "captcha") {
+				form.Set(k, "")
+			}
+		}
+		body = []byte(form.Encode())
+	}
+	sum := sha256.Sum256([]byte(method + " " + stripPII(rawURL) + "\n" + stripPII(string(body))))
+	return hex.EncodeToString(sum[:])
+}
+
+func (t *recordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req, reqBody, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return resp, err
+	}
+
+	secrets := cookieValues(req, resp)
+	exchange := fixtureExchange{
+		Method:  req.Method,
+		URL:     scrubPII(req.URL.String(), secrets...),
+		Request: fixtureKey(req.Method, req.URL.String(), reqBody),
+		Status:  resp.StatusCode,
+		Header:  http.Header{},
+	}
+	for _, name := range fixtureHeaders {
+		for _, v := range resp.Header.Values(name) {
+			exchange.Header.Add(name, scrubPII(v, secrets...))
+		}
+	}
+	if !includePII {
+		if isTextResponse(resp) {
+			body = []byte(scrubPII(string(body), secrets...))
+		} else {
+			exchange.Note = fmt.Sprintf("%s response of %d bytes left out; record with --include-pii to keep it", resp.Header.Get("Content-Type"), len(body))
+			body = nil
+		}
+	}
+
+	name := fmt.Sprintf("%04d_%s_%s", t.seq.Add(1), req.Method, artifactName(req.URL.Path))
+	exchange.Body = name + ".body"
+	meta, err := json.MarshalIndent(exchange, "", "  ")
+	if err == nil {
+		err = os.WriteFile(filepath.Join(t.dir, exchange.Body), body, 0600)
+	}
+	if err == nil {
+		err = os.WriteFile(filepath.Join(t.dir, name+".json"), meta, 0600)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "warning: failed to save fixture:", err)
+	}
+	return resp, nil
+}
+
+// replayTransport answers every request from recorded fixtures and never
+// touches the network. A request gets the responses recorded for the same
+// form in order, else those recorded for the same URL; the last one is
+// repeated once they run out.
+type replayTransport struct {
+	mu     sync.Mutex
+	byForm map[string][]fixtureExchange
+	byURL  map[string][]fixtureExchange
+	bodies map[string][]byte
+}
+
+func loadFixtures(dir string) (*replayTransport, error) {
+	names, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no fixtures in %s; record some with --record", dir)
+	}
+	sort.Strings(names)
+
+	t := &replayTransport{byForm: map[string][]fixtureExchange{}, byURL: map[string][]fixtureExchange{}, bodies: map[string][]byte{}}
+	for _, name := range names {
+		raw, err := os.ReadFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read fixture: %w", err)
+		}
+		var e fixtureExchange
+		if err := json.Unmarshal(raw, &e); err != nil {
+			return nil, fmt.Errorf("invalid fixture %s: %w", filepath.Base(name), err)
+		}
+		body, err := os.ReadFile(filepath.Join(dir, filepath.Base(e.Body)))
+		if err != nil {
+			return nil, fmt.Errorf("fixture %s: %w", filepath.Base(name), err)
+		}
+		t.bodies[e.Body] = body
+		t.byForm[e.Request] = append(t.byForm[e.Request], e)
+		key := e.Method + " " + stripPII(e.URL)
+		t.byURL[key] = append(t.byURL[key], e)
+	}
+	return t, nil
+}
+
+func enableFixtureReplay(dir string) error {
+	t, err := loadFixtures(dir)
+	if err != nil {
+		return err
+	}
+	sharedTransport = t
+	return nil
+}
+
+// next takes the first exchange queued under key, leaving the last one.
+func (t *replayTransport) next(queues map[string][]fixtureExchange, key string) (fixtureExchange, bool) {
+	queue := queues[key]
+	if len(queue) == 0 {
+		return fixtureExchange{}, false
+	}
+	if len(queue) > 1 {
+		queues[key] = queue[1:]
+	}
+	return queue[0], true
+}
+
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req, body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	t.mu.Lock()
+	e, ok := t.next(t.byForm, fixtureKey(req.Method, req.URL.String(), body))
+	if !ok {
+		e, ok = t.next(t.byURL, req.Method+" "+stripPII(req.URL.String()))
+	}
+	t.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("no recorded response for %s %s", req.Method, req.URL.Redacted())
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.Status, http.StatusText(e.Status)),
+		StatusCode:    e.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(t.bodies[e.Body])),
+		ContentLength: int64(len(t.bodies[e.Body])),
+		Request:       req,
+	}, nil
+}
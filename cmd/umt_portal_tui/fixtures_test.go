@@ -0,0 +1,69 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	useMockPortal(t, portal)
+	t.Cleanup(func() { cacheMode = CACHE_NORMAL })
+	cacheMode = CACHE_OFF
+
+	fetch := func(creds Credentials) Student {
+		t.Helper()
+		s := NewSession()
+		if code, text := s.Login(creds, false); code != ErrNone {
+			t.Fatalf("login failed: %v %s", code, text)
+		}
+		courses, err := s.GetCourses()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, c := range courses {
+			if err := s.GetCourseAttendance(true, c.ID); err != nil {
+				t.Fatal(err)
+			}
+			if err := s.GetCourseAssessments(c.ID); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := s.GetTranscript(true); err != nil {
+			t.Fatal(err)
+		}
+		return s.Student
+	}
+
+	dir := filepath.Join(t.TempDir(), "fixtures")
+	if err := enableFixtureRecording(dir); err != nil {
+		t.Fatal(err)
+	}
+	recorded := fetch(Credentials{StudentID: "F2023000000", Password: "hunter2"})
+
+	saved, _ := filepath.Glob(filepath.Join(dir, "*"))
+	for _, name := range saved {
+		raw, _ := os.ReadFile(name)
+		if strings.Contains(string(raw), "hunter2") || strings.Contains(string(raw), "F2023000000") {
+			t.Errorf("%s holds the password or student ID", filepath.Base(name))
+		}
+	}
+
+	if err := enableFixtureReplay(dir); err != nil {
+		t.Fatal(err)
+	}
+	hits := portal.Hits("POST /Reports/Attendance.aspx")
+	replayed := fetch(Credentials{StudentID: "F2023999999", Password: "anything"})
+	if got := portal.Hits("POST /Reports/Attendance.aspx"); got != hits {
+		t.Errorf("replay reached the portal %d times", got-hits)
+	}
+	if len(replayed.Courses) == 0 || !reflect.DeepEqual(replayed.Courses, recorded.Courses) {
+		t.Errorf("replayed courses differ:\n%+v\nrecorded:\n%+v", replayed.Courses, recorded.Courses)
+	}
+	if !reflect.DeepEqual(replayed.Transcript, recorded.Transcript) {
+		t.Error("replayed transcript differs")
+	}
+}
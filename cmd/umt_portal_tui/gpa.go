@@ -0,0 +1,153 @@
+package main
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// gradePoints is UMT's 4.0 scale, used where the portal shows a letter grade
+// without its grade point.
+var gradePoints = map[string]float32{
+	"A":  4.00,
+	"A-": 3.67,
+	"B+": 3.33,
+	"B":  3.00,
+	"B-": 2.67,
+	"C+": 2.33,
+	"C":  2.00,
+	"C-": 1.67,
+	"D+": 1.33,
+	"D":  1.00,
+	"F":  0,
+}
+
+// NO_POINT_GRADES are the transcript grades outside the letter scale, which
+// carry no grade points.
+var NO_POINT_GRADES = []string{"P", "I", "W", "SA", "S", "NC"}
+
+// NO_GPA_MARK tags the transcript rows graded outside the letter scale.
+const NO_GPA_MARK = "◌"
+
+// noGradePoints reports whether grade is one of NO_POINT_GRADES, such as W
+// (withdrawn), I (incomplete) or P (pass), however the report cased it.
+func noGradePoints(grade string) bool {
+	return slices.Contains(NO_POINT_GRADES, strings.ToUpper(strings.TrimSpace(grade)))
+}
+
+func isZeroGradePointGrade(grade string) bool {
+	return strings.EqualFold(strings.TrimSpace(grade), "F") || noGradePoints(grade)
+}
+
+// countsTowardGPA reports whether an attempt contributes credit hours to the
+// GPA denominator. F counts with zero points; the other zero-point grades
+// (pass, incomplete, withdrawn, ...) are excluded entirely.
+func countsTowardGPA(c TranscriptCourse) bool {
+	if c.Superseded {
+		return false
+	}
+	return !noGradePoints(c.Grade)
+}
+
+func normalizeCourseCode(code string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(code), ""))
+}
+
+// applyRepeatPolicy implements UMT's repeat rule: only the latest attempt of a
+// course counts, earlier attempts stay on the transcript but are excluded from
+// GPA. Semesters are walked in chronological order so "latest" is well
+// defined.
+func applyRepeatPolicy(t *Transcript) {
+	type attempt struct {
+		semester Semester
+		index    int
+	}
+	latest := make(map[string]attempt)
+
+	for _, key := range parseAndSortSemesters(t.Semester) {
+		courses := t.Semester[key.semester]
+		for i := range courses {
+			courses[i].Superseded = false
+			courses[i].Retake = strings.Contains(courses[i].Title, "[R]")
+
+			code := normalizeCourseCode(courses[i].Code)
+			if previous, ok := latest[code]; ok {
+				t.Semester[previous.semester][previous.index].Superseded = true
+				courses[i].Retake = true
+			}
+			latest[code] = attempt{semester: key.semester, index: i}
+		}
+	}
+}
+
+// gpaTotals recomputes credit hours for GPA and total quality points from the
+// individual course rows, honoring the repeat policy.
+func (t *Transcript) gpaTotals() (creditHours int, gradePoints float64) {
+	for _, courses := range t.Semester {
+		for _, c := range courses {
+			if !countsTowardGPA(c) {
+				continue
+			}
+			creditHours += c.CreditHours
+			gradePoints += float64(c.GradePoint) * float64(c.CreditHours)
+		}
+	}
+	return creditHours, gradePoints
+}
+
+// Courses graded below RETAKE_BELOW (C-, D+, D and F) are suggested for a
+// retake.
+const RETAKE_BELOW = 2.00
+
+// RetakeOption is what retaking one course for TargetGrade would do to the
+// CGPA, assuming the repeat policy replaces the earlier attempt.
+type RetakeOption struct {
+	Course      TranscriptCourse
+	Semester    string
+	TargetGrade string
+	NewCGPA     float64
+	Delta       float64
+	// DeltaPerCreditHour is the CGPA gained for each credit hour retaken.
+	DeltaPerCreditHour float64
+}
+
+// retakeOptions lists the counted courses graded below RETAKE_BELOW that
+// would raise the CGPA if retaken for target, best CGPA gain per credit hour
+// first, then biggest gain.
+func retakeOptions(t Transcript, target string) []RetakeOption {
+	creditHours, points := t.gpaTotals()
+	targetPoint, ok := gradePoints[target]
+	if creditHours == 0 || !ok {
+		return nil
+	}
+	cgpa := points / float64(creditHours)
+
+	var options []RetakeOption
+	for sem, courses := range t.Semester {
+		for _, c := range courses {
+			if !countsTowardGPA(c) || c.GradePoint >= RETAKE_BELOW || c.GradePoint >= targetPoint {
+				continue
+			}
+			newCGPA := (points + float64(targetPoint-c.GradePoint)*float64(c.CreditHours)) / float64(creditHours)
+			options = append(options, RetakeOption{
+				Course:             c,
+				Semester:           sem.Name,
+				TargetGrade:        target,
+				NewCGPA:            newCGPA,
+				Delta:              newCGPA - cgpa,
+				DeltaPerCreditHour: float64(targetPoint-c.GradePoint) / float64(creditHours),
+			})
+		}
+	}
+
+	slices.SortFunc(options, func(a, b RetakeOption) int {
+		if c := cmp.Compare(b.DeltaPerCreditHour, a.DeltaPerCreditHour); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(b.Delta, a.Delta); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Course.Code, b.Course.Code)
+	})
+	return options
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// gradeScaleRow is a letter of a grading scale with the percentages that
+// earn it, from From up to just below To. Open marks the best letter, which
+// has no upper bound.
+type gradeScaleRow struct {
+	Letter string
+	Points float32
+	From   float64
+	To     float64
+	Open   bool
+}
+
+// scale lists the letters a course can earn, best first and ending with F.
+// Letters missing from a course's cutoffs can't be earned and are left out.
+// Under a relative scheme without a class average, From and To are offsets
+// from the average.
+func (g CourseGrading) scale() []gradeScaleRow {
+	cutoffs, base := g.Cutoffs, 0.0
+	if g.Scheme == SCHEME_RELATIVE {
+		base = g.ClassAverage
+	} else if len(cutoffs) == 0 {
+		cutoffs = DEFAULT_CUTOFFS
+	}
+
+	var rows []gradeScaleRow
+	for _, letter := range gradeLetters() {
+		cutoff, ok := cutoffs[letter]
+		if !ok {
+			continue
+		}
+		row := gradeScaleRow{Letter: letter, Points: gradePoints[letter], From: base + cutoff}
+		if len(rows) == 0 {
+			row.To, row.Open = 100, true
+		} else {
+			row.To = rows[len(rows)-1].From
+		}
+		rows = append(rows, row)
+	}
+	f := gradeScaleRow{Letter: "F", Open: len(rows) == 0}
+	if len(rows) > 0 {
+		f.To = rows[len(rows)-1].From
+	}
+	if g.Scheme != SCHEME_RELATIVE || g.ClassAverage > 0 {
+		f.From = 0
+	} else {
+		f.From = math.Inf(-1)
+	}
+	return append(rows, f)
+}
+
+// percentRange describes the percentages of a scale row, e.g. "80 – 84.99",
+// "≥ 85" or "< 50". offsets writes them relative to the class average.
+func (r gradeScaleRow) percentRange(offsets bool) string {
+	number := func(p float64) string {
+		p = math.Round(p*100) / 100
+		if offsets {
+			return T("grade_scale.offset", p)
+		}
+		return strconv.FormatFloat(p, 'f', -1, 64)
+	}
+	switch {
+	case r.Open && math.IsInf(r.From, -1):
+		return "-"
+	case r.Open:
+		return "≥ " + number(r.From)
+	case math.IsInf(r.From, -1):
+		return "< " + number(r.To)
+	}
+	return number(r.From) + " – " + number(r.To-0.01)
+}
+
+// openGradeScale shows the grading scale of a course, or the default scale
+// for an empty code.
+func (m *model) openGradeScale(courseCode string, grading CourseGrading) {
+	m.gradeScaleCourse = courseCode
+	m.gradeScale = grading
+	m.pushView(GradeScaleView)
+}
+
+func (m model) handleGradeScaleKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		if !m.rememberMe {
+			deleteCaches()
+		}
+		return m, tea.Quit
+	case "esc", "G":
+		m.goBack()
+	}
+	return m, nil
+}
+
+func (m model) renderGradeScale() string {
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(ACCENT).
+		MarginBottom(1)
+
+	summaryStyle := lipgloss.NewStyle().
+		Foreground(WHITE).
+		MarginBottom(1)
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(WHITE).
+		Background(BLUE)
+
+	normalStyle := lipgloss.NewStyle().
+		Foreground(SILVER)
+
+	failStyle := lipgloss.NewStyle().
+		Foreground(DANGER)
+
+	noteStyle := lipgloss.NewStyle().
+		Foreground(GREY).
+		MarginTop(1)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(GREY).
+		MarginTop(1)
+
+	g := m.gradeScale
+	var summary string
+	switch {
+	case g.Scheme == SCHEME_RELATIVE && g.ClassAverage > 0:
+		summary = T("grade_scale.relative_average", m.gradeScaleCourse, g.ClassAverage)
+	case g.Scheme == SCHEME_RELATIVE:
+		summary = T("grade_scale.relative", m.gradeScaleCourse)
+	case m.gradeScaleCourse != "" && len(g.Cutoffs) > 0:
+		summary = T("grade_scale.course", m.gradeScaleCourse)
+	default:
+		summary = T("grade_scale.default")
+	}
+
+	offsets := g.Scheme == SCHEME_RELATIVE && g.ClassAverage <= 0
+	format := "  %-6s %6s  %s"
+	rows := []string{headerStyle.Render(fmt.Sprintf(format,
+		T("grade_scale.col_letter"), T("grade_scale.col_points"), padText(T("grade_scale.col_percent"), 18)))}
+	for _, r := range g.scale() {
+		line := fmt.Sprintf(format, r.Letter, fmt.Sprintf("%.2f", r.Points), padText(r.percentRange(offsets), 18))
+		if r.Letter == "F" {
+			rows = append(rows, failStyle.Render(line))
+		} else {
+			rows = append(rows, normalStyle.Render(line))
+		}
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Center,
+		titleStyle.Render(T("grade_scale.title")),
+		summaryStyle.Render(summary),
+		strings.Join(rows, "\n"),
+		noteStyle.Render(T("grade_scale.no_points", strings.Join(NO_POINT_GRADES, ", "))),
+		helpStyle.Render(T("grade_scale.help")),
+	)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, content)
+}
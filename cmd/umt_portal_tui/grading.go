@@ -0,0 +1,232 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// CourseGrading says how a course's assessments add up to its grade:
+// Weights is the percentage of the grade each category carries, and Best
+// keeps only the N highest-scoring assessments of a category, for courses
+// that drop the lowest quizzes ("best 3 of 4").
+//
+// Scheme and Cutoffs turn the percentage into a letter grade. Under the
+// absolute scheme, the default, Cutoffs are the minimum percentage of each
+// letter; under the relative scheme they are offsets from the class average,
+// which ClassAverage gives.
+type CourseGrading struct {
+	Weights map[string]float64 `json:"weights"`
+	Best    map[string]int     `json:"best"`
+
+	Scheme       string             `json:"scheme"`
+	Cutoffs      map[string]float64 `json:"cutoffs"`
+	ClassAverage float64            `json:"class_average"`
+}
+
+const (
+	SCHEME_ABSOLUTE = "absolute"
+	SCHEME_RELATIVE = "relative"
+)
+
+// DEFAULT_CUTOFFS is a typical absolute scale, for courses without cutoffs
+// of their own.
+var DEFAULT_CUTOFFS = map[string]float64{
+	"A":  85,
+	"A-": 80,
+	"B+": 75,
+	"B":  71,
+	"B-": 68,
+	"C+": 64,
+	"C":  61,
+	"C-": 58,
+	"D+": 54,
+	"D":  50,
+}
+
+// courseGrading returns the grading config of a course, matching grading
+// keys case-insensitively. An absolute scheme without cutoffs gets the
+// configured grade scale.
+func (c Config) courseGrading(courseCode string) CourseGrading {
+	var grading CourseGrading
+	for code, g := range c.Grading {
+		if strings.EqualFold(code, courseCode) {
+			grading = g
+			break
+		}
+	}
+	if grading.Scheme != SCHEME_RELATIVE && len(grading.Cutoffs) == 0 {
+		grading.Cutoffs = c.GradeScale
+	}
+	return grading
+}
+
+func (g CourseGrading) validate() error {
+	switch g.Scheme {
+	case "", SCHEME_ABSOLUTE:
+		for letter, cutoff := range g.Cutoffs {
+			if cutoff < 0 || cutoff > 100 {
+				return fmt.Errorf("cutoff of %s %v is not between 0 and 100", letter, cutoff)
+			}
+		}
+	case SCHEME_RELATIVE:
+		if len(g.Cutoffs) == 0 {
+			return fmt.Errorf("a relative scheme needs cutoffs")
+		}
+	default:
+		return fmt.Errorf("unknown scheme %q (want %s or %s)", g.Scheme, SCHEME_ABSOLUTE, SCHEME_RELATIVE)
+	}
+	letters := gradeLetters()
+	for letter := range g.Cutoffs {
+		if !slices.Contains(letters, letter) {
+			return fmt.Errorf("unknown letter grade %q in cutoffs", letter)
+		}
+	}
+	// A better letter can't need less than a worse one.
+	var prev string
+	for _, letter := range letters {
+		cutoff, ok := g.Cutoffs[letter]
+		if !ok {
+			continue
+		}
+		if prev != "" && cutoff > g.Cutoffs[prev] {
+			return fmt.Errorf("cutoff of %s is above that of %s", letter, prev)
+		}
+		prev = letter
+	}
+
+	total := 0.0
+	for category, weight := range g.Weights {
+		if !slices.Contains(CATEGORY_ORDER, category) {
+			return fmt.Errorf("unknown category %q (want one of %s)", category, strings.Join(CATEGORY_ORDER, ", "))
+		}
+		if weight < 0 {
+			return fmt.Errorf("weight of %s is negative", category)
+		}
+		total += weight
+	}
+	if total > 100 {
+		return fmt.Errorf("weights add up to %v, more than 100", total)
+	}
+	for category, n := range g.Best {
+		if !slices.Contains(CATEGORY_ORDER, category) {
+			return fmt.Errorf("unknown category %q (want one of %s)", category, strings.Join(CATEGORY_ORDER, ", "))
+		}
+		if n < 1 {
+			return fmt.Errorf("best %s must keep at least one", category)
+		}
+	}
+	return nil
+}
+
+func assessmentScore(a Assessment) float32 {
+	if a.totalMarks <= 0 {
+		return 0
+	}
+	return a.obtainedMarks / a.totalMarks
+}
+
+// dropped reports which of a category's assessments fall outside its best
+// N, the lowest scores first; ties drop the later assessment.
+func (g CourseGrading) dropped(category string, assessments []Assessment) []bool {
+	drop := make([]bool, len(assessments))
+	n, ok := g.Best[category]
+	if !ok || len(assessments) <= n {
+		return drop
+	}
+	order := make([]int, len(assessments))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return cmp.Compare(assessmentScore(assessments[b]), assessmentScore(assessments[a]))
+	})
+	for _, i := range order[n:] {
+		drop[i] = true
+	}
+	return drop
+}
+
+// gradeProjection is where a course's weighted grade stands: Earned points
+// of the Assessed weight so far, both out of 100 for the whole course.
+type gradeProjection struct {
+	Earned   float64
+	Assessed float64
+}
+
+// Percentage projects the course grade assuming the rest goes as well as
+// what has been assessed.
+func (p gradeProjection) Percentage() float64 {
+	if p.Assessed == 0 {
+		return 0
+	}
+	return p.Earned / p.Assessed * 100
+}
+
+// project weighs the assessments by category. It is false when the course
+// has no weights or none of its weighted categories has been assessed.
+func (g CourseGrading) project(assessments []Assessment) (gradeProjection, bool) {
+	var p gradeProjection
+	for _, group := range g.groups(assessments) {
+		weight := g.Weights[group.Category]
+		if weight == 0 || group.Total == 0 {
+			continue
+		}
+		p.Earned += weight * float64(group.Obtained/group.Total)
+		p.Assessed += weight
+	}
+	return p, p.Assessed > 0
+}
+
+// groups is groupAssessments with each group's subtotal counting only the
+// assessments its best-of-N policy keeps.
+func (g CourseGrading) groups(assessments []Assessment) []assessmentGroup {
+	groups := groupAssessments(assessments)
+	for i := range groups {
+		group := &groups[i]
+		group.Obtained, group.Total = 0, 0
+		for j, drop := range g.dropped(group.Category, group.Assessments) {
+			if !drop {
+				group.Obtained += group.Assessments[j].obtainedMarks
+				group.Total += group.Assessments[j].totalMarks
+			}
+		}
+	}
+	return groups
+}
+
+// gradeLetters lists the letter grades from best to worst, without F.
+func gradeLetters() []string {
+	var letters []string
+	for letter, points := range gradePoints {
+		if points > 0 {
+			letters = append(letters, letter)
+		}
+	}
+	slices.SortFunc(letters, func(a, b string) int {
+		return cmp.Compare(gradePoints[b], gradePoints[a])
+	})
+	return letters
+}
+
+// letterGrade is the letter a course percentage earns under the course's
+// scheme: the best letter whose cutoff it reaches, else F. It is false for
+// a relative scheme without a class average.
+func (g CourseGrading) letterGrade(percentage float64) (string, bool) {
+	cutoffs, base := g.Cutoffs, 0.0
+	if g.Scheme == SCHEME_RELATIVE {
+		if g.ClassAverage <= 0 {
+			return "", false
+		}
+		base = g.ClassAverage
+	} else if len(cutoffs) == 0 {
+		cutoffs = DEFAULT_CUTOFFS
+	}
+	for _, letter := range gradeLetters() {
+		if cutoff, ok := cutoffs[letter]; ok && percentage >= base+cutoff {
+			return letter, true
+		}
+	}
+	return "F", true
+}
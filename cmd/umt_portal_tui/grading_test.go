@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestCourseGrading(t *testing.T) {
+	assessments := []Assessment{
+		{name: "Quiz 1", obtainedMarks: 8, totalMarks: 10},
+		{name: "Quiz 2", obtainedMarks: 3, totalMarks: 10},
+		{name: "Quiz 3", obtainedMarks: 9, totalMarks: 10},
+		{name: "Assignment 1", obtainedMarks: 18, totalMarks: 20},
+		{name: "Mid Term", obtainedMarks: 21, totalMarks: 30},
+	}
+	grading := CourseGrading{
+		Weights: map[string]float64{CATEGORY_QUIZ: 10, CATEGORY_ASSIGNMENT: 10, CATEGORY_MIDTERM: 30, CATEGORY_FINAL: 50},
+		Best:    map[string]int{CATEGORY_QUIZ: 2},
+	}
+
+	groups := grading.groups(assessments)
+	if quizzes := groups[0]; quizzes.Obtained != 17 || quizzes.Total != 20 {
+		t.Errorf("best 2 quizzes = %.1f/%.1f, want 17/20", quizzes.Obtained, quizzes.Total)
+	}
+
+	// 10*0.85 + 10*0.9 + 30*0.7 of the 50 assessed.
+	p, ok := grading.project(assessments)
+	if !ok || math.Abs(p.Earned-38.5) > 1e-6 || p.Assessed != 50 {
+		t.Fatalf("project = %+v, %v", p, ok)
+	}
+	if got := p.Percentage(); math.Abs(got-77) > 1e-6 {
+		t.Errorf("projected %.2f%%, want 77%%", got)
+	}
+	if _, ok := (CourseGrading{}).project(assessments); ok {
+		t.Error("a course without weights was projected")
+	}
+
+	prevConfig := appConfig
+	t.Cleanup(func() { appConfig = prevConfig })
+	appConfig = Config{Grading: map[string]CourseGrading{"cs1001": grading}}
+	m := model{currentView: AssessmentView, width: 120, height: 40, courses: []Course{{Code: "CS1001", Assessment: assessments}}}
+	view := m.View()
+	for _, want := range []string{"Quizzes (best 2 of 3)", "dropped", "projected 77.0% (B+)"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("assessments view is missing %q:\n%s", want, view)
+		}
+	}
+
+	for _, bad := range []CourseGrading{
+		{Weights: map[string]float64{"quizzes": 10}},
+		{Weights: map[string]float64{CATEGORY_MIDTERM: 60, CATEGORY_FINAL: 50}},
+		{Best: map[string]int{CATEGORY_QUIZ: 0}},
+		{Scheme: "curved"},
+		{Scheme: SCHEME_RELATIVE},
+		{Cutoffs: map[string]float64{"A": 80, "A-": 85}},
+		{Cutoffs: map[string]float64{"E": 40}},
+	} {
+		if err := bad.validate(); err == nil {
+			t.Errorf("%+v was accepted", bad)
+		}
+	}
+}
+
+func TestLetterGrade(t *testing.T) {
+	custom := CourseGrading{Cutoffs: map[string]float64{"A": 90, "B": 70, "C": 50}}
+	relative := CourseGrading{Scheme: SCHEME_RELATIVE, Cutoffs: map[string]float64{"A": 15, "B": 0, "C": -15}, ClassAverage: 60}
+	tests := []struct {
+		grading    CourseGrading
+		percentage float64
+		want       string
+	}{
+		{CourseGrading{}, 86, "A"},
+		{CourseGrading{}, 72.5, "B"},
+		{CourseGrading{}, 49.9, "F"},
+		{custom, 86, "B"},
+		{custom, 50, "C"},
+		{relative, 76, "A"},
+		{relative, 60, "B"},
+		{relative, 44, "F"},
+	}
+	for _, tt := range tests {
+		if got, ok := tt.grading.letterGrade(tt.percentage); !ok || got != tt.want {
+			t.Errorf("%+v: letterGrade(%v) = %s, %v, want %s", tt.grading, tt.percentage, got, ok, tt.want)
+		}
+	}
+
+	relative.ClassAverage = 0
+	if _, ok := relative.letterGrade(70); ok {
+		t.Error("relative grade without a class average")
+	}
+}
+
+func TestClassGrading(t *testing.T) {
+	assessments := []Assessment{
+		{name: "Quiz 1", obtainedMarks: 9, totalMarks: 10, classAverage: 6},
+		{name: "Mid Term", obtainedMarks: 24, totalMarks: 30, classAverage: 18},
+	}
+	grading := CourseGrading{
+		Weights: map[string]float64{CATEGORY_QUIZ: 25, CATEGORY_MIDTERM: 75},
+		Scheme:  SCHEME_RELATIVE,
+		Cutoffs: map[string]float64{"A": 15, "B": 0},
+	}
+	g := classGrading(grading, assessments)
+	if math.Abs(g.ClassAverage-60) > 1e-4 {
+		t.Fatalf("class average = %v, want 60", g.ClassAverage)
+	}
+	// 25*0.9 + 75*0.8 = 82.5, 22.5 above the class.
+	p, _ := g.project(assessments)
+	if letter, ok := g.letterGrade(p.Percentage()); !ok || letter != "A" {
+		t.Errorf("letterGrade(%.1f) = %s, %v", p.Percentage(), letter, ok)
+	}
+
+	assessments[1].classAverage = 0
+	if g := classGrading(grading, assessments); g.ClassAverage != 0 {
+		t.Errorf("class average %v from partial class marks", g.ClassAverage)
+	}
+}
+
+func TestGradeScale(t *testing.T) {
+	describe := func(g CourseGrading) string {
+		var rows []string
+		offsets := g.Scheme == SCHEME_RELATIVE && g.ClassAverage <= 0
+		for _, r := range g.scale() {
+			rows = append(rows, fmt.Sprintf("%s %.2f %s", r.Letter, r.Points, r.percentRange(offsets)))
+		}
+		return strings.Join(rows, "; ")
+	}
+	tests := []struct {
+		grading CourseGrading
+		want    string
+	}{
+		{CourseGrading{Cutoffs: map[string]float64{"A": 90, "B": 70.5, "C": 50}},
+			"A 4.00 ≥ 90; B 3.00 70.5 – 89.99; C 2.00 50 – 70.49; F 0.00 0 – 49.99"},
+		{CourseGrading{Scheme: SCHEME_RELATIVE, Cutoffs: map[string]float64{"A": 15, "B": 0, "C": -15}, ClassAverage: 60},
+			"A 4.00 ≥ 75; B 3.00 60 – 74.99; C 2.00 45 – 59.99; F 0.00 0 – 44.99"},
+		{CourseGrading{Scheme: SCHEME_RELATIVE, Cutoffs: map[string]float64{"A": 15, "B": 0, "C": -15}},
+			"A 4.00 ≥ avg+15; B 3.00 avg+0 – avg+14.99; C 2.00 avg-15 – avg-0.01; F 0.00 < avg-15"},
+	}
+	for _, tt := range tests {
+		if got := describe(tt.grading); got != tt.want {
+			t.Errorf("%+v:\n got %s\nwant %s", tt.grading, got, tt.want)
+		}
+	}
+
+	prev := appConfig
+	t.Cleanup(func() { appConfig = prev })
+	appConfig = Config{GradeScale: map[string]float64{"A": 90, "B": 80}}
+	if got := describe(appConfig.courseGrading("CS1001")); !strings.HasPrefix(got, "A 4.00 ≥ 90; B 3.00 80 – 89.99") {
+		t.Errorf("configured grade scale not used: %s", got)
+	}
+}
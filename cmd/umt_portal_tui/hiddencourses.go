@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// hiddenCourses are the courses a student has hidden from the course list,
+// such as ones withdrawn from that the portal still lists, by course ID.
+type hiddenCourses struct {
+	StudentID string   `json:"student_id"`
+	CourseIDs []string `json:"course_ids"`
+}
+
+func hiddenCoursesPath() (string, error) {
+	dir, err := stateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "hidden_courses.json"), nil
+}
+
+// readHiddenCourses returns the IDs studentID has hidden; none when the file
+// is missing or belongs to someone else.
+func readHiddenCourses(studentID string) map[string]bool {
+	hidden := map[string]bool{}
+	path, err := hiddenCoursesPath()
+	if err != nil || studentID == "" {
+		return hidden
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return hidden
+	}
+	var stored hiddenCourses
+	if json.Unmarshal(raw, &stored) != nil || !strings.EqualFold(stored.StudentID, studentID) {
+		return hidden
+	}
+	for _, id := range stored.CourseIDs {
+		hidden[id] = true
+	}
+	return hidden
+}
+
+func writeHiddenCourses(studentID string, hidden map[string]bool) error {
+	path, err := hiddenCoursesPath()
+	if err != nil {
+		return err
+	}
+	stored := hiddenCourses{StudentID: studentID}
+	for id, ok := range hidden {
+		if ok {
+			stored.CourseIDs = append(stored.CourseIDs, id)
+		}
+	}
+	if len(stored.CourseIDs) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	slices.Sort(stored.CourseIDs)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create app cache dir: %w", err)
+	}
+	raw, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, raw, 0600)
+}
+
+// listedCourses are the indexes of the courses the course list shows: all
+// of them while showHidden is on, else those not hidden.
+func (m model) listedCourses() []int {
+	var listed []int
+	for i, c := range m.courses {
+		if m.showHidden || !m.hiddenCourses[c.ID] {
+			listed = append(listed, i)
+		}
+	}
+	return listed
+}
+
+// stepCourse moves the selection delta places through the listed courses.
+func (m *model) stepCourse(delta int) {
+	listed := m.listedCourses()
+	pos := slices.Index(listed, m.selectedCourse)
+	switch {
+	case len(listed) == 0:
+		return
+	case pos == -1:
+		// The selected course was just hidden; land on its neighbour.
+		pos, _ = slices.BinarySearch(listed, m.selectedCourse)
+		m.selectedCourse = listed[min(pos, len(listed)-1)]
+	default:
+		m.selectedCourse = listed[min(max(pos+delta, 0), len(listed)-1)]
+	}
+}
+
+// toggleHiddenCourse hides or unhides the selected course and saves the
+// change.
+func (m *model) toggleHiddenCourse() {
+	if m.selectedCourse >= len(m.courses) {
+		return
+	}
+	if m.hiddenCourses == nil {
+		m.hiddenCourses = map[string]bool{}
+	}
+	id := m.courses[m.selectedCourse].ID
+	if m.hiddenCourses[id] {
+		delete(m.hiddenCourses, id)
+	} else {
+		m.hiddenCourses[id] = true
+	}
+	if err := writeHiddenCourses(m.Credentials.StudentID, m.hiddenCourses); err != nil {
+		m.refreshNotice = T("courses.hide_failed", err)
+	}
+	if !m.showHidden {
+		m.stepCourse(0)
+	}
+}
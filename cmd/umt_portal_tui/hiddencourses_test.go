@@ -0,0 +1,60 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestHideCourse(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	useMockPortal(t, portal)
+
+	s := NewSession()
+	if code, text := s.Login(Credentials{StudentID: "F2023000000", Password: "hunter2"}, false); code != ErrNone {
+		t.Fatalf("login failed: %v %s", code, text)
+	}
+	courses, err := s.GetCourses()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(courses) < 3 {
+		t.Fatalf("mock portal lists %d courses", len(courses))
+	}
+	m := model{session: s, courses: courses, Credentials: Credentials{StudentID: "F2023000000"}, currentView: CoursesView, width: 80, height: 40}
+	press := func(keys ...string) {
+		t.Helper()
+		for _, key := range keys {
+			next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+			m = next.(model)
+		}
+	}
+
+	press("j", "x")
+	if m.selectedCourse != 2 {
+		t.Errorf("selection stayed on the hidden course: %d", m.selectedCourse)
+	}
+	if view := m.View(); strings.Contains(view, courses[1].Code) || !strings.Contains(view, "2. "+courses[2].Code) {
+		t.Errorf("hidden course still listed:\n%s", view)
+	}
+	if hidden := readHiddenCourses("F2023000000"); !hidden[courses[1].ID] || len(hidden) != 1 {
+		t.Errorf("hidden courses not saved: %v", hidden)
+	}
+
+	// The digits count the listed courses only.
+	press("2")
+	if m.currentView != CourseDetailView || m.selectedCourse != 2 {
+		t.Errorf("2 opened %v for course %d", m.currentView, m.selectedCourse)
+	}
+
+	m.goBack()
+	press("H")
+	if view := m.View(); !strings.Contains(view, courses[1].Code+" - "+courses[1].Title) {
+		t.Errorf("hidden course not shown with H:\n%s", view)
+	}
+	press("k", "x")
+	if hidden := readHiddenCourses("F2023000000"); len(hidden) != 0 {
+		t.Errorf("course not unhidden: %v", hidden)
+	}
+}
@@ -0,0 +1,307 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	HOOK_NEW_ASSESSMENT    = "new_assessment"
+	HOOK_ABSENCE           = "absence"
+	HOOK_TRANSCRIPT_UPDATE = "transcript_update"
+
+	HOOK_TIMEOUT = 30 * time.Second
+	// HOOK_LOG_OUTPUT_MAX bounds how much of a failed hook's output the hook
+	// log keeps.
+	HOOK_LOG_OUTPUT_MAX = 2048
+)
+
+// HooksConfig holds shell commands run after a fetch finds something new,
+// with the event as JSON on stdin. Nothing runs for the first fetch of a
+// course or the transcript, which only sets the baseline, or with
+// --no-cache, which keeps no baseline.
+type HooksConfig struct {
+	OnNewAssessment    string `json:"on_new_assessment"`
+	OnAbsence          string `json:"on_absence"`
+	OnTranscriptUpdate string `json:"on_transcript_update"`
+}
+
+type hookCourse struct {
+	ID    string `json:"id"`
+	Code  string `json:"code"`
+	Title string `json:"title"`
+}
+
+type hookAssessment struct {
+	Name          string  `json:"name"`
+	ObtainedMarks float32 `json:"obtained_marks"`
+	TotalMarks    float32 `json:"total_marks"`
+	Date          string  `json:"date"`
+}
+
+type hookLecture struct {
+	Number  int    `json:"number"`
+	Date    string `json:"date"`
+	Faculty string `json:"faculty"`
+}
+
+type hookGrade struct {
+	Semester      string `json:"semester"`
+	Code          string `json:"code"`
+	Title         string `json:"title"`
+	Grade         string `json:"grade"`
+	PreviousGrade string `json:"previous_grade,omitempty"`
+}
+
+// hookEvent is what a hook reads on stdin; which of the optional fields are
+// set depends on Event.
+type hookEvent struct {
+	Event      string          `json:"event"`
+	Time       time.Time       `json:"time"`
+	StudentID  string          `json:"student_id"`
+	Course     *hookCourse     `json:"course,omitempty"`
+	Assessment *hookAssessment `json:"assessment,omitempty"`
+	Lecture    *hookLecture    `json:"lecture,omitempty"`
+	// Attendance is the course's attendance percentage after the absence.
+	Attendance   float64     `json:"attendance_percentage,omitempty"`
+	PreviousCGPA string      `json:"previous_cgpa,omitempty"`
+	CGPA         string      `json:"cgpa,omitempty"`
+	Grades       []hookGrade `json:"grades,omitempty"`
+}
+
+func (c HooksConfig) command(event string) string {
+	switch event {
+	case HOOK_NEW_ASSESSMENT:
+		return c.OnNewAssessment
+	case HOOK_ABSENCE:
+		return c.OnAbsence
+	case HOOK_TRANSCRIPT_UPDATE:
+		return c.OnTranscriptUpdate
+	}
+	return ""
+}
+
+// cachedCourse is the course as the data cache had it before a fetch, the
+// baseline hooks compare with. ok is false when there is none.
+func cachedCourse(s *Session, courseID string) (course SerializableCourse, ok bool) {
+	if cacheMode == CACHE_OFF {
+		return course, false
+	}
+	data, err := readDataCache()
+	if err != nil || data.Student.ID != s.Student.ID {
+		return course, false
+	}
+	for _, c := range data.Courses {
+		if c.ID == courseID {
+			return c, true
+		}
+	}
+	return course, false
+}
+
+// cachedTranscript is the transcript cache before a fetch, or nil.
+func cachedTranscript() *SerializableTranscript {
+	if cacheMode == CACHE_OFF {
+		return nil
+	}
+	raw, err := readCacheFile(transcriptCachePath())
+	if err != nil {
+		return nil
+	}
+	var t SerializableTranscript
+	if json.Unmarshal(raw, &t) != nil || len(t.Semesters) == 0 {
+		return nil
+	}
+	return &t
+}
+
+// assessmentEvents are the assessments of the course that weren't there
+// before.
+func assessmentEvents(s *Session, before SerializableCourse, course Course) []hookEvent {
+	if len(before.Assessments) == 0 {
+		return nil
+	}
+	known := map[string]bool{}
+	for _, a := range before.Assessments {
+		known[a.Name] = true
+	}
+	var events []hookEvent
+	for _, a := range course.Assessment {
+		if known[a.name] {
+			continue
+		}
+		events = append(events, hookEvent{
+			Event:      HOOK_NEW_ASSESSMENT,
+			StudentID:  s.Student.ID,
+			Course:     &hookCourse{ID: course.ID, Code: course.Code, Title: course.Title},
+			Assessment: &hookAssessment{Name: a.name, ObtainedMarks: a.obtainedMarks, TotalMarks: a.totalMarks, Date: a.assignedDate},
+		})
+	}
+	return events
+}
+
+// absenceEvents are the lectures newly marked absent, including ones
+// changed from present.
+func absenceEvents(s *Session, before SerializableCourse, course Course) []hookEvent {
+	if len(before.Attendance) == 0 {
+		return nil
+	}
+	absent := map[int]bool{}
+	for _, a := range before.Attendance {
+		absent[a.LectureNumber] = !a.Attendance
+	}
+	var events []hookEvent
+	for _, a := range course.Attendance {
+		if a.Attendance || absent[a.LectureNumber] {
+			continue
+		}
+		events = append(events, hookEvent{
+			Event:      HOOK_ABSENCE,
+			StudentID:  s.Student.ID,
+			Course:     &hookCourse{ID: course.ID, Code: course.Code, Title: course.Title},
+			Lecture:    &hookLecture{Number: a.LectureNumber, Date: a.LectureDate, Faculty: a.Faculty},
+			Attendance: course.AttendancePercentage,
+		})
+	}
+	return events
+}
+
+// transcriptEvent reports the grades added or changed since before, and
+// the CGPA.
+func transcriptEvent(s *Session, before *SerializableTranscript) []hookEvent {
+	if before == nil {
+		return nil
+	}
+	type key struct{ semester, code string }
+	previous := map[key]string{}
+	for _, sem := range before.Semesters {
+		for _, c := range sem.Courses {
+			previous[key{sem.Name, c.Code}] = c.Grade
+		}
+	}
+	after := s.Student.Transcript.ToSerializable()
+	event := hookEvent{Event: HOOK_TRANSCRIPT_UPDATE, StudentID: s.Student.ID, PreviousCGPA: before.TotalCGPA, CGPA: after.TotalCGPA}
+	for _, sem := range after.Semesters {
+		for _, c := range sem.Courses {
+			grade, ok := previous[key{sem.Name, c.Code}]
+			if ok && grade == c.Grade {
+				continue
+			}
+			event.Grades = append(event.Grades, hookGrade{Semester: sem.Name, Code: c.Code, Title: c.Title, Grade: c.Grade, PreviousGrade: grade})
+		}
+	}
+	if len(event.Grades) == 0 && event.CGPA == event.PreviousCGPA {
+		return nil
+	}
+	return []hookEvent{event}
+}
+
+// runHookCommand runs a hook through the shell; tests replace it.
+var runHookCommand = func(command string, stdin []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), HOOK_TIMEOUT)
+	defer cancel()
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	cmd.Stdin = bytes.NewReader(stdin)
+	return cmd.CombinedOutput()
+}
+
+// runHooks runs the configured hook of each event in turn. The fetch they
+// follow has already succeeded, so a failing hook is only written to the
+// hook log, where the TUI can't be disturbed by it.
+func runHooks(events []hookEvent) {
+	for _, e := range events {
+		command := appConfig.Hooks.command(e.Event)
+		if command == "" {
+			continue
+		}
+		e.Time = time.Now()
+		payload, err := json.Marshal(e)
+		if err != nil {
+			continue
+		}
+		if out, err := runHookCommand(command, payload); err != nil {
+			if len(out) > HOOK_LOG_OUTPUT_MAX {
+				out = out[:HOOK_LOG_OUTPUT_MAX]
+			}
+			logHookFailure(e.Event, err, out)
+		}
+	}
+}
+
+var (
+	// hooksMu guards lastHooks, closed once the hooks queued last are done.
+	hooksMu   sync.Mutex
+	lastHooks chan struct{}
+	// pendingHooks counts the queued hook runs the process waits for
+	// before it exits.
+	pendingHooks sync.WaitGroup
+)
+
+// queueHooks runs the hooks of events in the background, after the ones
+// queued before them, so neither the fetch they follow nor the fetches
+// coalesced onto it wait on a user's script.
+func queueHooks(events []hookEvent) {
+	if len(events) == 0 {
+		return
+	}
+	hooksMu.Lock()
+	previous, done := lastHooks, make(chan struct{})
+	lastHooks = done
+	hooksMu.Unlock()
+
+	pendingHooks.Add(1)
+	go func() {
+		defer pendingHooks.Done()
+		defer close(done)
+		if previous != nil {
+			<-previous
+		}
+		runHooks(events)
+	}()
+}
+
+// waitForHooks waits until every queued hook has run.
+func waitForHooks() {
+	pendingHooks.Wait()
+}
+
+func hookLogPath() (string, error) {
+	dir, err := stateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "hooks.log"), nil
+}
+
+func logHookFailure(event string, err error, out []byte) {
+	path, pathErr := hookLogPath()
+	if pathErr != nil {
+		return
+	}
+	if os.MkdirAll(filepath.Dir(path), 0700) != nil {
+		return
+	}
+	f, openErr := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if openErr != nil {
+		return
+	}
+	defer f.Close()
+	fmt.Fprintf(f, "%s on_%s failed: %v\n", time.Now().Format(time.RFC3339), event, err)
+	if trimmed := strings.TrimSpace(string(out)); trimmed != "" {
+		fmt.Fprintln(f, trimmed)
+	}
+}
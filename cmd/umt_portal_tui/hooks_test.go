@@ -0,0 +1,154 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestHooks(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	useMockPortal(t, portal)
+	appConfig.Hooks = HooksConfig{OnNewAssessment: "new-assessment.sh", OnAbsence: "absence.sh", OnTranscriptUpdate: "transcript.sh"}
+
+	var ran []string
+	var events []hookEvent
+	prev := runHookCommand
+	t.Cleanup(func() { runHookCommand = prev })
+	runHookCommand = func(command string, stdin []byte) ([]byte, error) {
+		var e hookEvent
+		if err := json.Unmarshal(stdin, &e); err != nil {
+			t.Fatalf("%s got %s: %v", command, stdin, err)
+		}
+		ran = append(ran, command)
+		events = append(events, e)
+		return nil, nil
+	}
+
+	var s *Session
+	var id string
+	// Each fetch is a run of its own, starting from the cache.
+	fetch := func() {
+		t.Helper()
+		s = NewSession()
+		if code, text := s.Login(Credentials{StudentID: "F2023000000", Password: "hunter2"}, false); code != ErrNone {
+			t.Fatalf("login failed: %v %s", code, text)
+		}
+		courses, err := s.GetCourses()
+		if err != nil {
+			t.Fatal(err)
+		}
+		id = courses[0].ID
+		if err := s.GetCourseAssessments(id); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.GetCourseAttendance(true, id); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.GetTranscript(true); err != nil {
+			t.Fatal(err)
+		}
+		waitForHooks()
+	}
+
+	fetch()
+	if len(ran) != 0 {
+		t.Fatalf("the first fetch ran %v", ran)
+	}
+
+	// Forget an assessment, an absence and a grade, as if they were new.
+	data, err := readDataCache()
+	if err != nil {
+		t.Fatal(err)
+	}
+	course := &data.Courses[getCourseIndex(s, id)]
+	missing := course.Assessments[0].Name
+	course.Assessments = course.Assessments[1:]
+	var absence int
+	for i, a := range course.Attendance {
+		if !a.Attendance {
+			absence = a.LectureNumber
+			course.Attendance[i].Attendance = true
+			break
+		}
+	}
+	if err := writeDataCache(data); err != nil {
+		t.Fatal(err)
+	}
+	transcript := s.Student.Transcript.ToSerializable()
+	graded := transcript.Semesters[0].Courses[0]
+	transcript.Semesters[0].Courses[0].Grade = "I"
+	raw, _ := json.Marshal(transcript)
+	path, _ := transcriptCachePath()
+	if err := os.WriteFile(path, raw, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	fetch()
+	if len(events) != 3 {
+		t.Fatalf("ran %v", ran)
+	}
+	if e := events[0]; ran[0] != "new-assessment.sh" || e.Event != HOOK_NEW_ASSESSMENT || e.StudentID != "F2023000000" || e.Assessment == nil || e.Assessment.Name != missing || e.Course.ID != id {
+		t.Errorf("new assessment: %s %+v", ran[0], e)
+	}
+	if e := events[1]; ran[1] != "absence.sh" || e.Event != HOOK_ABSENCE || e.Lecture == nil || e.Lecture.Number != absence {
+		t.Errorf("absence: %s %+v", ran[1], e)
+	}
+	if e := events[2]; ran[2] != "transcript.sh" || len(e.Grades) != 1 || e.Grades[0].Code != graded.Code || e.Grades[0].PreviousGrade != "I" || e.Grades[0].Grade != graded.Grade {
+		t.Errorf("transcript update: %s %+v", ran[2], e)
+	}
+}
+
+func TestSlowHookDoesNotHoldUpFetch(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	useMockPortal(t, portal)
+	appConfig.Hooks = HooksConfig{OnNewAssessment: "slow.sh"}
+
+	s := NewSession()
+	if code, text := s.Login(Credentials{StudentID: "F2023000000", Password: "hunter2"}, false); code != ErrNone {
+		t.Fatalf("login failed: %v %s", code, text)
+	}
+	courses, err := s.GetCourses()
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := courses[0].ID
+	if err := s.GetCourseAssessments(id); err != nil {
+		t.Fatal(err)
+	}
+	data, err := readDataCache()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data.Courses[getCourseIndex(s, id)].Assessments = data.Courses[getCourseIndex(s, id)].Assessments[1:]
+	if err := writeDataCache(data); err != nil {
+		t.Fatal(err)
+	}
+
+	release, ran := make(chan struct{}), make(chan string, 1)
+	prev := runHookCommand
+	t.Cleanup(func() { runHookCommand = prev })
+	runHookCommand = func(command string, stdin []byte) ([]byte, error) {
+		<-release
+		ran <- command
+		return nil, nil
+	}
+
+	fetched := make(chan error, 1)
+	go func() { fetched <- s.GetCourseAssessments(id) }()
+	select {
+	case err := <-fetched:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		close(release)
+		t.Fatal("the fetch waited on its hook")
+	}
+	close(release)
+	waitForHooks()
+	if command := <-ran; command != "slow.sh" {
+		t.Errorf("ran %q, want slow.sh", command)
+	}
+}
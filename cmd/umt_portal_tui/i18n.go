@@ -0,0 +1,429 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+const DEFAULT_LANGUAGE = "en"
+
+var catalogs = map[string]map[string]string{
+	"en": enMessages,
+	"ur": urMessages,
+}
+
+var currentLanguage = DEFAULT_LANGUAGE
+
+// T returns the message for key in the current language, falling back to
+// English and then to the key itself. Args are applied with fmt.Sprintf.
+func T(key string, args ...any) string {
+	msg, ok := catalogs[currentLanguage][key]
+	if !ok {
+		msg, ok = enMessages[key]
+	}
+	if !ok {
+		msg = key
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
+
+func setLanguage(lang string) {
+	if _, ok := catalogs[lang]; ok {
+		currentLanguage = lang
+		return
+	}
+	currentLanguage = DEFAULT_LANGUAGE
+}
+
+// detectLanguage picks the UI language from the config file, then from
+// LC_ALL, LC_MESSAGES and LANG. The first non-empty setting wins, so
+// language "en" in the config overrides LANG=ur_PK.UTF-8.
+func detectLanguage(cfg Config) string {
+	for _, value := range []string{cfg.Language, os.Getenv("LC_ALL"), os.Getenv("LC_MESSAGES"), os.Getenv("LANG")} {
+		if value == "" {
+			continue
+		}
+		lang := strings.ToLower(value)
+		if i := strings.IndexAny(lang, "_-.@"); i >= 0 {
+			lang = lang[:i]
+		}
+		if _, ok := catalogs[lang]; ok {
+			return lang
+		}
+		return DEFAULT_LANGUAGE
+	}
+	return DEFAULT_LANGUAGE
+}
+
+var enMessages = map[string]string{
+	"error":        "❌ Error: %v",
+	"view.unknown": "Unknown view",
+
+	"update.available": "⬆ %s is available (you have %s) • run `%s update`",
+
+	"recovery.lost":     "⚠ Connection lost — retrying in %s",
+	"recovery.retrying": "⚠ Connection lost — retrying now...",
+
+	"diag.hint":   "⚠ %s failed: %v • Ctrl+E: Details",
+	"diag.title":  "🩺 Diagnostics",
+	"diag.help":   "• Ctrl+Y: Copy report (redacted) • Ctrl+E/Esc: Close",
+	"diag.copied": "✓ Report copied to the clipboard",
+
+	"picker.title":      "📅 Jump to a semester",
+	"picker.more_above": "↑ %d more",
+	"picker.more_below": "↓ %d more",
+	"picker.help":       "• ↑ ↓: Navigate • G/Shift+G: First/Last • Enter: Open • Esc: Cancel",
+
+	"retake.title":          "🎯 Retake Analysis",
+	"retake.summary":        "Current CGPA: %.2f • Assumed retake grade: %s",
+	"retake.none":           "No course below C counts toward your CGPA",
+	"retake.all":            "Retaking all %d: CGPA %.2f (+%.2f)",
+	"retake.col_code":       "Code",
+	"retake.col_title":      "Course Title",
+	"retake.col_semester":   "Semester",
+	"retake.col_credits":    "Cr.",
+	"retake.col_grade":      "Grade",
+	"retake.col_cgpa":       "New CGPA",
+	"retake.col_delta":      "Gain",
+	"retake.col_per_credit": "Gain/Cr.",
+	"retake.help":           "• ↑ ↓: Navigate • ← →: Assumed grade • Esc: Back • Q: Quit",
+
+	"loading.login":             "🔐 Logging in, please wait",
+	"loading.login_help":        "Authenticating your credentials with the UMT portal",
+	"loading.login_cached_help": "Authenticating your cached credentials with the UMT portal",
+	"loading.courses":           "📚 Loading courses, please wait",
+	"loading.courses_help":      "Fetching your enrolled courses from the portal",
+	"loading.transcript":        "📄 Getting transcript, please wait",
+	"loading.transcript_help":   "Fetching your complete academic transcript",
+	"loading.attendance":        "📊 Getting attendance for %s...",
+	"loading.attendance_help":   "Fetching attendance records",
+	"loading.assessments":       "📝 Getting assessments for %s...",
+	"loading.assessments_help":  "Fetching detailed assessment information",
+	"loading.outline":           "📑 Getting outline for %s...",
+	"loading.outline_help":      "Downloading the course outline from the portal",
+	"loading.results":           "🎓 Getting semester result, please wait",
+	"loading.results_help":      "Fetching the provisional result from the portal",
+	"loading.fees":              "💳 Getting fee records, please wait",
+	"loading.fees_help":         "Fetching your payment history from the portal",
+	"progress.stage":            "%s %d/%d",
+	"progress.attempt":          "attempt %d/%d",
+	"progress.select_course":    "Selecting course...",
+	"progress.open_report":      "Opening report...",
+	"progress.render_report":    "Waiting for the report...",
+	"progress.read_attendance":  "Reading attendance...",
+	"progress.open_transcript":  "Opening transcript...",
+	"progress.load_transcript":  "Loading the report...",
+	"progress.read_transcript":  "Reading grades...",
+
+	"progress.busy": "Portal busy, retrying at %s",
+
+	"progress.partial": "Report still loading (attempt %d/%d) • %d lectures so far",
+
+	"refresh.active": "Running %d background job(s)... (J: Jobs)",
+	"refresh.done":   "✓ Updated at %s",
+	"refresh.failed": "✗ Refresh failed: %v",
+
+	"jobs.title":       "⚙ Background Jobs",
+	"jobs.none":        "No background jobs",
+	"jobs.col_job":     "Job",
+	"jobs.col_state":   "State",
+	"jobs.col_time":    "Time",
+	"jobs.col_detail":  "Detail",
+	"jobs.queued":      "queued",
+	"jobs.running":     "running",
+	"jobs.done":        "done",
+	"jobs.failed":      "failed",
+	"jobs.cancelled":   "cancelled",
+	"jobs.courses":     "Courses",
+	"jobs.transcript":  "Transcript",
+	"jobs.attendance":  "Attendance: %s",
+	"jobs.assessments": "Assessments: %s",
+	"jobs.help":        "• ↑/↓: Navigate • X: Cancel • R: Retry • C: Clear finished • Esc: Back • Q: Quit",
+
+	"loading.help_quit":         "• Q: Cancel and quit",
+	"loading.help_back":         "• Esc: Back • Q: Cancel and quit",
+	"loading.help_back_courses": "• Esc: Back to courses • Q: Cancel and quit",
+	"loading.help_back_chat":    "• Esc: Back to chat • Q: Cancel and quit",
+
+	"login.title":                  "UMT Portal TUI by Sunbreeze",
+	"login.student_id":             "Student ID:",
+	"login.student_id_placeholder": "Enter your student ID",
+	"login.password":               "Password:",
+	"login.password_placeholder":   "Enter your password",
+	"login.remember_me":            "%s Remember me",
+	"login.button":                 "Login",
+	"login.help":                   "• ↑/↓: Navigate • Ctrl+S: Show password • Enter/Space: Select • Ctrl+C/Q: Quit",
+	"login.insecure_warning":       "⚠️ TLS certificate verification is DISABLED (insecure_skip_verify)",
+
+	"login.id_required":       "Enter your student ID",
+	"login.id_format":         "A student ID is F or S, the year and 6 digits, e.g. F2021123456",
+	"login.id_year":           "%d is not a year UMT has admitted students in",
+	"login.password_required": "Enter your password",
+
+	"result.success":       "✅ You have successfully logged in to the UMT portal!\n",
+	"result.network":       "🌐 Network issue encountered! Please check your internet.\n",
+	"result.invalid":       "❌ Invalid credentials! Please check your student ID and password.\n",
+	"result.parse":         "❓ Error parsing the response! Please try again later.\n",
+	"result.locked":        "🔒 %d failed logins in a row. The saved password was removed to protect your account.\nTry again in %s.\n",
+	"result.unlocked":      "🔓 You can try logging in again.\n",
+	"captcha.title":        "🔐 The portal is asking for a security code",
+	"captcha.prompt":       "Type the characters shown above:",
+	"captcha.unreadable":   "The security code image could not be shown. Press Ctrl+R for a new one.",
+	"captcha.help":         "• Enter: Log in • Ctrl+R: New image • Esc: Back",
+	"session.expired":      "⚠ Your portal session expired and could not be renewed. Log out (L) and sign in again.",
+	"result.prefetching":   "⏳ Fetching your courses and transcript in the background...\n",
+	"result.courses_ready": "📚 %d courses ready\n",
+	"result.unknown":       "❓ An unknown error occurred! Please try again later.\n",
+	"result.help_success":  "• Enter: Continue to courses • R: Retry • Q: Quit",
+	"result.help_failure":  "• R: Retry • Q: Quit",
+
+	"courses.welcome":       "Welcome",
+	"courses.cgpa":          "CGPA",
+	"courses.ch_registered": "C.Hrs. Registered:",
+	"courses.ch_earned":     "C.Hrs. Earned:",
+	"courses.none":          "No courses found.",
+	"courses.item":          "%s - %s (%s CH)",
+	"courses.help_empty":    "• T: Transcript • G: Results • F: Fees • C: AI Chat • R: Refresh • J: Jobs • N: Latency • O: Open in browser • L: Log out • Q: Quit",
+	"courses.deadlines":     "📌 Upcoming Deadlines",
+	"courses.lms_error":     "LMS: %v",
+	"courses.help":          "• ↑/↓: Navigate • Enter/1-9: Details • A/Shift+1-9: Attendance • S: Assessments • Shift+A: Fetch all attendance • T: Transcript • G: Results • F: Fees • C: AI Chat • R: Refresh • J: Jobs • N: Latency • O: Open in browser • Ctrl+F: Search • X: Hide • L: Log out • Q: Quit",
+
+	"courses.hidden_tag":   "[hidden]",
+	"courses.hidden_count": "%d hidden course(s) • Shift+H: Show",
+	"courses.all_hidden":   "All %d courses are hidden • Shift+H: Show them",
+	"courses.hide_failed":  "Could not save hidden courses: %v",
+
+	"courses.panel_no_attendance": "Attendance not fetched yet (A: fetch)",
+	"courses.panel_absences":      "Absences: %d",
+
+	"detail.title":        "📖 Course Details: %s",
+	"detail.course_title": "Title:",
+	"detail.credit_hours": "Credit Hours:",
+	"detail.type":         "Type:",
+	"detail.faculty":      "Faculty:",
+	"detail.email":        "Email:",
+	"detail.mode":         "Mode:",
+	"detail.section":      "Section:",
+	"detail.semester":     "Semester:",
+	"detail.deadlines":    "📌 LMS Deadlines:",
+	"detail.no_deadlines": "No pending LMS deadlines",
+	"detail.help":         "• Tab/Shift+Tab: Switch tab • A: Get Attendance • S: Get Assessments • O: View Outline • D: Save Outline • Esc: Back to courses • Q: Quit",
+
+	"outline.title":    "📑 Course Outline: %s",
+	"outline.saved":    "Saved to %s",
+	"outline.position": "Lines %d-%d of %d",
+	"outline.help":     "• Tab/Shift+Tab: Switch tab • ↑/↓ PgUp/PgDn: Scroll • D: Save • Esc: Back • Q: Quit",
+
+	"report.title":              "%s Report: %s",
+	"report.attendance":         "📊 Attendance",
+	"report.attendance_summary": "Total Lectures: %d | Attendance: %.1f%%",
+	"report.attendance_empty":   "No attendance records available",
+	"report.below_threshold":    "⚠ below %.0f%% threshold",
+	"report.assessment":         "📝 Assessment",
+	"report.assessment_summary": "Total Assessments: %d | Obtained: %.1f/%.1f (%.1f%%)",
+	"report.assessment_empty":   "No assessment records available",
+	"report.col_number":         "#",
+	"report.col_date":           "Date",
+	"report.col_status":         "Status",
+	"report.col_faculty":        "Faculty",
+	"report.col_name":           "Name",
+	"report.col_obtained":       "Obtained",
+	"report.col_total":          "Total",
+	"report.col_percentage":     "Percentage",
+	"report.present":            "Present",
+	"report.absent":             "Absent",
+	"report.page":               "Page %d/%d • ←/→ to navigate",
+	"report.help_empty":         "• Tab/Shift+Tab: Switch tab • O: Open in browser • Esc/Enter: Back • R: Refresh • Q: Quit",
+	"report.help":               "• Tab/Shift+Tab: Switch tab • Esc: Back • R: Refresh • Q: Quit",
+
+	"assessment.category":            "%s (%d)",
+	"assessment.category_quiz":       "Quizzes",
+	"assessment.category_assignment": "Assignments",
+	"assessment.category_midterm":    "Midterm",
+	"assessment.category_final":      "Final",
+	"assessment.category_project":    "Projects",
+	"assessment.category_other":      "Other",
+
+	"assessment.category_best":   "%s (best %d of %d)",
+	"assessment.dropped":         "dropped",
+	"assessment.weighted":        "Weighted: %.1f of %.0f%% assessed so far • projected %.1f%%",
+	"assessment.projected_grade": "(%s)",
+
+	"assessment.help":   "• E: Copy as Markdown • Shift+E: Save as HTML • Shift+G: Grade scale • D: Request a recheck • Tab/Shift+Tab: Switch tab • O: Open in browser • Esc: Back • R: Refresh • Q: Quit",
+	"assessment.copied": "Assessments copied to the clipboard as a Markdown table",
+
+	"report.col_class":      "vs Class",
+	"assessment.vs_average": "%+.1f vs avg",
+	"assessment.highest":    "• top %.1f",
+
+	"attendance.help":           "• /: Filter by date • A: Absences only • M: Month • X: Clear filter • D: Dispute an absence • Tab/Shift+Tab: Switch tab • O: Open in browser • Esc: Back • R: Refresh • Q: Quit",
+	"attendance.filter":         "Filter:",
+	"attendance.filter_hint":    "A date (12-Mar-2025), a month (Mar 2025) or a range (1-Mar-2025..15-Mar-2025) • Enter: Apply • Esc: Cancel",
+	"attendance.filter_invalid": "Can't read %q as a date, month or range",
+	"attendance.filter_absent":  "Absences",
+	"attendance.filter_count":   "(%d of %d lectures)",
+	"attendance.filter_none":    "No lectures match the filter.",
+
+	"dispute.title":       "✉️ Dispute an Absence: %s",
+	"dispute.lecture":     "Lecture %d  %s",
+	"dispute.to":          "To: %s",
+	"dispute.subject":     "Subject: %s",
+	"dispute.no_email":    "(no faculty email listed)",
+	"dispute.help":        "• ↑/↓: Choose • Enter: Open in mail client • C: Copy draft • Esc: Cancel",
+	"dispute.none":        "No absences to dispute in the lectures shown",
+	"dispute.opened":      "Draft opened in your mail client",
+	"dispute.copied":      "Draft copied to the clipboard",
+	"dispute.open_failed": "Could not open a mail client (%v); the draft was copied to the clipboard instead",
+
+	"recheck.title":      "✉️ Request a Recheck: %s",
+	"recheck.assessment": "%s  %.1f/%.1f  %s",
+
+	"results.title":      "🎓 Provisional Result - %s",
+	"results.not_posted": "Results for this semester haven't been posted yet.",
+	"results.pending":    "Pending",
+	"results.sgpa":       "Provisional SGPA: %.2f (%d of %d courses graded)",
+	"results.note":       "Grades are provisional until they appear on the transcript.",
+	"results.help":       "• O: Open in browser • Esc: Back • R: Refresh • Q: Quit",
+
+	"fees.title":        "💳 Fees & Payments",
+	"fees.outstanding":  "Outstanding dues: %s",
+	"fees.clear":        "No outstanding dues",
+	"fees.none":         "No payment records found",
+	"fees.col_challan":  "Challan",
+	"fees.col_semester": "Semester",
+	"fees.col_desc":     "Description",
+	"fees.col_amount":   "Amount",
+	"fees.col_due":      "Due",
+	"fees.col_status":   "Status",
+	"fees.save_prompt":  "Save challan to: ",
+	"fees.downloading":  "Downloading challan %s...",
+	"fees.saved":        "Saved to %s",
+	"fees.help":         "• ↑/↓: Navigate • D: Download challan PDF • P: Payment QR • R: Refresh • O: Open in browser • Esc: Back • Q: Quit",
+	"fees.help_prompt":  "• Enter: Save • Esc: Cancel",
+
+	"fees.qr_caption": "Scan with your banking app to pay %s • %s",
+	"fees.qr_paid":    "Challan %s is already paid",
+
+	"fees.installments": "Installments: %s",
+	"fees.installment":  "%s by %s",
+
+	"cgpa.below_floor": "⚠ CGPA %.2f is below your floor of %.2f",
+
+	"transcript.empty":        "No transcript data available",
+	"transcript.title":        "📄 Academic Transcript - %s",
+	"transcript.ch_earned":    "C.Hrs. Earned:",
+	"transcript.ch_gpa":       "C.Hrs. for GPA:",
+	"transcript.total_gp":     "Total G.P:",
+	"transcript.sgpa":         "SGPA:",
+	"transcript.cgpa":         "CGPA:",
+	"transcript.semester_of":  "Semester %d of %d",
+	"transcript.unrecognized": " • ⚠️ Unrecognized semester name, shown last",
+	"transcript.help":         "• ← →: Switch semesters • S: Pick a semester • I: Retake analysis • P: Official PDF • C: Copy as text • Shift+G: Grade scale • ↑ ↓: Navigate • O: Open in browser • Esc: Back • R: Refresh • Q: Quit",
+	"transcript.legend":       "↻ Repeat attempt (counts toward GPA) • ⊘ Earlier attempt, excluded from GPA",
+	"transcript.col_code":     "Code",
+	"transcript.col_title":    "Course Title",
+	"transcript.col_credits":  "Cr. Hrs",
+	"transcript.col_grade":    "Grade",
+	"transcript.col_gp":       "G.P.",
+
+	"transcript.legend_no_gpa": "%s Withdrawn (W), incomplete (I) or pass (P): no grade points, excluded from GPA",
+
+	"transcript.pdf_downloading": "Downloading the official transcript...",
+	"transcript.copied":          "Transcript copied to the clipboard as plain text",
+
+	"lms.deadline": "%s · %s · due %s",
+
+	"grade_scale.title":            "📏 Grade Scale",
+	"grade_scale.default":          "Used for courses without a scale of their own",
+	"grade_scale.course":           "Scale used for %s",
+	"grade_scale.relative":         "%s is graded relative to the class average",
+	"grade_scale.relative_average": "%s is graded relative to the class average of %.1f%%",
+	"grade_scale.offset":           "avg%+g",
+	"grade_scale.col_letter":       "Grade",
+	"grade_scale.col_points":       "Points",
+	"grade_scale.col_percent":      "Percentage",
+	"grade_scale.no_points":        "%s carry no grade points and don't count toward the GPA",
+	"grade_scale.help":             "• Esc: Back • Q: Quit",
+
+	"latency.title":           "Portal Response Times",
+	"latency.col_endpoint":    "Endpoint",
+	"latency.col_last":        "Last",
+	"latency.col_when":        "When",
+	"latency.col_status":      "Status",
+	"latency.col_connect":     "Connect",
+	"latency.col_wait":        "Server",
+	"latency.ago":             "%s ago",
+	"latency.error":           "error",
+	"latency.probing":         "Measuring...",
+	"latency.verdict_network": "Connecting took most of the time: the slowness is likely your network",
+	"latency.verdict_portal":  "The portal took most of the time to answer: the slowness is likely on its side",
+	"latency.note":            "Last: the latest request the app made • Connect: DNS, TCP and TLS (your network) • Server: time to the first byte (the portal)",
+	"latency.help":            "• R: Measure now • Esc: Back • Q: Quit",
+
+	"browser.opened": "Opened %s in your browser; log in there if the portal asks",
+	"browser.failed": "Could not open a browser (%s); the page is %s",
+
+	"snapshot.saved":  "Saved this screen to %s and %s",
+	"snapshot.failed": "Could not save this screen: %v",
+
+	"attention.done": "Finished loading after %s",
+
+	"search.title":           "🔍 Search",
+	"search.hint":            "Type to search courses, faculty, assessments, lecture dates and the transcript",
+	"search.none":            "Nothing loaded matches",
+	"search.kind_course":     "Course",
+	"search.kind_faculty":    "Faculty",
+	"search.kind_assessment": "Assessment",
+	"search.kind_lecture":    "Lecture",
+	"search.kind_transcript": "Transcript",
+	"search.help":            "• ↑ ↓: Navigate • Enter: Open • Esc: Back",
+
+	"nav.courses":     "Courses",
+	"nav.course":      "Course",
+	"nav.details":     "Details",
+	"nav.attendance":  "Attendance",
+	"nav.assessments": "Assessments",
+	"nav.transcript":  "Transcript",
+	"nav.chat":        "AI Chat",
+	"nav.outline":     "Outline",
+	"nav.results":     "Results",
+	"nav.fees":        "Fees",
+	"nav.jobs":        "Jobs",
+	"nav.retake":      "Retakes",
+	"nav.grade_scale": "Grade Scale",
+	"nav.latency":     "Latency",
+	"nav.search":      "Search",
+
+	"chat.title":               "🤖 AI Assistant",
+	"chat.welcome":             "👋 Hi! Ask me about your CGPA, grades, courses, attendance, or transcript!\n\nExamples:\n• What's my CGPA?\n• Show me my attendance\n• Check my grades\n• Who are you?",
+	"chat.you":                 "🧑 You:",
+	"chat.query":               "Your query:",
+	"chat.help":                "• Type your query and press Enter • Esc: Back to courses • Q: Quit",
+	"chat.login_first":         "⚠️ Please log in first",
+	"chat.cgpa":                "📊 Your CGPA is: %s",
+	"chat.found_course":        "🎯 Found course: %s",
+	"chat.fetch_attendance":    "🔄 Fetching attendance for %s...",
+	"chat.fetch_assessments":   "🔄 Fetching assessments for %s...",
+	"chat.fetch_transcript":    "🔄 Fetching transcript data first...",
+	"chat.select_course":       "🔢 Please select a course by number:",
+	"chat.greeting":            "👋 Hello! I'm the UMT Portal AI Assistant, created by Sunbreeze.",
+	"chat.greeting_help":       "I can help you check your marks, attendance, and transcript. How can I help you today?",
+	"chat.identity":            "🤖 I am the UMT Portal TUI Assistant.",
+	"chat.identity_detail":     "I was created by Sunbreeze to help students access their portal data easily via the terminal.",
+	"chat.semester_not_found":  "❌ Semester %d not found in your transcript.",
+	"chat.semester_sgpa":       "📄 Semester %s SGPA: %.2f",
+	"chat.semester_cgpa":       "📈 Semester %s CGPA: %.2f",
+	"chat.semester_courses":    "📚 Courses in %s:",
+	"chat.semester_summary":    "📄 %s Summary:",
+	"chat.semester_stats":      "  SGPA: %.2f | CGPA: %.2f | Cr. Hrs: %d",
+	"chat.showing_details":     "📖 Showing details for %s...",
+	"chat.course_count":        "📚 You have %d enrolled courses. Select one to view details.",
+	"chat.unknown_intent":      "❓ Unknown intent: %s",
+	"chat.selection_cancelled": "❌ Selection cancelled",
+	"chat.invalid_selection":   "❌ Invalid selection. Please enter a number between 1 and %d",
+	"chat.no_matcher":          "❌ Intent matcher not available",
+}
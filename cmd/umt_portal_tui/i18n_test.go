@@ -0,0 +1,85 @@
+package main
+
+import (
+	"regexp"
+	"testing"
+)
+
+var formatVerbPattern = regexp.MustCompile(`%[-+# 0-9.*]*[a-zA-Z%]`)
+
+// TestCatalogsMatchEnglish checks that every translation has an English
+// counterpart and takes the same format arguments.
+func TestCatalogsMatchEnglish(t *testing.T) {
+	for lang, catalog := range catalogs {
+		for key, msg := range catalog {
+			en, ok := enMessages[key]
+			if !ok {
+				t.Errorf("%s: key %q has no English message", lang, key)
+				continue
+			}
+			got := formatVerbPattern.FindAllString(msg, -1)
+			want := formatVerbPattern.FindAllString(en, -1)
+			if len(got) != len(want) {
+				t.Errorf("%s: key %q has verbs %v, English has %v", lang, key, got, want)
+				continue
+			}
+			for i := range got {
+				if got[i] != want[i] {
+					t.Errorf("%s: key %q has verbs %v, English has %v", lang, key, got, want)
+					break
+				}
+			}
+		}
+	}
+}
+
+// TestCatalogsComplete checks that every English message is translated, so
+// a new key can't fall back to English unnoticed.
+func TestCatalogsComplete(t *testing.T) {
+	for lang, catalog := range catalogs {
+		for key := range enMessages {
+			if _, ok := catalog[key]; !ok {
+				t.Errorf("%s: key %q is not translated", lang, key)
+			}
+		}
+	}
+}
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		config, lcAll, lang string
+		want                string
+	}{
+		{"", "", "", "en"},
+		{"", "", "ur_PK.UTF-8", "ur"},
+		{"", "en_US.UTF-8", "ur_PK.UTF-8", "en"},
+		{"ur", "", "en_US.UTF-8", "ur"},
+		{"en", "", "ur_PK.UTF-8", "en"},
+		{"", "", "fr_FR.UTF-8", "en"},
+		{"", "", "C", "en"},
+	}
+
+	for _, tt := range tests {
+		t.Setenv("LC_ALL", tt.lcAll)
+		t.Setenv("LC_MESSAGES", "")
+		t.Setenv("LANG", tt.lang)
+		if got := detectLanguage(Config{Language: tt.config}); got != tt.want {
+			t.Errorf("detectLanguage(config=%q, LC_ALL=%q, LANG=%q) = %q, want %q", tt.config, tt.lcAll, tt.lang, got, tt.want)
+		}
+	}
+}
+
+func TestTFallsBackToEnglish(t *testing.T) {
+	t.Cleanup(func() { setLanguage(DEFAULT_LANGUAGE) })
+	setLanguage("ur")
+
+	if got := T("transcript.col_gp"); got != "G.P." {
+		t.Errorf("T(transcript.col_gp) = %q, want English fallback", got)
+	}
+	if got := T("missing.key"); got != "missing.key" {
+		t.Errorf("T(missing.key) = %q, want the key", got)
+	}
+	if got := T("nav.courses"); got == enMessages["nav.courses"] {
+		t.Errorf("T(nav.courses) was not translated")
+	}
+}
@@ -0,0 +1,372 @@
+package main
+
+// urMessages is the Urdu catalog. Course codes, grade terms (CGPA, SGPA,
+// G.P.) and key names are kept in English as they appear on the portal.
+var urMessages = map[string]string{
+	"error":        "❌ خرابی: %v",
+	"view.unknown": "نامعلوم صفحہ",
+
+	"update.available": "⬆ نیا ورژن %s دستیاب ہے (آپ کے پاس %s ہے) • `%s update` چلائیں",
+
+	"recovery.lost":     "⚠ کنکشن منقطع — %s میں دوبارہ کوشش",
+	"recovery.retrying": "⚠ کنکشن منقطع — دوبارہ کوشش ہو رہی ہے...",
+
+	"diag.hint":   "⚠ %s ناکام: %v • Ctrl+E: تفصیلات",
+	"diag.title":  "🩺 تشخیص",
+	"diag.help":   "• Ctrl+Y: رپورٹ کاپی کریں (حساس معلومات کے بغیر) • Ctrl+E/Esc: بند کریں",
+	"diag.copied": "✓ رپورٹ کلپ بورڈ پر کاپی ہو گئی",
+
+	"picker.title":      "📅 سمسٹر پر جائیں",
+	"picker.more_above": "↑ %d مزید",
+	"picker.more_below": "↓ %d مزید",
+	"picker.help":       "• ↑ ↓: منتقل کریں • G/Shift+G: پہلا/آخری • Enter: کھولیں • Esc: منسوخ",
+
+	"retake.title":          "🎯 دوبارہ کورس کا تجزیہ",
+	"retake.summary":        "موجودہ CGPA: %.2f • دوبارہ کورس میں فرض کردہ گریڈ: %s",
+	"retake.none":           "C سے کم کوئی کورس آپ کے CGPA میں شامل نہیں",
+	"retake.all":            "تمام %d دوبارہ کرنے پر: CGPA %.2f (+%.2f)",
+	"retake.col_semester":   "سمسٹر",
+	"retake.col_code":       "کوڈ",
+	"retake.col_title":      "کورس کا عنوان",
+	"retake.col_credits":    "کریڈٹ",
+	"retake.col_cgpa":       "نیا CGPA",
+	"retake.col_delta":      "اضافہ",
+	"retake.col_per_credit": "اضافہ/کریڈٹ",
+	"retake.col_grade":      "گریڈ",
+	"retake.help":           "• ↑ ↓: منتقل کریں • ← →: فرض کردہ گریڈ • Esc: واپس • Q: بند کریں",
+
+	"loading.login":             "🔐 لاگ ان ہو رہا ہے، براہ کرم انتظار کریں",
+	"loading.login_help":        "UMT پورٹل سے آپ کی اسناد کی تصدیق کی جا رہی ہے",
+	"loading.login_cached_help": "UMT پورٹل سے آپ کی محفوظ شدہ اسناد کی تصدیق کی جا رہی ہے",
+	"loading.courses":           "📚 کورسز لوڈ ہو رہے ہیں، براہ کرم انتظار کریں",
+	"loading.courses_help":      "پورٹل سے آپ کے رجسٹرڈ کورسز حاصل کیے جا رہے ہیں",
+	"loading.transcript":        "📄 ٹرانسکرپٹ حاصل کی جا رہی ہے، براہ کرم انتظار کریں",
+	"loading.transcript_help":   "آپ کی مکمل تعلیمی ٹرانسکرپٹ حاصل کی جا رہی ہے",
+	"loading.attendance":        "📊 %s کی حاضری حاصل کی جا رہی ہے...",
+	"loading.attendance_help":   "حاضری کا ریکارڈ حاصل کیا جا رہا ہے",
+	"loading.assessments":       "📝 %s کے اسیسمنٹس حاصل کیے جا رہے ہیں...",
+	"loading.assessments_help":  "اسیسمنٹس کی تفصیلی معلومات حاصل کی جا رہی ہیں",
+	"loading.outline":           "📑 %s کا کورس آؤٹ لائن حاصل کیا جا رہا ہے...",
+	"loading.outline_help":      "پورٹل سے کورس آؤٹ لائن ڈاؤن لوڈ کیا جا رہا ہے",
+	"loading.results":           "🎓 سمسٹر کا نتیجہ حاصل کیا جا رہا ہے، براہ کرم انتظار کریں",
+	"loading.results_help":      "پورٹل سے عارضی نتیجہ حاصل کیا جا رہا ہے",
+	"loading.fees":              "💳 فیس کا ریکارڈ حاصل کیا جا رہا ہے، براہ کرم انتظار کریں",
+	"loading.fees_help":         "پورٹل سے ادائیگیوں کی تاریخ حاصل کی جا رہی ہے",
+	"progress.stage":            "%s %d/%d",
+	"progress.attempt":          "کوشش %d/%d",
+	"progress.select_course":    "کورس منتخب کیا جا رہا ہے...",
+	"progress.open_report":      "رپورٹ کھولی جا رہی ہے...",
+	"progress.render_report":    "رپورٹ کا انتظار ہے...",
+	"progress.read_attendance":  "حاضری پڑھی جا رہی ہے...",
+	"progress.open_transcript":  "ٹرانسکرپٹ کھولی جا رہی ہے...",
+	"progress.load_transcript":  "رپورٹ لوڈ ہو رہی ہے...",
+	"progress.read_transcript":  "گریڈز پڑھے جا رہے ہیں...",
+
+	"progress.busy": "پورٹل مصروف ہے، %s پر دوبارہ کوشش ہو گی",
+
+	"progress.partial": "رپورٹ ابھی لوڈ ہو رہی ہے (کوشش %d/%d) • اب تک %d لیکچر",
+
+	"refresh.active": "پس منظر میں %d کام جاری ہیں... (J: کام)",
+	"refresh.done":   "✓ %s پر تازہ کیا گیا",
+	"refresh.failed": "✗ تازہ کرنا ناکام: %v",
+
+	"jobs.title":       "⚙ پس منظر کے کام",
+	"jobs.col_job":     "کام",
+	"jobs.col_state":   "حالت",
+	"jobs.col_time":    "وقت",
+	"jobs.col_detail":  "تفصیل",
+	"jobs.none":        "پس منظر میں کوئی کام نہیں",
+	"jobs.queued":      "قطار میں",
+	"jobs.running":     "جاری",
+	"jobs.done":        "مکمل",
+	"jobs.failed":      "ناکام",
+	"jobs.cancelled":   "منسوخ",
+	"jobs.courses":     "کورسز",
+	"jobs.transcript":  "ٹرانسکرپٹ",
+	"jobs.attendance":  "حاضری: %s",
+	"jobs.assessments": "اسیسمنٹس: %s",
+	"jobs.help":        "• ↑/↓: منتقل کریں • X: منسوخ کریں • R: دوبارہ کوشش • C: مکمل صاف کریں • Esc: واپس • Q: بند کریں",
+
+	"loading.help_quit":         "• Q: منسوخ کریں اور بند کریں",
+	"loading.help_back":         "• Esc: واپس • Q: منسوخ کریں اور بند کریں",
+	"loading.help_back_courses": "• Esc: کورسز پر واپس • Q: منسوخ کریں اور بند کریں",
+	"loading.help_back_chat":    "• Esc: چیٹ پر واپس • Q: منسوخ کریں اور بند کریں",
+
+	"login.title":                  "UMT Portal TUI از Sunbreeze",
+	"login.student_id":             "اسٹوڈنٹ آئی ڈی:",
+	"login.student_id_placeholder": "اپنی اسٹوڈنٹ آئی ڈی درج کریں",
+	"login.password":               "پاس ورڈ:",
+	"login.password_placeholder":   "اپنا پاس ورڈ درج کریں",
+	"login.remember_me":            "%s مجھے یاد رکھیں",
+	"login.button":                 "لاگ ان",
+	"login.help":                   "• ↑/↓: منتقل کریں • Ctrl+S: پاس ورڈ دکھائیں • Enter/Space: منتخب کریں • Ctrl+C/Q: بند کریں",
+	"login.insecure_warning":       "⚠️ TLS سرٹیفکیٹ کی تصدیق بند ہے (insecure_skip_verify)",
+
+	"login.id_required":       "اپنی اسٹوڈنٹ آئی ڈی درج کریں",
+	"login.id_format":         "اسٹوڈنٹ آئی ڈی F یا S، سال اور 6 ہندسوں پر مشتمل ہوتی ہے، جیسے F2021123456",
+	"login.id_year":           "%d میں UMT میں داخلے نہیں ہوئے",
+	"login.password_required": "اپنا پاس ورڈ درج کریں",
+
+	"result.success":       "✅ آپ کامیابی سے UMT پورٹل میں لاگ ان ہو گئے ہیں!\n",
+	"result.network":       "🌐 نیٹ ورک کا مسئلہ! براہ کرم اپنا انٹرنیٹ چیک کریں۔\n",
+	"result.invalid":       "❌ غلط اسناد! براہ کرم اپنی اسٹوڈنٹ آئی ڈی اور پاس ورڈ چیک کریں۔\n",
+	"result.parse":         "❓ جواب پڑھنے میں خرابی! براہ کرم بعد میں دوبارہ کوشش کریں۔\n",
+	"result.locked":        "🔒 لگاتار %d ناکام لاگ ان۔ آپ کے اکاؤنٹ کی حفاظت کے لیے محفوظ پاس ورڈ ہٹا دیا گیا ہے۔\n%s بعد دوبارہ کوشش کریں۔\n",
+	"result.unlocked":      "🔓 اب آپ دوبارہ لاگ ان کر سکتے ہیں۔\n",
+	"captcha.title":        "🔐 پورٹل سیکیورٹی کوڈ مانگ رہا ہے",
+	"captcha.prompt":       "اوپر دکھائے گئے حروف لکھیں:",
+	"captcha.unreadable":   "سیکیورٹی کوڈ کی تصویر نہیں دکھائی جا سکی۔ نئی تصویر کے لیے Ctrl+R دبائیں۔",
+	"captcha.help":         "• Enter: لاگ ان • Ctrl+R: نئی تصویر • Esc: واپس",
+	"session.expired":      "⚠ پورٹل سیشن ختم ہو گیا اور دوبارہ شروع نہیں ہو سکا۔ لاگ آؤٹ (L) کر کے دوبارہ سائن ان کریں۔",
+	"result.prefetching":   "⏳ آپ کے کورسز اور ٹرانسکرپٹ پس منظر میں حاصل کیے جا رہے ہیں...\n",
+	"result.courses_ready": "📚 %d کورسز تیار ہیں\n",
+	"result.unknown":       "❓ ایک نامعلوم خرابی پیش آئی! براہ کرم بعد میں دوبارہ کوشش کریں۔\n",
+	"result.help_success":  "• Enter: کورسز پر جائیں • R: دوبارہ کوشش • Q: بند کریں",
+	"result.help_failure":  "• R: دوبارہ کوشش • Q: بند کریں",
+
+	"courses.welcome":       "خوش آمدید",
+	"courses.cgpa":          "CGPA",
+	"courses.ch_registered": "رجسٹرڈ کریڈٹ آورز:",
+	"courses.ch_earned":     "حاصل کردہ کریڈٹ آورز:",
+	"courses.none":          "کوئی کورس نہیں ملا۔",
+	"courses.item":          "%s - %s (%s کریڈٹ)",
+	"courses.help_empty":    "• T: ٹرانسکرپٹ • G: نتائج • F: فیس • C: اے آئی چیٹ • R: تازہ کریں • J: کام • N: رفتار • O: براؤزر میں کھولیں • L: لاگ آؤٹ • Q: بند کریں",
+	"courses.deadlines":     "📌 آنے والی آخری تاریخیں",
+	"courses.lms_error":     "LMS: %v",
+	"courses.help":          "• ↑/↓: منتقل کریں • Enter/1-9: تفصیلات • A/Shift+1-9: حاضری • S: اسیسمنٹس • Shift+A: تمام حاضری • T: ٹرانسکرپٹ • G: نتائج • F: فیس • C: اے آئی چیٹ • R: تازہ کریں • J: کام • N: رفتار • O: براؤزر میں کھولیں • Ctrl+F: تلاش • X: چھپائیں • L: لاگ آؤٹ • Q: بند کریں",
+
+	"courses.hidden_tag":   "[چھپا ہوا]",
+	"courses.hidden_count": "%d چھپے کورس • Shift+H: دکھائیں",
+	"courses.all_hidden":   "تمام %d کورس چھپے ہوئے ہیں • Shift+H: دکھائیں",
+	"courses.hide_failed":  "چھپے کورس محفوظ نہیں ہو سکے: %v",
+
+	"courses.panel_no_attendance": "حاضری ابھی حاصل نہیں کی گئی (A: حاصل کریں)",
+	"courses.panel_absences":      "غیر حاضریاں: %d",
+
+	"detail.title":        "📖 کورس کی تفصیلات: %s",
+	"detail.course_title": "عنوان:",
+	"detail.credit_hours": "کریڈٹ آورز:",
+	"detail.type":         "قسم:",
+	"detail.faculty":      "استاد:",
+	"detail.email":        "ای میل:",
+	"detail.mode":         "طریقہ:",
+	"detail.section":      "سیکشن:",
+	"detail.semester":     "سمسٹر:",
+	"detail.deadlines":    "📌 LMS آخری تاریخیں:",
+	"detail.no_deadlines": "LMS پر کوئی زیر التوا کام نہیں",
+	"detail.help":         "• Tab/Shift+Tab: ٹیب بدلیں • A: حاضری دیکھیں • S: اسیسمنٹس دیکھیں • O: آؤٹ لائن دیکھیں • D: آؤٹ لائن محفوظ کریں • Esc: کورسز پر واپس • Q: بند کریں",
+
+	"outline.title":    "📑 کورس آؤٹ لائن: %s",
+	"outline.saved":    "%s میں محفوظ کر دیا گیا",
+	"outline.position": "سطریں %d-%d از %d",
+	"outline.help":     "• Tab/Shift+Tab: ٹیب بدلیں • ↑/↓ PgUp/PgDn: اسکرول • D: محفوظ کریں • Esc: واپس • Q: بند کریں",
+
+	"report.title":              "%s رپورٹ: %s",
+	"report.attendance":         "📊 حاضری",
+	"report.attendance_summary": "کل لیکچرز: %d | حاضری: %.1f%%",
+	"report.below_threshold":    "⚠ %.0f%% کی حد سے کم",
+	"report.attendance_empty":   "حاضری کا کوئی ریکارڈ دستیاب نہیں",
+	"report.assessment":         "📝 اسیسمنٹ",
+	"report.assessment_summary": "کل اسیسمنٹس: %d | حاصل کردہ: %.1f/%.1f (%.1f%%)",
+	"report.assessment_empty":   "اسیسمنٹ کا کوئی ریکارڈ دستیاب نہیں",
+	"report.col_date":           "تاریخ",
+	"report.col_number":         "#",
+	"report.col_status":         "حیثیت",
+	"report.col_faculty":        "استاد",
+	"report.col_name":           "نام",
+	"report.col_obtained":       "حاصل کردہ",
+	"report.col_total":          "کل",
+	"report.col_percentage":     "فیصد",
+	"report.present":            "حاضر",
+	"report.absent":             "غیر حاضر",
+	"report.page":               "صفحہ %d/%d • ←/→ سے منتقل کریں",
+	"report.help_empty":         "• Tab/Shift+Tab: ٹیب بدلیں • O: براؤزر میں کھولیں • Esc/Enter: واپس • R: تازہ کریں • Q: بند کریں",
+	"report.help":               "• Tab/Shift+Tab: ٹیب بدلیں • Esc: واپس • R: تازہ کریں • Q: بند کریں",
+
+	"assessment.category":            "%s (%d)",
+	"assessment.category_quiz":       "کوئز",
+	"assessment.category_assignment": "اسائنمنٹس",
+	"assessment.category_midterm":    "مڈٹرم",
+	"assessment.category_final":      "فائنل",
+	"assessment.category_project":    "پراجیکٹس",
+	"assessment.category_other":      "دیگر",
+
+	"assessment.category_best":   "%s (بہترین %d، کل %d)",
+	"assessment.dropped":         "خارج",
+	"assessment.weighted":        "وزنی: اب تک جانچے گئے %.1f از %.0f%% • متوقع %.1f%%",
+	"assessment.projected_grade": "(%s)",
+
+	"assessment.help":   "• E: Markdown کاپی کریں • Shift+E: HTML محفوظ کریں • Shift+G: گریڈنگ اسکیل • D: دوبارہ جانچ کی درخواست • Tab/Shift+Tab: ٹیب بدلیں • O: براؤزر میں کھولیں • Esc: واپس • R: تازہ کریں • Q: بند کریں",
+	"assessment.copied": "اسیسمنٹس Markdown جدول کی صورت میں کاپی کر دیے گئے",
+
+	"report.col_class":      "کلاس کے مقابلے",
+	"assessment.vs_average": "اوسط سے %+.1f",
+	"assessment.highest":    "• سب سے زیادہ %.1f",
+
+	"attendance.help":           "• /: تاریخ سے فلٹر • A: صرف غیر حاضریاں • M: مہینہ • X: فلٹر ختم کریں • D: غیر حاضری پر اعتراض • Tab/Shift+Tab: ٹیب بدلیں • O: براؤزر میں کھولیں • Esc: واپس • R: تازہ کریں • Q: بند کریں",
+	"attendance.filter":         "فلٹر:",
+	"attendance.filter_hint":    "تاریخ (12-Mar-2025)، مہینہ (Mar 2025) یا دورانیہ (1-Mar-2025..15-Mar-2025) • Enter: لاگو کریں • Esc: منسوخ",
+	"attendance.filter_invalid": "%q کو تاریخ، مہینہ یا دورانیہ نہیں سمجھا جا سکا",
+	"attendance.filter_absent":  "غیر حاضریاں",
+	"attendance.filter_count":   "(%d از %d لیکچرز)",
+	"attendance.filter_none":    "فلٹر سے کوئی لیکچر مطابقت نہیں رکھتا۔",
+
+	"dispute.title":       "✉️ غیر حاضری پر اعتراض: %s",
+	"dispute.lecture":     "لیکچر %d  %s",
+	"dispute.to":          "بنام: %s",
+	"dispute.subject":     "موضوع: %s",
+	"dispute.no_email":    "(فیکلٹی کا ای میل درج نہیں)",
+	"dispute.help":        "• ↑/↓: منتخب کریں • Enter: ای میل ایپ میں کھولیں • C: مسودہ کاپی کریں • Esc: منسوخ",
+	"dispute.none":        "دکھائے گئے لیکچرز میں کوئی غیر حاضری نہیں",
+	"dispute.opened":      "مسودہ آپ کی ای میل ایپ میں کھول دیا گیا",
+	"dispute.copied":      "مسودہ کلپ بورڈ پر کاپی کر دیا گیا",
+	"dispute.open_failed": "ای میل ایپ نہیں کھل سکی (%v)؛ مسودہ کلپ بورڈ پر کاپی کر دیا گیا",
+
+	"recheck.title":      "✉️ دوبارہ جانچ کی درخواست: %s",
+	"recheck.assessment": "%s  %.1f/%.1f  %s",
+
+	"transcript.empty":        "ٹرانسکرپٹ کا کوئی ڈیٹا دستیاب نہیں",
+	"transcript.title":        "📄 تعلیمی ٹرانسکرپٹ - %s",
+	"transcript.ch_earned":    "حاصل کردہ کریڈٹ آورز:",
+	"transcript.sgpa":         "SGPA:",
+	"transcript.cgpa":         "CGPA:",
+	"transcript.ch_gpa":       "GPA کے کریڈٹ آورز:",
+	"transcript.total_gp":     "کل G.P:",
+	"transcript.semester_of":  "سمسٹر %d از %d",
+	"transcript.unrecognized": " • ⚠️ نامعلوم سمسٹر کا نام، آخر میں دکھایا گیا",
+	"results.title":           "🎓 عارضی نتیجہ - %s",
+	"results.not_posted":      "اس سمسٹر کے نتائج ابھی جاری نہیں ہوئے۔",
+	"results.pending":         "زیر التوا",
+	"results.sgpa":            "عارضی SGPA: %.2f (%d میں سے %d کورسز کے گریڈ)",
+	"results.note":            "ٹرانسکرپٹ پر آنے تک گریڈز عارضی ہیں۔",
+	"results.help":            "• O: براؤزر میں کھولیں • Esc: واپس • R: تازہ کریں • Q: بند کریں",
+
+	"fees.title":        "💳 فیس اور ادائیگیاں",
+	"fees.col_challan":  "چالان",
+	"fees.col_semester": "سمسٹر",
+	"fees.col_desc":     "تفصیل",
+	"fees.col_amount":   "رقم",
+	"fees.col_due":      "آخری تاریخ",
+	"fees.col_status":   "حیثیت",
+	"fees.outstanding":  "واجب الادا رقم: %s",
+	"fees.clear":        "کوئی واجب الادا رقم نہیں",
+	"fees.none":         "ادائیگی کا کوئی ریکارڈ نہیں ملا",
+	"fees.save_prompt":  "چالان محفوظ کریں: ",
+	"fees.downloading":  "چالان %s ڈاؤن لوڈ ہو رہا ہے...",
+	"fees.saved":        "%s میں محفوظ کر دیا گیا",
+	"fees.help":         "• ↑/↓: منتقل کریں • D: چالان PDF ڈاؤن لوڈ کریں • P: ادائیگی QR • R: تازہ کریں • O: براؤزر میں کھولیں • Esc: واپس • Q: بند کریں",
+	"fees.help_prompt":  "• Enter: محفوظ کریں • Esc: منسوخ کریں",
+
+	"fees.qr_caption": "%s ادا کرنے کے لیے بینکنگ ایپ سے اسکین کریں • %s",
+	"fees.qr_paid":    "چالان %s پہلے ہی ادا ہو چکا ہے",
+
+	"fees.installments": "اقساط: %s",
+	"fees.installment":  "%s، %s تک",
+
+	"cgpa.below_floor": "⚠ سی جی پی اے %.2f آپ کی کم از کم حد %.2f سے نیچے ہے",
+
+	"transcript.help":        "• ← →: سمسٹر بدلیں • S: سمسٹر منتخب کریں • I: دوبارہ کورس کا تجزیہ • P: سرکاری PDF • C: متن کاپی کریں • Shift+G: گریڈنگ اسکیل • ↑ ↓: منتقل کریں • O: براؤزر میں کھولیں • Esc: واپس • R: تازہ کریں • Q: بند کریں",
+	"transcript.legend":      "↻ دوبارہ کوشش (GPA میں شامل) • ⊘ پچھلی کوشش، GPA سے خارج",
+	"transcript.col_code":    "کوڈ",
+	"transcript.col_title":   "کورس کا عنوان",
+	"transcript.col_credits": "کریڈٹ",
+	"transcript.col_grade":   "گریڈ",
+	"transcript.col_gp":      "G.P.",
+
+	"transcript.legend_no_gpa": "%s واپس لیا گیا (W)، نامکمل (I) یا پاس (P): کوئی گریڈ پوائنٹ نہیں، GPA سے خارج",
+
+	"transcript.pdf_downloading": "سرکاری ٹرانسکرپٹ ڈاؤن لوڈ ہو رہی ہے...",
+	"transcript.copied":          "ٹرانسکرپٹ سادہ متن کے طور پر کاپی کر دی گئی",
+
+	"lms.deadline": "%s · %s · آخری تاریخ %s",
+
+	"grade_scale.title":            "📏 گریڈنگ اسکیل",
+	"grade_scale.default":          "ان کورسز کے لیے جن کا اپنا اسکیل نہیں",
+	"grade_scale.course":           "%s کا اسکیل",
+	"grade_scale.relative":         "%s کی گریڈنگ کلاس اوسط کے لحاظ سے ہے",
+	"grade_scale.relative_average": "%s کی گریڈنگ %.1f%% کی کلاس اوسط کے لحاظ سے ہے",
+	"grade_scale.col_letter":       "گریڈ",
+	"grade_scale.col_points":       "پوائنٹس",
+	"grade_scale.col_percent":      "فیصد",
+	"grade_scale.offset":           "اوسط%+g",
+	"grade_scale.no_points":        "%s کے کوئی گریڈ پوائنٹس نہیں اور یہ GPA میں شامل نہیں",
+	"grade_scale.help":             "• Esc: واپس • Q: بند کریں",
+
+	"latency.title":           "پورٹل کا جوابی وقت",
+	"latency.col_endpoint":    "صفحہ",
+	"latency.col_last":        "آخری",
+	"latency.col_when":        "کب",
+	"latency.col_status":      "اسٹیٹس",
+	"latency.col_connect":     "کنکشن",
+	"latency.col_wait":        "سرور",
+	"latency.ago":             "%s پہلے",
+	"latency.error":           "خرابی",
+	"latency.probing":         "پیمائش ہو رہی ہے...",
+	"latency.verdict_network": "زیادہ وقت کنکشن میں لگا: سستی غالباً آپ کے نیٹ ورک میں ہے",
+	"latency.verdict_portal":  "زیادہ وقت پورٹل کے جواب میں لگا: سستی غالباً پورٹل کی طرف ہے",
+	"latency.note":            "آخری: ایپ کی آخری درخواست • کنکشن: DNS، TCP اور TLS (آپ کا نیٹ ورک) • سرور: پہلے بائٹ تک کا وقت (پورٹل)",
+	"latency.help":            "• R: ابھی ناپیں • Esc: واپس • Q: بند کریں",
+
+	"browser.opened": "%s براؤزر میں کھول دیا گیا؛ پورٹل کہے تو وہاں لاگ ان کریں",
+	"browser.failed": "براؤزر نہیں کھل سکا (%s)؛ صفحہ %s ہے",
+
+	"snapshot.saved":  "یہ اسکرین %s اور %s میں محفوظ کر دی گئی",
+	"snapshot.failed": "یہ اسکرین محفوظ نہیں ہو سکی: %v",
+
+	"attention.done": "%s بعد لوڈنگ مکمل ہو گئی",
+
+	"search.title":           "🔍 تلاش",
+	"search.hint":            "کورسز، اساتذہ، اسیسمنٹس، لیکچر کی تاریخیں اور ٹرانسکرپٹ تلاش کرنے کے لیے لکھیں",
+	"search.none":            "لوڈ شدہ ڈیٹا میں کچھ نہیں ملا",
+	"search.kind_course":     "کورس",
+	"search.kind_faculty":    "استاد",
+	"search.kind_assessment": "اسیسمنٹ",
+	"search.kind_lecture":    "لیکچر",
+	"search.kind_transcript": "ٹرانسکرپٹ",
+	"search.help":            "• ↑ ↓: منتخب کریں • Enter: کھولیں • Esc: واپس",
+
+	"nav.courses":     "کورسز",
+	"nav.course":      "کورس",
+	"nav.details":     "تفصیلات",
+	"nav.attendance":  "حاضری",
+	"nav.assessments": "اسیسمنٹس",
+	"nav.transcript":  "ٹرانسکرپٹ",
+	"nav.chat":        "اے آئی چیٹ",
+	"nav.outline":     "آؤٹ لائن",
+	"nav.results":     "نتائج",
+	"nav.fees":        "فیس",
+	"nav.jobs":        "کام",
+	"nav.retake":      "دوبارہ کورس",
+	"nav.grade_scale": "گریڈنگ اسکیل",
+	"nav.latency":     "رفتار",
+	"nav.search":      "تلاش",
+
+	"chat.title":               "🤖 اے آئی اسسٹنٹ",
+	"chat.welcome":             "👋 السلام علیکم! مجھ سے اپنے CGPA، گریڈز، کورسز، حاضری یا ٹرانسکرپٹ کے بارے میں پوچھیں!\n\nسوالات انگریزی میں لکھیں، مثلاً:\n• What's my CGPA?\n• Show me my attendance\n• Check my grades\n• Who are you?",
+	"chat.you":                 "🧑 آپ:",
+	"chat.query":               "آپ کا سوال:",
+	"chat.help":                "• سوال لکھ کر Enter دبائیں • Esc: کورسز پر واپس • Q: بند کریں",
+	"chat.login_first":         "⚠️ براہ کرم پہلے لاگ ان کریں",
+	"chat.cgpa":                "📊 آپ کا CGPA ہے: %s",
+	"chat.found_course":        "🎯 کورس مل گیا: %s",
+	"chat.fetch_attendance":    "🔄 %s کی حاضری حاصل کی جا رہی ہے...",
+	"chat.fetch_assessments":   "🔄 %s کے اسیسمنٹس حاصل کیے جا رہے ہیں...",
+	"chat.fetch_transcript":    "🔄 پہلے ٹرانسکرپٹ کا ڈیٹا حاصل کیا جا رہا ہے...",
+	"chat.select_course":       "🔢 براہ کرم نمبر سے کورس منتخب کریں:",
+	"chat.greeting":            "👋 السلام علیکم! میں UMT پورٹل اے آئی اسسٹنٹ ہوں، جسے Sunbreeze نے بنایا ہے۔",
+	"chat.greeting_help":       "میں آپ کے نمبرز، حاضری اور ٹرانسکرپٹ دیکھنے میں مدد کر سکتا ہوں۔ آج میں آپ کی کیا مدد کروں؟",
+	"chat.identity":            "🤖 میں UMT Portal TUI اسسٹنٹ ہوں۔",
+	"chat.identity_detail":     "مجھے Sunbreeze نے بنایا ہے تاکہ طلبہ ٹرمینل سے آسانی سے اپنا پورٹل ڈیٹا دیکھ سکیں۔",
+	"chat.semester_not_found":  "❌ سمسٹر %d آپ کی ٹرانسکرپٹ میں نہیں ملا۔",
+	"chat.semester_sgpa":       "📄 سمسٹر %s کا SGPA: %.2f",
+	"chat.semester_cgpa":       "📈 سمسٹر %s کا CGPA: %.2f",
+	"chat.semester_courses":    "📚 %s کے کورسز:",
+	"chat.semester_summary":    "📄 %s کا خلاصہ:",
+	"chat.semester_stats":      "  SGPA: %.2f | CGPA: %.2f | کریڈٹ آورز: %d",
+	"chat.showing_details":     "📖 %s کی تفصیلات دکھائی جا رہی ہیں...",
+	"chat.course_count":        "📚 آپ %d کورسز میں رجسٹرڈ ہیں۔ تفصیلات کے لیے ایک منتخب کریں۔",
+	"chat.unknown_intent":      "❓ نامعلوم ارادہ: %s",
+	"chat.selection_cancelled": "❌ انتخاب منسوخ کر دیا گیا",
+	"chat.invalid_selection":   "❌ غلط انتخاب۔ براہ کرم 1 سے %d کے درمیان نمبر درج کریں",
+	"chat.no_matcher":          "❌ انٹینٹ میچر دستیاب نہیں",
+}
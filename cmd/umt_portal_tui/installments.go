@@ -0,0 +1,116 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+// INSTALLMENT_DATE_FORMAT is how installment dates are written in the
+// config and on the command line.
+const INSTALLMENT_DATE_FORMAT = "2006-01-02"
+
+// DEFAULT_INSTALLMENT_REMINDER_DAYS is how many days ahead check reminds
+// of an installment.
+const DEFAULT_INSTALLMENT_REMINDER_DAYS = 3
+
+// Installment is a part of the outstanding dues and the date to pay it by.
+type Installment struct {
+	Due    time.Time `json:"due"`
+	Amount float64   `json:"amount"`
+}
+
+// feesInstallments is bound to the fees --installments flag.
+var feesInstallments string
+
+func feesFlags(fs *flag.FlagSet) {
+	fs.StringVar(&feesInstallments, "installments", "", "comma-separated dates (YYYY-MM-DD) to split the outstanding dues over, instead of installments in the config")
+}
+
+// parseInstallmentDates reads YYYY-MM-DD dates, earliest first.
+func parseInstallmentDates(dates []string) ([]time.Time, error) {
+	var parsed []time.Time
+	for _, d := range dates {
+		t, err := time.ParseInLocation(INSTALLMENT_DATE_FORMAT, strings.TrimSpace(d), time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("installment date %q is not YYYY-MM-DD", d)
+		}
+		parsed = append(parsed, t)
+	}
+	slices.SortFunc(parsed, func(a, b time.Time) int { return a.Compare(b) })
+	return slices.Compact(parsed), nil
+}
+
+func (c Config) validateInstallments() error {
+	if c.InstallmentReminderDays < 0 {
+		return fmt.Errorf("installment_reminder_days %d is negative", c.InstallmentReminderDays)
+	}
+	_, err := parseInstallmentDates(c.Installments)
+	return err
+}
+
+// installmentDates is the configured plan; LoadConfig has validated it.
+func (c Config) installmentDates() []time.Time {
+	dates, _ := parseInstallmentDates(c.Installments)
+	return dates
+}
+
+func (c Config) installmentReminderDays() int {
+	if c.InstallmentReminderDays > 0 {
+		return c.InstallmentReminderDays
+	}
+	return DEFAULT_INSTALLMENT_REMINDER_DAYS
+}
+
+// splitInstallments divides dues over the dates from today on, in whole
+// rupees with the last installment taking the remainder. Once every date
+// has passed, whatever is still owed is due on the last of them.
+func splitInstallments(dues float64, dates []time.Time, now time.Time) []Installment {
+	if dues <= 0 || len(dates) == 0 {
+		return nil
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	var upcoming []time.Time
+	for _, d := range dates {
+		if !d.Before(today) {
+			upcoming = append(upcoming, d)
+		}
+	}
+	if len(upcoming) == 0 {
+		return []Installment{{Due: dates[len(dates)-1], Amount: dues}}
+	}
+
+	share := math.Floor(dues / float64(len(upcoming)))
+	installments := make([]Installment, len(upcoming))
+	for i, d := range upcoming {
+		installments[i] = Installment{Due: d, Amount: share}
+	}
+	installments[len(installments)-1].Amount = dues - share*float64(len(upcoming)-1)
+	return installments
+}
+
+// installmentReminders reports the installments due within days of now,
+// or already past their date.
+func installmentReminders(installments []Installment, days int, now time.Time) []checkChange {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	var reminders []checkChange
+	for _, in := range installments {
+		left := int(math.Round(in.Due.Sub(today).Hours() / 24))
+		var when string
+		switch {
+		case left < 0:
+			when = fmt.Sprintf("overdue since %s", in.Due.Format(INSTALLMENT_DATE_FORMAT))
+		case left == 0:
+			when = "due today"
+		case left <= days:
+			when = fmt.Sprintf("due %s, in %d day(s)", in.Due.Format(INSTALLMENT_DATE_FORMAT), left)
+		default:
+			continue
+		}
+		reminders = append(reminders, checkChange{Kind: CHECK_FEES, Detail: fmt.Sprintf("installment of %s %s", formatAmount(in.Amount), when)})
+	}
+	return reminders
+}
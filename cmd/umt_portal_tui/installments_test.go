@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSplitInstallments(t *testing.T) {
+	now := time.Date(2026, 10, 17, 15, 0, 0, 0, time.Local)
+	dates, err := parseInstallmentDates([]string{"2026-12-01", "2026-10-01", "2026-11-01", "2026-10-17"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := splitInstallments(98500, dates, now)
+	want := []float64{32833, 32833, 32834}
+	if len(got) != len(want) || !got[0].Due.Equal(time.Date(2026, 10, 17, 0, 0, 0, 0, time.Local)) {
+		t.Fatalf("installments %+v, want three from today", got)
+	}
+	for i, in := range got {
+		if in.Amount != want[i] {
+			t.Errorf("installment %d is %v, want %v", i+1, in.Amount, want[i])
+		}
+	}
+
+	reminders := installmentReminders(got, 3, now)
+	if len(reminders) != 1 || !strings.Contains(reminders[0].Detail, "due today") {
+		t.Errorf("reminders %+v, want only today's", reminders)
+	}
+	late := splitInstallments(5000, dates, now.AddDate(0, 3, 0))
+	if len(late) != 1 || late[0].Amount != 5000 || !strings.Contains(installmentReminders(late, 3, now.AddDate(0, 3, 0))[0].Detail, "overdue since 2026-12-01") {
+		t.Errorf("after the last date: %+v", late)
+	}
+	if _, err := parseInstallmentDates([]string{"1/11/2026"}); err == nil {
+		t.Error("expected a date not in YYYY-MM-DD to be rejected")
+	}
+}
+
+func TestCheckInstallments(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	useMockPortal(t, portal)
+	withTerminal(t, false, "")
+	t.Setenv("UMT_STUDENT_ID", "F2023000000")
+	t.Setenv("UMT_PASSWORD", "hunter2")
+	prev := appConfig
+	t.Cleanup(func() { appConfig = prev })
+
+	appConfig.Installments = []string{time.Now().AddDate(0, 0, 2).Format(INSTALLMENT_DATE_FORMAT), time.Now().AddDate(0, 1, 0).Format(INSTALLMENT_DATE_FORMAT)}
+	c, _ := findCommand("check")
+	var out bytes.Buffer
+	err := runCommand(c, []string{"--format", "json", "fees"}, strings.NewReader(""), &out)
+	if exitCode(err) != EXIT_PAYMENT_DUE {
+		t.Fatalf("expected exit code %d, got %d (%v)", EXIT_PAYMENT_DUE, exitCode(err), err)
+	}
+	var result checkResult
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+	}
+	if len(result.Reminders) != 1 || !strings.Contains(result.Reminders[0].Detail, "in 2 day(s)") {
+		t.Errorf("unexpected reminders: %+v", result.Reminders)
+	}
+
+	c, _ = findCommand("fees")
+	out.Reset()
+	if err := runCommand(c, []string{"--installments", "2099-01-01,2099-02-01"}, strings.NewReader(""), &out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "Installment 2 of 2:") || !strings.Contains(out.String(), "by 2099-02-01") {
+		t.Errorf("no installment plan in the output:\n%s", out.String())
+	}
+}
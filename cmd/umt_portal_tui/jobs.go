@@ -0,0 +1,226 @@
+package main
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Background fetches run as jobs, at most MAX_RUNNING_JOBS at a time so a
+// fetch-all does not hammer the portal; the rest wait in the queue.
+// MAX_JOB_HISTORY bounds how many finished jobs the Jobs view keeps.
+const (
+	MAX_RUNNING_JOBS = 2
+	MAX_JOB_HISTORY  = 50
+)
+
+type JobState int
+
+const (
+	JobQueued JobState = iota
+	JobRunning
+	JobDone
+	JobFailed
+	JobCancelled
+)
+
+// Job is one background fetch. Task identifies what it fetches (see
+// progress.go), so the same data is never queued twice.
+type Job struct {
+	ID       int
+	Task     string
+	State    JobState
+	Err      error
+	Queued   time.Time
+	Started  time.Time
+	Finished time.Time
+	fetch    func(s *Session) error
+	// inFlight is set while the fetch runs, even after a cancel.
+	inFlight bool
+}
+
+func (j Job) active() bool {
+	return j.State == JobQueued || j.State == JobRunning
+}
+
+// JobFinishedMsg ends a running job. Courses is the session's course list
+// afterwards, for the views to pick up.
+type JobFinishedMsg struct {
+	ID      int
+	Courses []Course
+	Error   error
+}
+
+// courseReport reports whether task reads a course report. The portal keeps
+// the selected course in its session, so two report fetches at once would
+// read each other's course; they are run one at a time.
+func courseReport(task string) bool {
+	return strings.HasPrefix(task, TASK_ATTENDANCE_PREFIX) || strings.HasPrefix(task, TASK_ASSESSMENTS_PREFIX)
+}
+
+func (m model) jobLabel(j Job) string {
+	switch {
+	case j.Task == TASK_COURSES:
+		return T("jobs.courses")
+	case j.Task == TASK_TRANSCRIPT:
+		return T("jobs.transcript")
+	case strings.HasPrefix(j.Task, TASK_ATTENDANCE_PREFIX):
+		return T("jobs.attendance", m.courseCode(strings.TrimPrefix(j.Task, TASK_ATTENDANCE_PREFIX)))
+	case strings.HasPrefix(j.Task, TASK_ASSESSMENTS_PREFIX):
+		return T("jobs.assessments", m.courseCode(strings.TrimPrefix(j.Task, TASK_ASSESSMENTS_PREFIX)))
+	default:
+		return j.Task
+	}
+}
+
+func (m model) courseCode(id string) string {
+	for _, c := range m.courses {
+		if c.ID == id {
+			return c.Code
+		}
+	}
+	return id
+}
+
+func (m model) jobIndex(id int) int {
+	for i, j := range m.jobs {
+		if j.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m model) activeJobs() int {
+	n := 0
+	for _, j := range m.jobs {
+		if j.active() {
+			n++
+		}
+	}
+	return n
+}
+
+// enqueueJob adds a job unless one for the same task is already queued or
+// running, and starts it if there is room.
+func (m *model) enqueueJob(task string, fetch func(s *Session) error) tea.Cmd {
+	if m.session == nil {
+		return nil
+	}
+	for _, j := range m.jobs {
+		if j.Task == task && j.active() {
+			return nil
+		}
+	}
+	m.nextJobID++
+	m.jobs = append(m.jobs, Job{ID: m.nextJobID, Task: task, Queued: time.Now(), fetch: fetch})
+	m.pruneJobs()
+	m.refreshNotice = ""
+	return tea.Batch(m.spinner.Tick, m.scheduleJobs())
+}
+
+// scheduleJobs starts queued jobs, oldest first, while fewer than
+// MAX_RUNNING_JOBS are running.
+func (m *model) scheduleJobs() tea.Cmd {
+	running, reportRunning := 0, false
+	for _, j := range m.jobs {
+		if j.inFlight {
+			running++
+			reportRunning = reportRunning || courseReport(j.Task)
+		}
+	}
+
+	var cmds []tea.Cmd
+	for i := range m.jobs {
+		if running >= MAX_RUNNING_JOBS {
+			break
+		}
+		if m.jobs[i].State != JobQueued || (reportRunning && courseReport(m.jobs[i].Task)) {
+			continue
+		}
+		m.jobs[i].State = JobRunning
+		m.jobs[i].Started = time.Now()
+		m.jobs[i].inFlight = true
+		running++
+		reportRunning = reportRunning || courseReport(m.jobs[i].Task)
+
+		id, fetch, session := m.jobs[i].ID, m.jobs[i].fetch, m.session
+		cmds = append(cmds, func() tea.Msg {
+			err := fetch(session)
+			return JobFinishedMsg{ID: id, Courses: session.GetStudent().Courses, Error: err}
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+// finishJob records a job's result and reports whether it still counted:
+// results of cancelled jobs are dropped.
+func (m *model) finishJob(msg JobFinishedMsg) bool {
+	i := m.jobIndex(msg.ID)
+	if i == -1 {
+		return false
+	}
+	m.jobs[i].inFlight = false
+	if m.jobs[i].State != JobRunning {
+		return false
+	}
+	m.jobs[i].Finished = time.Now()
+	m.jobs[i].Err = msg.Error
+	if msg.Error != nil {
+		m.jobs[i].State = JobFailed
+	} else {
+		m.jobs[i].State = JobDone
+	}
+	return true
+}
+
+// cancelJob stops a queued job from starting. A running fetch cannot be
+// interrupted mid-request, so it is abandoned instead: its result is
+// ignored, and its slot frees up once the request returns.
+func (m *model) cancelJob(id int) {
+	i := m.jobIndex(id)
+	if i == -1 || !m.jobs[i].active() {
+		return
+	}
+	m.jobs[i].State = JobCancelled
+	m.jobs[i].Finished = time.Now()
+}
+
+// retryJob queues a failed or cancelled job again.
+func (m *model) retryJob(id int) tea.Cmd {
+	i := m.jobIndex(id)
+	if i == -1 || m.jobs[i].active() || m.jobs[i].State == JobDone {
+		return nil
+	}
+	return m.enqueueJob(m.jobs[i].Task, m.jobs[i].fetch)
+}
+
+// clearFinishedJobs drops everything that is no longer queued or running.
+func (m *model) clearFinishedJobs() {
+	var kept []Job
+	for _, j := range m.jobs {
+		if j.active() || j.inFlight {
+			kept = append(kept, j)
+		}
+	}
+	m.jobs = kept
+	m.selectedJob = min(m.selectedJob, max(len(m.jobs)-1, 0))
+}
+
+// pruneJobs keeps the history bounded by dropping the oldest finished jobs.
+func (m *model) pruneJobs() {
+	for len(m.jobs) > MAX_JOB_HISTORY {
+		dropped := false
+		for i, j := range m.jobs {
+			if !j.active() && !j.inFlight {
+				m.jobs = append(m.jobs[:i:i], m.jobs[i+1:]...)
+				dropped = true
+				break
+			}
+		}
+		if !dropped {
+			return
+		}
+	}
+}
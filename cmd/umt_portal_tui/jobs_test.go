@@ -0,0 +1,66 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestJobQueue(t *testing.T) {
+	m := model{session: NewSession()}
+	fail := errors.New("portal unreachable")
+	noop := func(s *Session) error { return nil }
+
+	m.enqueueJob(attendanceTask("1"), noop)
+	m.enqueueJob(attendanceTask("2"), func(s *Session) error { return fail })
+	m.enqueueJob(TASK_TRANSCRIPT, noop)
+	if cmd := m.enqueueJob(attendanceTask("1"), noop); cmd != nil || len(m.jobs) != 3 {
+		t.Fatalf("duplicate job was queued: %v", m.jobs)
+	}
+
+	// Course reports share the portal's selected course, so the second
+	// attendance job waits while the transcript takes the other slot.
+	states := func() []JobState {
+		var s []JobState
+		for _, j := range m.jobs {
+			s = append(s, j.State)
+		}
+		return s
+	}
+	if got := states(); got[0] != JobRunning || got[1] != JobQueued || got[2] != JobRunning {
+		t.Fatalf("states after scheduling = %v", got)
+	}
+
+	m.cancelJob(m.jobs[0].ID)
+	m.scheduleJobs()
+	if m.jobs[1].State != JobQueued {
+		t.Fatal("a report job started while a cancelled one was still in flight")
+	}
+
+	// The cancelled job's late result is ignored and frees its slot.
+	if m.finishJob(JobFinishedMsg{ID: m.jobs[0].ID}) {
+		t.Error("cancelled job result was applied")
+	}
+	m.scheduleJobs()
+	if m.jobs[1].State != JobRunning {
+		t.Fatalf("queued report job did not start: %v", states())
+	}
+
+	m.finishJob(JobFinishedMsg{ID: m.jobs[1].ID, Error: fail})
+	m.finishJob(JobFinishedMsg{ID: m.jobs[2].ID})
+	if got := states(); got[1] != JobFailed || got[2] != JobDone || m.activeJobs() != 0 {
+		t.Fatalf("states after finishing = %v", got)
+	}
+
+	if m.retryJob(m.jobs[2].ID) != nil {
+		t.Error("a finished job was retried")
+	}
+	m.retryJob(m.jobs[1].ID)
+	if last := m.jobs[len(m.jobs)-1]; last.Task != attendanceTask("2") || last.State != JobRunning {
+		t.Fatalf("retry did not run the job again: %+v", last)
+	}
+
+	m.clearFinishedJobs()
+	if len(m.jobs) != 1 {
+		t.Errorf("clear kept %d jobs, want only the running one", len(m.jobs))
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const UMT_HOME_URL string = "https://online.umt.edu.pk/Home/Index"
+
+// The portal drops idle sessions after about 20 minutes; pinging it more
+// often keeps a long TUI session usable.
+var keepaliveInterval = 10 * time.Minute
+
+var errSessionExpired = errors.New("portal session expired")
+
+// keepAlive requests a light page to reset the portal's idle timer. An
+// expired session is redirected to the login page, which is reported as
+// errSessionExpired.
+func (s *Session) keepAlive() error {
+	if len(s.Cookies) == 0 {
+		return errSessionExpired
+	}
+
+	req, err := http.NewRequest("GET", UMT_HOME_URL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create keepalive request: %w", err)
+	}
+	for _, cookie := range s.Cookies {
+		req.AddCookie(cookie)
+	}
+
+	resp, err := s.httpClient(OP_OTHER).Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach the portal: %w", err)
+	}
+	resp.Body.Close()
+
+	if strings.EqualFold(resp.Request.URL.Path, "/Account/Login") {
+		return errSessionExpired
+	}
+	return nil
+}
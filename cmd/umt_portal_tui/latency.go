@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// endpointLatency is how long a portal endpoint took to answer. A probe
+// also splits that into Connect, the DNS lookup and TCP and TLS handshakes
+// on the way to the portal, and Wait, from sending the request to the
+// first byte of the answer, which is the portal's own time.
+type endpointLatency struct {
+	Endpoint portalEndpoint
+	Duration time.Duration
+	At       time.Time
+	Status   int
+	Err      error
+	Connect  time.Duration
+	Wait     time.Duration
+	TLS      uint16
+}
+
+type LatencyProbedMsg struct {
+	Results []endpointLatency
+}
+
+func (e portalEndpoint) path() string {
+	u, err := url.Parse(e.URL)
+	if err != nil {
+		return ""
+	}
+	return u.Path
+}
+
+// lastLatencies finds the latest logged request to each endpoint; those
+// never requested are left at zero.
+func (l *requestLog) lastLatencies() []endpointLatency {
+	records := l.recent(MAX_REQUEST_LOG)
+	latencies := make([]endpointLatency, len(PORTAL_ENDPOINTS))
+	for i, e := range PORTAL_ENDPOINTS {
+		latencies[i].Endpoint = e
+		for j := len(records) - 1; j >= 0; j-- {
+			r := records[j]
+			if u, err := url.Parse(r.URL); err == nil && strings.EqualFold(u.Path, e.path()) {
+				latencies[i].Duration, latencies[i].At, latencies[i].Status, latencies[i].Err = r.Duration, r.Time, r.Status, r.Err
+				break
+			}
+		}
+	}
+	return latencies
+}
+
+// probeEndpoint requests e without logging in or following redirects, on
+// a fresh connection so the connect time is measured too.
+func probeEndpoint(s *Session, e portalEndpoint) endpointLatency {
+	result := endpointLatency{Endpoint: e, At: time.Now()}
+	var getConn, wroteRequest time.Time
+	trace := &httptrace.ClientTrace{
+		GetConn:      func(string) { getConn = time.Now() },
+		GotConn:      func(httptrace.GotConnInfo) { result.Connect = time.Since(getConn) },
+		WroteRequest: func(httptrace.WroteRequestInfo) { wroteRequest = time.Now() },
+		GotFirstResponseByte: func() {
+			if !wroteRequest.IsZero() {
+				result.Wait = time.Since(wroteRequest)
+			}
+		},
+	}
+	req, err := http.NewRequestWithContext(httptrace.WithClientTrace(context.Background(), trace), "GET", e.URL, nil)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	req.Close = true
+
+	client := s.httpClient(OP_OTHER)
+	client.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+	resp, err := client.Do(req)
+	result.Duration = time.Since(result.At)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	resp.Body.Close()
+	result.Status = resp.StatusCode
+	if resp.TLS != nil {
+		result.TLS = resp.TLS.Version
+	}
+	return result
+}
+
+func probeLatencyCmd(s *Session) tea.Cmd {
+	return func() tea.Msg {
+		var results []endpointLatency
+		for _, e := range PORTAL_ENDPOINTS {
+			results = append(results, probeEndpoint(s, e))
+		}
+		return LatencyProbedMsg{Results: results}
+	}
+}
+
+// latencyVerdict says whether connecting or the portal's answer took most
+// of the probes' time, or nothing before a probe.
+func latencyVerdict(probes []endpointLatency) string {
+	var connect, wait time.Duration
+	for _, p := range probes {
+		if p.Err == nil {
+			connect += p.Connect
+			wait += p.Wait
+		}
+	}
+	switch {
+	case connect == 0 && wait == 0:
+		return ""
+	case connect > wait:
+		return T("latency.verdict_network")
+	default:
+		return T("latency.verdict_portal")
+	}
+}
+
+func (m model) handleLatencyKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		if !m.rememberMe {
+			deleteCaches()
+		}
+		return m, tea.Quit
+	case "esc", "N":
+		m.goBack()
+	case "r":
+		if !m.latencyProbing && m.session != nil {
+			m.latencyProbing = true
+			return m, tea.Batch(m.spinner.Tick, probeLatencyCmd(m.session))
+		}
+	}
+	return m, nil
+}
+
+func (m model) renderLatency() string {
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(ACCENT).
+		MarginBottom(1)
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(WHITE).
+		Background(BLUE)
+
+	normalStyle := lipgloss.NewStyle().
+		Foreground(SILVER)
+
+	failStyle := lipgloss.NewStyle().
+		Foreground(DANGER)
+
+	verdictStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(WARN).
+		MarginTop(1)
+
+	noteStyle := lipgloss.NewStyle().
+		Foreground(GREY).
+		MarginTop(1)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(GREY).
+		MarginTop(1)
+
+	ms := func(d time.Duration) string {
+		if d <= 0 {
+			return "-"
+		}
+		return d.Round(time.Millisecond).String()
+	}
+
+	var last []endpointLatency
+	if m.session != nil {
+		last = m.session.requests.lastLatencies()
+	}
+	format := "  %-16s %9s %9s  %s %9s %9s"
+	rows := []string{headerStyle.Render(fmt.Sprintf(format,
+		T("latency.col_endpoint"), T("latency.col_last"), T("latency.col_when"), padText(T("latency.col_status"), 7), T("latency.col_connect"), T("latency.col_wait")))}
+	for i, e := range PORTAL_ENDPOINTS {
+		when, status, style := "-", "-", normalStyle
+		var l endpointLatency
+		if i < len(last) {
+			l = last[i]
+		}
+		if !l.At.IsZero() {
+			when = T("latency.ago", formatElapsed(time.Since(l.At)))
+		}
+		switch {
+		case l.Err != nil:
+			status, style = T("latency.error"), failStyle
+		case l.Status != 0:
+			status = fmt.Sprint(l.Status)
+		}
+		connect, wait := "-", "-"
+		if i < len(m.latencyProbe) {
+			if p := m.latencyProbe[i]; p.Err == nil {
+				connect, wait = ms(p.Connect), ms(p.Wait)
+			} else {
+				connect, wait = T("latency.error"), T("latency.error")
+			}
+		}
+		rows = append(rows, style.Render(fmt.Sprintf(format, e.Name, ms(l.Duration), when, padText(status, 7), connect, wait)))
+	}
+
+	parts := []string{titleStyle.Render(T("latency.title")), strings.Join(rows, "\n")}
+	switch {
+	case m.latencyProbing:
+		parts = append(parts, verdictStyle.Render(m.spinner.View()+" "+T("latency.probing")))
+	case latencyVerdict(m.latencyProbe) != "":
+		parts = append(parts, verdictStyle.Render(latencyVerdict(m.latencyProbe)))
+	}
+	parts = append(parts,
+		noteStyle.Render(wrapHelp("• "+T("latency.note"), m.width-4)),
+		helpStyle.Render(T("latency.help")),
+	)
+	content := lipgloss.JoinVertical(lipgloss.Center, parts...)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, content)
+}
+
+// tlsDetail names the TLS version of a probe, if it used TLS.
+func (l endpointLatency) tlsDetail() string {
+	if l.TLS == 0 {
+		return ""
+	}
+	return tls.VersionName(l.TLS)
+}
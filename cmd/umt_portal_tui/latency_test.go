@@ -0,0 +1,58 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestLatencyPanel(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	useMockPortal(t, portal)
+
+	s := NewSession()
+	if code, text := s.Login(Credentials{StudentID: "F2023000000", Password: "hunter2"}, false); code != ErrNone {
+		t.Fatalf("login failed: %v %s", code, text)
+	}
+	if _, err := s.GetCourses(); err != nil {
+		t.Fatal(err)
+	}
+
+	last := s.requests.lastLatencies()
+	if last[0].At.IsZero() || last[1].At.IsZero() {
+		t.Errorf("login and MyCourses not measured: %+v", last)
+	}
+	if !last[3].At.IsZero() {
+		t.Errorf("Transcript.aspx measured without being requested: %+v", last[3])
+	}
+
+	m := model{session: s, currentView: CoursesView, width: 160, height: 40}
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("N")})
+	m = next.(model)
+	if m.currentView != LatencyView {
+		t.Fatalf("N opened %v", m.currentView)
+	}
+
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	m = next.(model)
+	if !m.latencyProbing || cmd == nil {
+		t.Fatal("r did not start a probe")
+	}
+	msg := probeLatencyCmd(s)().(LatencyProbedMsg)
+	if len(msg.Results) != len(PORTAL_ENDPOINTS) {
+		t.Fatalf("probed %d endpoints, want %d", len(msg.Results), len(PORTAL_ENDPOINTS))
+	}
+	for _, r := range msg.Results {
+		if r.Err != nil || r.Connect <= 0 || r.Wait <= 0 {
+			t.Errorf("%s: connect %s, wait %s, err %v", r.Endpoint.Name, r.Connect, r.Wait, r.Err)
+		}
+	}
+
+	next, _ = m.Update(msg)
+	m = next.(model)
+	view := m.View()
+	if m.latencyProbing || !strings.Contains(view, "Transcript.aspx") || latencyVerdict(m.latencyProbe) == "" || !strings.Contains(view, latencyVerdict(m.latencyProbe)) {
+		t.Errorf("probe results not shown:\n%s", view)
+	}
+}
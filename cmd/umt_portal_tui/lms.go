@@ -0,0 +1,207 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	LMS_TOKEN_PATH   = "/login/token.php"
+	LMS_REST_PATH    = "/webservice/rest/server.php"
+	LMS_SERVICE      = "moodle_mobile_app"
+	LMS_EVENTS_LIMIT = 50
+	LMS_TIMEOUT      = 30 * time.Second
+)
+
+// LMSConfig points at the university's Moodle site. Either a web service
+// token (Moodle: Preferences › Security keys) or a username and password,
+// exchanged for a mobile-app token, is required.
+type LMSConfig struct {
+	URL      string `json:"url"`
+	Token    string `json:"token"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// LMSDeadline is a pending Moodle activity, usually an assignment, with its
+// due date. CourseCode is the matching portal course code, if any.
+type LMSDeadline struct {
+	Name       string
+	Module     string
+	Course     string
+	CourseCode string
+	Due        time.Time
+	URL        string
+}
+
+type LMSDeadlinesMsg struct {
+	Deadlines []LMSDeadline
+	Error     error
+}
+
+func (c LMSConfig) configured() bool {
+	return c.URL != "" && (c.token() != "" || c.Username != "")
+}
+
+func (c LMSConfig) token() string {
+	if t := os.Getenv("UMT_LMS_TOKEN"); t != "" {
+		return t
+	}
+	return c.Token
+}
+
+func (c LMSConfig) password() string {
+	if p := os.Getenv("UMT_LMS_PASSWORD"); p != "" {
+		return p
+	}
+	return c.Password
+}
+
+// moodleError is how Moodle reports failures, with HTTP 200.
+type moodleError struct {
+	Error     string `json:"error"`
+	Exception string `json:"exception"`
+	ErrorCode string `json:"errorcode"`
+	Message   string `json:"message"`
+}
+
+func (e moodleError) err() error {
+	switch {
+	case e.Exception != "":
+		return fmt.Errorf("lms: %s (%s)", e.Message, e.ErrorCode)
+	case e.Error != "":
+		return fmt.Errorf("lms: %s", e.Error)
+	}
+	return nil
+}
+
+type lmsClient struct {
+	cfg    LMSConfig
+	client *http.Client
+}
+
+func newLMSClient(cfg LMSConfig) *lmsClient {
+	return &lmsClient{cfg: cfg, client: &http.Client{Transport: sharedTransport, Timeout: LMS_TIMEOUT}}
+}
+
+func (c *lmsClient) post(path string, form url.Values, v any) error {
+	resp, err := c.client.PostForm(strings.TrimRight(c.cfg.URL, "/")+path, form)
+	if err != nil {
+		return fmt.Errorf("failed to reach lms: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("lms returned %s", resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read lms response: %w", err)
+	}
+
+	var me moodleError
+	if json.Unmarshal(body, &me) == nil && me.err() != nil {
+		return me.err()
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to parse lms response: %w", err)
+	}
+	return nil
+}
+
+func (c *lmsClient) login() (string, error) {
+	if t := c.cfg.token(); t != "" {
+		return t, nil
+	}
+	var resp struct {
+		Token string `json:"token"`
+	}
+	err := c.post(LMS_TOKEN_PATH, url.Values{
+		"username": {c.cfg.Username},
+		"password": {c.cfg.password()},
+		"service":  {LMS_SERVICE},
+	}, &resp)
+	if err != nil {
+		return "", err
+	}
+	if resp.Token == "" {
+		return "", errors.New("lms: login returned no token")
+	}
+	return resp.Token, nil
+}
+
+type moodleActionEvents struct {
+	Events []struct {
+		Name       string `json:"name"`
+		ModuleName string `json:"modulename"`
+		TimeSort   int64  `json:"timesort"`
+		URL        string `json:"url"`
+		Course     struct {
+			ShortName string `json:"shortname"`
+			FullName  string `json:"fullname"`
+		} `json:"course"`
+	} `json:"events"`
+}
+
+// fetchLMSDeadlines lists upcoming activities that still need action, as on
+// the Moodle dashboard timeline, and matches them to portal courses by
+// course code.
+func fetchLMSDeadlines(cfg LMSConfig, courses []Course, now time.Time) ([]LMSDeadline, error) {
+	c := newLMSClient(cfg)
+	token, err := c.login()
+	if err != nil {
+		return nil, err
+	}
+
+	var resp moodleActionEvents
+	err = c.post(LMS_REST_PATH, url.Values{
+		"wstoken":            {token},
+		"wsfunction":         {"core_calendar_get_action_events_by_timesort"},
+		"moodlewsrestformat": {"json"},
+		"timesortfrom":       {strconv.FormatInt(now.Unix(), 10)},
+		"limitnum":           {strconv.Itoa(LMS_EVENTS_LIMIT)},
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	var deadlines []LMSDeadline
+	for _, e := range resp.Events {
+		d := LMSDeadline{
+			Name:   e.Name,
+			Module: e.ModuleName,
+			Course: e.Course.FullName,
+			Due:    time.Unix(e.TimeSort, 0),
+			URL:    e.URL,
+		}
+		haystack := strings.ToUpper(e.Course.ShortName + " " + e.Course.FullName)
+		for _, course := range courses {
+			if course.Code != "" && strings.Contains(haystack, strings.ToUpper(course.Code)) {
+				d.CourseCode = course.Code
+				break
+			}
+		}
+		deadlines = append(deadlines, d)
+	}
+	sort.SliceStable(deadlines, func(i, j int) bool { return deadlines[i].Due.Before(deadlines[j].Due) })
+	return deadlines, nil
+}
+
+// deadlinesFor returns the deadlines of one portal course.
+func deadlinesFor(deadlines []LMSDeadline, courseCode string) []LMSDeadline {
+	var matched []LMSDeadline
+	for _, d := range deadlines {
+		if d.CourseCode == courseCode {
+			matched = append(matched, d)
+		}
+	}
+	return matched
+}
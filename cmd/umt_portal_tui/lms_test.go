@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newMockLMS(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST "+LMS_TOKEN_PATH, func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("username") != "f2023000000" || r.FormValue("password") != "moodle-pass" {
+			fmt.Fprint(w, `{"error":"Invalid login, please try again","errorcode":"invalidlogin"}`)
+			return
+		}
+		fmt.Fprint(w, `{"token":"tok123"}`)
+	})
+	mux.HandleFunc("POST "+LMS_REST_PATH, func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("wstoken") != "tok123" {
+			fmt.Fprint(w, `{"exception":"moodle_exception","errorcode":"invalidtoken","message":"Invalid token - token not found"}`)
+			return
+		}
+		fmt.Fprint(w, `{"events":[
+			{"name":"Lab 4 is due","modulename":"assign","timesort":2000,"url":"https://lms/mod/assign/view.php?id=4","course":{"shortname":"CS3051-B2","fullname":"Operating Systems"}},
+			{"name":"Assignment 2 is due","modulename":"assign","timesort":1000,"course":{"shortname":"cc2042 a1","fullname":"Database Systems"}},
+			{"name":"Seminar quiz","modulename":"quiz","timesort":3000,"course":{"shortname":"SEM101","fullname":"Seminar"}}
+		]}`)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestFetchLMSDeadlines(t *testing.T) {
+	server := newMockLMS(t)
+	t.Setenv("UMT_LMS_TOKEN", "")
+	t.Setenv("UMT_LMS_PASSWORD", "moodle-pass")
+	courses := []Course{{Code: "CC2042"}, {Code: "CS3051"}, {Code: "MA2110"}}
+
+	cfg := LMSConfig{URL: server.URL + "/", Username: "f2023000000"}
+	if !cfg.configured() {
+		t.Fatal("config with username should count as configured")
+	}
+	deadlines, err := fetchLMSDeadlines(cfg, courses, time.Unix(0, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deadlines) != 3 {
+		t.Fatalf("got %d deadlines, want 3", len(deadlines))
+	}
+	if deadlines[0].CourseCode != "CC2042" || deadlines[1].CourseCode != "CS3051" || deadlines[2].CourseCode != "" {
+		t.Errorf("unexpected course matching or order: %+v", deadlines)
+	}
+	if got := deadlinesFor(deadlines, "CS3051"); len(got) != 1 || got[0].Name != "Lab 4 is due" {
+		t.Errorf("unexpected deadlines for CS3051: %+v", got)
+	}
+
+	if _, err := fetchLMSDeadlines(LMSConfig{URL: server.URL, Token: "wrong"}, courses, time.Now()); err == nil || err.Error() != "lms: Invalid token - token not found (invalidtoken)" {
+		t.Errorf("expected token error, got %v", err)
+	}
+	t.Setenv("UMT_LMS_PASSWORD", "nope")
+	if _, err := fetchLMSDeadlines(cfg, courses, time.Now()); err == nil {
+		t.Error("expected login error")
+	}
+}
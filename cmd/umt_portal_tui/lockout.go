@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// The portal locks accounts after a handful of wrong passwords, so logins
+// are refused locally well before that: after MAX_FAILED_LOGINS rejected
+// attempts in a row, the saved password is wiped and no login for that
+// student ID is sent until LOGIN_COOLDOWN has passed.
+const (
+	MAX_FAILED_LOGINS = 3
+	LOGIN_COOLDOWN    = 15 * time.Minute
+)
+
+type loginFailures struct {
+	StudentID   string    `json:"student_id"`
+	Count       int       `json:"count"`
+	LockedUntil time.Time `json:"locked_until"`
+}
+
+func loginFailuresPath() (string, error) {
+	dir, err := stateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "login_failures.json"), nil
+}
+
+func readLoginFailures(studentID string) loginFailures {
+	failures := loginFailures{StudentID: studentID}
+	path, err := loginFailuresPath()
+	if err != nil {
+		return failures
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return failures
+	}
+	var stored loginFailures
+	if json.Unmarshal(raw, &stored) != nil || !strings.EqualFold(stored.StudentID, studentID) {
+		return failures
+	}
+	return stored
+}
+
+func writeLoginFailures(failures loginFailures) error {
+	path, err := loginFailuresPath()
+	if err != nil {
+		return err
+	}
+	if failures.Count == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create app cache dir: %w", err)
+	}
+	raw, err := json.Marshal(failures)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, raw, 0600)
+}
+
+// loginCooldown returns how long logins for studentID are still refused.
+func loginCooldown(studentID string, now time.Time) time.Duration {
+	until := readLoginFailures(studentID).LockedUntil
+	if until.After(now) {
+		return until.Sub(now)
+	}
+	return 0
+}
+
+// recordLoginResult updates the failure count after a login attempt and
+// reports whether the account is now in cooldown. Only rejected credentials
+// count; network and parsing errors say nothing about the password.
+func recordLoginResult(studentID string, code ErrorCode, now time.Time) bool {
+	switch code {
+	case ErrNone:
+		writeLoginFailures(loginFailures{StudentID: studentID})
+		return false
+	case ErrInvalidCredentials:
+	default:
+		return false
+	}
+
+	failures := readLoginFailures(studentID)
+	if !failures.LockedUntil.IsZero() && !failures.LockedUntil.After(now) {
+		failures.Count = 0
+		failures.LockedUntil = time.Time{}
+	}
+	failures.Count++
+	locked := failures.Count >= MAX_FAILED_LOGINS
+	if locked {
+		failures.LockedUntil = now.Add(LOGIN_COOLDOWN)
+		if saved, err := LoadCreds(); err == nil && strings.EqualFold(saved.StudentID, studentID) {
+			deleteCreds()
+		}
+	}
+	writeLoginFailures(failures)
+	return locked
+}
+
+// formatCooldown renders a remaining cooldown as m:ss.
+func formatCooldown(d time.Duration) string {
+	d = d.Round(time.Second)
+	return fmt.Sprintf("%d:%02d", int(d.Minutes()), int(d.Seconds())%60)
+}
@@ -5,12 +5,20 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"image"
+	"math"
 	"net/http"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
 )
 
 type Attendance struct {
@@ -25,6 +33,11 @@ type Assessment struct {
 	obtainedMarks float32
 	totalMarks    float32
 	assignedDate  string
+
+	// classAverage and classHighest are the class's marks, when the portal
+	// shows them; zero when it doesn't.
+	classAverage float32
+	classHighest float32
 }
 
 type Course struct {
@@ -38,13 +51,16 @@ type Course struct {
 	Mode         string
 	Section      string
 	Semester     string
+	OutlineURL   string
 
+	// Room, Days, StartTime and EndTime are meant for the class timetable,
+	// which is not scraped yet; they are always empty.
 	Room                 string
 	Days                 []string
 	StartTime            string
 	EndTime              string
 	TotalLectures        int
-	AttendancePercentage int
+	AttendancePercentage float64
 	Attendance           []Attendance
 	Assessment           []Assessment
 }
@@ -55,6 +71,11 @@ type TranscriptCourse struct {
 	CreditHours int
 	Grade       string
 	GradePoint  float32
+
+	// Retake marks a repeat attempt; Superseded marks an earlier attempt
+	// that a later one replaced and which no longer counts toward GPA.
+	Retake     bool
+	Superseded bool
 }
 
 type Semester struct {
@@ -68,6 +89,10 @@ type SemesterKey struct {
 	semester Semester
 	year     int
 	season   int
+	// parsed is false when the season or year could not be recognized in the
+	// semester name; such semesters are sorted after all the others rather
+	// than dropped.
+	parsed bool
 }
 
 type Transcript struct {
@@ -107,10 +132,32 @@ type Session struct {
 	loggedIn bool
 	Student  Student
 	Cookies  []*http.Cookie
+
+	limiter *rateLimiter
+	// cachedAt is when the student data was saved, if it was restored from
+	// the cache rather than fetched.
+	cachedAt time.Time
+	// captcha is set when the last login attempt stopped at a captcha.
+	captcha *loginCaptcha
+	// onProgress, when set, is told how multi-request fetches advance.
+	onProgress func(FetchProgress)
+	// flights coalesces identical fetches running at the same time.
+	flights singleflight.Group
+	// requests is the recent request history, for diagnostics.
+	requests *requestLog
+	// pages holds the validators and parsed contents of fetched pages.
+	pages *pageCache
+	// attendanceMu serializes attendance report round trips; warmForm is
+	// the attendance report opened ahead of time, if any.
+	attendanceMu sync.Mutex
+	warmForm     *attendanceForm
+	// studentMu guards Student while the courses and the transcript are
+	// fetched at the same time after login.
+	studentMu sync.Mutex
 }
 
 func NewSession() *Session {
-	return &Session{}
+	return &Session{limiter: newRateLimiter(appConfig.RequestsPerMinute), requests: &requestLog{}, pages: &pageCache{}}
 }
 
 type ErrorCode int
@@ -120,6 +167,8 @@ const (
 	ErrInvalidCredentials
 	ErrNetworkIssue
 	ErrParsingError
+	ErrLockedOut
+	ErrCaptchaRequired
 )
 
 func decodeFacultyEmail(email string) string {
@@ -137,12 +186,17 @@ func decodeFacultyEmail(email string) string {
 	return string(decoded)
 }
 
+// SaveCreds keeps creds for "Remember me". With the pass backend they are
+// already in the password store, and nothing is saved.
 func SaveCreds(creds Credentials) error {
-	dir, err := os.UserCacheDir()
+	if appConfig.Credentials.usesPass() {
+		return nil
+	}
+	dir, err := stateDir()
 	if err != nil {
 		return err
 	}
-	filePath := filepath.Join(dir, "umt_tui", "creds.gob")
+	filePath := filepath.Join(dir, "creds.gob")
 	os.MkdirAll(filepath.Dir(filePath), 0700)
 
 	file, err := os.Create(filePath)
@@ -156,11 +210,14 @@ func SaveCreds(creds Credentials) error {
 }
 
 func LoadCreds() (Credentials, error) {
-	dir, err := os.UserCacheDir()
+	if appConfig.Credentials.usesPass() {
+		return loadPassCreds(appConfig.Credentials)
+	}
+	dir, err := stateDir()
 	if err != nil {
 		return Credentials{}, err
 	}
-	filePath := filepath.Join(dir, "umt_tui", "creds.gob")
+	filePath := filepath.Join(dir, "creds.gob")
 
 	file, err := os.Open(filePath)
 	if err != nil {
@@ -175,11 +232,14 @@ func LoadCreds() (Credentials, error) {
 }
 
 func deleteCreds() error {
-	dir, err := os.UserCacheDir()
+	if appConfig.Credentials.usesPass() {
+		return nil
+	}
+	dir, err := stateDir()
 	if err != nil {
 		return err
 	}
-	filePath := filepath.Join(dir, "umt_tui", "creds.gob")
+	filePath := filepath.Join(dir, "creds.gob")
 	err = os.Remove(filePath)
 	if err != nil {
 		return err
@@ -188,9 +248,38 @@ func deleteCreds() error {
 }
 
 func (s *Session) Login(crendetials Credentials, rememberMe bool) (ErrorCode, string) {
-	cookies, errorCode, errorString := s.loginAPI(crendetials)
+	return s.login(crendetials, "", rememberMe)
+}
+
+// LoginWithCaptcha completes a login that returned ErrCaptchaRequired with
+// the code shown in s.Captcha().
+func (s *Session) LoginWithCaptcha(crendetials Credentials, securityCode string, rememberMe bool) (ErrorCode, string) {
+	return s.login(crendetials, securityCode, rememberMe)
+}
+
+func (s *Session) Captcha() image.Image {
+	if s.captcha == nil {
+		return nil
+	}
+	return s.captcha.Image
+}
+
+func (s *Session) login(crendetials Credentials, securityCode string, rememberMe bool) (ErrorCode, string) {
+	if wait := loginCooldown(crendetials.StudentID, time.Now()); wait > 0 {
+		return ErrLockedOut, formatCooldown(wait)
+	}
+	registerSecrets(crendetials.StudentID, crendetials.Password)
+	cookies, errorCode, errorString := s.loginAPI(crendetials, securityCode)
+	if crendetials.StudentID != "" && crendetials.Password != "" {
+		if recordLoginResult(crendetials.StudentID, errorCode, time.Now()) {
+			return ErrLockedOut, formatCooldown(LOGIN_COOLDOWN)
+		}
+	}
 	if errorCode == ErrNone {
 		s.Cookies = cookies
+		for _, c := range cookies {
+			registerSecrets(c.Value)
+		}
 		if rememberMe {
 			SaveCreds(crendetials)
 		}
@@ -198,8 +287,24 @@ func (s *Session) Login(crendetials Credentials, rememberMe bool) (ErrorCode, st
 	return errorCode, errorString
 }
 
+// GetStudent is a copy of the student, safe to read while fetches fill in
+// the session's own.
 func (s *Session) GetStudent() Student {
-	return s.Student
+	s.studentMu.Lock()
+	defer s.studentMu.Unlock()
+	st := s.Student
+	st.Courses = slices.Clone(st.Courses)
+	return st
+}
+
+// courseSnapshot is a copy of the course with the given ID.
+func (s *Session) courseSnapshot(courseId string) (Course, bool) {
+	s.studentMu.Lock()
+	defer s.studentMu.Unlock()
+	if i := getCourseIndex(s, courseId); i != -1 {
+		return s.Student.Courses[i], true
+	}
+	return Course{}, false
 }
 
 func getCourseIndex(s *Session, courseId string) int {
@@ -211,44 +316,132 @@ func getCourseIndex(s *Session, courseId string) int {
 	return -1
 }
 
+// coalesce runs fetch unless one with the same key is already in flight,
+// in which case it waits for that one and shares its result. Keys are the
+// progress task names, so a repeated R or re-entered view never sends the
+// same requests twice at once.
+func coalesce[T any](s *Session, key string, fetch func() (T, error)) (T, error) {
+	v, err, _ := s.flights.Do(key, func() (any, error) {
+		return fetch()
+	})
+	result, _ := v.(T)
+	return result, err
+}
+
 func (s *Session) GetCourses() ([]Course, error) {
-	if err := s.fetchUserCourses(); err != nil {
-		return nil, err
-	}
-	return s.Student.Courses, nil
+	return coalesce(s, TASK_COURSES, func() ([]Course, error) {
+		if err := s.fetchUserCourses(); err != nil {
+			return nil, err
+		}
+		saveDataCache(s)
+		return s.GetStudent().Courses, nil
+	})
 }
 
 func (s *Session) GetCourseAssessments(courseId string) error {
-	return s.fetchCourseAssessments(courseId)
+	_, err := coalesce(s, assessmentsTask(courseId), func() (struct{}, error) {
+		before, baseline := cachedCourse(s, courseId)
+		if err := s.fetchCourseAssessments(courseId); err != nil {
+			return struct{}{}, err
+		}
+		saveDataCache(s)
+		if course, ok := s.courseSnapshot(courseId); baseline && ok && appConfig.Hooks.OnNewAssessment != "" {
+			queueHooks(assessmentEvents(s, before, course))
+		}
+		return struct{}{}, nil
+	})
+	return err
 }
 
+// GetCourseAttendance only joins a fetch with the same refresh setting, so
+// a refresh is never answered from the cache.
 func (s *Session) GetCourseAttendance(refresh bool, courseId string) error {
-	return s.fetchCourseAttendance(refresh, courseId)
+	_, err := coalesce(s, fmt.Sprintf("%s/%t", attendanceTask(courseId), refresh), func() (struct{}, error) {
+		before, baseline := cachedCourse(s, courseId)
+		if err := s.fetchCourseAttendance(refresh, courseId); err != nil {
+			return struct{}{}, err
+		}
+		saveDataCache(s)
+		if course, ok := s.courseSnapshot(courseId); baseline && ok && appConfig.Hooks.OnAbsence != "" {
+			queueHooks(absenceEvents(s, before, course))
+		}
+		return struct{}{}, nil
+	})
+	return err
+}
+
+// WarmAttendance opens a course's attendance report in the background, so
+// a later GetCourseAttendance needs a single round trip.
+func (s *Session) WarmAttendance(courseId string) error {
+	_, err := coalesce(s, "warm:"+courseId, func() (struct{}, error) {
+		return struct{}{}, s.warmAttendance(courseId)
+	})
+	return err
+}
+
+func (s *Session) GetCourseOutline(courseId string) (CourseOutline, error) {
+	return coalesce(s, "outline:"+courseId, func() (CourseOutline, error) {
+		return s.fetchCourseOutline(courseId)
+	})
+}
+
+func (s *Session) GetResults() (ProvisionalResult, error) {
+	return coalesce(s, "results", s.fetchResults)
+}
+
+func (s *Session) GetFees() ([]FeeChallan, error) {
+	return coalesce(s, "fees", s.fetchFees)
+}
+
+func (s *Session) DownloadChallan(challan FeeChallan, dir string) (string, error) {
+	return s.downloadChallan(challan, dir)
+}
+
+func (s *Session) DownloadTranscriptPDF(dir string) (string, error) {
+	return s.downloadTranscriptPDF(dir)
 }
 
 func (s *Session) GetTranscript(refresh bool) error {
-	return s.fetchTranscript(refresh)
+	_, err := coalesce(s, fmt.Sprintf("%s/%t", TASK_TRANSCRIPT, refresh), func() (struct{}, error) {
+		if !refresh || appConfig.Hooks.OnTranscriptUpdate == "" {
+			return struct{}{}, s.fetchTranscript(refresh)
+		}
+		before := cachedTranscript()
+		if err := s.fetchTranscript(refresh); err != nil {
+			return struct{}{}, err
+		}
+		queueHooks(transcriptEvent(s, before))
+		return struct{}{}, nil
+	})
+	return err
 }
 
-func saveTranscriptCache(s *Session) error {
-	cacheDir, err := os.UserCacheDir()
+func transcriptCachePath() (string, error) {
+	dir, err := cacheDir()
 	if err != nil {
-		return fmt.Errorf("failed to get user cache dir: %w", err)
+		return "", err
 	}
+	return filepath.Join(dir, "transcript.json"), nil
+}
 
-	appCacheDir := filepath.Join(cacheDir, "umt_tui")
-	if err := os.MkdirAll(appCacheDir, 0755); err != nil {
+func saveTranscriptCache(s *Session) error {
+	cacheFile, err := transcriptCachePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(cacheFile), 0755); err != nil {
 		return fmt.Errorf("failed to create app cache dir: %w", err)
 	}
 
+	s.studentMu.Lock()
 	serializableTranscript := s.Student.Transcript.ToSerializable()
+	s.studentMu.Unlock()
 
 	data, err := json.MarshalIndent(serializableTranscript, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal transcript: %w", err)
 	}
 
-	cacheFile := filepath.Join(appCacheDir, "transcript.json")
 	if err := os.WriteFile(cacheFile, data, 0644); err != nil {
 		return fmt.Errorf("failed to write cache file: %w", err)
 	}
@@ -257,13 +450,14 @@ func saveTranscriptCache(s *Session) error {
 }
 
 func loadTranscriptCache(s *Session) error {
-	cacheDir, err := os.UserCacheDir()
+	if cacheMode != CACHE_NORMAL {
+		return errCacheSkipped
+	}
+	cacheFile, err := transcriptCachePath()
 	if err != nil {
-		return fmt.Errorf("failed to get user cache dir: %w", err)
+		return err
 	}
 
-	cacheFile := filepath.Join(cacheDir, "umt_tui", "transcript.json")
-
 	data, err := os.ReadFile(cacheFile)
 	if err != nil {
 		return fmt.Errorf("failed to read cache file: %w", err)
@@ -274,18 +468,31 @@ func loadTranscriptCache(s *Session) error {
 		return fmt.Errorf("failed to unmarshal transcript: %w", err)
 	}
 
-	s.Student.Transcript = serializableTranscript.ToTranscript()
+	s.setTranscript(serializableTranscript.ToTranscript())
 
 	return nil
 }
 
+// setTranscript stores a transcript along with the CGPA and earned credit
+// hours it reports, which are more current than the dashboard's, whether it
+// arrives before or after the profile.
+func (s *Session) setTranscript(t Transcript) {
+	s.studentMu.Lock()
+	defer s.studentMu.Unlock()
+	s.Student.Transcript = t
+	if t.TotalCGPA != "" {
+		s.Student.CgpaEarned = t.TotalCGPA
+	}
+	if t.CreditHoursEarned != "" {
+		s.Student.CompletedCreditHours = t.CreditHoursEarned
+	}
+}
+
 func deleteTranscriptCache() error {
-	cacheDir, err := os.UserCacheDir()
+	cacheFile, err := transcriptCachePath()
 	if err != nil {
-		return fmt.Errorf("failed to get user cache dir: %w", err)
+		return err
 	}
-
-	cacheFile := filepath.Join(cacheDir, "umt_tui", "transcript.json")
 	err = os.Remove(cacheFile)
 	if err != nil {
 		return err
@@ -302,49 +509,77 @@ func (s *Session) Logout() {
 	s.Student = Student{}
 }
 
-func parseAndSortSemesters(semesterData map[Semester][]TranscriptCourse) []SemesterKey {
-	var semesterKeys []SemesterKey
-	for sem := range semesterData {
-		parts := strings.Fields(sem.Name)
-		if len(parts) < 2 {
-			continue
-		}
+var (
+	semesterSeasonPattern = regexp.MustCompile(`(?i)\b(spring|summer|fall|winter)\b`)
+	semesterYearPattern   = regexp.MustCompile(`\b(19|20)\d{2}\b`)
+)
 
-		year, err := strconv.Atoi(parts[1])
-		if err != nil {
-			continue
-		}
+const (
+	seasonWinter = iota
+	seasonSpring
+	seasonSummer
+	seasonFall
+	seasonUnknown
+)
+
+// parseSemesterName extracts the season and year from names such as
+// "Fall 2023", "Fall 2023 (Makeup)" or "Summer-2022". ok is false if either
+// part is missing.
+func parseSemesterName(name string) (year int, season int, ok bool) {
+	year = math.MaxInt
+	season = seasonUnknown
 
-		var season int
-		switch strings.ToLower(parts[0]) {
+	if match := semesterYearPattern.FindString(name); match != "" {
+		year, _ = strconv.Atoi(match)
+	}
+
+	if match := semesterSeasonPattern.FindString(name); match != "" {
+		switch strings.ToLower(match) {
+		case "winter":
+			season = seasonWinter
 		case "spring":
-			season = 1
+			season = seasonSpring
 		case "summer":
-			season = 2
+			season = seasonSummer
 		case "fall":
-			season = 3
-		default:
-			continue
+			season = seasonFall
 		}
+	}
+
+	return year, season, year != math.MaxInt && season != seasonUnknown
+}
 
+func parseAndSortSemesters(semesterData map[Semester][]TranscriptCourse) []SemesterKey {
+	var semesterKeys []SemesterKey
+	for sem := range semesterData {
+		year, season, ok := parseSemesterName(sem.Name)
 		semesterKeys = append(semesterKeys, SemesterKey{
 			semester: sem,
 			year:     year,
 			season:   season,
+			parsed:   ok,
 		})
 	}
 
 	sort.Slice(semesterKeys, func(i, j int) bool {
-		if semesterKeys[i].year == semesterKeys[j].year {
-			return semesterKeys[i].season < semesterKeys[j].season
+		a, b := semesterKeys[i], semesterKeys[j]
+		if a.parsed != b.parsed {
+			return a.parsed
+		}
+		if a.year != b.year {
+			return a.year < b.year
+		}
+		if a.season != b.season {
+			return a.season < b.season
 		}
-		return semesterKeys[i].year < semesterKeys[j].year
+		return a.semester.Name < b.semester.Name
 	})
 
 	return semesterKeys
 }
 
 type SerializableTranscript struct {
+	SchemaVersion     int                    `json:"schema_version"`
 	Semesters         []SerializableSemester `json:"semesters"`
 	CreditHoursEarned string                 `json:"credit_hours_earned"`
 	CreditHoursForGPA string                 `json:"credit_hours_for_gpa"`
@@ -362,7 +597,8 @@ type SerializableSemester struct {
 
 func (t *Transcript) ToSerializable() SerializableTranscript {
 	var semesters []SerializableSemester
-	for semester, courses := range t.Semester {
+	for _, key := range parseAndSortSemesters(t.Semester) {
+		semester, courses := key.semester, t.Semester[key.semester]
 		serializableSem := SerializableSemester{
 			Name:              semester.Name,
 			CreditHoursEarned: strconv.Itoa(semester.CreditHoursEarned),
@@ -373,6 +609,7 @@ func (t *Transcript) ToSerializable() SerializableTranscript {
 		semesters = append(semesters, serializableSem)
 	}
 	return SerializableTranscript{
+		SchemaVersion:     SCHEMA_VERSION,
 		Semesters:         semesters,
 		CreditHoursEarned: t.CreditHoursEarned,
 		CreditHoursForGPA: t.CreditHoursForGPA,
@@ -396,11 +633,13 @@ func (st *SerializableTranscript) ToTranscript() Transcript {
 		}
 		semesterMap[semester] = serializableSem.Courses
 	}
-	return Transcript{
+	transcript := Transcript{
 		Semester:          semesterMap,
 		CreditHoursEarned: st.CreditHoursEarned,
 		CreditHoursForGPA: st.CreditHoursForGPA,
 		TotalGradePoints:  st.TotalGradePoints,
 		TotalCGPA:         st.TotalCGPA,
 	}
+	applyRepeatPolicy(&transcript)
+	return transcript
 }
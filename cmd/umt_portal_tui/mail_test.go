@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeSMTPServer accepts a single message without TLS or auth and returns
+// the DATA payload on the channel.
+func fakeSMTPServer(t *testing.T) (host string, port int, received <-chan string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	ch := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		tp := textproto.NewConn(conn)
+		tp.PrintfLine("220 localhost ESMTP")
+		for {
+			line, err := tp.ReadLine()
+			if err != nil {
+				return
+			}
+			switch verb := strings.ToUpper(strings.Fields(line)[0]); verb {
+			case "EHLO", "HELO":
+				tp.PrintfLine("250 localhost")
+			case "DATA":
+				tp.PrintfLine("354 go ahead")
+				data, _ := io.ReadAll(tp.DotReader())
+				ch <- string(data)
+				tp.PrintfLine("250 queued")
+			case "QUIT":
+				tp.PrintfLine("221 bye")
+				return
+			default:
+				tp.PrintfLine("250 ok")
+			}
+		}
+	}()
+
+	addr := ln.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port, ch
+}
+
+func TestSendMail(t *testing.T) {
+	host, port, received := fakeSMTPServer(t)
+	cfg := SMTPConfig{Host: host, Port: port, From: "tui@example.com", To: []string{"me@example.com"}}
+	msg := mailMessage{Subject: "Attendance alert ⚠", Text: "CC2042 is at 72%", HTML: "<p>" + strings.Repeat("long line ", 200) + "</p>"}
+	if err := sendMail(cfg, msg); err != nil {
+		t.Fatal(err)
+	}
+
+	var raw string
+	select {
+	case raw = <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no message received")
+	}
+
+	m, err := mail.ReadMessage(strings.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	subject, _ := new(mime.WordDecoder).DecodeHeader(m.Header.Get("Subject"))
+	if subject != msg.Subject || m.Header.Get("To") != "me@example.com" {
+		t.Errorf("unexpected headers: %v", m.Header)
+	}
+	for _, line := range strings.Split(raw, "\n") {
+		if len(line) > 998 {
+			t.Fatalf("line exceeds SMTP limit: %d bytes", len(line))
+		}
+	}
+
+	_, params, err := mime.ParseMediaType(m.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mr := multipart.NewReader(m.Body, params["boundary"])
+	var bodies []string
+	for {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(bufio.NewReader(p))
+		bodies = append(bodies, string(body))
+	}
+	if len(bodies) != 2 || bodies[0] != msg.Text || bodies[1] != msg.HTML {
+		t.Errorf("unexpected parts: %q", bodies)
+	}
+}
+
+func TestSendMailNotConfigured(t *testing.T) {
+	if err := sendMail(SMTPConfig{Host: "localhost"}, mailMessage{}); err != errSMTPNotConfigured {
+		t.Errorf("expected errSMTPNotConfigured, got %v", err)
+	}
+}
@@ -1,15 +1,183 @@
 package main
 
 import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
 	tea "github.com/charmbracelet/bubbletea"
 )
 
+// uiTrace is the --trace-ui file, or nil.
+var uiTrace io.Writer
+
+// inlineTUI is --inline: the TUI draws on the terminal's main screen rather
+// than the alternate one, so its last view stays in the scrollback.
+var inlineTUI bool
+
 func StartTUI() error {
-	p := tea.NewProgram(NewModel(), tea.WithAltScreen())
-	_, err := p.Run()
+	m := NewModel()
+	var start tea.Model = m
+	if uiTrace != nil {
+		start = newTraceModel(m, uiTrace)
+	}
+	opts := []tea.ProgramOption{tea.WithReportFocus()}
+	if !inlineTUI {
+		opts = append(opts, tea.WithAltScreen())
+	}
+	p := tea.NewProgram(start, opts...)
+	final, err := p.Run()
+	if t, ok := final.(*traceModel); ok {
+		final = t.model
+	}
+	if m, ok := final.(model); ok {
+		if err := saveUIState(m); err != nil {
+			fmt.Fprintln(os.Stderr, "failed to save UI state:", err)
+		}
+	}
 	return err
 }
 
+// commandName is the name the binary was invoked as, used in help text.
+func commandName() string {
+	return filepath.Base(os.Args[0])
+}
+
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "Usage: %s [flags] [command]\n\n", commandName())
+	fmt.Fprintln(out, "Commands:")
+	for _, c := range commands {
+		fmt.Fprintf(out, "  %-24s %s\n", strings.TrimSpace(c.name+" "+c.args), c.summary)
+	}
+	fmt.Fprintf(out, "  %-24s %s\n", "update", "download and install the latest release")
+	fmt.Fprintf(out, "\nWith no command the interactive TUI is started. Run '%s <command> -h' for command flags.\n\nFlags:\n", commandName())
+	flag.PrintDefaults()
+}
+
 func main() {
-	StartTUI()
+	// ContinueOnError so a bad flag exits with EXIT_USAGE rather than the
+	// flag package's 2, which means invalid credentials here.
+	flag.CommandLine.Init(os.Args[0], flag.ContinueOnError)
+	plain := flag.Bool("plain", false, "render without colors or text styling (also enabled by NO_COLOR)")
+	showVersion := flag.Bool("version", false, "print version and build information")
+	noCache := flag.Bool("no-cache", false, "neither read nor write any local cache for this run")
+	refresh := flag.Bool("refresh", false, "ignore cached data and fetch everything from the portal, saving it as usual")
+	cacheDirFlag := flag.String("cache-dir", "", "keep cached portal data in this `dir` (also "+CACHE_DIR_ENV+")")
+	debugArtifacts := flag.String("debug-artifacts", "", "save the response of every portal request into this `dir`")
+	traceUI := flag.String("trace-ui", "", "append every message the TUI handles and the view it leads to, with timestamps, to `file`")
+	record := flag.String("record", "", "save every portal response of this run into `dir` as fixtures for --replay")
+	replay := flag.String("replay", "", "answer every portal request from the fixtures in `dir` instead of the portal")
+	flag.BoolVar(&inlineTUI, "inline", false, "draw the TUI without the alternate screen, so the last view stays in the terminal after quitting")
+	pii := flag.Bool("include-pii", false, "keep student IDs, passwords, cookies and CNICs in diagnostics and debug artifacts instead of redacting them")
+	flag.Usage = usage
+	if err := flag.CommandLine.Parse(os.Args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		os.Exit(EXIT_USAGE)
+	}
+
+	if *showVersion {
+		fmt.Printf("%s %s\n", commandName(), readBuildInfo())
+		return
+	}
+
+	cacheDirOverride = *cacheDirFlag
+	includePII = *pii
+	if err := migrateStateFiles(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+
+	if *record != "" && *replay != "" {
+		fmt.Fprintln(os.Stderr, "--record and --replay can't be used together")
+		os.Exit(EXIT_USAGE)
+	}
+
+	switch {
+	case *noCache, *record != "", *replay != "":
+		cacheMode = CACHE_OFF
+	case *refresh:
+		cacheMode = CACHE_REFRESH
+	}
+
+	if *plain || os.Getenv("NO_COLOR") != "" {
+		enablePlainOutput()
+	}
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(EXIT_FAILURE)
+	}
+	appConfig = cfg
+	setLanguage(detectLanguage(appConfig))
+	applyPalette(appConfig.Palette)
+
+	if err := configureTransport(appConfig); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(EXIT_FAILURE)
+	}
+	if *replay != "" {
+		if err := enableFixtureReplay(*replay); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(EXIT_FAILURE)
+		}
+	}
+	if *debugArtifacts != "" {
+		if err := enableDebugArtifacts(*debugArtifacts); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(EXIT_FAILURE)
+		}
+	}
+	if *record != "" {
+		if err := enableFixtureRecording(*record); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(EXIT_FAILURE)
+		}
+	}
+	if *traceUI != "" {
+		f, err := os.OpenFile(*traceUI, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "failed to open UI trace:", err)
+			os.Exit(EXIT_FAILURE)
+		}
+		defer f.Close()
+		uiTrace = f
+	}
+	if appConfig.InsecureSkipVerify {
+		fmt.Fprintln(os.Stderr, "WARNING: insecure_skip_verify is enabled; portal TLS certificates will NOT be verified and your credentials can be intercepted.")
+	}
+
+	switch flag.Arg(0) {
+	case "":
+		StartTUI()
+		waitForHooks()
+	case "update":
+		if err := runUpdate(os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, "update failed:", err)
+			os.Exit(exitCode(err))
+		}
+	default:
+		c, ok := findCommand(flag.Arg(0))
+		if !ok {
+			fmt.Fprintf(os.Stderr, "unknown command %q\n\n", flag.Arg(0))
+			usage()
+			os.Exit(EXIT_USAGE)
+		}
+		err := runCommand(c, flag.Args()[1:], os.Stdin, os.Stdout)
+		waitForHooks()
+		switch {
+		case err == nil, errors.Is(err, flag.ErrHelp):
+		case errors.Is(err, errUsage):
+			os.Exit(EXIT_USAGE)
+		default:
+			fmt.Fprintf(os.Stderr, "%s: %v\n", c.name, err)
+			os.Exit(exitCode(err))
+		}
+	}
 }
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	METRICS_PATH         = "/metrics"
+	METRICS_CONTENT_TYPE = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+// METRICS_LATENCY_BUCKETS are the upper bounds, in seconds, of the portal
+// latency histogram; the ReportViewer reports can take minutes.
+var METRICS_LATENCY_BUCKETS = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120}
+
+// metricHelp describes every metric the daemon exposes, and its type.
+var metricHelp = map[string][2]string{
+	"umt_portal_requests_total":                 {"counter", "Portal requests made, by operation."},
+	"umt_portal_request_errors_total":           {"counter", "Portal requests that failed or got a 5xx response, by operation."},
+	"umt_portal_request_duration_seconds":       {"histogram", "Time the portal took to answer, by operation."},
+	"umt_digests_total":                         {"counter", "Digests the daemon tried to build and send."},
+	"umt_digest_failures_total":                 {"counter", "Digests that failed."},
+	"umt_digest_last_success_timestamp_seconds": {"gauge", "When the last digest was sent, as a Unix time."},
+	"umt_attendance_percentage":                 {"gauge", "Attendance percentage of each course at the last digest."},
+	"umt_attendance_threshold_percentage":       {"gauge", "Attendance percentage each course needs."},
+	"umt_absences":                              {"gauge", "Lectures missed in each course at the last digest."},
+	"umt_assessment_percentage":                 {"gauge", "Marks obtained as a percentage of the marks of the assessments so far, per course."},
+	"umt_cgpa":                                  {"gauge", "CGPA on the transcript at the last digest that fetched it."},
+	"umt_fee_dues_rupees":                       {"gauge", "Outstanding fee dues at the last digest."},
+}
+
+type histogram struct {
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+// metricsRegistry keeps the daemon's metrics and writes them in the
+// Prometheus text format. Series are keyed by their rendered labels.
+type metricsRegistry struct {
+	mu         sync.Mutex
+	values     map[string]map[string]float64
+	histograms map[string]map[string]*histogram
+}
+
+// portalMetrics is set while the daemon serves --metrics; requests are
+// only counted then.
+var portalMetrics *metricsRegistry
+
+func newMetricsRegistry() *metricsRegistry {
+	return &metricsRegistry{values: map[string]map[string]float64{}, histograms: map[string]map[string]*histogram{}}
+}
+
+// metricLabels renders label pairs, e.g. `{course="CC2042"}`.
+func metricLabels(pairs ...string) string {
+	if len(pairs) == 0 {
+		return ""
+	}
+	escape := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	var parts []string
+	for i := 0; i+1 < len(pairs); i += 2 {
+		parts = append(parts, fmt.Sprintf(`%s="%s"`, pairs[i], escape.Replace(pairs[i+1])))
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func (r *metricsRegistry) add(name, labels string, delta float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.values[name] == nil {
+		r.values[name] = map[string]float64{}
+	}
+	r.values[name][labels] += delta
+}
+
+func (r *metricsRegistry) set(name, labels string, value float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.values[name] == nil {
+		r.values[name] = map[string]float64{}
+	}
+	r.values[name][labels] = value
+}
+
+// replace sets every series of a gauge at once, dropping those of courses
+// no longer listed.
+func (r *metricsRegistry) replace(name string, series map[string]float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.values[name] = series
+}
+
+func (r *metricsRegistry) observe(name, labels string, value float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.histograms[name] == nil {
+		r.histograms[name] = map[string]*histogram{}
+	}
+	h := r.histograms[name][labels]
+	if h == nil {
+		h = &histogram{counts: make([]uint64, len(METRICS_LATENCY_BUCKETS))}
+		r.histograms[name][labels] = h
+	}
+	for i, bound := range METRICS_LATENCY_BUCKETS {
+		if value <= bound {
+			h.counts[i]++
+		}
+	}
+	h.sum += value
+	h.count++
+}
+
+func formatMetricValue(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// write renders every metric in the Prometheus text exposition format.
+func (r *metricsRegistry) write(w io.Writer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names := make([]string, 0, len(r.values)+len(r.histograms))
+	for name := range r.values {
+		names = append(names, name)
+	}
+	for name := range r.histograms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	names = slices.Compact(names)
+
+	for _, name := range names {
+		help := metricHelp[name]
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help[1], name, help[0])
+		for _, labels := range sortedKeys(r.values[name]) {
+			fmt.Fprintf(w, "%s%s %s\n", name, labels, formatMetricValue(r.values[name][labels]))
+		}
+		for _, labels := range sortedKeys(r.histograms[name]) {
+			h := r.histograms[name][labels]
+			inner := strings.TrimSuffix(strings.TrimPrefix(labels, "{"), "}")
+			if inner != "" {
+				inner += ","
+			}
+			for i, bound := range METRICS_LATENCY_BUCKETS {
+				fmt.Fprintf(w, "%s_bucket{%sle=\"%s\"} %d\n", name, inner, formatMetricValue(bound), h.counts[i])
+			}
+			fmt.Fprintf(w, "%s_bucket{%sle=\"+Inf\"} %d\n", name, inner, h.count)
+			fmt.Fprintf(w, "%s_sum%s %s\n%s_count%s %d\n", name, labels, formatMetricValue(h.sum), name, labels, h.count)
+		}
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (r *metricsRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", METRICS_CONTENT_TYPE)
+	r.write(w)
+}
+
+// metricsTransport counts the requests of a portal operation and how long
+// they took, not counting the wait for the rate limiter.
+type metricsTransport struct {
+	op      string
+	metrics *metricsRegistry
+	next    http.RoundTripper
+}
+
+func (t *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	labels := metricLabels("operation", t.op)
+	t.metrics.add("umt_portal_requests_total", labels, 1)
+	t.metrics.observe("umt_portal_request_duration_seconds", labels, time.Since(start).Seconds())
+	if err != nil || resp.StatusCode >= 500 {
+		t.metrics.add("umt_portal_request_errors_total", labels, 1)
+	}
+	return resp, err
+}
+
+// recordDigest updates the digest counters and, when it was built, the
+// per-course gauges from the data it fetched.
+func (r *metricsRegistry) recordDigest(s *Session, d digest, err error) {
+	r.add("umt_digests_total", "", 1)
+	if err != nil {
+		r.add("umt_digest_failures_total", "", 1)
+	} else {
+		r.set("umt_digest_last_success_timestamp_seconds", "", float64(d.Date.Unix()))
+		r.set("umt_fee_dues_rupees", "", d.Dues)
+	}
+	if s == nil || len(s.Student.Courses) == 0 {
+		return
+	}
+
+	attendance, thresholds, absences, assessments := map[string]float64{}, map[string]float64{}, map[string]float64{}, map[string]float64{}
+	for _, c := range s.Student.Courses {
+		labels := metricLabels("course", c.Code)
+		thresholds[labels] = appConfig.attendanceThreshold(c.Code)
+		if len(c.Attendance) > 0 {
+			attendance[labels] = c.AttendancePercentage
+			missed := 0
+			for _, a := range c.Attendance {
+				if !a.Attendance {
+					missed++
+				}
+			}
+			absences[labels] = float64(missed)
+		}
+		var obtained, total float32
+		for _, a := range c.Assessment {
+			obtained += a.obtainedMarks
+			total += a.totalMarks
+		}
+		if total > 0 {
+			assessments[labels] = float64(obtained / total * 100)
+		}
+	}
+	r.replace("umt_attendance_percentage", attendance)
+	r.replace("umt_attendance_threshold_percentage", thresholds)
+	r.replace("umt_absences", absences)
+	r.replace("umt_assessment_percentage", assessments)
+	if cgpa, err := strconv.ParseFloat(s.Student.Transcript.TotalCGPA, 64); err == nil {
+		r.set("umt_cgpa", "", cgpa)
+	}
+}
+
+// serveMetrics starts serving the registry on addr, returning once it
+// listens so a bad address fails the daemon at startup.
+func serveMetrics(addr string, r *metricsRegistry) (net.Listener, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serve metrics: %w", err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle(METRICS_PATH, r)
+	go http.Serve(ln, mux)
+	return ln, nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDaemonMetrics(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	useMockPortal(t, portal)
+	t.Cleanup(func() { portalMetrics = nil })
+	portalMetrics = newMetricsRegistry()
+
+	s := NewSession()
+	if code, text := s.Login(Credentials{StudentID: "F2023000000", Password: "hunter2"}, false); code != ErrNone {
+		t.Fatalf("login failed: %v %s", code, text)
+	}
+	courses, err := s.GetCourses()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.GetCourseAttendance(true, courses[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	portalMetrics.recordDigest(s, digest{Date: time.Unix(1760000000, 0), Dues: 15000}, nil)
+
+	ln, err := serveMetrics("127.0.0.1:0", portalMetrics)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	resp, err := http.Get("http://" + ln.Addr().String() + METRICS_PATH)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	raw, _ := io.ReadAll(resp.Body)
+	body := string(raw)
+
+	for _, want := range []string{
+		"# TYPE umt_portal_request_duration_seconds histogram\n",
+		`umt_portal_requests_total{operation="login"} `,
+		`umt_portal_request_duration_seconds_bucket{operation="attendance",le="+Inf"} `,
+		`umt_portal_request_duration_seconds_count{operation="courses"} `,
+		`umt_attendance_percentage{course="` + courses[0].Code + `"} `,
+		`umt_attendance_threshold_percentage{course="` + courses[0].Code + `"} 80` + "\n",
+		"umt_digests_total 1\n",
+		"umt_digest_last_success_timestamp_seconds 1.76e+09\n",
+		"umt_fee_dues_rupees 15000\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics lack %q:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "umt_portal_request_errors_total") {
+		t.Errorf("errors counted for a healthy portal:\n%s", body)
+	}
+}
@@ -0,0 +1,369 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// mockPortal is an in-memory stand-in for online.umt.edu.pk. It implements
+// the same login handshake, cookie-based auth and ASPX report round trips
+// that the Session relies on.
+type mockPortal struct {
+	*httptest.Server
+
+	studentID string
+	password  string
+	fixtures  string
+
+	// incompleteReports is how many attendance report POSTs return an
+	// unfinished report before the real one is served.
+	incompleteReports int
+	// partialReports makes the unfinished reports stop just before their
+	// totals row rather than show only the loading placeholder.
+	partialReports bool
+
+	// captcha, when set, makes the login page show a captcha image and the
+	// login require it as SecurityCode.
+	captcha string
+
+	// noDisposition serves challan vouchers without a Content-Disposition,
+	// leaving their file name to the client.
+	noDisposition bool
+
+	// latency delays every authenticated response.
+	latency time.Duration
+
+	// exports makes rendered reports offer their ReportViewer export URL,
+	// served from testdata/<report>_export.csv.
+	exports bool
+	// exported counts the exports served to a logged-in session, unlike
+	// hits, which also counts those sent to the login page. Guarded by mu.
+	exported int
+
+	// busy is how many authenticated requests are answered 503 with a
+	// Retry-After of busyRetryAfter before the portal recovers. Guarded by
+	// mu.
+	busy           int
+	busyRetryAfter string
+
+	mu       sync.Mutex
+	sessions map[string]string // ASP.NET_SessionId -> selected course id
+	authed   map[string]bool   // .ASPXAUTH values issued
+	hits     map[string]int    // "METHOD /path" -> count
+	failing  map[string]bool   // "METHOD /path" -> answered 500
+}
+
+func newMockPortal(t *testing.T, studentID, password string) *mockPortal {
+	t.Helper()
+
+	fixtures, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := &mockPortal{
+		studentID: studentID,
+		password:  password,
+		fixtures:  fixtures,
+		sessions:  map[string]string{},
+		authed:    map[string]bool{},
+		hits:      map[string]int{},
+		failing:   map[string]bool{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /Account/Login", p.handleLoginPage)
+	mux.HandleFunc("POST /Account/Login", p.handleLogin)
+	mux.HandleFunc("GET /Account/Captcha", p.handleCaptcha)
+	mux.HandleFunc("GET /Home/Index", p.requireAuth(p.serveFixture("course_request.html")))
+	mux.HandleFunc("GET /CourseRequest", p.requireAuth(p.serveFixture("course_request.html")))
+	mux.HandleFunc("GET /MyCourses", p.requireAuth(p.serveFixture("courses.html")))
+	mux.HandleFunc("GET /MyCourses/CourseOutline", p.requireAuth(p.serveFixture("course_outline.html")))
+	mux.HandleFunc("GET /MyCourses/ViewAssesments", p.requireAuth(p.serveFixture("assessments.html")))
+	mux.HandleFunc("GET /Attendance/ViewAttendance", p.requireAuth(p.handleSelectCourse))
+	mux.HandleFunc("GET /Reports/Attendance.aspx", p.requireAuth(p.handleReportForm))
+	mux.HandleFunc("POST /Reports/Attendance.aspx", p.requireAuth(p.handleAttendanceReport))
+	mux.HandleFunc("GET /Result", p.requireAuth(p.serveFixture("results.html")))
+	mux.HandleFunc("GET /Payment", p.requireAuth(p.serveFixture("payments.html")))
+	mux.HandleFunc("GET /Payment/Payment_Voucher", p.requireAuth(p.challanVoucher))
+	mux.HandleFunc("GET /Transcript", p.requireAuth(p.serveFixture("course_request.html")))
+	mux.HandleFunc("GET /Reports/Transcript.aspx", p.requireAuth(p.serveReport("transcript_report.html")))
+	mux.HandleFunc("GET /Reserved.ReportViewerWebControl.axd", p.requireAuth(p.handleExport))
+
+	p.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := r.Method + " " + r.URL.Path
+		p.mu.Lock()
+		p.hits[route]++
+		failing := p.failing[route]
+		p.mu.Unlock()
+		if failing {
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(p.Close)
+
+	return p
+}
+
+func (p *mockPortal) Hits(route string) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.hits[route]
+}
+
+// Fail makes the portal answer every request to route with a 500.
+func (p *mockPortal) Fail(route string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.failing[route] = true
+}
+
+// Exported is how many report exports were served to a logged-in session.
+func (p *mockPortal) Exported() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.exported
+}
+
+func (p *mockPortal) handleLoginPage(w http.ResponseWriter, r *http.Request) {
+	id := fmt.Sprintf("sess-%d", time.Now().UnixNano())
+	p.mu.Lock()
+	p.sessions[id] = ""
+	p.mu.Unlock()
+
+	http.SetCookie(w, &http.Cookie{Name: "ASP.NET_SessionId", Value: id, Path: "/"})
+	http.SetCookie(w, &http.Cookie{Name: "__RequestVerificationToken", Value: "token", Path: "/"})
+	if p.captcha != "" {
+		fmt.Fprint(w, "<html><body><form method=\"post\"><img id=\"captchaImage\" src=\"/Account/Captcha\"></form></body></html>")
+		return
+	}
+	fmt.Fprint(w, "<html><body><form method=\"post\"></form></body></html>")
+}
+
+// handleCaptcha serves a black bar on white; the code itself is not drawn.
+func (p *mockPortal) handleCaptcha(w http.ResponseWriter, r *http.Request) {
+	img := image.NewGray(image.Rect(0, 0, 40, 10))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	for x := 5; x < 35; x++ {
+		for y := 3; y < 7; y++ {
+			img.SetGray(x, y, color.Gray{})
+		}
+	}
+	w.Header().Set("Content-Type", "image/png")
+	png.Encode(w, img)
+}
+
+func (p *mockPortal) handleLogin(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if r.PostForm.Get("student_id") != p.studentID || r.PostForm.Get("Password") != p.password ||
+		p.captcha != "" && r.PostForm.Get("SecurityCode") != p.captcha {
+		fmt.Fprint(w, "<html><body><div class=\"validation-summary-errors\">Invalid login attempt.</div></body></html>")
+		return
+	}
+
+	auth := fmt.Sprintf("auth-%d", time.Now().UnixNano())
+	p.mu.Lock()
+	p.authed[auth] = true
+	p.mu.Unlock()
+
+	http.SetCookie(w, &http.Cookie{Name: ".ASPXAUTH", Value: auth, Path: "/"})
+	http.Redirect(w, r, "/Home/Index", http.StatusFound)
+}
+
+// requireAuth rejects requests without a valid auth cookie the same way the
+// portal does: by bouncing to the login page.
+func (p *mockPortal) requireAuth(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie(".ASPXAUTH")
+		p.mu.Lock()
+		ok := err == nil && p.authed[cookie.Value]
+		busy := ok && p.busy > 0
+		if busy {
+			p.busy--
+		}
+		p.mu.Unlock()
+		if !ok {
+			http.Redirect(w, r, "/Account/Login", http.StatusFound)
+			return
+		}
+		if busy {
+			w.Header().Set("Retry-After", p.busyRetryAfter)
+			http.Error(w, "Server Too Busy", http.StatusServiceUnavailable)
+			return
+		}
+		time.Sleep(p.latency)
+		next(w, r)
+	}
+}
+
+func (p *mockPortal) serveFixture(name string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, filepath.Join(p.fixtures, name))
+	}
+}
+
+func (p *mockPortal) challanVoucher(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("id")
+	w.Header().Set("Content-Type", "application/pdf")
+	if !p.noDisposition {
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="challan_%s.pdf"`, id))
+	}
+	fmt.Fprintf(w, "%%PDF-1.4\n%% challan %s\n", id)
+}
+
+func (p *mockPortal) serveReport(name string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		data, err := os.ReadFile(filepath.Join(p.fixtures, name))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Write(data)
+		if p.exports {
+			report := strings.TrimSuffix(name, "_report.html")
+			fmt.Fprintf(w, `<script>$create(Microsoft.Reporting.WebFormsClient._InternalReportViewer, {"ExportUrlBase":"\/Reserved.ReportViewerWebControl.axd?ReportSession=mock\u0026ControlID=ctl\u0026OpType=Export\u0026FileName=%s\u0026ContentDisposition=OnlyHtmlInline\u0026Format="});</script>`, report)
+		}
+	}
+}
+
+func (p *mockPortal) handleExport(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("OpType") != "Export" {
+		http.NotFound(w, r)
+		return
+	}
+	p.mu.Lock()
+	p.exported++
+	p.mu.Unlock()
+	switch q.Get("Format") {
+	case "CSV":
+		http.ServeFile(w, r, filepath.Join(p.fixtures, q.Get("FileName")+"_export.csv"))
+	case "PDF":
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Disposition", `attachment; filename="`+q.Get("FileName")+`.pdf"`)
+		io.WriteString(w, "%PDF-1.4\n%EOF\n")
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (p *mockPortal) sessionID(r *http.Request) string {
+	if cookie, err := r.Cookie("ASP.NET_SessionId"); err == nil {
+		return cookie.Value
+	}
+	return ""
+}
+
+func (p *mockPortal) handleSelectCourse(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	p.sessions[p.sessionID(r)] = r.URL.Query().Get("id")
+	p.mu.Unlock()
+	fmt.Fprint(w, "<html><body><iframe src=\"/Reports/Attendance.aspx\"></iframe></body></html>")
+}
+
+func (p *mockPortal) handleReportForm(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprint(w, `<html><body><form method="post" action="./Attendance.aspx">
+<input type="hidden" name="__VIEWSTATE" value="viewstate" />
+<input type="hidden" name="__VIEWSTATEGENERATOR" value="generator" />
+<input type="hidden" name="__EVENTVALIDATION" value="validation" />
+</form></body></html>`)
+}
+
+func (p *mockPortal) handleAttendanceReport(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("__VIEWSTATE") != "viewstate" {
+		http.Error(w, "invalid viewstate", http.StatusBadRequest)
+		return
+	}
+
+	p.mu.Lock()
+	course := p.sessions[p.sessionID(r)]
+	incomplete := p.incompleteReports > 0
+	if incomplete {
+		p.incompleteReports--
+	}
+	p.mu.Unlock()
+
+	if course == "" {
+		http.Error(w, "no course selected", http.StatusBadRequest)
+		return
+	}
+	if incomplete && p.partialReports {
+		data, err := os.ReadFile(filepath.Join(p.fixtures, "attendance_report.html"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Write(data[:bytes.Index(data, []byte("Total Lectures"))])
+		return
+	}
+	if incomplete {
+		fmt.Fprint(w, "<html><body><div id=\"Attendance_Report_AsyncWait\">Loading...</div></body></html>")
+		return
+	}
+	p.serveReport("attendance_report.html")(w, r)
+}
+
+// rewriteTransport sends every request to the mock portal while leaving the
+// request URL (and therefore cookie scoping) untouched.
+type rewriteTransport struct {
+	target *url.URL
+	next   http.RoundTripper
+}
+
+func (t rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r := req.Clone(req.Context())
+	r.URL.Scheme = t.target.Scheme
+	r.URL.Host = t.target.Host
+	r.Host = ""
+	return t.next.RoundTrip(r)
+}
+
+// useMockPortal points the shared transport at p, disables throttling and
+// retry delays, and isolates the cache and working directories.
+func useMockPortal(t *testing.T, p *mockPortal) {
+	t.Helper()
+
+	target, err := url.Parse(p.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prevTransport, prevConfig, prevDelay := sharedTransport, appConfig, retryDelay
+	t.Cleanup(func() {
+		sharedTransport, appConfig, retryDelay = prevTransport, prevConfig, prevDelay
+	})
+
+	sharedTransport = rewriteTransport{target: target, next: http.DefaultTransport}
+	appConfig = Config{RequestsPerMinute: -1}
+	retryDelay = time.Millisecond
+
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(dir, "cache"))
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(dir, "config"))
+	t.Setenv("XDG_STATE_HOME", filepath.Join(dir, "state"))
+	t.Setenv(CACHE_DIR_ENV, "")
+	t.Setenv("LocalAppData", filepath.Join(dir, "cache"))
+	t.Setenv("AppData", filepath.Join(dir, "config"))
+	t.Chdir(dir)
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// pushView opens v on top of the current view; Esc will return to the current
+// view. Transient views (loading, results) are never kept on the stack.
+func (m *model) pushView(v ViewType) {
+	if m.currentView != v && m.currentView != LoadingView && m.currentView != ResultView && m.currentView != LoginView {
+		m.viewStack = append(m.viewStack, m.currentView)
+	}
+	m.currentView = v
+}
+
+// popView returns to the previous view. It reports false when there is
+// nothing to go back to.
+func (m *model) popView() bool {
+	if len(m.viewStack) == 0 {
+		return false
+	}
+	m.currentView = m.viewStack[len(m.viewStack)-1]
+	m.viewStack = m.viewStack[:len(m.viewStack)-1]
+	return true
+}
+
+// goBack pops to the previous view, falling back to the course list when the
+// stack is empty.
+func (m *model) goBack() {
+	if !m.popView() && m.session != nil && m.session.loggedIn {
+		m.currentView = CoursesView
+	}
+}
+
+// replaceView swaps the current (usually loading) view for v. If v is already
+// the view underneath, as when refreshing, it is popped instead so the stack
+// doesn't grow duplicates.
+func (m *model) replaceView(v ViewType) {
+	if len(m.viewStack) > 0 && m.viewStack[len(m.viewStack)-1] == v {
+		m.popView()
+		return
+	}
+	m.currentView = v
+}
+
+// resetViews makes v the root of the navigation stack.
+func (m *model) resetViews(v ViewType) {
+	m.viewStack = nil
+	m.currentView = v
+}
+
+func (m model) viewLabel(v ViewType) string {
+	switch v {
+	case CoursesView:
+		return T("nav.courses")
+	case CourseDetailView:
+		if m.selectedCourse < len(m.courses) {
+			return m.courses[m.selectedCourse].Code
+		}
+		return T("nav.course")
+	case AttendanceView, AssessmentView, OutlineView:
+		// The tab bar says which part of the course is open.
+		return m.viewLabel(CourseDetailView)
+	case TranscriptView:
+		return T("nav.transcript")
+	case ChatView:
+		return T("nav.chat")
+	case ProvisionalResultView:
+		return T("nav.results")
+	case FeesView:
+		return T("nav.fees")
+	case JobsView:
+		return T("nav.jobs")
+	case RetakeView:
+		return T("nav.retake")
+	case GradeScaleView:
+		return T("nav.grade_scale")
+	case LatencyView:
+		return T("nav.latency")
+	case SearchView:
+		return T("nav.search")
+	default:
+		return ""
+	}
+}
+
+// renderBreadcrumbs renders the navigation trail, e.g.
+// "Courses ▸ CC2042 ▸ Attendance". It is empty outside the logged-in views.
+func (m model) renderBreadcrumbs() string {
+	current := m.viewLabel(m.currentView)
+	if current == "" {
+		return ""
+	}
+
+	trailStyle := lipgloss.NewStyle().Foreground(GREY)
+	currentStyle := lipgloss.NewStyle().Foreground(ACCENT).Bold(true)
+
+	var parts []string
+	for _, v := range m.viewStack {
+		if label := m.viewLabel(v); label != "" {
+			parts = append(parts, trailStyle.Render(label))
+		}
+	}
+	parts = append(parts, currentStyle.Render(current))
+
+	return lipgloss.NewStyle().Padding(0, 1).Render(strings.Join(parts, trailStyle.Render(" ▸ ")))
+}
+
+// courseTabs are the views of a single course. They share one level of the
+// navigation stack: Tab and Shift+Tab switch between them and Esc leaves the
+// course.
+var courseTabs = []ViewType{CourseDetailView, AttendanceView, AssessmentView, OutlineView}
+
+func courseTabIndex(v ViewType) int {
+	for i, tab := range courseTabs {
+		if tab == v {
+			return i
+		}
+	}
+	return -1
+}
+
+// switchCourseTab moves delta tabs from the current one, wrapping around.
+func (m model) switchCourseTab(delta int) (tea.Model, tea.Cmd) {
+	i := courseTabIndex(m.currentView)
+	if i == -1 {
+		return m, nil
+	}
+	return m.openCourseTab(courseTabs[(i+delta+len(courseTabs))%len(courseTabs)])
+}
+
+// openCourseTab shows tab in place of the current course tab, fetching its
+// data first the first time it is opened.
+func (m model) openCourseTab(tab ViewType) (tea.Model, tea.Cmd) {
+	if m.selectedCourse >= len(m.courses) {
+		return m, nil
+	}
+	course := m.courses[m.selectedCourse]
+	m.outlineStatus = ""
+
+	// Loading from the tab's own level of the stack keeps the fetched tab
+	// there too, instead of on top of the tab that was open.
+	switch {
+	case tab == AttendanceView && len(course.Attendance) == 0:
+		m.currentView = LoadingView
+		return m.openAttendance()
+	case tab == AssessmentView && len(course.Assessment) == 0:
+		m.currentView = LoadingView
+		return m.openAssessments()
+	case tab == OutlineView && m.outlineCourse != course.ID:
+		m.setLoadingState(T("loading.outline", course.Code), T("loading.outline_help"), T("loading.help_back"))
+		m.currentView = LoadingView
+		return m, tea.Batch(m.spinner.Tick, outlineCmd(m.session, course.ID, false))
+	}
+	m.currentView = tab
+	return m, nil
+}
+
+func (m model) renderCourseTabs() string {
+	if courseTabIndex(m.currentView) == -1 {
+		return ""
+	}
+
+	activeStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(WHITE).
+		Background(BLUE).
+		Padding(0, 1)
+
+	tabStyle := lipgloss.NewStyle().
+		Foreground(GREY).
+		Padding(0, 1)
+
+	labels := []string{T("nav.details"), T("nav.attendance"), T("nav.assessments"), T("nav.outline")}
+	var tabs []string
+	for i, tab := range courseTabs {
+		if tab == m.currentView {
+			tabs = append(tabs, activeStyle.Render(labels[i]))
+		} else {
+			tabs = append(tabs, tabStyle.Render(labels[i]))
+		}
+	}
+	return lipgloss.NewStyle().Padding(0, 1).Render(strings.Join(tabs, tabStyle.Render("│")))
+}
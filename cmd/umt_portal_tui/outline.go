@@ -0,0 +1,242 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/ledongthuc/pdf"
+)
+
+var errNoOutline = errors.New("the portal has no outline for this course")
+
+// CourseOutline is a downloaded outline document as served by the portal.
+type CourseOutline struct {
+	FileName    string
+	ContentType string
+	Data        []byte
+}
+
+func (s *Session) fetchCourseOutline(courseId string) (CourseOutline, error) {
+	var outline CourseOutline
+
+	if len(s.Cookies) == 0 {
+		return outline, fmt.Errorf("no cookies found during fetching course outline")
+	}
+	course, ok := s.courseSnapshot(courseId)
+	if !ok {
+		return outline, fmt.Errorf("course not found")
+	}
+	if course.OutlineURL == "" {
+		return outline, errNoOutline
+	}
+
+	base, _ := url.Parse(UMT_COURSES_URL)
+	ref, err := url.Parse(course.OutlineURL)
+	if err != nil {
+		return outline, fmt.Errorf("invalid outline link %q: %w", course.OutlineURL, err)
+	}
+	outlineURL := base.ResolveReference(ref)
+
+	req, err := http.NewRequest("GET", outlineURL.String(), nil)
+	if err != nil {
+		return outline, fmt.Errorf("failed to create outline request: %w", err)
+	}
+	for _, cookie := range s.Cookies {
+		req.AddCookie(cookie)
+	}
+
+	resp, err := s.httpClient(OP_OTHER).Do(req)
+	if err != nil {
+		return outline, fmt.Errorf("failed to get course outline: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return outline, fmt.Errorf("failed to get course outline: %s", resp.Status)
+	}
+
+	outline.Data, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return outline, fmt.Errorf("failed to read course outline: %w", err)
+	}
+	outline.ContentType, _, _ = mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	outline.FileName = outlineFileName(course, resp.Header.Get("Content-Disposition"), outlineURL.Path, outline.ContentType)
+	return outline, nil
+}
+
+// outlineFileName prefers the server's attachment name, then the last path
+// segment if it looks like a file, and otherwise names the file after the
+// course code.
+func outlineFileName(course Course, disposition, urlPath, contentType string) string {
+	if _, params, err := mime.ParseMediaType(disposition); err == nil && params["filename"] != "" {
+		return filepath.Base(params["filename"])
+	}
+	if name := path.Base(urlPath); path.Ext(name) != "" {
+		return name
+	}
+	ext := ".html"
+	switch (CourseOutline{ContentType: contentType}).kind() {
+	case "pdf":
+		ext = ".pdf"
+	case "docx":
+		ext = ".docx"
+	case "text":
+		ext = ".txt"
+	}
+	return course.Code + "_outline" + ext
+}
+
+func (o CourseOutline) kind() string {
+	switch {
+	case o.ContentType == "application/pdf", strings.EqualFold(filepath.Ext(o.FileName), ".pdf"):
+		return "pdf"
+	case strings.Contains(o.ContentType, "wordprocessingml"), strings.EqualFold(filepath.Ext(o.FileName), ".docx"):
+		return "docx"
+	case strings.HasPrefix(o.ContentType, "text/html"), strings.HasPrefix(strings.ToLower(filepath.Ext(o.FileName)), ".htm"):
+		return "html"
+	case strings.HasPrefix(o.ContentType, "text/"):
+		return "text"
+	default:
+		return ""
+	}
+}
+
+var blankLines = regexp.MustCompile(`\n[ \t]*\n(\s*\n)+`)
+
+// Text extracts readable text from PDF, DOCX, HTML and plain-text outlines.
+func (o CourseOutline) Text() (string, error) {
+	var text string
+	switch o.kind() {
+	case "pdf":
+		t, err := pdfText(o.Data)
+		if err != nil {
+			return "", err
+		}
+		text = t
+	case "docx":
+		t, err := docxText(o.Data)
+		if err != nil {
+			return "", err
+		}
+		text = t
+	case "html":
+		doc, err := goquery.NewDocumentFromReader(bytes.NewReader(o.Data))
+		if err != nil {
+			return "", fmt.Errorf("failed to parse outline HTML: %w", err)
+		}
+		doc.Find("script, style").Remove()
+		text = doc.Find("body").Text()
+	case "text":
+		text = string(o.Data)
+	default:
+		return "", fmt.Errorf("can't extract text from %s outlines; save it instead", o.ContentType)
+	}
+
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = blankLines.ReplaceAllString(text, "\n\n")
+	return strings.TrimSpace(text), nil
+}
+
+// pdfText extracts the text layer of a PDF. The PDF reader panics on some
+// malformed files, which is turned into an error.
+func pdfText(data []byte) (text string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed to extract outline PDF text: %v", r)
+		}
+	}()
+	r, err := pdf.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", fmt.Errorf("failed to open outline PDF: %w", err)
+	}
+	plain, err := r.GetPlainText()
+	if err != nil {
+		return "", fmt.Errorf("failed to extract outline PDF text: %w", err)
+	}
+	raw, err := io.ReadAll(plain)
+	if err != nil {
+		return "", fmt.Errorf("failed to extract outline PDF text: %w", err)
+	}
+	return string(raw), nil
+}
+
+// docxText pulls the paragraphs out of word/document.xml.
+func docxText(data []byte) (string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", fmt.Errorf("failed to open outline document: %w", err)
+	}
+	f, err := zr.Open("word/document.xml")
+	if err != nil {
+		return "", fmt.Errorf("failed to open outline document: %w", err)
+	}
+	defer f.Close()
+
+	var b strings.Builder
+	dec := xml.NewDecoder(f)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to read outline document: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "tab":
+				b.WriteString("\t")
+			case "br":
+				b.WriteString("\n")
+			}
+		case xml.EndElement:
+			if t.Name.Local == "p" {
+				b.WriteString("\n")
+			}
+		case xml.CharData:
+			b.Write(t)
+		}
+	}
+	return b.String(), nil
+}
+
+// downloadDir is where documents fetched from the portal are saved: the
+// download_dir setting, else ~/Downloads when it exists, else the working
+// directory.
+func downloadDir() string {
+	if appConfig.DownloadDir != "" {
+		return appConfig.DownloadDir
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		dir := filepath.Join(home, "Downloads")
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir
+		}
+	}
+	return "."
+}
+
+// saveCourseOutline writes the outline into dir and returns its path.
+func saveCourseOutline(o CourseOutline, dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create download dir: %w", err)
+	}
+	path := filepath.Join(dir, o.FileName)
+	if err := os.WriteFile(path, o.Data, 0644); err != nil {
+		return "", fmt.Errorf("failed to save outline: %w", err)
+	}
+	return path, nil
+}
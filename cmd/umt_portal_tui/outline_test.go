@@ -0,0 +1,70 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCourseOutline(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	useMockPortal(t, portal)
+
+	s := NewSession()
+	if code, text := s.Login(Credentials{StudentID: "F2023000000", Password: "hunter2"}, false); code != ErrNone {
+		t.Fatalf("login failed: %v %s", code, text)
+	}
+	courses, err := s.GetCourses()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	outline, err := s.GetCourseOutline(courses[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if outline.FileName != "CC2042_outline.html" {
+		t.Errorf("file name = %q", outline.FileName)
+	}
+	text, err := outline.Text()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(text, "Course Objectives") || strings.Contains(text, "tracking") || strings.Contains(text, "\n\n\n") {
+		t.Errorf("unexpected outline text:\n%s", text)
+	}
+
+	path, err := saveCourseOutline(outline, filepath.Join(t.TempDir(), "downloads"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved, _ := os.ReadFile(path); !bytes.Equal(saved, outline.Data) {
+		t.Error("saved outline differs from the download")
+	}
+
+	if _, err := s.GetCourseOutline(courses[1].ID); !errors.Is(err, errNoOutline) {
+		t.Errorf("expected errNoOutline, got %v", err)
+	}
+}
+
+func TestDocxOutlineText(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, _ := zw.Create("word/document.xml")
+	w.Write([]byte(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+		`<w:p><w:r><w:t>Week 1</w:t><w:tab/><w:t>Introduction</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:t>Week 2</w:t></w:r></w:p></w:body></w:document>`))
+	zw.Close()
+
+	text, err := CourseOutline{FileName: "outline.docx", Data: buf.Bytes()}.Text()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text != "Week 1\tIntroduction\nWeek 2" {
+		t.Errorf("unexpected text %q", text)
+	}
+}
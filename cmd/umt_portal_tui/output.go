@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	FORMAT_TABLE = "table"
+	FORMAT_JSON  = "json"
+	FORMAT_CSV   = "csv"
+	FORMAT_TSV   = "tsv"
+)
+
+var outputFormats = []string{FORMAT_TABLE, FORMAT_JSON, FORMAT_CSV, FORMAT_TSV}
+
+// Output is what a CLI subcommand produces. Header and Rows feed the table,
+// CSV and TSV formats; Value is encoded for JSON. Notes are extra lines for
+// humans and only appear in table output, as does Record, which prints a
+// single row as "header: value" lines instead of a one-line table. HTML is
+// an optional rendering used as the body of --email. Text, when set, is
+// written in place of the table.
+type Output struct {
+	Header []string
+	Rows   [][]string
+	Value  any
+	Notes  []string
+	Record bool
+	HTML   string
+	Text   string
+}
+
+type formatFlag string
+
+func (f *formatFlag) String() string { return string(*f) }
+
+func (f *formatFlag) Set(s string) error {
+	for _, format := range outputFormats {
+		if s == format {
+			*f = formatFlag(s)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown format %q (want %s)", s, strings.Join(outputFormats, ", "))
+}
+
+func writeOutput(w io.Writer, format string, o Output) error {
+	switch format {
+	case FORMAT_JSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(o.Value)
+	case FORMAT_CSV:
+		cw := csv.NewWriter(w)
+		cw.Write(o.Header)
+		cw.WriteAll(o.Rows)
+		return cw.Error()
+	case FORMAT_TSV:
+		for _, row := range append([][]string{o.Header}, o.Rows...) {
+			fields := make([]string, len(row))
+			for i, field := range row {
+				fields[i] = fieldReplacer.Replace(field)
+			}
+			if _, err := fmt.Fprintln(w, strings.Join(fields, "\t")); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		if o.Text != "" {
+			_, err := io.WriteString(w, o.Text)
+			return err
+		}
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		if o.Record && len(o.Rows) == 1 {
+			for i, field := range o.Rows[0] {
+				label := strings.ReplaceAll(o.Header[i], "_", " ")
+				fmt.Fprintf(tw, "%s%s:\t%s\n", strings.ToUpper(label[:1]), label[1:], field)
+			}
+			return tw.Flush()
+		}
+		if len(o.Header) > 0 {
+			fmt.Fprintln(tw, strings.ToUpper(strings.Join(o.Header, "\t")))
+		}
+		for _, row := range o.Rows {
+			fields := make([]string, len(row))
+			for i, field := range row {
+				fields[i] = fieldReplacer.Replace(field)
+			}
+			fmt.Fprintln(tw, strings.Join(fields, "\t"))
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+		if len(o.Notes) > 0 {
+			fmt.Fprintln(w)
+			for _, note := range o.Notes {
+				fmt.Fprintln(w, note)
+			}
+		}
+		return nil
+	}
+}
+
+// fieldReplacer keeps each field in its column and each record on one line,
+// since neither TSV nor the table format has quoting.
+var fieldReplacer = strings.NewReplacer("\t", " ", "\n", " ", "\r", " ")
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteOutput(t *testing.T) {
+	o := Output{
+		Header: []string{"code", "title"},
+		Rows:   [][]string{{"CC2042", "Data Structures, Lab"}, {"MA2110", "Linear\tAlgebra"}},
+		Value:  []map[string]string{{"code": "CC2042"}},
+		Notes:  []string{"2 courses"},
+	}
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{FORMAT_TABLE, "CODE    TITLE\nCC2042  Data Structures, Lab\nMA2110  Linear Algebra\n\n2 courses\n"},
+		{FORMAT_CSV, "code,title\nCC2042,\"Data Structures, Lab\"\nMA2110,Linear\tAlgebra\n"},
+		{FORMAT_TSV, "code\ttitle\nCC2042\tData Structures, Lab\nMA2110\tLinear Algebra\n"},
+		{FORMAT_JSON, "[\n  {\n    \"code\": \"CC2042\"\n  }\n]\n"},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := writeOutput(&buf, tt.format, o); err != nil {
+			t.Fatalf("%s: %v", tt.format, err)
+		}
+		if buf.String() != tt.want {
+			t.Errorf("%s output:\n%q\nwant:\n%q", tt.format, buf.String(), tt.want)
+		}
+	}
+}
+
+func TestFormatFlagRejectsUnknown(t *testing.T) {
+	var f formatFlag
+	if err := f.Set("xml"); err == nil {
+		t.Error("accepted unknown format xml")
+	}
+	if err := f.Set(FORMAT_CSV); err != nil || f != FORMAT_CSV {
+		t.Errorf("Set(csv) = %v, format %q", err, f)
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// cachedPage is the last copy of a portal page, the validators it came with
+// and what it parsed to.
+type cachedPage struct {
+	etag         string
+	lastModified string
+	sum          [sha256.Size]byte
+	body         []byte
+	value        any
+}
+
+// pageCache remembers the pages a session has fetched, so fetching one
+// again can be a conditional request and an unchanged page is not parsed
+// again. A nil cache remembers nothing.
+type pageCache struct {
+	mu    sync.Mutex
+	pages map[string]*cachedPage
+}
+
+func (c *pageCache) get(pageURL string) *cachedPage {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.pages[pageURL]
+}
+
+func (c *pageCache) put(pageURL string, page *cachedPage) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.pages == nil {
+		c.pages = map[string]*cachedPage{}
+	}
+	c.pages[pageURL] = page
+}
+
+func (c *pageCache) setValue(pageURL string, value any) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if page := c.pages[pageURL]; page != nil {
+		page.value = value
+	}
+}
+
+// getPage GETs pageURL with the session cookies. If the page was fetched
+// before, the request carries its ETag and Last-Modified validators; a 304,
+// or a body identical to the last one for pages that send no validators,
+// reports the page unchanged. With --no-cache no validators are sent.
+func (s *Session) getPage(op, pageURL string) (body []byte, unchanged bool, err error) {
+	req, err := http.NewRequest("GET", pageURL, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	for _, cookie := range s.Cookies {
+		req.AddCookie(cookie)
+	}
+
+	cached := s.pages.get(pageURL)
+	if cacheMode == CACHE_OFF {
+		cached = nil
+	}
+	if cached != nil {
+		if cached.etag != "" {
+			req.Header.Set("If-None-Match", cached.etag)
+		}
+		if cached.lastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.lastModified)
+		}
+	}
+
+	resp, err := s.httpClient(op).Do(req)
+	if err != nil {
+		return nil, false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		return cached.body, true, nil
+	}
+	body, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, false, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return body, false, nil
+	}
+
+	page := &cachedPage{
+		etag:         resp.Header.Get("ETag"),
+		lastModified: resp.Header.Get("Last-Modified"),
+		sum:          sha256.Sum256(body),
+		body:         body,
+	}
+	unchanged = cached != nil && cached.sum == page.sum
+	if unchanged {
+		page.value = cached.value
+	}
+	s.pages.put(pageURL, page)
+	return body, unchanged, nil
+}
+
+// fetchPage fetches pageURL and parses it, or returns what it parsed to last
+// time if the page hasn't changed since. op picks the request timeout; name
+// describes the page in errors.
+func fetchPage[T any](s *Session, op, pageURL, name string, parse func(io.Reader) (T, error)) (T, error) {
+	var zero T
+	body, unchanged, err := s.getPage(op, pageURL)
+	if err != nil {
+		return zero, fmt.Errorf("failed to get %s: %w", name, err)
+	}
+	if page := s.pages.get(pageURL); unchanged && page != nil {
+		if v, ok := page.value.(T); ok {
+			return v, nil
+		}
+	}
+
+	v, err := parse(bytes.NewReader(body))
+	if err != nil {
+		return zero, err
+	}
+	s.pages.setValue(pageURL, v)
+	return v, nil
+}
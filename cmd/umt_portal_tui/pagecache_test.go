@@ -0,0 +1,54 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestPageCache(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	useMockPortal(t, portal)
+
+	s := NewSession()
+	if code, text := s.Login(Credentials{StudentID: "F2023000000", Password: "hunter2"}, false); code != ErrNone {
+		t.Fatalf("login failed: %v %s", code, text)
+	}
+
+	// The mock portal serves fixtures with Last-Modified, so the second
+	// fetch is a conditional request answered with 304.
+	first, err := s.GetFees()
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := s.GetFees()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(second) != len(first) || len(second) == 0 {
+		t.Errorf("got %d challans from the cached page, want %d", len(second), len(first))
+	}
+	if last := s.requests.recent(1)[0]; last.Status != http.StatusNotModified {
+		t.Errorf("second fetch got %d, want 304", last.Status)
+	}
+
+	// The transcript report is served without validators, so an identical
+	// body is recognized by its hash instead.
+	parses := 0
+	parse := func(r io.Reader) (int, error) {
+		parses++
+		b, err := io.ReadAll(r)
+		return len(b), err
+	}
+	for range 2 {
+		if n, err := fetchPage(s, OP_TRANSCRIPT, TRANSCRIPT_ASPX_URL, "transcript report", parse); err != nil || n == 0 {
+			t.Fatalf("fetchPage = %d, %v", n, err)
+		}
+	}
+	if parses != 1 {
+		t.Errorf("parsed the unchanged report %d times", parses)
+	}
+	if last := s.requests.recent(1)[0]; last.Status != http.StatusOK {
+		t.Errorf("report fetch got %d, want 200", last.Status)
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	PALETTE_DEFAULT      = "default"
+	PALETTE_DEUTERANOPIA = "deuteranopia"
+	PALETTE_PROTANOPIA   = "protanopia"
+)
+
+// Semantic color roles. Views color what a value means rather than pick a
+// hue, so the configured palette recolors them all at once: SUCCESS for
+// what is fine, WARN for what needs a look, DANGER for errors and anything
+// below a threshold, ACCENT for titles and selections.
+var (
+	SUCCESS = GREEN
+	WARN    = YELLOW
+	DANGER  = RED
+	ACCENT  = LIGHT_BLUE
+)
+
+type palette struct {
+	success, warn, danger, accent lipgloss.Color
+}
+
+// PALETTES are the presets for the palette config key. The colorblind ones
+// take their hues from the Okabe-Ito palette: success is blue instead of
+// green, so it can't be mistaken for danger, and danger is told from warn
+// by its lightness as well as its hue.
+var PALETTES = map[string]palette{
+	PALETTE_DEFAULT:      {GREEN, YELLOW, RED, LIGHT_BLUE},
+	PALETTE_DEUTERANOPIA: {"#56B4E9", "#F0E442", "#D55E00", "#CC79A7"},
+	PALETTE_PROTANOPIA:   {"#56B4E9", "#F0E442", "#FF7F0E", "#CC79A7"},
+}
+
+func paletteNames() []string {
+	names := make([]string, 0, len(PALETTES))
+	for name := range PALETTES {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func validatePalette(name string) error {
+	if _, ok := PALETTES[name]; name != "" && !ok {
+		return fmt.Errorf("palette %q is not one of %s", name, strings.Join(paletteNames(), ", "))
+	}
+	return nil
+}
+
+// applyPalette sets the color roles from a preset; an empty name keeps the
+// default.
+func applyPalette(name string) {
+	p, ok := PALETTES[name]
+	if !ok {
+		p = PALETTES[PALETTE_DEFAULT]
+	}
+	SUCCESS, WARN, DANGER, ACCENT = p.success, p.warn, p.danger, p.accent
+}
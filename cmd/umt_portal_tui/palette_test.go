@@ -0,0 +1,54 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+func TestPalette(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("AppData", dir)
+	prevProfile := lipgloss.ColorProfile()
+	t.Cleanup(func() {
+		lipgloss.SetColorProfile(prevProfile)
+		applyPalette("")
+	})
+	lipgloss.SetColorProfile(termenv.TrueColor)
+
+	path := filepath.Join(dir, APP_DIR, "config.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(`{"palette": "deuteranopia"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	applyPalette(cfg.Palette)
+
+	// Errors are drawn in the preset's vermillion, not the default red.
+	m := model{currentView: ResultView, courseError: errors.New("portal down"), width: 80, height: 24}
+	view := m.renderResult()
+	if !strings.Contains(view, "38;2;213;94;0m") {
+		t.Errorf("error not in the deuteranopia danger color:\n%q", view)
+	}
+	if strings.Contains(view, "38;2;255;85;85m") {
+		t.Errorf("error still in the default red:\n%q", view)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"palette": "sepia"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadConfig(); err == nil || !strings.Contains(err.Error(), "sepia") {
+		t.Errorf("unknown palette: %v", err)
+	}
+}
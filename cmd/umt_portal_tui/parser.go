@@ -0,0 +1,485 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// errReportIncomplete is returned by the report parsers when the ReportViewer
+// response did not contain the expected data yet; callers should retry.
+var errReportIncomplete = errors.New("report incomplete")
+
+// Report responses are parsed as they stream in; MAX_REPORT_SIZE guards
+// against a runaway response, real reports being a few hundred KB.
+const MAX_REPORT_SIZE = 16 << 20
+
+var errReportTooLarge = fmt.Errorf("report larger than %d MB", MAX_REPORT_SIZE>>20)
+
+type reportReader struct {
+	r    io.Reader
+	read int64
+}
+
+// limitReport fails reading r with errReportTooLarge once it has yielded
+// more than MAX_REPORT_SIZE bytes.
+func limitReport(r io.Reader) io.Reader {
+	return &reportReader{r: r}
+}
+
+func (l *reportReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.read += int64(n)
+	if l.read > MAX_REPORT_SIZE {
+		return n, errReportTooLarge
+	}
+	return n, err
+}
+
+var attendancePercentagePattern = regexp.MustCompile(`\d+(\.\d+)?`)
+
+type AttendanceReport struct {
+	TotalLectures        int
+	AttendancePercentage float64
+	Records              []Attendance
+}
+
+// extractTablixText collects the text of every ReportViewer tablix cell, in
+// document order, along with the text of any sibling element.
+func extractTablixText(doc *goquery.Document) []string {
+	var extractedData []string
+	doc.Find("div.canGrowTextBoxInTablix.cannotShrinkTextBoxInTablix").Each(func(i int, s *goquery.Selection) {
+		currentText := strings.TrimSpace(s.Text())
+		if currentText != "" && !strings.Contains(currentText, "canGrowTextBoxInTablix") {
+			extractedData = append(extractedData, currentText)
+		}
+		sibling := s.Next()
+		if sibling.Length() > 0 {
+			siblingText := strings.TrimSpace(sibling.Text())
+			if siblingText != "" {
+				extractedData = append(extractedData, siblingText)
+			}
+		}
+	})
+	return extractedData
+}
+
+func parseCoursesHTML(r io.Reader) ([]Course, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse courses HTML: %w", err)
+	}
+
+	var courses []Course
+	doc.Find(".table tr").Each(func(rowIndex int, row *goquery.Selection) {
+		if row.Find("th").Length() > 0 {
+			return
+		}
+
+		var rowData []string
+		var assignedID string
+
+		row.Find("td").Each(func(cellIndex int, cell *goquery.Selection) {
+			if emailLink := cell.Find("a.__cf_email__"); emailLink.Length() > 0 {
+				if encodedEmail, exists := emailLink.Attr("data-cfemail"); exists {
+					decodedEmail := decodeFacultyEmail(encodedEmail)
+					if decodedEmail != "" {
+						rowData = append(rowData, decodedEmail)
+					} else {
+						rowData = append(rowData, "[email protected]")
+					}
+				} else {
+					rowData = append(rowData, strings.TrimSpace(cell.Text()))
+				}
+			} else if assignedLink := cell.Find("a.assesment"); assignedLink.Length() > 0 {
+				if id, exists := assignedLink.Attr("data-assigned-id"); exists {
+					assignedID = id
+				}
+				rowData = append(rowData, "")
+			} else {
+				cellText := strings.TrimSpace(cell.Text())
+				rowData = append(rowData, cellText)
+			}
+		})
+
+		// The outline link, when a course has one, sits in its own column
+		// whose position varies, so it is looked up across the whole row.
+		var outlineURL string
+		row.Find("a[href]").EachWithBreak(func(_ int, link *goquery.Selection) bool {
+			href, _ := link.Attr("href")
+			if strings.Contains(strings.ToLower(href), "outline") || strings.Contains(strings.ToLower(link.Text()), "outline") {
+				outlineURL = href
+				return false
+			}
+			return true
+		})
+
+		if len(rowData) >= 9 {
+			courses = append(courses, Course{
+				ID:           assignedID,
+				Code:         rowData[0],
+				Title:        rowData[1],
+				CreditHours:  rowData[2],
+				CourseType:   rowData[3],
+				FacultyName:  rowData[4],
+				FacultyEmail: rowData[5],
+				Mode:         rowData[6],
+				Section:      rowData[7],
+				Semester:     rowData[8],
+				OutlineURL:   outlineURL,
+			})
+		}
+	})
+
+	return courses, nil
+}
+
+// parseAssessmentsHTML returns the assessment rows and whether an assessment
+// table was present at all, which distinguishes an empty course from a page
+// that failed to load.
+func parseAssessmentsHTML(r io.Reader) ([]Assessment, bool, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to parse assessments HTML: %w", err)
+	}
+
+	var assessmentRecords []Assessment
+	foundTable := false
+
+	doc.Find("table").Each(func(tableIndex int, table *goquery.Selection) {
+		// Columns are found by their headers; some courses add the class
+		// average or highest marks.
+		columns := map[string]int{}
+		table.Find("tr").First().Find("th").Each(func(i int, th *goquery.Selection) {
+			headerText := strings.ToLower(strings.TrimSpace(th.Text()))
+			switch {
+			case strings.Contains(headerText, "name"):
+				columns["name"] = i
+			case strings.Contains(headerText, "total marks"):
+				columns["total"] = i
+			case strings.Contains(headerText, "obtained marks"):
+				columns["obtained"] = i
+			case strings.Contains(headerText, "assigned date"):
+				columns["date"] = i
+			case strings.Contains(headerText, "average") || strings.Contains(headerText, "avg"):
+				columns["average"] = i
+			case strings.Contains(headerText, "highest") || strings.Contains(headerText, "max"):
+				columns["highest"] = i
+			}
+		})
+		for _, required := range []string{"name", "total", "obtained", "date"} {
+			if _, ok := columns[required]; !ok {
+				return
+			}
+		}
+
+		foundTable = true
+		table.Find("tr").Each(func(rowIndex int, row *goquery.Selection) {
+			if rowIndex == 0 {
+				return
+			}
+
+			cells := row.Find("td")
+			if cells.Length() < 4 {
+				return
+			}
+			cell := func(column string) string {
+				i, ok := columns[column]
+				if !ok {
+					return ""
+				}
+				return strings.TrimSpace(cells.Eq(i).Text())
+			}
+			marks := func(column string) float32 {
+				f, _ := strconv.ParseFloat(cell(column), 64)
+				return float32(f)
+			}
+
+			if name := cell("name"); name != "" {
+				assessmentRecords = append(assessmentRecords, Assessment{
+					name:          name,
+					obtainedMarks: marks("obtained"),
+					totalMarks:    marks("total"),
+					assignedDate:  cell("date"),
+					classAverage:  marks("average"),
+					classHighest:  marks("highest"),
+				})
+			}
+		})
+	})
+
+	return assessmentRecords, foundTable, nil
+}
+
+func parseAttendanceReport(r io.Reader) (AttendanceReport, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return AttendanceReport{}, fmt.Errorf("failed to parse attendance report HTML: %w", err)
+	}
+	return attendanceFromDoc(doc)
+}
+
+// attendanceFromDoc scrapes the attendance out of the report's HTML
+// rendering, by the position of its tablix cells.
+func attendanceFromDoc(doc *goquery.Document) (AttendanceReport, error) {
+	var report AttendanceReport
+
+	// A report still loading has no tablix, and a finished one always ends
+	// with its totals row. One cut short still yields the lectures sent so
+	// far, returned with errReportIncomplete.
+	extractedData := extractTablixText(doc)
+	if len(extractedData) < 6 {
+		return report, errReportIncomplete
+	}
+	complete := strings.HasPrefix(extractedData[len(extractedData)-2], "Total Lectures")
+
+	startIndex := 4
+	endIndex := len(extractedData)
+	if complete {
+		endIndex -= 2
+	}
+
+	for i := startIndex; i < endIndex; i += 4 {
+		if i+3 >= endIndex {
+			break
+		}
+
+		lectureNumStr := strings.TrimPrefix(extractedData[i], "Lecture No. ")
+		lectureNum, err := strconv.Atoi(lectureNumStr)
+		if err != nil {
+			continue
+		}
+
+		report.Records = append(report.Records, Attendance{
+			LectureNumber: lectureNum,
+			LectureDate:   extractedData[i+1],
+			Attendance:    strings.EqualFold(extractedData[i+2], "Present"),
+			Faculty:       extractedData[i+3],
+		})
+	}
+	if !complete {
+		return report, errReportIncomplete
+	}
+
+	totalLecturesStr := strings.TrimPrefix(extractedData[len(extractedData)-2], "Total Lectures : ")
+	if totalLectures, err := strconv.Atoi(totalLecturesStr); err == nil {
+		report.TotalLectures = totalLectures
+	}
+
+	// e.g. "87.5 % Attandence" (sic) or "100 % Attendance"
+	percentageStr := attendancePercentagePattern.FindString(extractedData[len(extractedData)-1])
+	if attendancePercentage, err := strconv.ParseFloat(percentageStr, 64); err == nil {
+		report.AttendancePercentage = attendancePercentage
+	}
+
+	return report, nil
+}
+
+func parseTranscriptReport(r io.Reader) (Transcript, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return Transcript{}, fmt.Errorf("failed to parse HTML document: %w", err)
+	}
+	return transcriptFromDoc(doc)
+}
+
+// transcriptFromDoc scrapes the transcript out of the report's HTML
+// rendering.
+func transcriptFromDoc(doc *goquery.Document) (Transcript, error) {
+	var transcript Transcript
+
+	spans := []string{}
+	doc.Find("span").Each(func(i int, s *goquery.Selection) {
+		spans = append(spans, strings.TrimSpace(s.Text()))
+	})
+
+	if err := parseSpanData(&transcript, spans); err != nil {
+		return transcript, fmt.Errorf("failed to parse span data: %w", err)
+	}
+
+	extractedData := extractTablixText(doc)
+	if len(extractedData) == 0 {
+		return transcript, fmt.Errorf("no transcript data found in response: %w", errReportIncomplete)
+	}
+
+	if err := parseTranscript(&transcript, extractedData); err != nil {
+		return transcript, fmt.Errorf("failed to parse transcript: %w", err)
+	}
+
+	transcript.fillTotals()
+	return transcript, nil
+}
+
+// fillTotals falls back to our own repeat-aware totals when the report
+// omits them.
+func (t *Transcript) fillTotals() {
+	creditHours, gradePoints := t.gpaTotals()
+	if t.CreditHoursForGPA == "" {
+		t.CreditHoursForGPA = strconv.Itoa(creditHours)
+	}
+	if t.TotalGradePoints == "" {
+		t.TotalGradePoints = fmt.Sprintf("%.2f", gradePoints)
+	}
+	if t.TotalCGPA == "" && creditHours > 0 {
+		t.TotalCGPA = fmt.Sprintf("%.2f", gradePoints/float64(creditHours))
+	}
+}
+
+func parseSpanData(t *Transcript, spans []string) error {
+	for i, span := range spans {
+		span = strings.TrimSpace(span)
+
+		if span == "Credit Hours Earned :" && i+1 < len(spans) {
+			t.CreditHoursEarned = strings.TrimSpace(spans[i+1])
+		}
+
+		if span == "Credit Hours for GPA :" && i+1 < len(spans) {
+			t.CreditHoursForGPA = strings.TrimSpace(spans[i+1])
+		}
+
+		if span == "Total Grade Points :" && i+1 < len(spans) {
+			t.TotalGradePoints = strings.TrimSpace(spans[i+1])
+		}
+
+		if span == "CGPA :" && i+1 < len(spans) {
+			cgpaValue := strings.TrimSpace(spans[i+1])
+			if parts := strings.Split(cgpaValue, " /"); len(parts) > 0 {
+				t.TotalCGPA = strings.TrimSpace(parts[0])
+			} else {
+				t.TotalCGPA = cgpaValue
+			}
+		}
+	}
+	return nil
+}
+
+// isSemesterHeader reports whether line i of a transcript names a semester:
+// a season and year such as "Winter 2024", or any line heading a course
+// table, such as "Bridging Courses".
+func isSemesterHeader(extractedData []string, i int) bool {
+	if _, _, ok := parseSemesterName(extractedData[i]); ok {
+		return true
+	}
+	return i+1 < len(extractedData) && strings.TrimSpace(extractedData[i+1]) == "Course Code"
+}
+
+func parseTranscript(t *Transcript, extractedData []string) error {
+	semesterData := make(map[Semester][]TranscriptCourse)
+	var currentSemester Semester
+	var courses []TranscriptCourse
+
+	i := 0
+	for i < len(extractedData) {
+		line := strings.TrimSpace(extractedData[i])
+
+		if line == "Course Code" || line == "Course Title" || line == "Cr. Hrs" || line == "Grade" || line == "G.P." {
+			i++
+			continue
+		}
+
+		if isSemesterHeader(extractedData, i) {
+			if currentSemester.Name != "" && len(courses) > 0 {
+				semesterData[currentSemester] = courses
+			}
+
+			currentSemester = Semester{Name: line}
+			courses = []TranscriptCourse{}
+			i++
+			continue
+		}
+
+		if strings.Contains(line, "Cr. Hrs. Earned:") {
+			parts := strings.Split(line, "CGPA:")
+			if len(parts) >= 2 {
+				creditHoursPart := strings.TrimSpace(strings.Replace(parts[0], "Cr. Hrs. Earned:", "", 1))
+				if creditHours, err := strconv.Atoi(creditHoursPart); err == nil {
+					currentSemester.CreditHoursEarned = creditHours
+				}
+
+				cgpaStr := strings.TrimSpace(parts[1])
+				if cgpa, err := strconv.ParseFloat(cgpaStr, 32); err == nil {
+					currentSemester.CGPA = float32(cgpa)
+				}
+			}
+			i++
+			continue
+		}
+
+		if strings.Contains(line, "SGPA:") {
+			sgpaStr := strings.TrimSpace(strings.Replace(line, "SGPA:", "", 1))
+			if sgpa, err := strconv.ParseFloat(sgpaStr, 32); err == nil {
+				currentSemester.SGPA = float32(sgpa)
+			}
+			i++
+			continue
+		}
+
+		if i+3 < len(extractedData) {
+			code := strings.TrimSpace(line)
+			title := strings.TrimSpace(extractedData[i+1])
+			creditHoursStr := strings.TrimSpace(extractedData[i+2])
+			grade := strings.TrimSpace(extractedData[i+3])
+
+			if creditHours, err := strconv.Atoi(creditHoursStr); err == nil {
+				var gradePoint float32
+				fieldsToSkip := 4 // code, title, credit hours, grade
+
+				if isZeroGradePointGrade(grade) {
+					gradePoint = 0.0
+				} else {
+					if i+4 < len(extractedData) {
+						gradePointStr := strings.TrimSpace(extractedData[i+4])
+
+						if gp, err := strconv.ParseFloat(gradePointStr, 32); err == nil &&
+							!strings.Contains(gradePointStr, "Cr. Hrs. Earned:") &&
+							!strings.Contains(gradePointStr, "Fall") &&
+							!strings.Contains(gradePointStr, "Spring") &&
+							!strings.Contains(gradePointStr, "Summer") &&
+							!strings.Contains(gradePointStr, "Course Code") {
+							gradePoint = float32(gp)
+							fieldsToSkip = 5
+						} else {
+							gradePoint = 0.0
+							fieldsToSkip = 4
+						}
+					} else {
+						gradePoint = 0.0
+					}
+				}
+
+				course := TranscriptCourse{
+					Code:        code,
+					Title:       title,
+					CreditHours: creditHours,
+					Grade:       grade,
+					GradePoint:  gradePoint,
+				}
+				courses = append(courses, course)
+
+				i += fieldsToSkip
+				continue
+			}
+		}
+		i++
+	}
+
+	if currentSemester.Name != "" && len(courses) > 0 {
+		semesterData[currentSemester] = courses
+	}
+
+	semesterKeys := parseAndSortSemesters(semesterData)
+
+	t.Semester = make(map[Semester][]TranscriptCourse)
+	for _, key := range semesterKeys {
+		t.Semester[key.semester] = semesterData[key.semester]
+	}
+
+	applyRepeatPolicy(t)
+
+	return nil
+}
@@ -0,0 +1,271 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// updateGolden reports whether -update was given to rewrite the golden files
+// in testdata. The flag is registered by the golden package teatest uses.
+func updateGolden() bool {
+	return flag.Lookup("update").Value.String() == "true"
+}
+
+func openFixture(t *testing.T, name string) *os.File {
+	t.Helper()
+	f, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { f.Close() })
+	return f
+}
+
+// assertGolden compares v, marshalled as indented JSON, against
+// testdata/<name>.golden.json. Run `go test -update` to regenerate.
+func assertGolden(t *testing.T, name string, v any) {
+	t.Helper()
+	got, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = append(got, '\n')
+
+	path := filepath.Join("testdata", name+".golden.json")
+	if updateGolden() {
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading golden file (run with -update to create it): %v", err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("%s mismatch\n--- got ---\n%s\n--- want ---\n%s", name, got, want)
+	}
+}
+
+func TestParseCoursesHTML(t *testing.T) {
+	courses, err := parseCoursesHTML(openFixture(t, "courses.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertGolden(t, "courses", courses)
+}
+
+func TestParseResultsHTML(t *testing.T) {
+	result, err := parseResultsHTML(openFixture(t, "results.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertGolden(t, "results", result)
+
+	// B+ has no grade point on the page and falls back to the scale; the
+	// ungraded course is left out of the SGPA.
+	if got, want := result.SGPA(), float32((3.67+3.33)/2); got != want {
+		t.Errorf("SGPA = %v, want %v", got, want)
+	}
+}
+
+func TestParseAttendanceReport(t *testing.T) {
+	report, err := parseAttendanceReport(openFixture(t, "attendance_report.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertGolden(t, "attendance_report", report)
+}
+
+func TestParseAttendanceReportIncomplete(t *testing.T) {
+	_, err := parseAttendanceReport(openFixture(t, "courses.html"))
+	if !errors.Is(err, errReportIncomplete) {
+		t.Fatalf("got %v, want errReportIncomplete", err)
+	}
+}
+
+func TestParseReportStream(t *testing.T) {
+	// A report cut off before its totals row is still loading.
+	full, err := os.ReadFile(filepath.Join("testdata", "attendance_report.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cut := full[:bytes.Index(full, []byte("Total Lectures"))]
+	if _, err := parseAttendanceReport(bytes.NewReader(cut)); !errors.Is(err, errReportIncomplete) {
+		t.Errorf("truncated report: got %v, want errReportIncomplete", err)
+	}
+
+	endless := io.MultiReader(bytes.NewReader(full), strings.NewReader(strings.Repeat(" ", MAX_REPORT_SIZE)))
+	if _, err := parseAttendanceReport(limitReport(endless)); !errors.Is(err, errReportTooLarge) {
+		t.Errorf("oversized report: got %v, want errReportTooLarge", err)
+	}
+}
+
+func TestParseTranscriptReport(t *testing.T) {
+	transcript, err := parseTranscriptReport(openFixture(t, "transcript_report.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertGolden(t, "transcript_report", transcript.ToSerializable())
+}
+
+func TestParseAndSortSemesters(t *testing.T) {
+	data := map[Semester][]TranscriptCourse{
+		{Name: "Fall 2023 (Makeup)"}: nil,
+		{Name: "Spring 2023"}:        nil,
+		{Name: "Summer-2023"}:        nil,
+		{Name: "Fall 2022"}:          nil,
+		{Name: "Bridging Courses"}:   nil,
+		{Name: "Special 2023"}:       nil,
+		{Name: "Winter 2024"}:        nil,
+	}
+
+	want := []struct {
+		name   string
+		parsed bool
+	}{
+		{"Fall 2022", true},
+		{"Spring 2023", true},
+		{"Summer-2023", true},
+		{"Fall 2023 (Makeup)", true},
+		{"Winter 2024", true},
+		{"Special 2023", false},
+		{"Bridging Courses", false},
+	}
+
+	got := parseAndSortSemesters(data)
+	if len(got) != len(want) {
+		t.Fatalf("got %d semesters, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		if got[i].semester.Name != w.name || got[i].parsed != w.parsed {
+			t.Errorf("position %d: got %q (parsed=%v), want %q (parsed=%v)", i, got[i].semester.Name, got[i].parsed, w.name, w.parsed)
+		}
+	}
+}
+
+func TestParseTranscriptUnusualSemesters(t *testing.T) {
+	header := []string{"Course Code", "Course Title", "Cr. Hrs", "Grade", "G.P."}
+	var data []string
+	for _, sem := range []struct{ name, code string }{{"Bridging Courses", "MA0001"}, {"Fall 2023", "CS1001"}, {"Winter 2024", "CS1100"}} {
+		data = append(data, sem.name)
+		data = append(data, header...)
+		data = append(data, sem.code, "Some Course", "3", "A", "4.00", "Cr. Hrs. Earned: 3  CGPA: 4.00", "SGPA: 4.00")
+	}
+
+	var transcript Transcript
+	if err := parseTranscript(&transcript, data); err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, key := range parseAndSortSemesters(transcript.Semester) {
+		if courses := transcript.Semester[key.semester]; len(courses) != 1 {
+			t.Errorf("%s has %d courses, want 1", key.semester.Name, len(courses))
+		}
+		names = append(names, key.semester.Name)
+	}
+	if want := []string{"Fall 2023", "Winter 2024", "Bridging Courses"}; !slices.Equal(names, want) {
+		t.Errorf("semesters = %v, want %v", names, want)
+	}
+}
+
+func TestTranscriptRepeatPolicy(t *testing.T) {
+	transcript, err := parseTranscriptReport(openFixture(t, "transcript_report.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The failed Linear Algebra attempt in Spring 2024 is replaced by the
+	// Fall 2024 retake, and the pass/fail course never counts.
+	creditHours, gradePoints := transcript.gpaTotals()
+	if creditHours != 21 {
+		t.Errorf("got %d credit hours for GPA, want 21", creditHours)
+	}
+	if gradePoints < 71.98 || gradePoints > 72.0 {
+		t.Errorf("got %.2f grade points, want 71.99", gradePoints)
+	}
+}
+
+func TestParsePaymentsHTML(t *testing.T) {
+	challans, err := parsePaymentsHTML(openFixture(t, "payments.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertGolden(t, "payments", challans)
+
+	if got := outstandingDues(challans); got != 98500 {
+		t.Errorf("outstanding dues = %v, want 98500", got)
+	}
+	for amount, want := range map[float64]string{98500: "PKR 98,500", 1250.5: "PKR 1,250.50", 750: "PKR 750", -1200: "-PKR 1,200"} {
+		if got := formatAmount(amount); got != want {
+			t.Errorf("formatAmount(%v) = %q, want %q", amount, got, want)
+		}
+	}
+}
+
+func TestRetakeOptions(t *testing.T) {
+	transcript := Transcript{Semester: map[Semester][]TranscriptCourse{
+		{Name: "Fall 2023"}: {
+			{Code: "CS1001", CreditHours: 4, Grade: "A", GradePoint: 4},
+			{Code: "MA1001", CreditHours: 3, Grade: "F", GradePoint: 0},
+			{Code: "HU1001", CreditHours: 2, Grade: "D", GradePoint: 1},
+		},
+		{Name: "Spring 2024"}: {
+			{Code: "PH1001", CreditHours: 4, Grade: "F", GradePoint: 0},
+			{Code: "CS1002", CreditHours: 3, Grade: "C", GradePoint: 2},
+			{Code: "EN1001", CreditHours: 2, Grade: "W", GradePoint: 0},
+		},
+	}}
+
+	// 16 counted credit hours and 24 grade points: a CGPA of 1.50.
+	options := retakeOptions(transcript, "A")
+	var got []string
+	for _, o := range options {
+		got = append(got, o.Course.Code)
+	}
+	if want := []string{"PH1001", "MA1001", "HU1001"}; !slices.Equal(got, want) {
+		t.Fatalf("ranked %v, want %v", got, want)
+	}
+	if o := options[0]; math.Abs(o.NewCGPA-2.5) > 1e-9 || math.Abs(o.Delta-1) > 1e-9 || math.Abs(o.DeltaPerCreditHour-0.25) > 1e-9 {
+		t.Errorf("retaking PH1001 for an A: %+v", o)
+	}
+	if options := retakeOptions(transcript, "C"); len(options) != 3 || options[2].Delta <= 0 {
+		t.Errorf("a C retake should still help all three: %+v", options)
+	}
+}
+
+func TestParseAssessmentClassStats(t *testing.T) {
+	page := `<table class="table">
+		<tr><th>Name</th><th>Obtained Marks</th><th>Total Marks</th><th>Class Average</th><th>Highest Marks</th><th>Assigned Date</th></tr>
+		<tr><td>Quiz 1</td><td>8</td><td>10</td><td>6.5</td><td>9.5</td><td>05-Sep-2025</td></tr>
+		<tr><td>Quiz 2</td><td>Absent</td><td>10</td><td></td><td></td><td>12-Sep-2025</td></tr>
+	</table>`
+	assessments, found, err := parseAssessmentsHTML(strings.NewReader(page))
+	if err != nil || !found || len(assessments) != 2 {
+		t.Fatalf("parsed %+v, %v, %v", assessments, found, err)
+	}
+	want := Assessment{name: "Quiz 1", obtainedMarks: 8, totalMarks: 10, assignedDate: "05-Sep-2025", classAverage: 6.5, classHighest: 9.5}
+	if assessments[0] != want {
+		t.Errorf("parsed %+v, want %+v", assessments[0], want)
+	}
+	if a := assessments[1]; a.obtainedMarks != 0 || a.classAverage != 0 || a.classHighest != 0 {
+		t.Errorf("parsed %+v without class marks", a)
+	}
+
+	if got := classBar(assessments[0], 10); got != "━━━━━━┃━─◆" {
+		t.Errorf("classBar = %q", got)
+	}
+	if got := classComparison(assessments[0]); got != "+1.5 vs avg • top 9.5" {
+		t.Errorf("classComparison = %q", got)
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// renderPartialAttendance shows the lectures of an attendance report that
+// arrived cut short, while the fetch keeps retrying for the whole report.
+// The latest lectures are kept when they don't all fit.
+func (m model) renderPartialAttendance() string {
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(ACCENT).
+		MarginBottom(1)
+
+	bannerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(WARN).
+		MarginBottom(1)
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(WHITE).
+		Background(BLUE).
+		Padding(0, 1)
+
+	presentStyle := lipgloss.NewStyle().
+		Foreground(SUCCESS)
+
+	absentStyle := lipgloss.NewStyle().
+		Foreground(DANGER)
+
+	neutralStyle := lipgloss.NewStyle().
+		Foreground(WHITE)
+
+	quitStyle := lipgloss.NewStyle().
+		Foreground(GREY).
+		MarginTop(1)
+
+	p := m.loadingProgress
+	var code string
+	if m.selectedCourse < len(m.courses) {
+		code = m.courses[m.selectedCourse].Code
+	}
+
+	banner := m.spinner.View() + " " + T("progress.partial", p.Attempt, p.MaxAttempts, len(p.Partial.Records))
+	if p.RetryAt.After(time.Now()) {
+		banner += " • " + T("progress.busy", p.RetryAt.Format("15:04:05"))
+	}
+	if !m.loadingSince.IsZero() {
+		banner += " • " + formatElapsed(time.Since(m.loadingSince))
+	}
+
+	headers := []string{headerStyle.Render(T("report.col_number")) + strings.Repeat(" ", 3), headerStyle.Render(T("report.col_date")) + strings.Repeat(" ", 3), headerStyle.Render(T("report.col_status")) + strings.Repeat(" ", 2), headerStyle.Render(T("report.col_faculty"))}
+	widths := []int{3, 12, 8, fitWidth(FACULTY_COLUMN_WIDTH, FACULTY_MIN_WIDTH, ATTENDANCE_FIXED_WIDTH, m.width)}
+	rows := []string{
+		strings.Join(headers, " "),
+		neutralStyle.Render(strings.Repeat("─", widths[0]+widths[1]+widths[2]+widths[3]+3)),
+	}
+
+	records := p.Partial.Records
+	records = records[max(len(records)-reportPageSize(attendancePageSize, m.height), 0):]
+	for _, record := range records {
+		var status string
+		if record.Attendance {
+			status = presentStyle.Render(padText(T("report.present"), widths[2]))
+		} else {
+			status = absentStyle.Render(padText(T("report.absent"), widths[2]))
+		}
+		rows = append(rows, fmt.Sprintf("%s %s %s %s",
+			neutralStyle.Render(fmt.Sprintf("%-*d", widths[0], record.LectureNumber)),
+			neutralStyle.Render(fmt.Sprintf("%-*s", widths[1], record.LectureDate)),
+			status,
+			neutralStyle.Render(fitText(record.Faculty, widths[3])),
+		))
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Center,
+		titleStyle.Render(T("report.title", T("report.attendance"), code)),
+		bannerStyle.Render(banner),
+		strings.Join(rows, "\n"),
+		quitStyle.Render(m.loadingState.BottomText),
+	)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, content)
+}
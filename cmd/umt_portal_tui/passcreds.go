@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Credential backends, chosen with credentials.backend in the config.
+const (
+	// CREDS_BACKEND_FILE is the file "Remember me" saves to.
+	CREDS_BACKEND_FILE = "file"
+	// CREDS_BACKEND_PASS reads an entry of the pass (or gopass) password
+	// store, which the user keeps; nothing is ever written to it.
+	CREDS_BACKEND_PASS = "pass"
+
+	DEFAULT_PASS_ENTRY   = "umt/portal"
+	DEFAULT_PASS_COMMAND = "pass"
+)
+
+// passIDKeys are the lines of a pass entry that can hold the student ID,
+// after the password on its first line, e.g. "login: F2023000000".
+var passIDKeys = []string{"student_id", "login", "username", "user"}
+
+type CredentialsConfig struct {
+	Backend string `json:"backend"`
+	// PassEntry is the entry read with "<PassCommand> show <PassEntry>".
+	PassEntry   string `json:"pass_entry"`
+	PassCommand string `json:"pass_command"`
+}
+
+func (c CredentialsConfig) validate() error {
+	switch c.Backend {
+	case "", CREDS_BACKEND_FILE, CREDS_BACKEND_PASS:
+		return nil
+	}
+	return fmt.Errorf("credentials backend %q is not %q or %q", c.Backend, CREDS_BACKEND_FILE, CREDS_BACKEND_PASS)
+}
+
+func (c CredentialsConfig) usesPass() bool {
+	return c.Backend == CREDS_BACKEND_PASS
+}
+
+func (c CredentialsConfig) passEntry() string {
+	if c.PassEntry == "" {
+		return DEFAULT_PASS_ENTRY
+	}
+	return c.PassEntry
+}
+
+// passShow runs "<command> show <entry>"; tests replace it. gpg may ask for
+// the passphrase on the terminal.
+var passShow = func(command, entry string) ([]byte, error) {
+	cmd := exec.Command(command, "show", entry)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	var exit *exec.ExitError
+	if errors.As(err, &exit) && len(bytes.TrimSpace(exit.Stderr)) > 0 {
+		return out, fmt.Errorf("%w: %s", err, bytes.TrimSpace(exit.Stderr))
+	}
+	return out, err
+}
+
+func loadPassCreds(c CredentialsConfig) (Credentials, error) {
+	command := c.PassCommand
+	if command == "" {
+		command = DEFAULT_PASS_COMMAND
+	}
+	out, err := passShow(command, c.passEntry())
+	if err != nil {
+		return Credentials{}, fmt.Errorf("failed to read pass entry %s: %w", c.passEntry(), err)
+	}
+	creds, err := parsePassEntry(string(out))
+	if err != nil {
+		return Credentials{}, fmt.Errorf("pass entry %s: %w", c.passEntry(), err)
+	}
+	return creds, nil
+}
+
+// parsePassEntry reads pass's convention: the password on the first line
+// and "key: value" lines after it, one of them the student ID.
+func parsePassEntry(entry string) (Credentials, error) {
+	lines := strings.Split(strings.ReplaceAll(entry, "\r\n", "\n"), "\n")
+	creds := Credentials{Password: lines[0]}
+	for _, line := range lines[1:] {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		for _, idKey := range passIDKeys {
+			if strings.EqualFold(strings.TrimSpace(key), idKey) && creds.StudentID == "" {
+				creds.StudentID = strings.TrimSpace(value)
+			}
+		}
+	}
+	switch {
+	case creds.Password == "":
+		return creds, errors.New("the first line, the password, is empty")
+	case creds.StudentID == "":
+		return creds, fmt.Errorf("no %s line with the student ID", strings.Join(passIDKeys, ", "))
+	}
+	return creds, nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPassCredentials(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_STATE_HOME", filepath.Join(dir, "state"))
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(dir, "cache"))
+	t.Setenv("LocalAppData", filepath.Join(dir, "cache"))
+
+	var ran []string
+	entry := "hunter2\nurl: https://online.umt.edu.pk\nLogin: F2023000000\n"
+	prevShow, prevConfig := passShow, appConfig
+	t.Cleanup(func() { passShow, appConfig = prevShow, prevConfig })
+	passShow = func(command, name string) ([]byte, error) {
+		ran = append(ran, command+" show "+name)
+		return []byte(entry), nil
+	}
+	appConfig = Config{Credentials: CredentialsConfig{Backend: CREDS_BACKEND_PASS, PassCommand: "gopass"}}
+
+	creds, err := LoadCreds()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if creds != (Credentials{StudentID: "F2023000000", Password: "hunter2"}) {
+		t.Errorf("read %+v", creds)
+	}
+	if len(ran) != 1 || ran[0] != "gopass show "+DEFAULT_PASS_ENTRY {
+		t.Errorf("ran %v", ran)
+	}
+
+	// The store is the user's; "Remember me" leaves it and the disk alone.
+	if err := SaveCreds(Credentials{StudentID: "F2023000001", Password: "other"}); err != nil {
+		t.Fatal(err)
+	}
+	state, _ := stateDir()
+	if _, err := os.Stat(filepath.Join(state, "creds.gob")); !os.IsNotExist(err) {
+		t.Errorf("credentials written to disk: %v", err)
+	}
+
+	entry = "hunter2\n"
+	if _, err := LoadCreds(); err == nil || !strings.Contains(err.Error(), "student ID") {
+		t.Errorf("entry without an ID: %v", err)
+	}
+	if err := (CredentialsConfig{Backend: "keychain"}).validate(); err == nil {
+		t.Error("unknown backend accepted")
+	}
+}
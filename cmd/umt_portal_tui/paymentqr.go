@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"rsc.io/qr"
+)
+
+// QR_QUIET_ZONE is the light border scanners need around a QR code, in
+// modules.
+const QR_QUIET_ZONE = 2
+
+// PaymentReference is what a banking app asks for to pay the challan: the
+// 1Link or consumer number when the portal lists one, otherwise the challan
+// number.
+func (c FeeChallan) PaymentReference() string {
+	if c.Reference != "" {
+		return c.Reference
+	}
+	return c.Number
+}
+
+// renderQR draws text as a QR code of half blocks, two modules to a line.
+// Light modules are drawn and dark ones left blank, so the code reads right
+// on the usual dark terminal background.
+func renderQR(text string) (string, error) {
+	code, err := qr.Encode(text, qr.M)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode QR code: %w", err)
+	}
+	light := func(x, y int) bool {
+		return !code.Black(x, y)
+	}
+	var b strings.Builder
+	for y := -QR_QUIET_ZONE; y < code.Size+QR_QUIET_ZONE; y += 2 {
+		for x := -QR_QUIET_ZONE; x < code.Size+QR_QUIET_ZONE; x++ {
+			top, bottom := light(x, y), light(x, y+1) && y+1 < code.Size+QR_QUIET_ZONE
+			switch {
+			case top && bottom:
+				b.WriteString("█")
+			case top:
+				b.WriteString("▀")
+			case bottom:
+				b.WriteString("▄")
+			default:
+				b.WriteString(" ")
+			}
+		}
+		if y+2 < code.Size+QR_QUIET_ZONE {
+			b.WriteString("\n")
+		}
+	}
+	return b.String(), nil
+}
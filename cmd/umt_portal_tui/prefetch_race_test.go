@@ -0,0 +1,82 @@
+package main
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TestPrefetchRace runs both post-login prefetches at once, as the login
+// handler does; run it with -race. Whichever finishes first, the profile
+// ends up with the transcript's CGPA and earned credit hours.
+func TestPrefetchRace(t *testing.T) {
+	useMockPortal(t, newMockPortal(t, "F2023000000", "hunter2"))
+	s := NewSession()
+	if code, text := s.Login(Credentials{StudentID: "F2023000000", Password: "hunter2"}, false); code != ErrNone {
+		t.Fatalf("login failed: %v %s", code, text)
+	}
+	// The dashboard's figures lag the transcript's after results come out.
+	s.Student.CgpaEarned, s.Student.CompletedCreditHours = "3.10", "20"
+
+	var wg sync.WaitGroup
+	msgs := make([]any, 2)
+	for i, cmd := range []func() any{
+		func() any { return prefetchCoursesCmd(s)() },
+		func() any { return prefetchTranscriptCmd(s)() },
+	} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			msgs[i] = cmd()
+		}()
+	}
+	wg.Wait()
+
+	if msg, ok := msgs[0].(CoursesLoadedMsg); !ok || msg.Error != nil || len(msg.Courses) == 0 {
+		t.Fatalf("courses prefetch = %#v", msgs[0])
+	}
+	if msg, ok := msgs[1].(TranscriptPrefetchedMsg); !ok || msg.Error != nil {
+		t.Fatalf("transcript prefetch = %#v", msgs[1])
+	}
+	st := s.Student
+	if st.Transcript.TotalCGPA == "" || st.CgpaEarned != st.Transcript.TotalCGPA {
+		t.Errorf("CGPA = %q, transcript says %q", st.CgpaEarned, st.Transcript.TotalCGPA)
+	}
+	if st.Transcript.CreditHoursEarned == "" || st.CompletedCreditHours != st.Transcript.CreditHoursEarned {
+		t.Errorf("credit hours earned = %q, transcript says %q", st.CompletedCreditHours, st.Transcript.CreditHoursEarned)
+	}
+}
+
+// TestRenderDuringPrefetch draws the courses view while the prefetches fill
+// in the session, as the program does between their messages; run it with
+// -race.
+func TestRenderDuringPrefetch(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	portal.latency = time.Millisecond
+	useMockPortal(t, portal)
+	s := NewSession()
+	if code, text := s.Login(Credentials{StudentID: "F2023000000", Password: "hunter2"}, false); code != ErrNone {
+		t.Fatalf("login failed: %v %s", code, text)
+	}
+
+	m := model{width: 120, height: 40, session: s, currentView: CoursesView}
+	done := make(chan tea.Msg, 2)
+	go func() { done <- prefetchCoursesCmd(s)() }()
+	go func() { done <- prefetchTranscriptCmd(s)() }()
+	for pending := 2; pending > 0; {
+		select {
+		case msg := <-done:
+			next, _ := m.Update(msg)
+			m = next.(model)
+			pending--
+		default:
+			m.View()
+		}
+	}
+	if !strings.Contains(m.View(), s.GetStudent().CgpaEarned) {
+		t.Errorf("courses view does not show the CGPA %q", s.GetStudent().CgpaEarned)
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// Stages of the multi-request fetches, as i18n keys.
+const (
+	STAGE_SELECT_COURSE   = "progress.select_course"
+	STAGE_OPEN_REPORT     = "progress.open_report"
+	STAGE_RENDER_REPORT   = "progress.render_report"
+	STAGE_READ_ATTENDANCE = "progress.read_attendance"
+	ATTENDANCE_STAGES     = 4
+
+	STAGE_OPEN_TRANSCRIPT = "progress.open_transcript"
+	STAGE_LOAD_TRANSCRIPT = "progress.load_transcript"
+	STAGE_READ_TRANSCRIPT = "progress.read_transcript"
+	TRANSCRIPT_STAGES     = 3
+)
+
+const (
+	TASK_COURSES            = "courses"
+	TASK_TRANSCRIPT         = "transcript"
+	TASK_ATTENDANCE_PREFIX  = "attendance:"
+	TASK_ASSESSMENTS_PREFIX = "assessments:"
+)
+
+const PROGRESS_CHANNEL_BUFFER = 16
+
+// FetchProgress reports where a long fetch is: stage Step of Steps, within
+// attempt Attempt of MaxAttempts. Task identifies the fetch, so the loading
+// screen only shows progress of what it is waiting for. RetryAt is set while
+// the fetch waits out a busy portal, and Partial holds the lectures of an
+// attendance report that arrived cut short.
+type FetchProgress struct {
+	Task        string
+	Stage       string
+	Step        int
+	Steps       int
+	Attempt     int
+	MaxAttempts int
+	RetryAt     time.Time
+	Partial     *AttendanceReport
+}
+
+type ProgressMsg FetchProgress
+
+func attendanceTask(courseID string) string {
+	return TASK_ATTENDANCE_PREFIX + courseID
+}
+
+func assessmentsTask(courseID string) string {
+	return TASK_ASSESSMENTS_PREFIX + courseID
+}
+
+// reportProgress passes p to the session's progress callback, if any.
+func (s *Session) reportProgress(p FetchProgress) {
+	if s.onProgress != nil {
+		s.onProgress(p)
+	}
+}
+
+// progressSink returns a callback that forwards progress to ch without ever
+// blocking the fetch; when the UI falls behind, updates are dropped.
+func progressSink(ch chan FetchProgress) func(FetchProgress) {
+	return func(p FetchProgress) {
+		select {
+		case ch <- p:
+		default:
+		}
+	}
+}
+
+func formatElapsed(d time.Duration) string {
+	d = d.Truncate(time.Second)
+	if d < time.Minute {
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	}
+	return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// The recheck request used when the config sets no format of its own.
+const (
+	DEFAULT_RECHECK_SUBJECT = "Recheck request: {{.Course}} {{.Assessment}}"
+	DEFAULT_RECHECK_BODY    = `Dear {{.Faculty}},
+
+I would like to request a recheck of my {{.Assessment}} in {{.Course}} {{.Title}}{{if .Section}}, section {{.Section}}{{end}}{{if .Date}}, held on {{.Date}}{{end}}. I was awarded {{.Obtained}} out of {{.Total}} marks.
+
+Could you please review it?
+
+Regards,
+{{.Student}}
+{{.ID}}
+`
+)
+
+// RecheckConfig is the department's format for assessment recheck
+// requests. Subject and Body are Go templates over recheckRequest.
+type RecheckConfig struct {
+	// To is where requests go, such as the department office; the course
+	// faculty when empty.
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+// recheckRequest is what the recheck templates can refer to.
+type recheckRequest struct {
+	Course     string
+	Title      string
+	Section    string
+	Faculty    string
+	Assessment string
+	Obtained   string
+	Total      string
+	Date       string
+	Student    string
+	ID         string
+	Program    string
+}
+
+func (c RecheckConfig) templates() (subject, body *template.Template, err error) {
+	text := func(s, fallback string) string {
+		if strings.TrimSpace(s) == "" {
+			return fallback
+		}
+		return s
+	}
+	if subject, err = template.New("subject").Parse(text(c.Subject, DEFAULT_RECHECK_SUBJECT)); err != nil {
+		return nil, nil, fmt.Errorf("recheck subject: %w", err)
+	}
+	if body, err = template.New("body").Parse(text(c.Body, DEFAULT_RECHECK_BODY)); err != nil {
+		return nil, nil, fmt.Errorf("recheck body: %w", err)
+	}
+	return subject, body, nil
+}
+
+// validate parses the templates and fills them in once, which catches
+// fields recheckRequest doesn't have.
+func (c RecheckConfig) validate() error {
+	_, err := c.draft(recheckRequest{})
+	return err
+}
+
+func (c RecheckConfig) draft(r recheckRequest) (disputeDraft, error) {
+	subject, body, err := c.templates()
+	if err != nil {
+		return disputeDraft{}, err
+	}
+	var s, b strings.Builder
+	if err := subject.Execute(&s, r); err != nil {
+		return disputeDraft{}, fmt.Errorf("recheck subject: %w", err)
+	}
+	if err := body.Execute(&b, r); err != nil {
+		return disputeDraft{}, fmt.Errorf("recheck body: %w", err)
+	}
+	return disputeDraft{
+		To:      c.To,
+		Subject: strings.Join(strings.Fields(s.String()), " "),
+		Body:    b.String(),
+	}, nil
+}
+
+func newRecheckRequest(st Student, c Course, a Assessment) recheckRequest {
+	return recheckRequest{
+		Course:     c.Code,
+		Title:      c.Title,
+		Section:    c.Section,
+		Faculty:    facultyGreeting(c),
+		Assessment: a.name,
+		Obtained:   fmt.Sprintf("%g", a.obtainedMarks),
+		Total:      fmt.Sprintf("%g", a.totalMarks),
+		Date:       a.assignedDate,
+		Student:    st.Name,
+		ID:         st.ID,
+		Program:    st.Program,
+	}
+}
+
+func (m *model) openRecheckPicker() {
+	m.assessmentStatus = ""
+	if m.selectedCourse >= len(m.courses) || len(m.courses[m.selectedCourse].Assessment) == 0 {
+		return
+	}
+	m.recheckPicker = true
+	m.pickerAssessment = 0
+}
+
+// selectedRecheck drafts the request for the highlighted assessment, to the
+// configured address or else the course faculty.
+func (m model) selectedRecheck() (disputeDraft, error) {
+	course := m.courses[m.selectedCourse]
+	var st Student
+	if m.session != nil {
+		st = m.session.GetStudent()
+	}
+	draft, err := appConfig.Recheck.draft(newRecheckRequest(st, course, course.Assessment[m.pickerAssessment]))
+	if draft.To == "" {
+		draft.To = course.FacultyEmail
+	}
+	return draft, err
+}
+
+func (m model) handleRecheckKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.selectedCourse >= len(m.courses) || m.pickerAssessment >= len(m.courses[m.selectedCourse].Assessment) {
+		m.recheckPicker = false
+		return m, nil
+	}
+	last := len(m.courses[m.selectedCourse].Assessment) - 1
+	switch msg.String() {
+	case "ctrl+c":
+		if !m.rememberMe {
+			deleteCaches()
+		}
+		return m, tea.Quit
+	case "esc", "d", "q":
+		m.recheckPicker = false
+	case "up", "k":
+		if m.pickerAssessment > 0 {
+			m.pickerAssessment--
+		}
+	case "down", "j":
+		if m.pickerAssessment < last {
+			m.pickerAssessment++
+		}
+	case "enter", "c":
+		m.recheckPicker = false
+		draft, err := m.selectedRecheck()
+		if err != nil {
+			m.assessmentStatus = T("error", err)
+			return m, nil
+		}
+		if msg.String() == "c" {
+			return m, copyToClipboard(draft.String(), DisputeDraftMsg{Copied: true})
+		}
+		return m, func() tea.Msg {
+			return DisputeDraftMsg{Text: draft.String(), Error: openURL(draft.mailto())}
+		}
+	}
+	return m, nil
+}
+
+func (m model) renderRecheckPicker() string {
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(ACCENT).
+		MarginBottom(1)
+
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(WHITE).
+		Background(BLUE)
+
+	normalStyle := lipgloss.NewStyle().
+		Foreground(SILVER)
+
+	moreStyle := lipgloss.NewStyle().
+		Foreground(GREY)
+
+	draftStyle := lipgloss.NewStyle().
+		Foreground(SILVER).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(GREY).
+		Padding(0, 1).
+		MarginTop(1)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(GREY).
+		MarginTop(1)
+
+	course := m.courses[m.selectedCourse]
+	nameWidth := 0
+	for _, a := range course.Assessment {
+		nameWidth = max(nameWidth, lipgloss.Width(a.name))
+	}
+	nameWidth = min(nameWidth, 40)
+
+	rows := DISPUTE_MAX_ROWS
+	if height := m.bodyHeight(); height > 0 {
+		rows = min(max(height-16, 3), rows)
+	}
+	start, end := pickerWindow(m.pickerAssessment, len(course.Assessment), rows)
+
+	var lines []string
+	if start > 0 {
+		lines = append(lines, moreStyle.Render(T("picker.more_above", start)))
+	}
+	for i := start; i < end; i++ {
+		a := course.Assessment[i]
+		line := T("recheck.assessment", padText(fitText(a.name, nameWidth), nameWidth), a.obtainedMarks, a.totalMarks, a.assignedDate)
+		if i == m.pickerAssessment {
+			lines = append(lines, selectedStyle.Render("→ "+line))
+		} else {
+			lines = append(lines, normalStyle.Render("  "+line))
+		}
+	}
+	if end < len(course.Assessment) {
+		lines = append(lines, moreStyle.Render(T("picker.more_below", len(course.Assessment)-end)))
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Center,
+		titleStyle.Render(T("recheck.title", course.Code)),
+		lipgloss.JoinVertical(lipgloss.Left, strings.Join(lines, "\n")),
+	)
+	draft, err := m.selectedRecheck()
+	width := min(max(m.width-8, 30), 80)
+	if err != nil {
+		content = lipgloss.JoinVertical(lipgloss.Center, content, draftStyle.Foreground(DANGER).Render(fitText(T("error", err), width)))
+	} else {
+		to := draft.To
+		if to == "" {
+			to = T("dispute.no_email")
+		}
+		preview := fitText(T("dispute.to", to), width) + "\n" + fitText(T("dispute.subject", draft.Subject), width)
+		content = lipgloss.JoinVertical(lipgloss.Center, content, draftStyle.Render(preview))
+	}
+	content = lipgloss.JoinVertical(lipgloss.Center, content, helpStyle.Render(wrapHelp(T("dispute.help"), m.width)))
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, content)
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestRecheckRequest(t *testing.T) {
+	var opened []string
+	prevURL, prevConfig := openURL, appConfig
+	t.Cleanup(func() { openURL, appConfig = prevURL, prevConfig })
+	openURL = func(link string) error {
+		opened = append(opened, link)
+		return nil
+	}
+	appConfig = Config{Recheck: RecheckConfig{
+		To:      "cs.office@umt.edu.pk",
+		Subject: "[{{.Course}}-{{.Section}}] Recheck: {{.Assessment}}",
+		Body:    "Student: {{.Student}} ({{.ID}})\nAssessment: {{.Assessment}} on {{.Date}}\nMarks: {{.Obtained}}/{{.Total}}\n",
+	}}
+
+	s := NewSession()
+	s.Student = Student{Name: "Ali Raza", ID: "F2023000000"}
+	course := Course{ID: "42", Code: "CC2042", Title: "Database Systems", Section: "B", FacultyEmail: "ayesha.khan@umt.edu.pk",
+		Assessment: []Assessment{
+			{name: "Quiz 1", obtainedMarks: 8, totalMarks: 10, assignedDate: "05-Sep-2025"},
+			{name: "Midterm", obtainedMarks: 21.5, totalMarks: 30, assignedDate: "20-Oct-2025"},
+		},
+	}
+	m := model{session: s, courses: []Course{course}, currentView: AssessmentView, width: 120, height: 40}
+	for _, key := range []tea.KeyMsg{
+		{Type: tea.KeyRunes, Runes: []rune("d")},
+		{Type: tea.KeyRunes, Runes: []rune("j")},
+	} {
+		next, _ := m.Update(key)
+		m = next.(model)
+	}
+	if view := m.View(); !strings.Contains(view, "[CC2042-B] Recheck: Midterm") {
+		t.Errorf("draft subject not previewed:\n%s", view)
+	}
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	next, _ = next.(model).Update(cmd())
+	if got := next.(model).assessmentStatus; got != T("dispute.opened") {
+		t.Errorf("status %q", got)
+	}
+
+	if len(opened) != 1 {
+		t.Fatalf("opened %v", opened)
+	}
+	link, err := url.Parse(opened[0])
+	if err != nil || link.Opaque != "cs.office@umt.edu.pk" {
+		t.Fatalf("not sent to the configured address: %s", opened[0])
+	}
+	if got, want := link.Query().Get("body"), "Student: Ali Raza (F2023000000)\nAssessment: Midterm on 20-Oct-2025\nMarks: 21.5/30\n"; got != want {
+		t.Errorf("body %q, want %q", got, want)
+	}
+
+	if err := (RecheckConfig{Body: "{{.Marks}}"}).validate(); err == nil {
+		t.Error("a template field recheckRequest lacks passed validation")
+	}
+}
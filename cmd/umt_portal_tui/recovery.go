@@ -0,0 +1,163 @@
+package main
+
+import (
+	"errors"
+	"net"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// A fetch that fails because the portal can't be reached is retried after
+// RECOVERY_BASE_DELAY, doubling up to RECOVERY_MAX_DELAY, while the current
+// view stays up under a banner. After RECOVERY_MAX_ATTEMPTS the failure is
+// handled as before.
+const (
+	RECOVERY_BASE_DELAY   = 10 * time.Second
+	RECOVERY_MAX_DELAY    = 2 * time.Minute
+	RECOVERY_MAX_ATTEMPTS = 6
+)
+
+// networkResult is implemented by the results of recoverable fetches.
+// networkError returns the failure if it was a network one, nil otherwise.
+type networkResult interface {
+	networkError() error
+}
+
+// NetworkLostMsg replaces the result of a recoverable fetch that failed to
+// reach the portal. Retry runs the fetch again; Msg is the original result.
+type NetworkLostMsg struct {
+	Msg   tea.Msg
+	Retry tea.Cmd
+	Error error
+}
+
+type recoveryTickMsg struct {
+	ID int
+}
+
+// networkRecovery is the state behind the connection-lost banner.
+type networkRecovery struct {
+	attempt int
+	due     time.Time
+	err     error
+	pending []NetworkLostMsg
+}
+
+func isNetworkError(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+func (msg LoginResultMsg) networkError() error {
+	if msg.Code == ErrNetworkIssue {
+		return errors.New(msg.Text)
+	}
+	return nil
+}
+
+func (msg CoursesLoadedMsg) networkError() error        { return onlyNetworkError(msg.Error) }
+func (msg CourseActionMsg) networkError() error         { return onlyNetworkError(msg.Error) }
+func (msg ResultsLoadedMsg) networkError() error        { return onlyNetworkError(msg.Error) }
+func (msg FeesLoadedMsg) networkError() error           { return onlyNetworkError(msg.Error) }
+func (msg OutlineLoadedMsg) networkError() error        { return onlyNetworkError(msg.Error) }
+func (msg TranscriptPrefetchedMsg) networkError() error { return onlyNetworkError(msg.Error) }
+
+func onlyNetworkError(err error) error {
+	if isNetworkError(err) {
+		return err
+	}
+	return nil
+}
+
+// recoverable wraps a fetch so that a network failure comes back as a
+// NetworkLostMsg instead of its usual result.
+func recoverable(fetch tea.Cmd) tea.Cmd {
+	return func() tea.Msg {
+		msg := fetch()
+		if r, ok := msg.(networkResult); ok {
+			if err := r.networkError(); err != nil {
+				return NetworkLostMsg{Msg: msg, Retry: fetch, Error: err}
+			}
+		}
+		return msg
+	}
+}
+
+func recoveryTick(id int) tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg { return recoveryTickMsg{ID: id} })
+}
+
+func recoveryDelay(attempt int) time.Duration {
+	return min(RECOVERY_BASE_DELAY<<attempt, RECOVERY_MAX_DELAY)
+}
+
+// handleNetworkLost queues msg's fetch for a retry and starts the countdown
+// if none is running.
+func (m model) handleNetworkLost(msg NetworkLostMsg) (tea.Model, tea.Cmd) {
+	if m.recovery == nil {
+		m.recovery = &networkRecovery{}
+		m.recoveryID++
+	}
+	if m.recovery.attempt >= RECOVERY_MAX_ATTEMPTS {
+		if len(m.recovery.pending) == 0 && m.recovery.due.IsZero() {
+			m.recovery = nil
+		}
+		return m.Update(msg.Msg)
+	}
+
+	m.recovery.err = msg.Error
+	m.recovery.pending = append(m.recovery.pending, msg)
+	if !m.recovery.due.IsZero() {
+		return m, nil
+	}
+	m.recovery.due = time.Now().Add(recoveryDelay(m.recovery.attempt))
+	return m, recoveryTick(m.recoveryID)
+}
+
+// handleRecoveryTick counts down the banner and retries the queued fetches
+// when the delay is up.
+func (m model) handleRecoveryTick(msg recoveryTickMsg) (tea.Model, tea.Cmd) {
+	if m.recovery == nil || msg.ID != m.recoveryID || m.recovery.due.IsZero() {
+		return m, nil
+	}
+	if time.Now().Before(m.recovery.due) {
+		return m, recoveryTick(m.recoveryID)
+	}
+
+	cmd := m.recovery.retry()
+	m.recovery.due = time.Time{}
+	m.recovery.attempt++
+	return m, cmd
+}
+
+// retry runs the pending fetches again, each still recoverable.
+func (r *networkRecovery) retry() tea.Cmd {
+	var cmds []tea.Cmd
+	for _, p := range r.pending {
+		cmds = append(cmds, recoverable(p.Retry))
+	}
+	r.pending = nil
+	return tea.Batch(cmds...)
+}
+
+// reconnected ends the recovery once any fetch reaches the portal again,
+// retrying whatever is still queued right away.
+func (m *model) reconnected() tea.Cmd {
+	if m.recovery == nil {
+		return nil
+	}
+	cmd := m.recovery.retry()
+	m.recovery = nil
+	return cmd
+}
+
+func (m model) recoveryBanner() string {
+	if m.recovery == nil {
+		return ""
+	}
+	if m.recovery.due.IsZero() {
+		return T("recovery.retrying")
+	}
+	return T("recovery.lost", formatElapsed(time.Until(m.recovery.due).Round(time.Second)))
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+const REDACTED = "[redacted]"
+
+// MIN_SECRET_LENGTH keeps very short secrets out of the redaction, since
+// they would blank out unrelated text.
+const MIN_SECRET_LENGTH = 4
+
+// includePII is the --include-pii flag. Otherwise the request log,
+// diagnostics and debug artifacts are scrubbed of personal data.
+var includePII bool
+
+var (
+	// e.g. F2023065123
+	studentIDPattern = regexp.MustCompile(`\b[A-Za-z]\d{10}\b`)
+	// e.g. 35202-1234567-1, with or without the dashes
+	cnicPattern = regexp.MustCompile(`\b\d{5}-?\d{7}-?\d\b`)
+)
+
+// knownSecrets are the credentials and session cookies seen so far, which
+// scrubPII removes wherever they turn up.
+var knownSecrets struct {
+	mu     sync.Mutex
+	values []string
+}
+
+func registerSecrets(values ...string) {
+	knownSecrets.mu.Lock()
+	defer knownSecrets.mu.Unlock()
+	for _, v := range values {
+		if len(strings.TrimSpace(v)) >= MIN_SECRET_LENGTH && !slices.Contains(knownSecrets.values, v) {
+			knownSecrets.values = append(knownSecrets.values, v)
+		}
+	}
+}
+
+// scrubPII removes personal data from text before it is logged or dumped,
+// unless --include-pii.
+func scrubPII(text string, secrets ...string) string {
+	if includePII {
+		return text
+	}
+	return stripPII(text, secrets...)
+}
+
+// stripPII removes the given secrets and those registered, student IDs,
+// CNICs and credential-like query parameters from text.
+func stripPII(text string, secrets ...string) string {
+	knownSecrets.mu.Lock()
+	secrets = append(secrets, knownSecrets.values...)
+	knownSecrets.mu.Unlock()
+
+	text = redact(text, secrets...)
+	text = studentIDPattern.ReplaceAllString(text, REDACTED)
+	return cnicPattern.ReplaceAllString(text, REDACTED)
+}
+
+// cookieValues lists the cookies a request sent and its response set.
+func cookieValues(req *http.Request, resp *http.Response) []string {
+	var values []string
+	for _, c := range req.Cookies() {
+		values = append(values, c.Value)
+	}
+	if resp != nil {
+		for _, c := range resp.Cookies() {
+			values = append(values, c.Value)
+		}
+	}
+	return values
+}
+
+// redact blanks out secrets and the values of query parameters that look
+// like credentials or tokens.
+func redact(text string, secrets ...string) string {
+	for _, s := range secrets {
+		if strings.TrimSpace(s) != "" {
+			text = strings.ReplaceAll(text, s, REDACTED)
+		}
+	}
+
+	words := strings.Fields(text)
+	for _, w := range words {
+		u, err := url.Parse(w)
+		if err != nil || u.RawQuery == "" {
+			continue
+		}
+		q := u.Query()
+		changed := false
+		for k := range q {
+			key := strings.ToLower(k)
+			if strings.Contains(key, "token") || strings.Contains(key, "pass") || strings.Contains(key, "key") || strings.Contains(key, "session") {
+				q.Set(k, REDACTED)
+				changed = true
+			}
+		}
+		if changed {
+			u.RawQuery = q.Encode()
+			text = strings.ReplaceAll(text, w, u.String())
+		}
+	}
+	return text
+}
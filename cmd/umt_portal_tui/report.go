@@ -0,0 +1,282 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+const DEFAULT_REPORT_FILE = "umt_report.html"
+
+type reportCourse struct {
+	Course
+	BelowThreshold    bool
+	Threshold         float64
+	AttendanceClass   string
+	AssessmentPercent float64
+	AssessmentClass   string
+	Obtained          float32
+	Total             float32
+}
+
+type reportSemester struct {
+	Semester
+	Courses []TranscriptCourse
+}
+
+type reportData struct {
+	Student     Student
+	GeneratedAt time.Time
+	SavedAt     time.Time
+	Courses     []reportCourse
+	Semesters   []reportSemester
+	Transcript  Transcript
+	GPAChart    template.HTML
+}
+
+// scoreClass buckets a percentage the same way the TUI colors it.
+func scoreClass(percentage float64) string {
+	switch {
+	case percentage >= 85:
+		return "good"
+	case percentage >= 70:
+		return "warn"
+	default:
+		return "bad"
+	}
+}
+
+// gpaChartSVG draws SGPA and CGPA per semester on a 0-4 scale.
+func gpaChartSVG(semesters []reportSemester) template.HTML {
+	if len(semesters) == 0 {
+		return ""
+	}
+
+	const width, height, pad = 640.0, 220.0, 32.0
+	step := 0.0
+	if len(semesters) > 1 {
+		step = (width - 2*pad) / float64(len(semesters)-1)
+	}
+	x := func(i int) float64 { return pad + float64(i)*step }
+	y := func(gpa float32) float64 { return height - pad - float64(gpa)/4*(height-2*pad) }
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg viewBox="0 0 %.0f %.0f" role="img" aria-label="SGPA and CGPA by semester">`, width, height)
+	for g := 0; g <= 4; g++ {
+		fmt.Fprintf(&b, `<line class="grid" x1="%.0f" x2="%.0f" y1="%.1f" y2="%.1f"/><text class="axis" x="4" y="%.1f">%d.0</text>`,
+			pad, width-pad, y(float32(g)), y(float32(g)), y(float32(g))+4, g)
+	}
+
+	for _, series := range []struct {
+		class string
+		value func(Semester) float32
+	}{
+		{"sgpa", func(s Semester) float32 { return s.SGPA }},
+		{"cgpa", func(s Semester) float32 { return s.CGPA }},
+	} {
+		var points []string
+		for i, sem := range semesters {
+			points = append(points, fmt.Sprintf("%.1f,%.1f", x(i), y(series.value(sem.Semester))))
+		}
+		fmt.Fprintf(&b, `<polyline class="%s" points="%s"/>`, series.class, strings.Join(points, " "))
+		for i, sem := range semesters {
+			v := series.value(sem.Semester)
+			fmt.Fprintf(&b, `<circle class="%s" cx="%.1f" cy="%.1f" r="3"><title>%s %s %.2f</title></circle>`,
+				series.class, x(i), y(v), template.HTMLEscapeString(sem.Name), strings.ToUpper(series.class), v)
+		}
+	}
+	for i, sem := range semesters {
+		fmt.Fprintf(&b, `<text class="axis" x="%.1f" y="%.0f" text-anchor="middle">%s</text>`, x(i), height-8, template.HTMLEscapeString(sem.Name))
+	}
+	b.WriteString(`</svg>`)
+	return template.HTML(b.String())
+}
+
+func buildReportData(s *Session, savedAt time.Time) reportData {
+	st := s.GetStudent()
+	data := reportData{
+		Student:     st,
+		GeneratedAt: time.Now(),
+		SavedAt:     savedAt,
+		Transcript:  st.Transcript,
+	}
+
+	for _, c := range st.Courses {
+		rc := reportCourse{
+			Course:          c,
+			BelowThreshold:  appConfig.belowAttendanceThreshold(c),
+			Threshold:       appConfig.attendanceThreshold(c.Code),
+			AttendanceClass: scoreClass(c.AttendancePercentage),
+		}
+		if rc.BelowThreshold {
+			rc.AttendanceClass = "bad"
+		}
+		for _, a := range c.Assessment {
+			rc.Obtained += a.obtainedMarks
+			rc.Total += a.totalMarks
+		}
+		if rc.Total > 0 {
+			rc.AssessmentPercent = float64(rc.Obtained / rc.Total * 100)
+		}
+		rc.AssessmentClass = scoreClass(rc.AssessmentPercent)
+		data.Courses = append(data.Courses, rc)
+	}
+
+	for _, sk := range parseAndSortSemesters(st.Transcript.Semester) {
+		data.Semesters = append(data.Semesters, reportSemester{Semester: sk.semester, Courses: st.Transcript.Semester[sk.semester]})
+	}
+	data.GPAChart = gpaChartSVG(data.Semesters)
+
+	return data
+}
+
+var reportFuncs = template.FuncMap{
+	"percent": func(obtained, total float32) float64 {
+		if total == 0 {
+			return 0
+		}
+		return float64(obtained / total * 100)
+	},
+	"class": scoreClass,
+}
+
+var reportTemplate = template.Must(template.New("report").Funcs(reportFuncs).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>UMT Report – {{.Student.Name}}</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 0 auto; max-width: 960px; padding: 24px; color: #222; }
+h1 { color: #0043a8; margin-bottom: 4px; }
+h2 { border-bottom: 2px solid #0043a8; padding-bottom: 4px; margin-top: 40px; }
+.meta { color: #626262; font-size: 0.9em; }
+.cards { display: flex; flex-wrap: wrap; gap: 12px; margin-top: 16px; }
+.card { border: 1px solid #ddd; border-radius: 8px; padding: 12px 16px; min-width: 140px; }
+.card b { display: block; font-size: 1.4em; color: #0043a8; }
+table { border-collapse: collapse; width: 100%; margin: 8px 0 16px; }
+th, td { text-align: left; padding: 6px 8px; border-bottom: 1px solid #eee; }
+th { background: #f4f6fb; }
+.bar { background: #eee; border-radius: 4px; height: 14px; width: 200px; display: inline-block; vertical-align: middle; }
+.bar span { display: block; height: 100%; border-radius: 4px; }
+.good { color: #1f8a3b; } .bar .good { background: #50c878; }
+.warn { color: #a68b00; } .bar .warn { background: #e6c229; }
+.bad { color: #c0392b; } .bar .bad { background: #ff5555; }
+.absent { color: #c0392b; }
+details { margin: 4px 0 12px; }
+svg { width: 100%; max-width: 640px; }
+svg .grid { stroke: #e5e5e5; }
+svg .axis { font-size: 10px; fill: #626262; }
+svg polyline { fill: none; stroke-width: 2; }
+svg .sgpa { stroke: #0043a8; fill: #0043a8; } svg polyline.sgpa { fill: none; }
+svg .cgpa { stroke: #50c878; fill: #50c878; } svg polyline.cgpa { fill: none; }
+.legend span { margin-right: 16px; }
+.superseded { color: #999; text-decoration: line-through; }
+@media print { h2 { break-after: avoid; } table { break-inside: avoid; } }
+</style>
+</head>
+<body>
+<h1>{{.Student.Name}}</h1>
+<div class="meta">{{.Student.ID}} · {{.Student.Program}} · {{.Student.Email}}</div>
+<div class="meta">Generated {{.GeneratedAt.Format "02 Jan 2006 15:04"}}{{if not .SavedAt.IsZero}} from data fetched {{.SavedAt.Format "02 Jan 2006 15:04"}}{{end}}</div>
+
+<div class="cards">
+  <div class="card">CGPA<b>{{.Student.CgpaEarned}}</b></div>
+  <div class="card">Credit hours earned<b>{{.Student.CompletedCreditHours}}{{with .Student.RequiredCreditHours}} / {{.}}{{end}}</b></div>
+  <div class="card">Registered this semester<b>{{.Student.RequestedCreditHours}}</b></div>
+  <div class="card">Current semester<b>{{.Student.CurrentSemester}}</b></div>
+</div>
+
+{{if .Courses}}
+<h2>Courses</h2>
+<table>
+<tr><th>Code</th><th>Title</th><th>CH</th><th>Section</th><th>Faculty</th></tr>
+{{range .Courses}}<tr><td>{{.Code}}</td><td>{{.Title}}</td><td>{{.CreditHours}}</td><td>{{.Section}}</td><td>{{.FacultyName}}{{with .FacultyEmail}} &lt;{{.}}&gt;{{end}}</td></tr>
+{{end}}</table>
+
+<h2>Attendance</h2>
+<table>
+<tr><th>Course</th><th>Lectures</th><th colspan="2">Attendance</th></tr>
+{{range .Courses}}<tr><td>{{.Code}}</td>{{if .Attendance}}<td>{{.TotalLectures}}</td><td><div class="bar"><span class="{{.AttendanceClass}}" style="width: {{printf "%.1f" .AttendancePercentage}}%"></span></div></td><td class="{{.AttendanceClass}}">{{printf "%.1f" .AttendancePercentage}}%{{if .BelowThreshold}} ⚠ below {{printf "%.0f" .Threshold}}%{{end}}</td>{{else}}<td colspan="3" class="meta">not fetched</td>{{end}}</tr>
+{{end}}</table>
+{{range .Courses}}{{if .Attendance}}
+<details><summary>{{.Code}} lecture by lecture</summary>
+<table>
+<tr><th>#</th><th>Date</th><th>Status</th><th>Faculty</th></tr>
+{{range .Attendance}}<tr><td>{{.LectureNumber}}</td><td>{{.LectureDate}}</td>{{if .Attendance}}<td>Present</td>{{else}}<td class="absent">Absent</td>{{end}}<td>{{.Faculty}}</td></tr>
+{{end}}</table>
+</details>
+{{end}}{{end}}
+
+<h2>Assessments</h2>
+{{range .Courses}}
+<h3>{{.Code}} – {{.Title}}</h3>
+{{if .Assessment}}
+<table>
+<tr><th>Assessment</th><th>Obtained</th><th>Total</th><th colspan="2">Score</th><th>Date</th></tr>
+{{range .Assessment}}{{$p := percent .ObtainedMarks .TotalMarks}}<tr><td>{{.Name}}</td><td>{{printf "%.1f" .ObtainedMarks}}</td><td>{{printf "%.1f" .TotalMarks}}</td><td><div class="bar"><span class="{{class $p}}" style="width: {{printf "%.1f" $p}}%"></span></div></td><td class="{{class $p}}">{{printf "%.1f" $p}}%</td><td>{{.AssignedDate}}</td></tr>
+{{end}}<tr><th>Total</th><th>{{printf "%.1f" .Obtained}}</th><th>{{printf "%.1f" .Total}}</th><th colspan="2" class="{{.AssessmentClass}}">{{printf "%.1f" .AssessmentPercent}}%</th><th></th></tr>
+</table>
+{{else}}<p class="meta">No assessments fetched.</p>{{end}}
+{{end}}
+{{end}}
+
+{{if .Semesters}}
+<h2>Transcript</h2>
+<p>CGPA <b>{{.Transcript.TotalCGPA}}</b> · {{.Transcript.CreditHoursEarned}} credit hours earned</p>
+{{.GPAChart}}
+<div class="legend meta"><span style="color:#0043a8">● SGPA</span><span style="color:#50c878">● CGPA</span></div>
+{{range .Semesters}}
+<h3>{{.Name}} <span class="meta">SGPA {{printf "%.2f" .SGPA}} · CGPA {{printf "%.2f" .CGPA}} · {{.CreditHoursEarned}} CH</span></h3>
+<table>
+<tr><th>Code</th><th>Title</th><th>CH</th><th>Grade</th><th>G.P.</th></tr>
+{{range .Courses}}<tr{{if .Superseded}} class="superseded" title="Earlier attempt, excluded from GPA"{{end}}><td>{{.Code}}</td><td>{{.Title}}{{if .Retake}} ↻{{end}}</td><td>{{.CreditHours}}</td><td>{{.Grade}}</td><td>{{printf "%.2f" .GradePoint}}</td></tr>
+{{end}}</table>
+{{end}}
+{{end}}
+</body>
+</html>
+`))
+
+// Assessment fields are unexported; the template reaches them through these.
+func (a Assessment) Name() string           { return a.name }
+func (a Assessment) ObtainedMarks() float32 { return a.obtainedMarks }
+func (a Assessment) TotalMarks() float32    { return a.totalMarks }
+func (a Assessment) AssignedDate() string   { return a.assignedDate }
+
+func writeReport(w io.Writer, s *Session) error {
+	if err := reportTemplate.Execute(w, buildReportData(s, s.cachedAt)); err != nil {
+		return fmt.Errorf("failed to render report: %w", err)
+	}
+	return nil
+}
+
+func runReport(s *Session, args []string) (Output, error) {
+	path := DEFAULT_REPORT_FILE
+	if len(args) > 1 {
+		return Output{}, withExitCode(EXIT_USAGE, fmt.Errorf("expected at most one output file"))
+	}
+	if len(args) == 1 {
+		path = args[0]
+	}
+
+	var html bytes.Buffer
+	if err := writeReport(&html, s); err != nil {
+		return Output{}, err
+	}
+	if err := os.WriteFile(path, html.Bytes(), 0644); err != nil {
+		return Output{}, fmt.Errorf("failed to write report file: %w", err)
+	}
+
+	return Output{
+		Header: []string{"report"},
+		Rows:   [][]string{{path}},
+		Value:  map[string]string{"report": path},
+		Record: true,
+		HTML:   html.String(),
+	}, nil
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReportFromCache(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	useMockPortal(t, portal)
+	withTerminal(t, false, "")
+	t.Setenv("UMT_STUDENT_ID", "F2023000000")
+	t.Setenv("UMT_PASSWORD", "hunter2")
+
+	report, _ := findCommand("report")
+	if err := runCommand(report, nil, strings.NewReader(""), io.Discard); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected missing cache error, got %v", err)
+	}
+
+	for _, args := range [][]string{{"attendance", "cc2042"}, {"assessments", "cc2042"}, {"transcript"}} {
+		c, _ := findCommand(args[0])
+		if err := runCommand(c, args[1:], strings.NewReader(""), io.Discard); err != nil {
+			t.Fatalf("%s: %v", args[0], err)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "report.html")
+	if err := runCommand(report, []string{path}, strings.NewReader(""), io.Discard); err != nil {
+		t.Fatal(err)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(raw)
+	for _, want := range []string{"TEST STUDENT", "CC2042", "87.5%", "<svg", "<polyline class=\"cgpa\""} {
+		if !strings.Contains(html, want) {
+			t.Errorf("report is missing %q", want)
+		}
+	}
+	if strings.Contains(html, "<script") || strings.Contains(html, "http://") || strings.Contains(html, "https://") {
+		t.Error("report should not reference external resources")
+	}
+}
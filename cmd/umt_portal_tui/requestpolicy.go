@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Portal operations, each with its own timeout and retry policy. OP_OTHER
+// covers the rest: fees, results, outlines, exports and keep-alives.
+const (
+	OP_LOGIN       = "login"
+	OP_COURSES     = "courses"
+	OP_ASSESSMENTS = "assessments"
+	OP_ATTENDANCE  = "attendance"
+	OP_TRANSCRIPT  = "transcript"
+	OP_OTHER       = "other"
+)
+
+var REQUEST_OPS = []string{OP_LOGIN, OP_COURSES, OP_ASSESSMENTS, OP_ATTENDANCE, OP_TRANSCRIPT, OP_OTHER}
+
+// RequestPolicy bounds the requests of an operation: TimeoutSeconds is the
+// most a single request may take, and MaxRetries how many times a fetch is
+// tried before giving up. Only assessments, attendance and the transcript
+// are retried; a login is never retried, so as not to trip the lockout.
+type RequestPolicy struct {
+	TimeoutSeconds float64 `json:"timeout_seconds"`
+	MaxRetries     int     `json:"max_retries"`
+}
+
+// DEFAULT_REQUEST_POLICIES gives the ReportViewer reports, which the portal
+// renders on demand, far longer than its plain pages.
+var DEFAULT_REQUEST_POLICIES = map[string]RequestPolicy{
+	OP_LOGIN:       {TimeoutSeconds: 30, MaxRetries: 1},
+	OP_COURSES:     {TimeoutSeconds: 30, MaxRetries: 1},
+	OP_ASSESSMENTS: {TimeoutSeconds: 30, MaxRetries: 10},
+	OP_ATTENDANCE:  {TimeoutSeconds: 120, MaxRetries: 10},
+	OP_TRANSCRIPT:  {TimeoutSeconds: 120, MaxRetries: 10},
+	OP_OTHER:       {TimeoutSeconds: 60, MaxRetries: 1},
+}
+
+func (p RequestPolicy) Timeout() time.Duration {
+	return time.Duration(p.TimeoutSeconds * float64(time.Second))
+}
+
+// requestPolicy returns the policy of an operation, with the fields the
+// config leaves at zero taken from the defaults.
+func (c Config) requestPolicy(op string) RequestPolicy {
+	policy := DEFAULT_REQUEST_POLICIES[op]
+	if override, ok := c.Requests[op]; ok {
+		if override.TimeoutSeconds > 0 {
+			policy.TimeoutSeconds = override.TimeoutSeconds
+		}
+		if override.MaxRetries > 0 {
+			policy.MaxRetries = override.MaxRetries
+		}
+	}
+	return policy
+}
+
+func (c Config) validateRequests() error {
+	for op, policy := range c.Requests {
+		if !slices.Contains(REQUEST_OPS, op) {
+			return fmt.Errorf("requests: unknown operation %q (want one of %s)", op, strings.Join(REQUEST_OPS, ", "))
+		}
+		if policy.TimeoutSeconds < 0 {
+			return fmt.Errorf("requests[%s]: timeout_seconds is negative", op)
+		}
+		if policy.MaxRetries < 0 {
+			return fmt.Errorf("requests[%s]: max_retries is negative", op)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+const RESULTS_URL string = "https://online.umt.edu.pk/Result"
+
+// ProvisionalResult is the current semester's result as posted before it
+// reaches the transcript. Courses without a grade yet have an empty Grade.
+type ProvisionalResult struct {
+	Semester string             `json:"semester"`
+	Courses  []TranscriptCourse `json:"courses"`
+}
+
+var semesterNamePattern = regexp.MustCompile(`(?i)\b(fall|spring|summer)\s+\d{4}\b`)
+
+// parseResultsHTML reads the results table. Columns are found by their
+// headers since the page has carried different column sets.
+func parseResultsHTML(r io.Reader) (ProvisionalResult, error) {
+	var result ProvisionalResult
+
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return result, fmt.Errorf("failed to parse results HTML: %w", err)
+	}
+
+	result.Semester = semesterNamePattern.FindString(doc.Find("body").Text())
+
+	table := doc.Find("table.table").First()
+	if table.Length() == 0 {
+		return result, errReportIncomplete
+	}
+
+	columns := map[string]int{}
+	table.Find("th").Each(func(i int, th *goquery.Selection) {
+		header := strings.ToLower(strings.TrimSpace(th.Text()))
+		switch {
+		case strings.Contains(header, "code"):
+			columns["code"] = i
+		case strings.Contains(header, "title") || header == "course":
+			columns["title"] = i
+		case strings.Contains(header, "cr"):
+			columns["credit_hours"] = i
+		case strings.Contains(header, "point") || header == "gp":
+			columns["grade_point"] = i
+		case strings.Contains(header, "grade"):
+			columns["grade"] = i
+		}
+	})
+	if _, ok := columns["code"]; !ok {
+		return result, fmt.Errorf("results table has no course code column")
+	}
+	if _, ok := columns["grade"]; !ok {
+		return result, fmt.Errorf("results table has no grade column")
+	}
+
+	table.Find("tbody tr").Each(func(_ int, row *goquery.Selection) {
+		cells := row.Find("td").Map(func(_ int, td *goquery.Selection) string {
+			return strings.TrimSpace(td.Text())
+		})
+		cell := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(cells) {
+				return cells[i]
+			}
+			return ""
+		}
+		if cell("code") == "" {
+			return
+		}
+
+		course := TranscriptCourse{
+			Code:  cell("code"),
+			Title: cell("title"),
+			Grade: strings.ToUpper(cell("grade")),
+		}
+		if course.Grade == "-" {
+			course.Grade = ""
+		}
+		course.CreditHours, _ = strconv.Atoi(cell("credit_hours"))
+		if gp, err := strconv.ParseFloat(cell("grade_point"), 32); err == nil {
+			course.GradePoint = float32(gp)
+		} else {
+			course.GradePoint = gradePoints[course.Grade]
+		}
+		result.Courses = append(result.Courses, course)
+	})
+
+	return result, nil
+}
+
+// Posted reports whether any grade has been posted yet.
+func (r ProvisionalResult) Posted() bool {
+	for _, c := range r.Courses {
+		if c.Grade != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// SGPA is computed from the posted grades only, with the same rules as the
+// transcript: pass, withdrawn and similar grades don't count.
+func (r ProvisionalResult) SGPA() float32 {
+	var creditHours int
+	var points float64
+	for _, c := range r.Courses {
+		if c.Grade == "" || !countsTowardGPA(c) {
+			continue
+		}
+		creditHours += c.CreditHours
+		points += float64(c.GradePoint) * float64(c.CreditHours)
+	}
+	if creditHours == 0 {
+		return 0
+	}
+	return float32(points / float64(creditHours))
+}
+
+func (s *Session) fetchResults() (ProvisionalResult, error) {
+	if len(s.Cookies) == 0 {
+		return ProvisionalResult{}, fmt.Errorf("no cookies found during fetching results")
+	}
+
+	return fetchPage(s, OP_OTHER, RESULTS_URL, "results page", parseResultsHTML)
+}
+
+func runResults(s *Session, args []string) (Output, error) {
+	result, err := s.GetResults()
+	if err != nil {
+		return Output{}, err
+	}
+
+	out := Output{Header: []string{"code", "title", "credit_hours", "grade", "grade_point"}, Value: result}
+	graded := 0
+	for _, c := range result.Courses {
+		gp := ""
+		if c.Grade != "" {
+			graded++
+			gp = fmt.Sprintf("%.2f", c.GradePoint)
+		}
+		out.Rows = append(out.Rows, []string{c.Code, c.Title, strconv.Itoa(c.CreditHours), c.Grade, gp})
+	}
+	if result.Posted() {
+		out.Notes = []string{fmt.Sprintf("%s: provisional SGPA %.2f (%d of %d courses graded)", result.Semester, result.SGPA(), graded, len(result.Courses))}
+	} else {
+		out.Notes = []string{"Results for this semester haven't been posted yet."}
+	}
+	return out, nil
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+)
+
+// SCHEMA_VERSION is the version of the JSON that profile, courses,
+// attendance, assessments and transcript print, and of the data in export
+// archives. It goes up whenever a field is removed, renamed or changes type;
+// fields may be added within a version. schema.json describes it.
+const SCHEMA_VERSION = 1
+
+//go:embed schema.json
+var jsonSchema []byte
+
+// runSchema prints the JSON Schema, for tools that validate the output.
+func runSchema(_ *Session, args []string) (Output, error) {
+	if len(args) > 0 {
+		return Output{}, withExitCode(EXIT_USAGE, fmt.Errorf("unexpected arguments"))
+	}
+	return Output{Text: string(jsonSchema), Value: json.RawMessage(jsonSchema)}, nil
+}
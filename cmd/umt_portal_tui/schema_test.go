@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// checkSchema validates v against the parts of JSON Schema that schema.json
+// uses: $ref into $defs, type, const, required, properties,
+// additionalProperties and items.
+func checkSchema(root, schema map[string]any, v any, path string) error {
+	if ref, ok := schema["$ref"].(string); ok {
+		def, ok := root["$defs"].(map[string]any)[strings.TrimPrefix(ref, "#/$defs/")].(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s: unknown $ref %s", path, ref)
+		}
+		return checkSchema(root, def, v, path)
+	}
+	if want, ok := schema["const"]; ok && v != want {
+		return fmt.Errorf("%s: %v, want %v", path, v, want)
+	}
+
+	var types []string
+	switch t := schema["type"].(type) {
+	case string:
+		types = []string{t}
+	case []any:
+		for _, s := range t {
+			types = append(types, s.(string))
+		}
+	}
+	var got string
+	switch v := v.(type) {
+	case nil:
+		got = "null"
+	case bool:
+		got = "boolean"
+	case string:
+		got = "string"
+	case float64:
+		got = "number"
+		if v == float64(int64(v)) && !slices.Contains(types, "number") {
+			got = "integer"
+		}
+	case []any:
+		got = "array"
+	case map[string]any:
+		got = "object"
+	}
+	if len(types) > 0 && !slices.Contains(types, got) {
+		return fmt.Errorf("%s: %s, want %v", path, got, types)
+	}
+
+	switch v := v.(type) {
+	case map[string]any:
+		properties, _ := schema["properties"].(map[string]any)
+		for _, key := range schema["required"].([]any) {
+			if _, ok := v[key.(string)]; !ok {
+				return fmt.Errorf("%s: missing %s", path, key)
+			}
+		}
+		for key, value := range v {
+			property, ok := properties[key].(map[string]any)
+			if !ok {
+				if schema["additionalProperties"] == false {
+					return fmt.Errorf("%s: %s is not in the schema", path, key)
+				}
+				continue
+			}
+			if err := checkSchema(root, property, value, path+"."+key); err != nil {
+				return err
+			}
+		}
+	case []any:
+		items, _ := schema["items"].(map[string]any)
+		for i, item := range v {
+			if err := checkSchema(root, items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func TestJSONMatchesSchema(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	useMockPortal(t, portal)
+	withTerminal(t, false, "")
+	t.Setenv("UMT_STUDENT_ID", "F2023000000")
+	t.Setenv("UMT_PASSWORD", "hunter2")
+
+	var root map[string]any
+	if err := json.Unmarshal(jsonSchema, &root); err != nil {
+		t.Fatalf("schema.json is not valid JSON: %v", err)
+	}
+	validate := func(def string, raw []byte) {
+		t.Helper()
+		var v any
+		if err := json.Unmarshal(raw, &v); err != nil {
+			t.Fatalf("%s: invalid JSON: %v\n%s", def, err, raw)
+		}
+		if err := checkSchema(root, map[string]any{"$ref": "#/$defs/" + def}, v, def); err != nil {
+			t.Errorf("%s does not match the schema: %v", def, err)
+		}
+	}
+
+	for _, run := range []struct {
+		command string
+		args    []string
+	}{
+		{"profile", nil},
+		{"courses", nil},
+		{"attendance", []string{"cc2042"}},
+		{"assessments", []string{"cc2042"}},
+		{"transcript", nil},
+	} {
+		c, _ := findCommand(run.command)
+		var out bytes.Buffer
+		if err := runCommand(c, append([]string{"--format", "json"}, run.args...), strings.NewReader(""), &out); err != nil {
+			t.Fatalf("%s: %v\n%s", run.command, err, out.String())
+		}
+		validate(run.command, out.Bytes())
+	}
+
+	// The commands above have filled the cache that export archives hold.
+	path, _ := dataCachePath()
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	validate("data", raw)
+}
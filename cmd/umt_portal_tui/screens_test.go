@@ -0,0 +1,177 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/exp/teatest"
+)
+
+// screenStep sends keys to the running program, then waits until the output
+// shows wait. Keys are typed out, except for the names in screenKeys.
+type screenStep struct {
+	keys string
+	wait string
+}
+
+var screenKeys = map[string]tea.KeyType{
+	"enter": tea.KeyEnter,
+	"tab":   tea.KeyTab,
+	"esc":   tea.KeyEsc,
+}
+
+// TestScreens runs the program against the mock portal, drives it to each
+// screen through the keyboard and compares what it shows with
+// testdata/screens/<name>.golden. Run `go test -update` to regenerate them.
+func TestScreens(t *testing.T) {
+	prevProfile, prevPlain := lipgloss.ColorProfile(), plainOutput
+	t.Cleanup(func() {
+		lipgloss.SetColorProfile(prevProfile)
+		plainOutput = prevPlain
+	})
+	enablePlainOutput()
+
+	goldenDir, err := filepath.Abs(filepath.Join("testdata", "screens"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	login := []screenStep{
+		{keys: "F2023000000"}, {keys: "tab"}, {keys: "hunter2"}, {keys: "tab"}, {keys: "tab"},
+		{keys: "enter", wait: T("result.courses_ready", 3)},
+	}
+	submit := login[:len(login)-1]
+	courses := then(login, screenStep{keys: "enter", wait: T("courses.welcome")})
+	details := then(courses, screenStep{keys: "enter", wait: T("detail.course_title")})
+
+	tests := []struct {
+		name    string
+		steps   []screenStep
+		latency time.Duration
+		captcha string
+	}{
+		{name: "login", steps: []screenStep{{wait: T("login.button")}}},
+		{name: "loading", steps: then(submit, screenStep{keys: "enter", wait: T("loading.login")}), latency: 300 * time.Millisecond},
+		{name: "captcha", steps: then(submit, screenStep{keys: "enter", wait: T("captcha.title")}), captcha: "x7k2p"},
+		{name: "result", steps: login},
+		{name: "courses", steps: courses},
+		{name: "details", steps: details},
+		{name: "attendance", steps: then(details, screenStep{keys: "a", wait: T("report.attendance")})},
+		{name: "assessments", steps: then(details, screenStep{keys: "s", wait: T("report.assessment")})},
+		{name: "outline", steps: then(details, screenStep{keys: "o", wait: "Course Objectives"})},
+		{name: "transcript", steps: then(courses, screenStep{keys: "t", wait: T("transcript.title", "")})},
+		{name: "grade_scale", steps: then(courses, screenStep{keys: "t", wait: T("transcript.title", "")}, screenStep{keys: "G", wait: T("grade_scale.title")})},
+		{name: "results", steps: then(courses, screenStep{keys: "g", wait: T("results.title", "")})},
+		{name: "fees", steps: then(courses, screenStep{keys: "f", wait: T("fees.title")})},
+		{name: "chat", steps: then(courses, screenStep{keys: "c", wait: T("chat.title")})},
+		{name: "jobs", steps: then(courses, screenStep{keys: "J", wait: T("jobs.none")})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			portal := newMockPortal(t, "F2023000000", "hunter2")
+			portal.latency, portal.captcha = tt.latency, tt.captcha
+			useMockPortal(t, portal)
+
+			probe := &screenProbe{model: NewModel()}
+			tm := teatest.NewTestModel(t, probe, teatest.WithInitialTermSize(120, 40))
+			for _, step := range tt.steps {
+				if key, ok := screenKeys[step.keys]; ok {
+					tm.Send(tea.KeyMsg{Type: key})
+				} else {
+					tm.Type(step.keys)
+				}
+				if step.wait == "" {
+					continue
+				}
+				teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
+					return strings.Contains(string(out), strings.TrimSpace(step.wait))
+				}, teatest.WithDuration(10*time.Second))
+			}
+			shown := probe.current()
+
+			// Fetches still running would outlive the test and its mock
+			// portal, so let them finish before quitting.
+			probe.waitFor(t, func(m model) bool {
+				return m.currentView != LoadingView && (m.session == nil || !m.coursesPending && m.transcriptReady)
+			})
+			tm.Quit()
+			tm.WaitFinished(t, teatest.WithFinalTimeout(10*time.Second))
+
+			// The spinner and the elapsed time move on their own.
+			shown.spinner = NewModel().spinner
+			shown.loadingSince = time.Now()
+			assertGoldenText(t, filepath.Join(goldenDir, tt.name+".golden"), shown.View())
+		})
+	}
+}
+
+// screenProbe runs a model and keeps its latest state where the test can
+// read it while the program is still running.
+type screenProbe struct {
+	mu    sync.Mutex
+	model model
+}
+
+func (p *screenProbe) Init() tea.Cmd {
+	return p.current().Init()
+}
+
+func (p *screenProbe) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	next, cmd := p.current().Update(msg)
+	p.mu.Lock()
+	p.model = next.(model)
+	p.mu.Unlock()
+	return p, cmd
+}
+
+func (p *screenProbe) View() string {
+	return p.current().View()
+}
+
+func (p *screenProbe) current() model {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.model
+}
+
+func (p *screenProbe) waitFor(t *testing.T, cond func(model) bool) {
+	t.Helper()
+	for deadline := time.Now().Add(10 * time.Second); !cond(p.current()); {
+		if time.Now().After(deadline) {
+			t.Fatalf("model did not settle, still on view %v", p.current().currentView)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func then(steps []screenStep, more ...screenStep) []screenStep {
+	return slices.Concat(steps, more)
+}
+
+func assertGoldenText(t *testing.T, path, got string) {
+	t.Helper()
+	if updateGolden() {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading golden file (run with -update to create it): %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("%s mismatch\n--- got ---\n%s\n--- want ---\n%s", filepath.Base(path), got, want)
+	}
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// SEARCH_MAX_HITS bounds the hits listed; a longer query narrows them.
+	SEARCH_MAX_HITS = 100
+	// SEARCH_MAX_ROWS is how many hits show at a time.
+	SEARCH_MAX_ROWS = 15
+)
+
+// searchHit is a match in the loaded data and where it is: a course view of
+// course, or a transcript semester. row is the assessment or transcript row
+// and date the lecture date that matched.
+type searchHit struct {
+	kind     string
+	text     string
+	view     ViewType
+	course   int
+	semester int
+	row      int
+	date     string
+}
+
+// searchHits looks through what has been loaded or cached, courses first
+// and the transcript last, for entries containing every word of query.
+func (m model) searchHits(query string) []searchHit {
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return nil
+	}
+	matches := func(fields ...string) bool {
+		text := strings.ToLower(strings.Join(fields, " "))
+		for _, term := range terms {
+			if !strings.Contains(text, term) {
+				return false
+			}
+		}
+		return true
+	}
+
+	var hits []searchHit
+	for _, i := range m.listedCourses() {
+		c := m.courses[i]
+		if matches(c.Code, c.Title) {
+			hits = append(hits, searchHit{kind: T("search.kind_course"), text: c.Code + " " + c.Title, view: CourseDetailView, course: i})
+		}
+		if c.FacultyName != "" && matches(c.FacultyName, c.FacultyEmail) {
+			hits = append(hits, searchHit{kind: T("search.kind_faculty"), text: fmt.Sprintf("%s · %s", c.FacultyName, c.Code), view: CourseDetailView, course: i})
+		}
+		for j, row := range assessmentRows(c.Assessment, classGrading(appConfig.courseGrading(c.Code), c.Assessment)) {
+			if a := row.assessment; row.group == nil && matches(c.Code, a.name) {
+				hits = append(hits, searchHit{kind: T("search.kind_assessment"), text: fmt.Sprintf("%s · %s %.1f/%.1f", c.Code, a.name, a.obtainedMarks, a.totalMarks), view: AssessmentView, course: i, row: j})
+			}
+		}
+		for _, a := range c.Attendance {
+			status := T("report.absent")
+			if a.Attendance {
+				status = T("report.present")
+			}
+			if matches(c.Code, a.LectureDate, status) {
+				hits = append(hits, searchHit{kind: T("search.kind_lecture"), text: fmt.Sprintf("%s · %s %s", c.Code, a.LectureDate, status), view: AttendanceView, course: i, date: a.LectureDate})
+			}
+		}
+	}
+	if m.transcriptReady && m.session != nil {
+		transcript := m.session.GetStudent().Transcript
+		for i, key := range m.transcriptSemesters {
+			for j, c := range transcript.Semester[key.semester] {
+				if matches(key.semester.Name, c.Code, c.Title, c.Grade) {
+					hits = append(hits, searchHit{kind: T("search.kind_transcript"), text: fmt.Sprintf("%s · %s %s %s", key.semester.Name, c.Code, c.Title, c.Grade), view: TranscriptView, semester: i, row: j})
+				}
+			}
+		}
+	}
+	if len(hits) > SEARCH_MAX_HITS {
+		hits = hits[:SEARCH_MAX_HITS]
+	}
+	return hits
+}
+
+func (m *model) openSearch() {
+	m.searchQuery = ""
+	m.selectedHit = 0
+	m.pushView(SearchView)
+}
+
+// openSearchHit leaves the search for the view the hit is in, with the
+// attendance filtered to its lecture, the assessments turned to its page or
+// the transcript's cursor on its row.
+func (m model) openSearchHit(hit searchHit) (tea.Model, tea.Cmd) {
+	m.popView()
+	if hit.view == TranscriptView {
+		m.pushView(TranscriptView)
+		m.currentSemester = hit.semester
+		if hit.semester < len(m.table) {
+			m.table[hit.semester].SetCursor(hit.row)
+		}
+		return m, nil
+	}
+
+	m.selectedCourse = hit.course
+	if courseTabIndex(m.currentView) == -1 {
+		m.pushView(CourseDetailView)
+	}
+	switch hit.view {
+	case AttendanceView:
+		if from, to, label, err := parseDateFilter(hit.date); err == nil {
+			m.attendanceFilter = attendanceFilter{from: from, to: to, label: label, input: hit.date}
+		}
+		m.currentAttendancePage = 0
+	case AssessmentView:
+		m.currentAttendancePage = hit.row / reportPageSize(assessmentPageSize, m.height)
+	}
+	return m.openCourseTab(hit.view)
+}
+
+func (m model) handleSearchKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		if !m.rememberMe {
+			deleteCaches()
+		}
+		return m, tea.Quit
+	case "esc":
+		m.goBack()
+	case "enter":
+		if hits := m.searchHits(m.searchQuery); m.selectedHit < len(hits) {
+			return m.openSearchHit(hits[m.selectedHit])
+		}
+	case "up":
+		if m.selectedHit > 0 {
+			m.selectedHit--
+		}
+	case "down":
+		if m.selectedHit < len(m.searchHits(m.searchQuery))-1 {
+			m.selectedHit++
+		}
+	case "backspace":
+		if m.searchQuery != "" {
+			_, size := utf8.DecodeLastRuneInString(m.searchQuery)
+			m.searchQuery = m.searchQuery[:len(m.searchQuery)-size]
+			m.selectedHit = 0
+		}
+	default:
+		if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+			m.searchQuery += string(msg.Runes)
+			m.selectedHit = 0
+		}
+	}
+	return m, nil
+}
+
+func (m model) renderSearch() string {
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(ACCENT).
+		MarginBottom(1)
+
+	inputStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(BLUE).
+		Padding(0, 1).
+		MarginBottom(1)
+
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(WHITE).
+		Background(BLUE)
+
+	normalStyle := lipgloss.NewStyle().
+		Foreground(SILVER)
+
+	moreStyle := lipgloss.NewStyle().
+		Foreground(GREY)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(GREY).
+		MarginTop(1)
+
+	width := min(max(m.width-8, 30), 100)
+	hits := m.searchHits(m.searchQuery)
+
+	kindWidth := 0
+	for _, hit := range hits {
+		kindWidth = max(kindWidth, lipgloss.Width(hit.kind))
+	}
+
+	rows := SEARCH_MAX_ROWS
+	if height := m.bodyHeight(); height > 0 {
+		rows = min(max(height-10, 3), rows)
+	}
+	start, end := pickerWindow(m.selectedHit, len(hits), rows)
+
+	var lines []string
+	switch {
+	case strings.TrimSpace(m.searchQuery) == "":
+		lines = append(lines, moreStyle.Render(T("search.hint")))
+	case len(hits) == 0:
+		lines = append(lines, moreStyle.Render(T("search.none")))
+	}
+	if start > 0 {
+		lines = append(lines, moreStyle.Render(T("picker.more_above", start)))
+	}
+	for i := start; i < end; i++ {
+		line := fitText(padText(hits[i].kind, kindWidth)+"  "+hits[i].text, width-2)
+		if i == m.selectedHit {
+			lines = append(lines, selectedStyle.Render("→ "+line))
+		} else {
+			lines = append(lines, normalStyle.Render("  "+line))
+		}
+	}
+	if end < len(hits) {
+		lines = append(lines, moreStyle.Render(T("picker.more_below", len(hits)-end)))
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Center,
+		titleStyle.Render(T("search.title")),
+		inputStyle.Render(fitText(m.searchQuery+"█", width-4)),
+		lipgloss.JoinVertical(lipgloss.Left, strings.Join(lines, "\n")),
+		helpStyle.Render(wrapHelp(T("search.help"), m.width)),
+	)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, content)
+}
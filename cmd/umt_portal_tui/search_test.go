@@ -0,0 +1,62 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSearchJumpsToMatch(t *testing.T) {
+	courses := []Course{
+		{ID: "1", Code: "CC2042", Title: "Database Systems", FacultyName: "Dr. Ayesha Khan"},
+		{ID: "2", Code: "CS2001", Title: "Data Structures", FacultyName: "Mr. Bilal Ahmed",
+			Attendance: []Attendance{
+				{LectureNumber: 1, LectureDate: "02-Sep-2025", Attendance: true},
+				{LectureNumber: 2, LectureDate: "04-Sep-2025", Attendance: false},
+			},
+			Assessment: []Assessment{{name: "Quiz 1", obtainedMarks: 8, totalMarks: 10}},
+		},
+	}
+	m := model{courses: courses, currentView: CoursesView, width: 120, height: 40}
+	press := func(keys ...tea.KeyMsg) {
+		t.Helper()
+		for _, key := range keys {
+			next, _ := m.Update(key)
+			m = next.(model)
+		}
+	}
+	typed := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+
+	press(tea.KeyMsg{Type: tea.KeyCtrlF}, typed("bilal"))
+	if m.currentView != SearchView {
+		t.Fatalf("Ctrl+F opened %v", m.currentView)
+	}
+	hits := m.searchHits(m.searchQuery)
+	if len(hits) != 1 || hits[0].view != CourseDetailView || hits[0].course != 1 {
+		t.Fatalf("faculty search: %+v", hits)
+	}
+	if !strings.Contains(m.View(), "Mr. Bilal Ahmed · CS2001") {
+		t.Errorf("hit not shown:\n%s", m.View())
+	}
+
+	// Every word has to match, so this finds only the absence.
+	for range "bilal" {
+		press(tea.KeyMsg{Type: tea.KeyBackspace})
+	}
+	press(typed("cs2001 absent"), tea.KeyMsg{Type: tea.KeyEnter})
+	if m.currentView != AttendanceView || m.selectedCourse != 1 {
+		t.Fatalf("lecture hit opened %v for course %d", m.currentView, m.selectedCourse)
+	}
+	if records := m.filteredAttendance(); len(records) != 1 || records[0].LectureNumber != 2 {
+		t.Errorf("attendance not filtered to the lecture: %+v", records)
+	}
+	if len(m.viewStack) != 1 || m.viewStack[0] != CoursesView {
+		t.Errorf("search left on the stack: %v", m.viewStack)
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyCtrlF}, typed("quiz"), tea.KeyMsg{Type: tea.KeyEnter})
+	if m.currentView != AssessmentView || m.selectedCourse != 1 {
+		t.Errorf("assessment hit opened %v for course %d", m.currentView, m.selectedCourse)
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// The semester picker lists every semester of the transcript with its SGPA
+// and CGPA, PICKER_MAX_ROWS at a time, and jumps to the one chosen.
+const PICKER_MAX_ROWS = 12
+
+func (m *model) openSemesterPicker() {
+	m.semesterPicker = true
+	m.pickerSemester = m.currentSemester
+}
+
+func (m model) handleSemesterPickerKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	last := len(m.transcriptSemesters) - 1
+	switch msg.String() {
+	case "ctrl+c":
+		if !m.rememberMe {
+			deleteCaches()
+		}
+		return m, tea.Quit
+	case "esc", "s", "q":
+		m.semesterPicker = false
+	case "up", "k":
+		if m.pickerSemester > 0 {
+			m.pickerSemester--
+		}
+	case "down", "j":
+		if m.pickerSemester < last {
+			m.pickerSemester++
+		}
+	case "home", "g":
+		m.pickerSemester = 0
+	case "end", "G":
+		m.pickerSemester = max(last, 0)
+	case "enter":
+		if m.pickerSemester <= last {
+			m.currentSemester = m.pickerSemester
+		}
+		m.semesterPicker = false
+	}
+	return m, nil
+}
+
+// pickerWindow is the range of semesters shown, scrolled to keep the
+// highlighted one in view.
+func pickerWindow(selected, total, rows int) (int, int) {
+	if total <= rows {
+		return 0, total
+	}
+	start := min(max(selected-rows/2, 0), total-rows)
+	return start, start + rows
+}
+
+func (m model) renderSemesterPicker() string {
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(ACCENT).
+		MarginBottom(1)
+
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(WHITE).
+		Background(BLUE)
+
+	normalStyle := lipgloss.NewStyle().
+		Foreground(SILVER)
+
+	moreStyle := lipgloss.NewStyle().
+		Foreground(GREY)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(GREY).
+		MarginTop(1)
+
+	nameWidth := 0
+	for _, key := range m.transcriptSemesters {
+		nameWidth = max(nameWidth, lipgloss.Width(key.semester.Name))
+	}
+
+	rows := PICKER_MAX_ROWS
+	if height := m.bodyHeight(); height > 0 {
+		rows = min(max(height-8, 3), rows)
+	}
+	start, end := pickerWindow(m.pickerSemester, len(m.transcriptSemesters), rows)
+
+	var lines []string
+	if start > 0 {
+		lines = append(lines, moreStyle.Render(T("picker.more_above", start)))
+	}
+	for i := start; i < end; i++ {
+		sem := m.transcriptSemesters[i].semester
+		line := fmt.Sprintf("%s  %s %.2f  %s %.2f", padText(sem.Name, nameWidth), T("transcript.sgpa"), sem.SGPA, T("transcript.cgpa"), sem.CGPA)
+		if i == m.currentSemester {
+			line += " •"
+		}
+		if i == m.pickerSemester {
+			lines = append(lines, selectedStyle.Render("→ "+line))
+		} else {
+			lines = append(lines, normalStyle.Render("  "+line))
+		}
+	}
+	if end < len(m.transcriptSemesters) {
+		lines = append(lines, moreStyle.Render(T("picker.more_below", len(m.transcriptSemesters)-end)))
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Center,
+		titleStyle.Render(T("picker.title")),
+		lipgloss.JoinVertical(lipgloss.Left, strings.Join(lines, "\n")),
+		helpStyle.Render(T("picker.help")),
+	)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, content)
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"html"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// The service "daemon install" sets up, under the name each service
+// manager knows it by.
+const (
+	SYSTEMD_UNIT  = "umt-tui-daemon.service"
+	LAUNCHD_LABEL = "com.github.feelsunbreeze.umt_tui.daemon"
+	WINDOWS_TASK  = "UMT Portal daemon"
+)
+
+// runServiceCommand runs a service manager command; tests replace it.
+var runServiceCommand = func(name string, args ...string) error {
+	if out, err := exec.Command(name, args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("%s %s failed: %w: %s", name, strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// daemonCommandLine is how the service starts the daemon: this executable,
+// with the cache directory and metrics address of the install command.
+func daemonCommandLine() ([]string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the executable: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	line := []string{exe}
+	if cacheDirOverride != "" {
+		dir, err := filepath.Abs(cacheDirOverride)
+		if err != nil {
+			return nil, err
+		}
+		line = append(line, "--cache-dir", dir)
+	}
+	line = append(line, "daemon")
+	if daemonMetricsAddr != "" {
+		line = append(line, "--metrics", daemonMetricsAddr)
+	}
+	return line, nil
+}
+
+// systemdQuote quotes a word of an ExecStart line, escaping specifiers.
+func systemdQuote(s string) string {
+	s = strings.ReplaceAll(s, "%", "%%")
+	if s != "" && !strings.ContainsAny(s, " \t\"'\\;$") {
+		return s
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", "$$").Replace(s) + `"`
+}
+
+func systemdUnit(line []string) string {
+	words := make([]string, len(line))
+	for i, w := range line {
+		words[i] = systemdQuote(w)
+	}
+	return fmt.Sprintf(`[Unit]
+Description=UMT Portal daily digest
+Wants=network-online.target
+After=network-online.target
+
+[Service]
+ExecStart=%s
+Restart=on-failure
+RestartSec=5min
+
+[Install]
+WantedBy=default.target
+`, strings.Join(words, " "))
+}
+
+func launchdPlist(line []string, logPath string) string {
+	var args strings.Builder
+	for _, w := range line {
+		fmt.Fprintf(&args, "\t\t<string>%s</string>\n", html.EscapeString(w))
+	}
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>%s</string>
+	<key>ProgramArguments</key>
+	<array>
+%s	</array>
+	<key>RunAtLoad</key>
+	<true/>
+	<key>KeepAlive</key>
+	<true/>
+	<key>StandardOutPath</key>
+	<string>%s</string>
+	<key>StandardErrorPath</key>
+	<string>%s</string>
+</dict>
+</plist>
+`, LAUNCHD_LABEL, args.String(), html.EscapeString(logPath), html.EscapeString(logPath))
+}
+
+// windowsCommandLine joins line for schtasks /TR, quoting words with
+// spaces.
+func windowsCommandLine(line []string) string {
+	words := make([]string, len(line))
+	for i, w := range line {
+		if w == "" || strings.ContainsAny(w, " \t") {
+			w = `"` + w + `"`
+		}
+		words[i] = w
+	}
+	return strings.Join(words, " ")
+}
+
+// installDaemon writes a user service running the daemon, for systemd,
+// launchd or the Windows task scheduler, and starts it. The service can't
+// see the environment of this shell, so the credentials have to be saved.
+func installDaemon(args []string) (Output, error) {
+	flags := flag.NewFlagSet("daemon install", flag.ContinueOnError)
+	daemonFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return Output{}, errUsage
+	}
+	if flags.NArg() > 0 || daemonOnce {
+		return Output{}, withExitCode(EXIT_USAGE, fmt.Errorf("daemon install takes no arguments other than --metrics"))
+	}
+	saved, err := LoadCreds()
+	if (err != nil && !errors.Is(err, fs.ErrNotExist)) || saved.StudentID == "" || saved.Password == "" {
+		return Output{}, fmt.Errorf("%w: the service only reads saved credentials; save them in the TUI with \"Remember me\" or with --remember", errNoCredentials)
+	}
+	line, err := daemonCommandLine()
+	if err != nil {
+		return Output{}, err
+	}
+
+	var path, remove string
+	switch runtime.GOOS {
+	case "windows":
+		path = WINDOWS_TASK
+		remove = fmt.Sprintf("schtasks /Delete /F /TN %q", WINDOWS_TASK)
+		if err := runServiceCommand("schtasks", "/Create", "/F", "/SC", "ONLOGON", "/TN", WINDOWS_TASK, "/TR", windowsCommandLine(line)); err != nil {
+			return Output{}, err
+		}
+		if err := runServiceCommand("schtasks", "/Run", "/TN", WINDOWS_TASK); err != nil {
+			return Output{}, err
+		}
+	case "darwin":
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return Output{}, fmt.Errorf("failed to get home dir: %w", err)
+		}
+		state, err := stateDir()
+		if err != nil {
+			return Output{}, err
+		}
+		path = filepath.Join(home, "Library", "LaunchAgents", LAUNCHD_LABEL+".plist")
+		remove = fmt.Sprintf("launchctl unload -w %s && rm %s", path, path)
+		if err := writeServiceFile(path, launchdPlist(line, filepath.Join(state, "daemon.log"))); err != nil {
+			return Output{}, err
+		}
+		// Reinstalling replaces a loaded agent; unloading one that isn't
+		// loaded fails harmlessly.
+		runServiceCommand("launchctl", "unload", path)
+		if err := runServiceCommand("launchctl", "load", "-w", path); err != nil {
+			return Output{}, err
+		}
+	default:
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return Output{}, fmt.Errorf("failed to get user config dir: %w", err)
+		}
+		path = filepath.Join(dir, "systemd", "user", SYSTEMD_UNIT)
+		remove = fmt.Sprintf("systemctl --user disable --now %s && rm %s", SYSTEMD_UNIT, path)
+		if err := writeServiceFile(path, systemdUnit(line)); err != nil {
+			return Output{}, err
+		}
+		if err := runServiceCommand("systemctl", "--user", "daemon-reload"); err != nil {
+			return Output{}, err
+		}
+		if err := runServiceCommand("systemctl", "--user", "enable", "--now", SYSTEMD_UNIT); err != nil {
+			return Output{}, err
+		}
+	}
+
+	return Output{
+		Header: []string{"service", "command"},
+		Rows:   [][]string{{path, strings.Join(line, " ")}},
+		Value:  map[string]any{"service": path, "command": line},
+		Notes:  []string{"The daemon now runs in the background; to remove it: " + remove},
+	}, nil
+}
+
+func writeServiceFile(path, content string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create service dir: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write service: %w", err)
+	}
+	return nil
+}
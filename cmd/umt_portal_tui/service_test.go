@@ -0,0 +1,64 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestDaemonInstall(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("installs a systemd unit")
+	}
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(dir, "config"))
+	t.Setenv("XDG_STATE_HOME", filepath.Join(dir, "state"))
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(dir, "cache"))
+
+	var ran []string
+	prevRun, prevCache := runServiceCommand, cacheDirOverride
+	t.Cleanup(func() {
+		runServiceCommand, cacheDirOverride = prevRun, prevCache
+		daemonMetricsAddr, daemonOnce = "", false
+	})
+	runServiceCommand = func(name string, args ...string) error {
+		ran = append(ran, name+" "+strings.Join(args, " "))
+		return nil
+	}
+	cacheDirOverride = filepath.Join(dir, "my cache")
+
+	// Without saved credentials the service could never log in.
+	if _, err := runDaemon(nil, []string{"install"}); !errors.Is(err, errNoCredentials) {
+		t.Fatalf("installed without credentials: %v", err)
+	}
+	if err := SaveCreds(Credentials{StudentID: "F2023000000", Password: "hunter2"}); err != nil {
+		t.Fatal(err)
+	}
+	out, err := runDaemon(nil, []string{"install", "--metrics", "localhost:9464"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unit := filepath.Join(dir, "config", "systemd", "user", SYSTEMD_UNIT)
+	if out.Rows[0][0] != unit {
+		t.Errorf("reported %q", out.Rows[0][0])
+	}
+	raw, err := os.ReadFile(unit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := ` --cache-dir "` + filepath.Join(dir, "my cache") + `" daemon --metrics localhost:9464` + "\n"
+	if !strings.Contains(string(raw), want) {
+		t.Errorf("unit:\n%s\nwant ExecStart ending in %q", raw, want)
+	}
+	if strings.Contains(string(raw), "hunter2") {
+		t.Error("password written to the unit")
+	}
+	if len(ran) != 2 || ran[0] != "systemctl --user daemon-reload" || ran[1] != "systemctl --user enable --now "+SYSTEMD_UNIT {
+		t.Errorf("ran %q", ran)
+	}
+}
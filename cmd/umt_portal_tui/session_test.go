@@ -0,0 +1,439 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSessionAgainstMockPortal(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	portal.incompleteReports = 2
+	useMockPortal(t, portal)
+
+	s := NewSession()
+	code, text := s.Login(Credentials{StudentID: "F2023000000", Password: "hunter2"}, false)
+	if code != ErrNone {
+		t.Fatalf("login failed: %v %s", code, text)
+	}
+	if !s.IsLoggedIn() {
+		t.Fatal("session has no auth cookies after login")
+	}
+	if s.Student.Name != "TEST STUDENT" || s.Student.Program != "BS Computer Science" || s.Student.CurrentSemester != "Fall 2025" {
+		t.Errorf("unexpected profile: %+v", s.Student)
+	}
+
+	courses, err := s.GetCourses()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(courses) != 3 {
+		t.Fatalf("got %d courses, want 3", len(courses))
+	}
+	courseID := courses[0].ID
+
+	if err := s.GetCourseAssessments(courseID); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(s.Student.Courses[0].Assessment); got != 4 {
+		t.Errorf("got %d assessments, want 4", got)
+	}
+
+	if err := s.GetCourseAttendance(true, courseID); err != nil {
+		t.Fatal(err)
+	}
+	course := s.Student.Courses[0]
+	if len(course.Attendance) != 8 || course.TotalLectures != 8 || course.AttendancePercentage != 87.5 {
+		t.Errorf("unexpected attendance: %d records, %d lectures, %.1f%%", len(course.Attendance), course.TotalLectures, course.AttendancePercentage)
+	}
+	if got := portal.Hits("POST /Reports/Attendance.aspx"); got != 3 {
+		t.Errorf("attendance report requested %d times, want 3 (2 retries)", got)
+	}
+
+	if err := s.GetTranscript(true); err != nil {
+		t.Fatal(err)
+	}
+	if s.Student.Transcript.TotalCGPA != "3.31" || len(s.Student.Transcript.Semester) != 3 {
+		t.Errorf("unexpected transcript: %+v", s.Student.Transcript)
+	}
+
+	cached := NewSession()
+	if err := loadTranscriptCache(cached); err != nil {
+		t.Fatalf("transcript cache not written: %v", err)
+	}
+}
+
+func TestLoginInvalidCredentials(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	useMockPortal(t, portal)
+
+	s := NewSession()
+	code, _ := s.Login(Credentials{StudentID: "F2023000000", Password: "wrong"}, false)
+	if code != ErrInvalidCredentials {
+		t.Fatalf("got %v, want ErrInvalidCredentials", code)
+	}
+	if s.IsLoggedIn() {
+		t.Error("session should not be logged in")
+	}
+}
+
+func TestExpiredSessionIsRejected(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	useMockPortal(t, portal)
+
+	s := NewSession()
+	if code, text := s.Login(Credentials{StudentID: "F2023000000", Password: "hunter2"}, false); code != ErrNone {
+		t.Fatalf("login failed: %v %s", code, text)
+	}
+
+	portal.mu.Lock()
+	portal.authed = map[string]bool{}
+	portal.mu.Unlock()
+
+	courses, err := s.GetCourses()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(courses) != 0 {
+		t.Errorf("got %d courses from an expired session, want 0", len(courses))
+	}
+}
+
+func TestLoginLockout(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	useMockPortal(t, portal)
+
+	stale := Credentials{StudentID: "F2023000000", Password: "stale"}
+	if err := SaveCreds(stale); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 1; i <= MAX_FAILED_LOGINS; i++ {
+		code, _ := NewSession().Login(stale, false)
+		want := ErrInvalidCredentials
+		if i == MAX_FAILED_LOGINS {
+			want = ErrLockedOut
+		}
+		if code != want {
+			t.Fatalf("attempt %d: got code %v, want %v", i, code, want)
+		}
+	}
+	if _, err := LoadCreds(); err == nil {
+		t.Error("saved password was not wiped after the lockout")
+	}
+
+	posts := portal.Hits("POST /Account/Login")
+	code, text := NewSession().Login(Credentials{StudentID: "F2023000000", Password: "hunter2"}, false)
+	if code != ErrLockedOut || text == "" {
+		t.Errorf("login during cooldown: got %v %q, want ErrLockedOut with the remaining time", code, text)
+	}
+	if portal.Hits("POST /Account/Login") != posts {
+		t.Error("a login was sent to the portal during the cooldown")
+	}
+
+	if wait := loginCooldown("f2023000000", time.Now().Add(LOGIN_COOLDOWN)); wait != 0 {
+		t.Errorf("cooldown still %v after it expired", wait)
+	}
+}
+
+func TestKeepAlive(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	useMockPortal(t, portal)
+
+	s := NewSession()
+	if code, text := s.Login(Credentials{StudentID: "F2023000000", Password: "hunter2"}, false); code != ErrNone {
+		t.Fatalf("login failed: %v %s", code, text)
+	}
+	if err := s.keepAlive(); err != nil {
+		t.Fatalf("keepalive on a live session: %v", err)
+	}
+
+	portal.mu.Lock()
+	clear(portal.authed)
+	portal.mu.Unlock()
+	if err := s.keepAlive(); !errors.Is(err, errSessionExpired) {
+		t.Fatalf("keepalive on an expired session: got %v, want errSessionExpired", err)
+	}
+}
+
+func TestLoginWithCaptcha(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	portal.captcha = "x7k2p"
+	useMockPortal(t, portal)
+
+	creds := Credentials{StudentID: "F2023000000", Password: "hunter2"}
+	s := NewSession()
+	if code, _ := s.Login(creds, false); code != ErrCaptchaRequired {
+		t.Fatalf("got code %v, want ErrCaptchaRequired", code)
+	}
+	if s.Captcha() == nil {
+		t.Fatal("no captcha image kept")
+	}
+	art := renderCaptcha(s.Captcha(), CAPTCHA_WIDTH, false)
+	if !strings.Contains(art, "@") || !strings.Contains(art, " ") {
+		t.Errorf("captcha not drawn:\n%s", art)
+	}
+
+	if code, _ := s.LoginWithCaptcha(creds, "wrong", false); code != ErrInvalidCredentials {
+		t.Fatalf("wrong code: got %v, want ErrInvalidCredentials", code)
+	}
+	if code, _ := s.Login(creds, false); code != ErrCaptchaRequired {
+		t.Fatalf("retry: got code %v, want ErrCaptchaRequired", code)
+	}
+	if code, text := s.LoginWithCaptcha(creds, "x7k2p", false); code != ErrNone {
+		t.Fatalf("login with the right code failed: %v %s", code, text)
+	}
+}
+
+func TestDebugArtifacts(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	useMockPortal(t, portal)
+
+	dir := filepath.Join(t.TempDir(), "artifacts")
+	if err := enableDebugArtifacts(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewSession()
+	if code, text := s.Login(Credentials{StudentID: "F2023000000", Password: "hunter2"}, false); code != ErrNone {
+		t.Fatalf("login failed: %v %s", code, text)
+	}
+	if err := s.GetTranscript(true); err != nil {
+		t.Fatal(err)
+	}
+
+	saved, _ := filepath.Glob(filepath.Join(dir, "*_GET_Reports_Transcript.aspx.html"))
+	if len(saved) == 0 {
+		t.Error("no artifact saved for the transcript report")
+	}
+	if _, err := os.Stat("transcript_initial.txt"); !errors.Is(err, os.ErrNotExist) {
+		t.Error("transcript page written to the working directory")
+	}
+}
+
+func TestFetchProgress(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	portal.incompleteReports = 1
+	useMockPortal(t, portal)
+
+	s := NewSession()
+	if code, text := s.Login(Credentials{StudentID: "F2023000000", Password: "hunter2"}, false); code != ErrNone {
+		t.Fatalf("login failed: %v %s", code, text)
+	}
+	courses, err := s.GetCourses()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var events []FetchProgress
+	s.onProgress = func(p FetchProgress) { events = append(events, p) }
+	if err := s.GetCourseAttendance(true, courses[0].ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(events) == 0 {
+		t.Fatal("no progress reported")
+	}
+	last := events[len(events)-1]
+	want := FetchProgress{Task: attendanceTask(courses[0].ID), Stage: STAGE_READ_ATTENDANCE, Step: 4, Steps: ATTENDANCE_STAGES, Attempt: 2, MaxAttempts: 10}
+	if last != want {
+		t.Errorf("last progress = %+v, want %+v", last, want)
+	}
+}
+
+func TestPartialAttendance(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	portal.incompleteReports = 1
+	portal.partialReports = true
+	useMockPortal(t, portal)
+
+	s := NewSession()
+	if code, text := s.Login(Credentials{StudentID: "F2023000000", Password: "hunter2"}, false); code != ErrNone {
+		t.Fatalf("login failed: %v %s", code, text)
+	}
+	courses, err := s.GetCourses()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var partial *FetchProgress
+	s.onProgress = func(p FetchProgress) {
+		if p.Partial != nil && partial == nil {
+			partial = &p
+		}
+	}
+	if err := s.GetCourseAttendance(true, courses[0].ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if partial == nil {
+		t.Fatal("no partial report shown while the report was loading")
+	}
+	if partial.Attempt != 1 || partial.MaxAttempts != 10 {
+		t.Errorf("partial report on attempt %d/%d, want 1/10", partial.Attempt, partial.MaxAttempts)
+	}
+	full := s.Student.Courses[0].Attendance
+	if got := len(partial.Partial.Records); got == 0 || got > len(full) {
+		t.Errorf("partial report has %d lectures, want 1 to %d", got, len(full))
+	}
+
+	m := model{courses: s.Student.Courses, currentView: LoadingView, loadingSince: time.Now(), loadingProgress: *partial, width: 120, height: 40}
+	if view := m.renderLoading(); !strings.Contains(view, T("progress.partial", 1, 10, len(partial.Partial.Records))) {
+		t.Errorf("loading screen lacks the partial report banner:\n%s", view)
+	}
+}
+
+func TestConcurrentFetchesCoalesce(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	useMockPortal(t, portal)
+
+	s := NewSession()
+	if code, text := s.Login(Credentials{StudentID: "F2023000000", Password: "hunter2"}, false); code != ErrNone {
+		t.Fatalf("login failed: %v %s", code, text)
+	}
+	portal.latency = 200 * time.Millisecond
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 5)
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := s.GetCourses()
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if hits := portal.Hits("GET /MyCourses"); hits != 1 {
+		t.Errorf("5 concurrent course fetches made %d requests, want 1", hits)
+	}
+	if _, err := s.GetCourses(); err != nil || portal.Hits("GET /MyCourses") != 2 {
+		t.Errorf("a later fetch was not sent: %v", err)
+	}
+}
+
+func TestRequestPolicy(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	portal.latency = 200 * time.Millisecond
+	useMockPortal(t, portal)
+	appConfig.Requests = map[string]RequestPolicy{
+		OP_TRANSCRIPT: {TimeoutSeconds: 0.05, MaxRetries: 2},
+		OP_ATTENDANCE: {MaxRetries: 3},
+	}
+
+	if got := appConfig.requestPolicy(OP_ATTENDANCE); got != (RequestPolicy{TimeoutSeconds: 120, MaxRetries: 3}) {
+		t.Errorf("attendance policy = %+v, want the default timeout with 3 retries", got)
+	}
+	if err := (Config{Requests: map[string]RequestPolicy{"timetable": {}}}).validateRequests(); err == nil {
+		t.Error("unknown operation accepted")
+	}
+
+	s := NewSession()
+	if code, text := s.Login(Credentials{StudentID: "F2023000000", Password: "hunter2"}, false); code != ErrNone {
+		t.Fatalf("login failed: %v %s", code, text)
+	}
+	err := s.GetTranscript(true)
+	if err == nil || !strings.Contains(err.Error(), "after 2 attempts") {
+		t.Fatalf("transcript fetch slower than its timeout: err = %v", err)
+	}
+	if got := portal.Hits("GET /Transcript"); got != 2 {
+		t.Errorf("transcript requested %d times, want 2", got)
+	}
+}
+
+func TestCacheModes(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	useMockPortal(t, portal)
+	t.Cleanup(func() { cacheMode = CACHE_NORMAL })
+
+	s := NewSession()
+	if code, text := s.Login(Credentials{StudentID: "F2023000000", Password: "hunter2"}, false); code != ErrNone {
+		t.Fatalf("login failed: %v %s", code, text)
+	}
+	fetches := func() int { return portal.Hits("GET /Reports/Transcript.aspx") }
+
+	cacheMode = CACHE_OFF
+	if err := s.GetTranscript(false); err != nil {
+		t.Fatal(err)
+	}
+	path, err := transcriptCachePath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("--no-cache wrote the transcript cache: %v", err)
+	}
+
+	cacheMode = CACHE_REFRESH
+	if err := s.GetTranscript(false); err != nil {
+		t.Fatal(err)
+	}
+	if fetches() != 2 {
+		t.Errorf("transcript fetched %d times, want 2", fetches())
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("--refresh did not save the transcript: %v", err)
+	}
+	if _, err := cachedSession(); !errors.Is(err, errCacheSkipped) {
+		t.Errorf("offline session under --refresh: err = %v", err)
+	}
+
+	cacheMode = CACHE_NORMAL
+	if err := s.GetTranscript(false); err != nil {
+		t.Fatal(err)
+	}
+	if fetches() != 2 {
+		t.Errorf("cached transcript fetched again (%d fetches)", fetches())
+	}
+}
+
+func TestAttendanceWarmUp(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	useMockPortal(t, portal)
+
+	s := NewSession()
+	if code, text := s.Login(Credentials{StudentID: "F2023000000", Password: "hunter2"}, false); code != ErrNone {
+		t.Fatalf("login failed: %v %s", code, text)
+	}
+	courses, err := s.GetCourses()
+	if err != nil {
+		t.Fatal(err)
+	}
+	courseID := courses[0].ID
+	opened := func() int { return portal.Hits("GET /Reports/Attendance.aspx") }
+
+	if err := s.WarmAttendance(courseID); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.WarmAttendance(courseID); err != nil || opened() != 1 {
+		t.Fatalf("warming up twice opened the report %d times (err %v)", opened(), err)
+	}
+	if err := s.GetCourseAttendance(true, courseID); err != nil {
+		t.Fatal(err)
+	}
+	if opened() != 1 || portal.Hits("POST /Reports/Attendance.aspx") != 1 {
+		t.Errorf("warmed-up fetch opened the report %d times and posted it %d times, want 1 and 1", opened(), portal.Hits("POST /Reports/Attendance.aspx"))
+	}
+	if len(s.Student.Courses[0].Attendance) != 8 {
+		t.Errorf("got %d attendance records, want 8", len(s.Student.Courses[0].Attendance))
+	}
+
+	// The form is good for one POST only.
+	if err := s.GetCourseAttendance(true, courseID); err != nil {
+		t.Fatal(err)
+	}
+	if opened() != 2 {
+		t.Errorf("report opened %d times after the warm form was used, want 2", opened())
+	}
+}
@@ -0,0 +1,291 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mattn/go-runewidth"
+)
+
+// The SVG snapshot draws every terminal cell this big, in pixels.
+const (
+	SNAPSHOT_CELL_WIDTH  = 8.4
+	SNAPSHOT_CELL_HEIGHT = 18.0
+	SNAPSHOT_FONT_SIZE   = 14.0
+	SNAPSHOT_PADDING     = 12.0
+	SNAPSHOT_BACKGROUND  = "#1a1b26"
+	SNAPSHOT_FOREGROUND  = "#d0d0d0"
+)
+
+// ansiColors are the 16 standard terminal colors, as xterm draws them.
+var ansiColors = [16]string{
+	"#000000", "#cd0000", "#00cd00", "#cdcd00", "#0000ee", "#cd00cd", "#00cdcd", "#e5e5e5",
+	"#7f7f7f", "#ff0000", "#00ff00", "#ffff00", "#5c5cff", "#ff00ff", "#00ffff", "#ffffff",
+}
+
+// SnapshotSavedMsg reports the screen saved with Ctrl+S.
+type SnapshotSavedMsg struct {
+	Text  string
+	SVG   string
+	Error error
+}
+
+// cellStyle is the SGR state text is drawn with.
+type cellStyle struct {
+	fg, bg                                          string
+	bold, faint, italic, underline, strike, reverse bool
+}
+
+// styledRun is text of one style on one line, starting at column col.
+type styledRun struct {
+	style cellStyle
+	text  string
+	col   int
+	width int
+}
+
+// color256 is the color of an xterm 256-color index.
+func color256(n int) string {
+	switch {
+	case n < 16:
+		return ansiColors[n]
+	case n < 232:
+		n -= 16
+		level := func(v int) int {
+			if v == 0 {
+				return 0
+			}
+			return 55 + v*40
+		}
+		return fmt.Sprintf("#%02x%02x%02x", level(n/36), level(n/6%6), level(n%6))
+	default:
+		g := 8 + (n-232)*10
+		return fmt.Sprintf("#%02x%02x%02x", g, g, g)
+	}
+}
+
+// applySGR updates s with the parameters of an SGR ("\x1b[...m") sequence.
+func (s *cellStyle) applySGR(params string) {
+	var codes []int
+	for _, p := range strings.Split(strings.ReplaceAll(params, ":", ";"), ";") {
+		n, _ := strconv.Atoi(p)
+		codes = append(codes, n)
+	}
+	for i := 0; i < len(codes); i++ {
+		switch c := codes[i]; {
+		case c == 0:
+			*s = cellStyle{}
+		case c == 1:
+			s.bold = true
+		case c == 2:
+			s.faint = true
+		case c == 3:
+			s.italic = true
+		case c == 4:
+			s.underline = true
+		case c == 7:
+			s.reverse = true
+		case c == 9:
+			s.strike = true
+		case c == 22:
+			s.bold, s.faint = false, false
+		case c == 23:
+			s.italic = false
+		case c == 24:
+			s.underline = false
+		case c == 27:
+			s.reverse = false
+		case c == 29:
+			s.strike = false
+		case c >= 30 && c <= 37:
+			s.fg = ansiColors[c-30]
+		case c >= 90 && c <= 97:
+			s.fg = ansiColors[c-90+8]
+		case c == 39:
+			s.fg = ""
+		case c >= 40 && c <= 47:
+			s.bg = ansiColors[c-40]
+		case c >= 100 && c <= 107:
+			s.bg = ansiColors[c-100+8]
+		case c == 49:
+			s.bg = ""
+		case c == 38 || c == 48:
+			var color string
+			switch {
+			case i+2 < len(codes) && codes[i+1] == 5:
+				color = color256(codes[i+2] & 0xff)
+				i += 2
+			case i+4 < len(codes) && codes[i+1] == 2:
+				color = fmt.Sprintf("#%02x%02x%02x", codes[i+2]&0xff, codes[i+3]&0xff, codes[i+4]&0xff)
+				i += 4
+			default:
+				continue
+			}
+			if c == 38 {
+				s.fg = color
+			} else {
+				s.bg = color
+			}
+		}
+	}
+}
+
+// parseStyledLine splits one rendered line into runs of equally styled
+// text, dropping escape sequences other than SGR.
+func parseStyledLine(line string) []styledRun {
+	var (
+		runs  []styledRun
+		style cellStyle
+		text  strings.Builder
+		start int
+		col   int
+	)
+	flush := func() {
+		if text.Len() > 0 {
+			runs = append(runs, styledRun{style: style, text: text.String(), col: start, width: col - start})
+			text.Reset()
+		}
+		start = col
+	}
+	for i := 0; i < len(line); {
+		if line[i] != '\x1b' || i+1 >= len(line) {
+			r, size := utf8.DecodeRuneInString(line[i:])
+			text.WriteRune(r)
+			col += runewidth.RuneWidth(r)
+			i += size
+			continue
+		}
+		switch line[i+1] {
+		case '[':
+			end := i + 2
+			for end < len(line) && (line[end] < 0x40 || line[end] > 0x7e) {
+				end++
+			}
+			if end < len(line) && line[end] == 'm' {
+				flush()
+				style.applySGR(line[i+2 : end])
+			}
+			i = end + 1
+		case ']':
+			// OSC, such as a hyperlink, ends with BEL or ESC \.
+			end := i + 2
+			for end < len(line) && line[end] != '\a' && !(line[end] == '\x1b' && end+1 < len(line) && line[end+1] == '\\') {
+				end++
+			}
+			if end < len(line) && line[end] == '\x1b' {
+				end++
+			}
+			i = end + 1
+		default:
+			i += 2
+		}
+	}
+	flush()
+	return runs
+}
+
+// snapshotText is the rendered view as plain text.
+func snapshotText(view string) string {
+	var b strings.Builder
+	for _, line := range strings.Split(view, "\n") {
+		var plain strings.Builder
+		for _, run := range parseStyledLine(line) {
+			plain.WriteString(run.text)
+		}
+		b.WriteString(strings.TrimRight(plain.String(), " "))
+		b.WriteByte('\n')
+	}
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
+
+// snapshotSVG draws the rendered view, colors and all, as an SVG image of
+// a terminal.
+func snapshotSVG(view string) string {
+	lines := strings.Split(strings.TrimRight(view, "\n"), "\n")
+	parsed := make([][]styledRun, len(lines))
+	columns := 0
+	for i, line := range lines {
+		parsed[i] = parseStyledLine(line)
+		if n := len(parsed[i]); n > 0 {
+			columns = max(columns, parsed[i][n-1].col+parsed[i][n-1].width)
+		}
+	}
+	width := float64(columns)*SNAPSHOT_CELL_WIDTH + 2*SNAPSHOT_PADDING
+	height := float64(len(lines))*SNAPSHOT_CELL_HEIGHT + 2*SNAPSHOT_PADDING
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f">`+"\n", width, height, width, height)
+	fmt.Fprintf(&b, `<rect width="100%%" height="100%%" rx="6" fill="%s"/>`+"\n", SNAPSHOT_BACKGROUND)
+	fmt.Fprintf(&b, `<g font-family="'DejaVu Sans Mono', Menlo, Consolas, monospace" font-size="%.0f" xml:space="preserve">`+"\n", SNAPSHOT_FONT_SIZE)
+	for row, runs := range parsed {
+		y := SNAPSHOT_PADDING + float64(row)*SNAPSHOT_CELL_HEIGHT
+		for _, run := range runs {
+			fg, bg := run.style.fg, run.style.bg
+			if fg == "" {
+				fg = SNAPSHOT_FOREGROUND
+			}
+			if run.style.reverse {
+				fg, bg = bg, fg
+				if fg == "" {
+					fg = SNAPSHOT_BACKGROUND
+				}
+			}
+			x := SNAPSHOT_PADDING + float64(run.col)*SNAPSHOT_CELL_WIDTH
+			w := float64(run.width) * SNAPSHOT_CELL_WIDTH
+			if bg != "" {
+				fmt.Fprintf(&b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.0f" fill="%s"/>`+"\n", x, y, w, SNAPSHOT_CELL_HEIGHT, bg)
+			}
+			if strings.TrimSpace(run.text) == "" {
+				continue
+			}
+			attrs := fmt.Sprintf(`x="%.1f" y="%.1f" fill="%s" textLength="%.1f" lengthAdjust="spacingAndGlyphs"`, x, y+SNAPSHOT_CELL_HEIGHT*0.75, fg, w)
+			if run.style.bold {
+				attrs += ` font-weight="bold"`
+			}
+			if run.style.faint {
+				attrs += ` fill-opacity="0.6"`
+			}
+			if run.style.italic {
+				attrs += ` font-style="italic"`
+			}
+			switch {
+			case run.style.underline && run.style.strike:
+				attrs += ` text-decoration="underline line-through"`
+			case run.style.underline:
+				attrs += ` text-decoration="underline"`
+			case run.style.strike:
+				attrs += ` text-decoration="line-through"`
+			}
+			fmt.Fprintf(&b, "<text %s>%s</text>\n", attrs, html.EscapeString(run.text))
+		}
+	}
+	b.WriteString("</g>\n</svg>\n")
+	return b.String()
+}
+
+// saveSnapshotCmd writes the rendered view to the download directory, as
+// text and as an SVG image, named after the view and the time.
+func saveSnapshotCmd(view, name string) tea.Cmd {
+	return func() tea.Msg {
+		dir := downloadDir()
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return SnapshotSavedMsg{Error: fmt.Errorf("failed to create download dir: %w", err)}
+		}
+		base := filepath.Join(dir, fmt.Sprintf("umt_%s_%s", name, time.Now().Format("20060102_150405")))
+		msg := SnapshotSavedMsg{Text: base + ".txt", SVG: base + ".svg"}
+		if err := os.WriteFile(msg.Text, []byte(snapshotText(view)), 0644); err != nil {
+			return SnapshotSavedMsg{Error: fmt.Errorf("failed to save snapshot: %w", err)}
+		}
+		if err := os.WriteFile(msg.SVG, []byte(snapshotSVG(view)), 0644); err != nil {
+			return SnapshotSavedMsg{Error: fmt.Errorf("failed to save snapshot: %w", err)}
+		}
+		return msg
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSnapshotSVG(t *testing.T) {
+	view := "\x1b[1;38;2;255;0;0mCGPA\x1b[0m 3.50 \x1b[48;5;21m<ok>\x1b[m\n\x1b]8;;https://umt.edu.pk\a→ link\x1b]8;;\a"
+	if got, want := snapshotText(view), "CGPA 3.50 <ok>\n→ link\n"; got != want {
+		t.Errorf("text %q, want %q", got, want)
+	}
+	svg := snapshotSVG(view)
+	for _, want := range []string{
+		`fill="#ff0000" textLength="33.6" lengthAdjust="spacingAndGlyphs" font-weight="bold">CGPA</text>`,
+		`<rect x="96.0" y="12.0" width="33.6" height="18" fill="#0000ff"/>`,
+		`>&lt;ok&gt;</text>`,
+		`>→ link</text>`,
+	} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("svg lacks %s:\n%s", want, svg)
+		}
+	}
+}
+
+func TestSnapshotKey(t *testing.T) {
+	useMockPortal(t, newMockPortal(t, "F2023000000", "hunter2"))
+	appConfig.DownloadDir = t.TempDir()
+
+	m := model{session: NewSession(), courses: []Course{{ID: "42", Code: "CC2042", Title: "Database Systems"}}, currentView: CoursesView, width: 100, height: 30}
+	next, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if cmd == nil {
+		t.Fatal("ctrl+s saved nothing")
+	}
+	next, _ = next.(model).Update(cmd())
+	if notice := next.(model).snapshotNotice; !strings.HasPrefix(notice, "Saved this screen") {
+		t.Fatalf("notice %q", notice)
+	}
+	saved, _ := filepath.Glob(filepath.Join(appConfig.DownloadDir, "umt_courses_*.txt"))
+	if len(saved) != 1 {
+		t.Fatalf("saved %v", saved)
+	}
+	raw, _ := os.ReadFile(saved[0])
+	if !strings.Contains(string(raw), "CC2042") || strings.Contains(string(raw), "\x1b") {
+		t.Errorf("text snapshot:\n%s", raw)
+	}
+
+	m.currentView = LoginView
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlS}); cmd != nil {
+		t.Error("ctrl+s snapshotted the login screen")
+	}
+}
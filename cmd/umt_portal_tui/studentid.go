@@ -0,0 +1,31 @@
+package main
+
+import (
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// UMT_FOUNDED is the first year a student ID can carry.
+const UMT_FOUNDED = 1990
+
+// validStudentID is a UMT student ID: the intake, F (fall) or S (spring),
+// its year and a six-digit serial, e.g. F2021123456.
+var validStudentID = regexp.MustCompile(`^[FfSs]\d{10}$`)
+
+// studentIDProblem explains what is wrong with a student ID typed into the
+// login form, or is "" when it can be submitted. It saves a round trip to
+// the portal that would only end in "invalid credentials".
+func studentIDProblem(id string, now time.Time) string {
+	if id == "" {
+		return T("login.id_required")
+	}
+	if !validStudentID.MatchString(id) {
+		return T("login.id_format")
+	}
+	// Next year's intake is admitted, and given IDs, this year.
+	if year, _ := strconv.Atoi(id[1:5]); year < UMT_FOUNDED || year > now.Year()+1 {
+		return T("login.id_year", year)
+	}
+	return ""
+}
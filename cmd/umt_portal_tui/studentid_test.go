@@ -0,0 +1,74 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestStudentIDProblem(t *testing.T) {
+	now := time.Date(2026, time.October, 17, 0, 0, 0, 0, time.UTC)
+	for id, ok := range map[string]bool{
+		"F2023000000": true,
+		"s2021123456": true,
+		"F2027000001": true,
+		"":            false,
+		"F2023":       false,
+		"2023000000":  false,
+		"X2023000000": false,
+		"F20230000OO": false,
+		"F2028000000": false,
+		"S1985123456": false,
+	} {
+		if problem := studentIDProblem(id, now); (problem == "") != ok {
+			t.Errorf("%q: %q", id, problem)
+		}
+	}
+}
+
+func TestLoginFormErrors(t *testing.T) {
+	m := model{currentView: LoginView, width: 80, height: 30}
+	press := func(keys ...string) {
+		t.Helper()
+		for _, k := range keys {
+			var key tea.KeyMsg
+			switch k {
+			case "tab", "enter", "backspace":
+				key = tea.KeyMsg{Type: map[string]tea.KeyType{"tab": tea.KeyTab, "enter": tea.KeyEnter, "backspace": tea.KeyBackspace}[k]}
+			default:
+				key = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+			}
+			next, _ := m.handleLoginKeys(key)
+			m = next.(model)
+		}
+	}
+
+	// A short ID is pointed out as soon as focus leaves it.
+	press("F", "2", "0", "2", "3", "tab")
+	if m.studentIDError != T("login.id_format") || !strings.Contains(m.renderLogin(), "✗ A student ID") {
+		t.Errorf("short ID: %q\n%s", m.studentIDError, m.renderLogin())
+	}
+
+	// Nothing is sent while the form has errors.
+	press("tab", "tab", "enter")
+	if m.currentView != LoginView || m.passwordError != T("login.password_required") {
+		t.Fatalf("submitted with errors: view %v, password error %q", m.currentView, m.passwordError)
+	}
+
+	// Editing a field clears its error.
+	press("tab", "0", "0", "0", "0", "0", "0")
+	if m.studentIDError != "" {
+		t.Errorf("error kept after editing: %q", m.studentIDError)
+	}
+	press("tab", "h", "u", "n", "t", "e", "r", "2")
+	if m.passwordError != "" {
+		t.Errorf("password error kept after editing: %q", m.passwordError)
+	}
+	m.session = NewSession()
+	press("tab", "tab", "enter")
+	if m.currentView != LoadingView {
+		t.Errorf("valid form not submitted: view %v, errors %q %q", m.currentView, m.studentIDError, m.passwordError)
+	}
+}
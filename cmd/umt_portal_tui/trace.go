@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TRACE_DETAIL_MAX bounds how much of a message's fields a trace line
+// shows; the messages carrying fetched data would otherwise fill it.
+const TRACE_DETAIL_MAX = 160
+
+var viewNames = map[ViewType]string{
+	LoginView:             "login",
+	LoadingView:           "loading",
+	ResultView:            "result",
+	CoursesView:           "courses",
+	CourseDetailView:      "course_detail",
+	AttendanceView:        "attendance",
+	AssessmentView:        "assessments",
+	TranscriptView:        "transcript",
+	ChatView:              "chat",
+	OutlineView:           "outline",
+	ProvisionalResultView: "results",
+	FeesView:              "fees",
+	CaptchaView:           "captcha",
+	JobsView:              "jobs",
+	RetakeView:            "retake",
+	GradeScaleView:        "grade_scale",
+	LatencyView:           "latency",
+	SearchView:            "search",
+}
+
+func viewName(v ViewType) string {
+	if name, ok := viewNames[v]; ok {
+		return name
+	}
+	return fmt.Sprintf("view(%d)", v)
+}
+
+// traceModel runs the TUI's model and, for --trace-ui, writes a line for
+// every message it handles: when, the message, how long Update took and
+// the view it left the TUI on. Keys typed on the login and captcha screens
+// are left out.
+type traceModel struct {
+	model model
+	out   io.Writer
+	start time.Time
+}
+
+func newTraceModel(m model, out io.Writer) *traceModel {
+	t := &traceModel{model: m, out: out, start: time.Now()}
+	fmt.Fprintf(t.out, "%s trace started, %s, view %s\n", t.start.Format(time.RFC3339), readBuildInfo(), viewName(m.currentView))
+	return t
+}
+
+func (t *traceModel) Init() tea.Cmd {
+	return t.model.Init()
+}
+
+func (t *traceModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	before := t.model
+	received := time.Now()
+	next, cmd := t.model.Update(msg)
+	t.model = next.(model)
+
+	view := viewName(t.model.currentView)
+	if t.model.currentView != before.currentView {
+		view = viewName(before.currentView) + " -> " + view
+	}
+	if t.model.currentView == LoadingView && t.model.loadingTask != "" {
+		view += fmt.Sprintf(" (%s)", t.model.loadingTask)
+	}
+	var pending string
+	if cmd != nil {
+		pending = " +cmd"
+	}
+	fmt.Fprintf(t.out, "%s +%s %T %s took=%s view=%s%s\n",
+		received.Format("15:04:05.000"), received.Sub(t.start).Round(time.Millisecond),
+		msg, traceDetail(before, msg), time.Since(received).Round(time.Microsecond), view, pending)
+	return t, cmd
+}
+
+func (t *traceModel) View() string {
+	return t.model.View()
+}
+
+// traceDetail is what a trace line shows of msg, scrubbed of personal data
+// unless --include-pii.
+func traceDetail(m model, msg tea.Msg) string {
+	if key, ok := msg.(tea.KeyMsg); ok {
+		if (m.currentView == LoginView || m.currentView == CaptchaView) && (key.Type == tea.KeyRunes || key.Type == tea.KeySpace) {
+			return fmt.Sprintf("%q", REDACTED)
+		}
+		return fmt.Sprintf("%q", key.String())
+	}
+	var secrets []string
+	if m.session != nil {
+		st := m.session.GetStudent()
+		secrets = append(secrets, st.ID, st.Name, st.Email)
+	}
+	detail := scrubPII(fmt.Sprintf("%+v", msg), secrets...)
+	detail = strings.Join(strings.Fields(detail), " ")
+	if runes := []rune(detail); len(runes) > TRACE_DETAIL_MAX {
+		detail = string(runes[:TRACE_DETAIL_MAX]) + "…"
+	}
+	return detail
+}
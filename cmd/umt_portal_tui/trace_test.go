@@ -0,0 +1,39 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestTraceUI(t *testing.T) {
+	var out bytes.Buffer
+	tm := newTraceModel(NewModel(), &out)
+	send := func(msg tea.Msg) {
+		t.Helper()
+		next, _ := tm.Update(msg)
+		tm = next.(*traceModel)
+	}
+
+	send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("hunter2")})
+	tm.model.courses = []Course{{ID: "1", Code: "CC2042"}}
+	tm.model.resetViews(CoursesView)
+	send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("N")})
+	send(tea.WindowSizeMsg{Width: 100, Height: 30})
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 4 || !strings.Contains(lines[0], "trace started") {
+		t.Fatalf("want a header and 3 messages:\n%s", out.String())
+	}
+	if strings.Contains(out.String(), "hunter2") || !strings.Contains(lines[1], `tea.KeyMsg "`+REDACTED+`"`) {
+		t.Errorf("login keys not redacted: %s", lines[1])
+	}
+	if !strings.Contains(lines[2], `"N"`) || !strings.Contains(lines[2], "view=courses -> latency") {
+		t.Errorf("view transition not traced: %s", lines[2])
+	}
+	if !strings.Contains(lines[3], "tea.WindowSizeMsg {Width:100 Height:30}") || !strings.Contains(lines[3], "view=latency") {
+		t.Errorf("message not traced: %s", lines[3])
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// TEXT_TITLE_WIDTH caps the course title column of the plain-text
+// transcript, so it fits an 80-column page or email.
+const TEXT_TITLE_WIDTH = 40
+
+// transcriptText lays the transcript out as aligned monospace text for
+// printing or pasting into an email. Like the CLI it is in English whatever
+// the UI language.
+func transcriptText(student Student, t Transcript) string {
+	semesters := parseAndSortSemesters(t.Semester)
+
+	titleWidth := runewidth.StringWidth("Course Title")
+	for _, sk := range semesters {
+		for _, c := range t.Semester[sk.semester] {
+			titleWidth = max(titleWidth, runewidth.StringWidth(c.Title))
+		}
+	}
+	titleWidth = min(titleWidth, TEXT_TITLE_WIDTH)
+
+	var b strings.Builder
+	if student.Name != "" || student.ID != "" {
+		fmt.Fprintf(&b, "Transcript of %s", student.Name)
+		if student.ID != "" {
+			fmt.Fprintf(&b, " (%s)", student.ID)
+		}
+		b.WriteString("\n")
+		if student.Program != "" {
+			b.WriteString(student.Program + "\n")
+		}
+		b.WriteString("\n")
+	}
+
+	row := func(code, title, hours, grade, gp, note string) {
+		line := fmt.Sprintf("  %-8s  %s  %7s  %-5s  %4s  %s", code, padText(title, titleWidth), hours, grade, gp, note)
+		b.WriteString(strings.TrimRight(line, " ") + "\n")
+	}
+	marked, noPoints := false, false
+	for i, sk := range semesters {
+		sem := sk.semester
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(sem.Name + "\n")
+		row("Code", "Course Title", "Cr. Hrs", "Grade", "G.P.", "")
+		row(strings.Repeat("-", 8), strings.Repeat("-", titleWidth), strings.Repeat("-", 7), strings.Repeat("-", 5), strings.Repeat("-", 4), "")
+		for _, c := range t.Semester[sem] {
+			note, gradePoint := "", fmt.Sprintf("%.2f", c.GradePoint)
+			switch {
+			case c.Superseded:
+				note = "*"
+			case c.Retake:
+				note = "R"
+			}
+			marked = marked || note != ""
+			if noGradePoints(c.Grade) {
+				gradePoint = "-"
+				noPoints = true
+			}
+			row(c.Code, truncateText(c.Title, titleWidth), fmt.Sprint(c.CreditHours), c.Grade, gradePoint, note)
+		}
+		fmt.Fprintf(&b, "  Credit hours earned: %d   SGPA: %.2f   CGPA: %.2f\n", sem.CreditHoursEarned, sem.SGPA, sem.CGPA)
+	}
+
+	b.WriteString("\n")
+	fmt.Fprintf(&b, "Credit hours earned:  %s\n", t.CreditHoursEarned)
+	fmt.Fprintf(&b, "Credit hours for GPA: %s\n", t.CreditHoursForGPA)
+	fmt.Fprintf(&b, "Total grade points:   %s\n", t.TotalGradePoints)
+	fmt.Fprintf(&b, "CGPA:                 %s / 4.00\n", t.TotalCGPA)
+	if marked || noPoints {
+		b.WriteString("\n")
+	}
+	if marked {
+		b.WriteString("R: repeat attempt, counts toward GPA   *: earlier attempt, excluded from GPA\n")
+	}
+	if noPoints {
+		b.WriteString("-: W (withdrawn), I (incomplete) and P (pass) carry no grade points and are excluded from GPA\n")
+	}
+	return b.String()
+}
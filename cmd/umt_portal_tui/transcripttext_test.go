@@ -0,0 +1,15 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestTranscriptText(t *testing.T) {
+	transcript, err := parseTranscriptReport(openFixture(t, "transcript_report.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	student := Student{Name: "Ali Raza", ID: "F2023000000", Program: "BS Computer Science"}
+	assertGoldenText(t, filepath.Join("testdata", "transcript_text.golden"), transcriptText(student, transcript))
+}
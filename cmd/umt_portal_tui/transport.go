@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// sharedTransport is used by every request made to the portal so that proxy
+// and connection settings only have to be applied once.
+var sharedTransport http.RoundTripper = &compressedTransport{next: http.DefaultTransport.(*http.Transport).Clone()}
+
+// ACCEPT_ENCODING is what portal responses may be compressed with. The
+// ReportViewer pages are hundreds of KB of HTML and shrink several times.
+const ACCEPT_ENCODING = "gzip, deflate"
+
+func configureTransport(cfg Config) error {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+
+	if cfg.ProxyURL != "" {
+		proxyURL, err := url.Parse(cfg.ProxyURL)
+		if err != nil {
+			return fmt.Errorf("invalid proxy_url: %w", err)
+		}
+		switch proxyURL.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return fmt.Errorf("unsupported proxy_url scheme %q", proxyURL.Scheme)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if cfg.CACertFile != "" {
+		pem, err := os.ReadFile(cfg.CACertFile)
+		if err != nil {
+			return fmt.Errorf("failed to read ca_cert_file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no PEM certificates found in %s", cfg.CACertFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if cfg.InsecureSkipVerify {
+		tlsConfig.InsecureSkipVerify = true
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	sharedTransport = &compressedTransport{next: transport}
+	return nil
+}
+
+// compressedTransport asks for compressed responses and decodes them, so
+// everything above it sees plain bodies. Go's own transport only does this
+// for gzip.
+type compressedTransport struct {
+	next http.RoundTripper
+}
+
+func (t *compressedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("Accept-Encoding") != "" || req.Header.Get("Range") != "" {
+		return t.next.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("Accept-Encoding", ACCEPT_ENCODING)
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || req.Method == http.MethodHead || resp.Body == nil || resp.Body == http.NoBody ||
+		resp.StatusCode == http.StatusNoContent || resp.StatusCode == http.StatusNotModified {
+		return resp, err
+	}
+
+	var decoded io.Reader
+	switch strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding"))) {
+	case "gzip", "x-gzip":
+		decoded, err = gzip.NewReader(resp.Body)
+	case "deflate":
+		decoded, err = newDeflateReader(resp.Body)
+	default:
+		return resp, nil
+	}
+	if err != nil {
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to decode %s response: %w", resp.Header.Get("Content-Encoding"), err)
+	}
+
+	resp.Body = decodedBody{Reader: decoded, body: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return resp, nil
+}
+
+// newDeflateReader decodes a deflate body. The standard wraps it in zlib,
+// but some servers send the raw stream, so the zlib header is checked for.
+func newDeflateReader(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	header, err := br.Peek(2)
+	if err != nil {
+		return nil, err
+	}
+	if header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+		return zlib.NewReader(br)
+	}
+	return flate.NewReader(br), nil
+}
+
+type decodedBody struct {
+	io.Reader
+	body io.ReadCloser
+}
+
+func (b decodedBody) Close() error {
+	if c, ok := b.Reader.(io.Closer); ok {
+		c.Close()
+	}
+	return b.body.Close()
+}
+
+// httpClient returns a client bound to the shared transport, throttled by
+// the session's rate limiter and recorded in its request log, with the
+// timeout configured for op. Busy responses come back as portalBusyError.
+func (s *Session) httpClient(op string) *http.Client {
+	next := sharedTransport
+	if portalMetrics != nil {
+		next = &metricsTransport{op: op, metrics: portalMetrics, next: next}
+	}
+	return &http.Client{
+		Transport: &busyTransport{next: &loggingTransport{log: s.requests, next: &throttledTransport{limiter: s.limiter, next: next}}},
+		Timeout:   appConfig.requestPolicy(op).Timeout(),
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCompressedTransport(t *testing.T) {
+	page := strings.Repeat("<div class=\"canGrowTextBoxInTablix\">Present</div>", 200)
+	encoders := map[string]func(io.Writer) io.WriteCloser{
+		"gzip":     func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) },
+		"deflate":  func(w io.Writer) io.WriteCloser { return zlib.NewWriter(w) },
+		"raw":      func(w io.Writer) io.WriteCloser { fw, _ := flate.NewWriter(w, flate.DefaultCompression); return fw },
+		"identity": nil,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Accept-Encoding"); got != ACCEPT_ENCODING {
+			t.Errorf("Accept-Encoding = %q", got)
+		}
+		encoding := r.URL.Query().Get("encoding")
+		newEncoder := encoders[encoding]
+		if newEncoder == nil {
+			io.WriteString(w, page)
+			return
+		}
+		var buf bytes.Buffer
+		enc := newEncoder(&buf)
+		io.WriteString(enc, page)
+		enc.Close()
+		if encoding == "raw" {
+			encoding = "deflate"
+		}
+		w.Header().Set("Content-Encoding", encoding)
+		w.Write(buf.Bytes())
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &compressedTransport{next: http.DefaultTransport}}
+	for encoding := range encoders {
+		resp, err := client.Get(server.URL + "?encoding=" + encoding)
+		if err != nil {
+			t.Fatalf("%s: %v", encoding, err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil || string(body) != page {
+			t.Errorf("%s: got %d bytes (%v), want the page", encoding, len(body), err)
+		}
+		if resp.Header.Get("Content-Encoding") != "" {
+			t.Errorf("%s: Content-Encoding left on the decoded response", encoding)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 10, 6, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		header string
+		want   time.Duration
+	}{
+		{"", 0},
+		{"30", 30 * time.Second},
+		{"Mon, 06 Oct 2025 12:01:30 GMT", 90 * time.Second},
+		{"Mon, 06 Oct 2025 11:59:00 GMT", 0},
+		{"86400", MAX_RETRY_AFTER},
+		{"soon", 0},
+	}
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.header, now); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestBusyPortalBackoff(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	useMockPortal(t, portal)
+
+	s := NewSession()
+	if code, text := s.Login(Credentials{StudentID: "F2023000000", Password: "hunter2"}, false); code != ErrNone {
+		t.Fatalf("login failed: %v %s", code, text)
+	}
+	portal.mu.Lock()
+	portal.busy, portal.busyRetryAfter = 2, "1"
+	portal.mu.Unlock()
+
+	var waits []FetchProgress
+	s.onProgress = func(p FetchProgress) {
+		if !p.RetryAt.IsZero() {
+			waits = append(waits, p)
+		}
+	}
+	start := time.Now()
+	if err := s.GetTranscript(true); err != nil {
+		t.Fatal(err)
+	}
+	if len(waits) != 2 {
+		t.Fatalf("reported %d busy waits, want 2", len(waits))
+	}
+	if d := waits[0].RetryAt.Sub(start); d < time.Second {
+		t.Errorf("retried %v after a Retry-After of 1s", d)
+	}
+	if elapsed := time.Since(start); elapsed < 2*time.Second {
+		t.Errorf("transcript fetched after %v, before the portal's hints ran out", elapsed)
+	}
+	if s.Student.Transcript.TotalCGPA == "" {
+		t.Error("transcript not fetched once the portal recovered")
+	}
+
+	portal.mu.Lock()
+	portal.busy, portal.busyRetryAfter = 1, ""
+	portal.mu.Unlock()
+	_, err := s.GetCourses()
+	var busy *portalBusyError
+	if !errors.As(err, &busy) || busy.Status != http.StatusServiceUnavailable {
+		t.Errorf("courses from a busy portal: err = %v", err)
+	}
+}
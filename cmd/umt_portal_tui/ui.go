@@ -1,14 +1,19 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+	"github.com/muesli/termenv"
 )
 
 const (
@@ -26,6 +31,16 @@ const (
 	SILVER      = lipgloss.Color("#A9B2D8")
 )
 
+// plainOutput is set by --plain or NO_COLOR. Views are rendered without any
+// ANSI styling, so focus that is otherwise shown only by color gets a text
+// marker instead.
+var plainOutput bool
+
+func enablePlainOutput() {
+	plainOutput = true
+	lipgloss.SetColorProfile(termenv.Ascii)
+}
+
 type ViewType int
 
 const (
@@ -38,6 +53,15 @@ const (
 	AssessmentView
 	TranscriptView
 	ChatView
+	OutlineView
+	ProvisionalResultView
+	FeesView
+	CaptchaView
+	JobsView
+	RetakeView
+	GradeScaleView
+	LatencyView
+	SearchView
 )
 
 type LoginResultMsg struct {
@@ -46,9 +70,17 @@ type LoginResultMsg struct {
 	Session *Session
 }
 
+// CoursesLoadedMsg carries the course list. Prefetch marks the fetch started
+// right after login, which only changes the view if the user is waiting on
+// it.
 type CoursesLoadedMsg struct {
-	Courses []Course
-	Error   error
+	Courses  []Course
+	Error    error
+	Prefetch bool
+}
+
+type TranscriptPrefetchedMsg struct {
+	Error error
 }
 
 type CourseActionMsg struct {
@@ -59,6 +91,66 @@ type CourseActionMsg struct {
 	UpdatedCourses []Course
 }
 
+// OutlineLoadedMsg carries a downloaded course outline; Text is set when it
+// was fetched for viewing and SavedPath when it was written to disk.
+type OutlineLoadedMsg struct {
+	CourseID  string
+	Outline   CourseOutline
+	Text      string
+	SavedPath string
+	Error     error
+}
+
+type ResultsLoadedMsg struct {
+	Result ProvisionalResult
+	Error  error
+}
+
+type FeesLoadedMsg struct {
+	Challans []FeeChallan
+	Error    error
+}
+
+type ChallanSavedMsg struct {
+	Path  string
+	Error error
+}
+
+type TranscriptPDFSavedMsg struct {
+	Path  string
+	Error error
+}
+
+type TranscriptCopiedMsg struct{}
+
+type AssessmentsCopiedMsg struct{}
+
+type AssessmentsSavedMsg struct {
+	Path  string
+	Error error
+}
+
+type AttendanceWarmedMsg struct {
+	CourseID string
+	Error    error
+}
+
+type lockoutTickMsg struct{}
+
+// keepaliveDueMsg, KeepAliveMsg and SessionRenewedMsg carry the generation
+// of the keepalive loop, so a loop left over from a previous login stops.
+type keepaliveDueMsg struct{ ID int }
+
+type KeepAliveMsg struct {
+	ID    int
+	Error error
+}
+
+type SessionRenewedMsg struct {
+	ID   int
+	Code ErrorCode
+}
+
 type LoadingState struct {
 	Reason     string
 	HelpText   string
@@ -85,6 +177,9 @@ type model struct {
 	showPassword   bool
 	submitted      bool
 	loginResult    *LoginResultMsg
+	lockedUntil    time.Time
+	captchaSession *Session
+	captchaInput   string
 	session        *Session
 	courses        []Course
 	selectedCourse int
@@ -93,12 +188,94 @@ type model struct {
 	loadingState   LoadingState
 	spinner        spinner.Model
 
+	// Inline errors of the login form, shown under their field until it
+	// is edited.
+	studentIDError string
+	passwordError  string
+
+	// hiddenCourses are left out of the course list unless showHidden.
+	hiddenCourses map[string]bool
+	showHidden    bool
+
+	progressCh      chan FetchProgress
+	loadingTask     string
+	loadingProgress FetchProgress
+	loadingSince    time.Time
+
+	// Background jobs, oldest first, and the outcome of the last to finish.
+	jobs          []Job
+	nextJobID     int
+	selectedJob   int
+	refreshNotice string
+
+	// Post-login prefetch state.
+	coursesPending  bool
+	awaitingCourses bool
+	transcriptReady bool
+
+	// Set while fetches wait to be retried after a network failure.
+	recovery   *networkRecovery
+	recoveryID int
+
+	// State saved by the last run, restored when the courses first load.
+	restore         *uiState
+	restoreSemester string
+
 	table                 []table.Model
 	transcriptSemesters   []SemesterKey
 	currentSemester       int
 	attendanceTotalPages  int
 	currentAttendancePage int
 
+	// semesterPicker lists the semesters to jump to; pickerSemester is the
+	// highlighted one.
+	semesterPicker bool
+	pickerSemester int
+
+	// transcriptStatus reports the download of the transcript PDF.
+	transcriptStatus string
+
+	// attendanceFilter narrows the attendance report; while editingFilter
+	// the filter bar takes a date, month or range typed into filterInput.
+	attendanceFilter attendanceFilter
+	editingFilter    bool
+	filterInput      string
+	filterError      string
+
+	// assessmentStatus reports the export of the assessment table.
+	assessmentStatus string
+
+	// disputePicker lists the absences to draft a correction email for;
+	// pickerAbsence is the highlighted one. attendanceStatus reports the
+	// draft.
+	disputePicker    bool
+	pickerAbsence    int
+	attendanceStatus string
+
+	// recheckPicker lists the assessments to draft a recheck request for;
+	// pickerAssessment is the highlighted one.
+	recheckPicker    bool
+	pickerAssessment int
+
+	// The retake analysis: the grade a retake is assumed to earn and the
+	// highlighted course.
+	retakeTarget   string
+	selectedRetake int
+
+	// The grading scale on show, and the course it belongs to; empty for
+	// the default scale.
+	gradeScale       CourseGrading
+	gradeScaleCourse string
+
+	// The latest latency probe of the portal endpoints, and whether one is
+	// running.
+	latencyProbe   []endpointLatency
+	latencyProbing bool
+
+	// The global search: what has been typed and the highlighted hit.
+	searchQuery string
+	selectedHit int
+
 	// Chat fields
 	matcher                 *IntentMatcher
 	chatInput               string
@@ -108,7 +285,46 @@ type model struct {
 	pendingAction           string // "attendance" or "assessment"
 
 	// Navigation State
-	lastView ViewType
+	viewStack []ViewType
+
+	outline       CourseOutline
+	outlineLines  []string
+	outlineOffset int
+	outlineStatus string
+	// outlineCourse is the course whose outline outlineLines holds.
+	outlineCourse string
+
+	lmsDeadlines []LMSDeadline
+	lmsError     error
+
+	provisionalResult ProvisionalResult
+
+	fees          []FeeChallan
+	selectedFee   int
+	feeStatus     string
+	savingChallan bool
+	challanDir    string
+	showQR        bool
+
+	updateNotice string
+	// browserNotice reports the last "open in browser" until the next key.
+	browserNotice string
+
+	// snapshotNotice reports the last Ctrl+S snapshot until the next key.
+	snapshotNotice string
+
+	keepaliveID   int
+	sessionNotice string
+	// cgpaWarning is set while the CGPA is below the configured floor.
+	cgpaWarning string
+
+	// The last failure, shown as a hint that expands into a diagnostics pane.
+	diagnostics       *Diagnostics
+	showDiagnostics   bool
+	diagnosticsCopied bool
+
+	// unfocused is set while the terminal reports it has lost focus.
+	unfocused bool
 }
 
 const (
@@ -141,13 +357,15 @@ func NewModel() model {
 		focusedField:   fieldStudentID,
 		selectedCourse: 0,
 		rememberMe:     shouldAutoLogin,
+		restore:        readUIState(creds.StudentID),
 		spinner:        s,
 		matcher:        matcher,
 		chatHistory:    []string{},
+		progressCh:     make(chan FetchProgress, PROGRESS_CHANNEL_BUFFER),
 		loadingState: LoadingState{
-			Reason:     "🔐 Logging in, please wait",
-			HelpText:   "Authenticating your cached credentials with the UMT portal",
-			BottomText: "• Q: Cancel and quit",
+			Reason:     T("loading.login"),
+			HelpText:   T("loading.login_cached_help"),
+			BottomText: T("loading.help_quit"),
 		},
 	}
 }
@@ -157,13 +375,16 @@ func (m model) Init() tea.Cmd {
 
 	cmds = append(cmds, m.spinner.Tick)
 
+	if appConfig.CheckForUpdates {
+		cmds = append(cmds, checkForUpdateCmd())
+	}
+
+	if m.progressCh != nil {
+		cmds = append(cmds, waitForProgress(m.progressCh))
+	}
+
 	if m.currentView == LoadingView && m.Credentials.StudentID != "" && m.Credentials.Password != "" {
-		cmds = append(cmds, func() tea.Msg {
-			session := NewSession()
-			loadTranscriptCache(session)
-			code, str := session.Login(m.Credentials, m.rememberMe)
-			return LoginResultMsg{Code: code, Text: str, Session: session}
-		})
+		cmds = append(cmds, loginCmd(m.Credentials, m.rememberMe, true))
 	}
 
 	return tea.Batch(cmds...)
@@ -172,59 +393,315 @@ func (m model) Init() tea.Cmd {
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
+	if r, ok := msg.(networkResult); ok && r.networkError() == nil && m.recovery != nil {
+		retry := m.reconnected()
+		next, cmd := m.Update(msg)
+		return next, tea.Batch(cmd, retry)
+	}
+	if f, ok := msg.(failedResult); ok {
+		m.recordFailure(f)
+	}
+	// A long fetch that ends while the terminal is in the background
+	// rings the bell, or whatever attention is set to.
+	if m.awaitingAttention() {
+		elapsed := time.Since(m.loadingSince)
+		m.unfocused = false
+		next, cmd := m.Update(msg)
+		nm, ok := next.(model)
+		if !ok {
+			return next, cmd
+		}
+		if _, focused := msg.(tea.FocusMsg); !focused {
+			nm.unfocused = true
+		}
+		if nm.currentView != LoadingView {
+			cmd = tea.Batch(cmd, attentionCmd(elapsed))
+		}
+		return nm, cmd
+	}
+
 	switch msg := msg.(type) {
+	case tea.FocusMsg:
+		m.unfocused = false
+
+	case tea.BlurMsg:
+		m.unfocused = true
+
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		m.resizeTables()
 
 	case spinner.TickMsg:
 		m.spinner, cmd = m.spinner.Update(msg)
 		return m, cmd
 
+	case NetworkLostMsg:
+		return m.handleNetworkLost(msg)
+
+	case recoveryTickMsg:
+		return m.handleRecoveryTick(msg)
+
+	case DiagnosticsCopiedMsg:
+		m.diagnosticsCopied = true
+		return m, nil
+
+	case BrowserOpenedMsg:
+		if msg.Error != nil {
+			m.browserNotice = T("browser.failed", msg.Error, msg.URL)
+		} else {
+			m.browserNotice = T("browser.opened", msg.URL)
+		}
+		return m, nil
+
+	case SnapshotSavedMsg:
+		if msg.Error != nil {
+			m.snapshotNotice = T("snapshot.failed", msg.Error)
+		} else {
+			m.snapshotNotice = T("snapshot.saved", msg.Text, msg.SVG)
+		}
+		return m, nil
+
+	case TranscriptCopiedMsg:
+		m.transcriptStatus = T("transcript.copied")
+		return m, nil
+
+	case AssessmentsCopiedMsg:
+		m.assessmentStatus = T("assessment.copied")
+		return m, nil
+
+	case DisputeDraftMsg:
+		status := &m.attendanceStatus
+		if m.currentView == AssessmentView {
+			status = &m.assessmentStatus
+		}
+		switch {
+		case msg.Copied:
+			*status = T("dispute.copied")
+		case msg.Error != nil:
+			*status = T("dispute.open_failed", msg.Error)
+			return m, copyToClipboard(msg.Text, nil)
+		default:
+			*status = T("dispute.opened")
+		}
+		return m, nil
+
+	case AssessmentsSavedMsg:
+		if msg.Error != nil {
+			m.assessmentStatus = T("error", msg.Error)
+		} else {
+			m.assessmentStatus = T("fees.saved", msg.Path)
+		}
+		return m, nil
+
+	case UpdateAvailableMsg:
+		m.updateNotice = T("update.available", msg.Version, readBuildInfo().Version, commandName())
+		return m, nil
+
 	case LoginResultMsg:
 		m.loginResult = &msg
 		m.submitted = false
 		if msg.Code == ErrNone {
 			m.session = msg.Session
-			m.currentView = ResultView
-		} else {
-			m.currentView = ResultView
+			if m.progressCh != nil {
+				m.session.onProgress = progressSink(m.progressCh)
+			}
+			m.keepaliveID++
+			m.sessionNotice = ""
+			m.hiddenCourses = readHiddenCourses(m.Credentials.StudentID)
+			m.resetViews(ResultView)
+			m.coursesPending = true
+			m.awaitingCourses = false
+			m.transcriptReady = false
+			return m, tea.Batch(keepaliveTick(m.keepaliveID), prefetchCoursesCmd(msg.Session), prefetchTranscriptCmd(msg.Session))
+		}
+		if msg.Code == ErrCaptchaRequired {
+			m.captchaSession = msg.Session
+			m.captchaInput = ""
+			m.resetViews(LoginView)
+			m.pushView(CaptchaView)
+			break
+		}
+		m.resetViews(ResultView)
+		if msg.Code == ErrLockedOut {
+			m.lockedUntil = time.Now().Add(loginCooldown(m.Credentials.StudentID, time.Now()))
+			return m, lockoutTick()
+		}
+
+	case ProgressMsg:
+		if msg.Task == m.loadingTask {
+			partial := m.loadingProgress.Partial
+			m.loadingProgress = FetchProgress(msg)
+			if m.loadingProgress.Partial == nil {
+				m.loadingProgress.Partial = partial
+			}
+		}
+		return m, waitForProgress(m.progressCh)
+
+	case lockoutTickMsg:
+		if m.currentView == ResultView && time.Now().Before(m.lockedUntil) {
+			return m, lockoutTick()
+		}
+
+	case keepaliveDueMsg:
+		if msg.ID != m.keepaliveID || m.session == nil {
+			break
+		}
+		return m, keepAliveCmd(m.session, msg.ID)
+
+	case KeepAliveMsg:
+		if msg.ID != m.keepaliveID || m.session == nil {
+			break
+		}
+		if !errors.Is(msg.Error, errSessionExpired) {
+			// Network hiccups are left to the next ping.
+			return m, keepaliveTick(msg.ID)
+		}
+		if m.Credentials.StudentID == "" || m.Credentials.Password == "" {
+			m.sessionNotice = T("session.expired")
+			break
+		}
+		return m, renewSessionCmd(m.session, m.Credentials, msg.ID)
+
+	case SessionRenewedMsg:
+		if msg.ID != m.keepaliveID {
+			break
+		}
+		if msg.Code != ErrNone {
+			m.sessionNotice = T("session.expired")
+			break
 		}
+		m.sessionNotice = ""
+		return m, keepaliveTick(msg.ID)
 
 	case CoursesLoadedMsg:
+		if msg.Prefetch {
+			m.coursesPending = false
+			if !m.awaitingCourses {
+				if msg.Error == nil {
+					m.courses = msg.Courses
+					if appConfig.LMS.configured() {
+						return m, lmsDeadlinesCmd(msg.Courses)
+					}
+				}
+				break
+			}
+			m.awaitingCourses = false
+		}
 		if msg.Error != nil {
 			m.courseError = msg.Error
-			m.currentView = ResultView
+			m.resetViews(ResultView)
 		} else {
 			m.courses = msg.Courses
 			m.courseError = nil
-			m.currentView = CoursesView
+			m.stepCourse(0)
+			m.resetViews(CoursesView)
+			next, cmd := m.restoreUIState()
+			if appConfig.LMS.configured() {
+				cmd = tea.Batch(cmd, lmsDeadlinesCmd(msg.Courses))
+			}
+			return next, cmd
+		}
+
+	case LatencyProbedMsg:
+		m.latencyProbe, m.latencyProbing = msg.Results, false
+		return m, nil
+
+	case JobFinishedMsg:
+		if !m.finishJob(msg) {
+			return m, m.scheduleJobs()
+		}
+		job := m.jobs[m.jobIndex(msg.ID)]
+		if msg.Error != nil {
+			m.refreshNotice = T("refresh.failed", msg.Error)
+			return m, m.scheduleJobs()
+		}
+		m.refreshNotice = T("refresh.done", time.Now().Format("15:04"))
+		if len(msg.Courses) > 0 {
+			var selectedID string
+			if m.selectedCourse < len(m.courses) {
+				selectedID = m.courses[m.selectedCourse].ID
+			}
+			m.courses = msg.Courses
+			m.selectedCourse = min(m.selectedCourse, len(m.courses)-1)
+			for i, c := range m.courses {
+				if c.ID == selectedID {
+					m.selectedCourse = i
+					break
+				}
+			}
+		}
+		if job.Task == TASK_TRANSCRIPT && m.session != nil {
+			m.setTranscriptTable(m.session.GetStudent().Transcript)
+			m.transcriptReady = true
+		}
+		return m, m.scheduleJobs()
+
+	case TranscriptPrefetchedMsg:
+		if msg.Error == nil && m.session != nil {
+			m.setTranscriptTable(m.session.GetStudent().Transcript)
+			m.transcriptReady = true
+		}
+
+	case ResultsLoadedMsg:
+		if msg.Error != nil {
+			m.courseError = msg.Error
+			if m.currentView == LoadingView {
+				m.goBack()
+			}
+			break
+		}
+		m.courseError = nil
+		m.provisionalResult = msg.Result
+		if m.session != nil {
+			m.updateCGPAWarning(m.session.GetStudent().Transcript)
+		}
+		if m.currentView == LoadingView {
+			m.replaceView(ProvisionalResultView)
+		}
+
+	case FeesLoadedMsg:
+		if msg.Error != nil {
+			m.courseError = msg.Error
+			if m.currentView == LoadingView {
+				m.goBack()
+			}
+			break
+		}
+		m.courseError = nil
+		m.fees = msg.Challans
+		m.selectedFee = min(m.selectedFee, max(len(m.fees)-1, 0))
+		m.feeStatus = ""
+		if m.currentView == LoadingView {
+			m.replaceView(FeesView)
 		}
 
-		// In ui.go - Update the CourseActionMsg struct to carry the data
+	case ChallanSavedMsg:
+		if msg.Error != nil {
+			m.feeStatus = T("error", msg.Error)
+		} else {
+			m.feeStatus = T("fees.saved", msg.Path)
+		}
 
-		type CourseActionMsg struct {
-			Action   string
-			CourseID string
-			Error    error
-			Success  bool
-			// ADD THIS: Carry the updated courses back to the UI
-			UpdatedCourses []Course
+	case TranscriptPDFSavedMsg:
+		if msg.Error != nil {
+			m.transcriptStatus = T("error", msg.Error)
+		} else {
+			m.transcriptStatus = T("fees.saved", msg.Path)
 		}
 
-	// In the Update() method, modify the CourseActionMsg handler:
+	case LMSDeadlinesMsg:
+		m.lmsDeadlines = msg.Deadlines
+		m.lmsError = msg.Error
 
 	case CourseActionMsg:
 		m.lastAction = msg.Action
 		if msg.Error != nil {
 			m.courseError = msg.Error
-			switch msg.Action {
-			case "transcript":
-				m.currentView = CoursesView
-			case "attendance":
-				m.currentView = CourseDetailView
-			case "assessments":
-				m.currentView = CourseDetailView
+			// Return to wherever the fetch was started from, unless the
+			// user already navigated away from the loading screen.
+			if m.currentView == LoadingView {
+				m.goBack()
 			}
 		} else {
 			m.courseError = nil
@@ -245,27 +722,60 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 			if msg.Action == "transcript" {
-				transcript := m.session.Student.Transcript
+				transcript := m.session.GetStudent().Transcript
 				m.setTranscriptTable(transcript)
-				m.currentView = TranscriptView
-			} else if msg.Action == "attendance" {
-				m.currentView = AttendanceView
-			} else if msg.Action == "assessments" {
-				m.currentView = AssessmentView
-			} else {
-				m.currentView = CoursesView
 			}
+
+			if m.currentView != LoadingView {
+				break
+			}
+
+			switch msg.Action {
+			case "transcript":
+				m.replaceView(TranscriptView)
+			case "attendance":
+				m.replaceView(AttendanceView)
+			case "assessments":
+				m.replaceView(AssessmentView)
+			default:
+				m.replaceView(CoursesView)
+			}
+		}
+
+	case OutlineLoadedMsg:
+		if msg.Error != nil {
+			m.outlineStatus = T("error", msg.Error)
+			if m.currentView == LoadingView {
+				m.goBack()
+			}
+			break
+		}
+		m.outline = msg.Outline
+		m.outlineStatus = ""
+		if msg.SavedPath != "" {
+			m.outlineStatus = T("outline.saved", msg.SavedPath)
+		}
+		if m.currentView != LoadingView {
+			break
+		}
+		if msg.Text != "" {
+			m.outlineLines = strings.Split(msg.Text, "\n")
+			m.outlineOffset = 0
+			m.outlineCourse = msg.CourseID
+			m.replaceView(OutlineView)
+		} else {
+			m.goBack()
 		}
 
 	case NLPClassificationMsg:
 		m.lastClassification = &msg
 		if msg.Error != nil {
-			m.chatHistory = append(m.chatHistory, fmt.Sprintf("❌ Error: %v", msg.Error))
+			m.chatHistory = append(m.chatHistory, T("error", msg.Error))
 			return m, nil
 		}
 
 		// Add query to history
-		m.chatHistory = append(m.chatHistory, fmt.Sprintf("🧑 You: %s", msg.Query))
+		m.chatHistory = append(m.chatHistory, T("chat.you")+" "+msg.Query)
 
 		// Route based on intent
 		return m.handleIntent(msg)
@@ -278,6 +788,50 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.browserNotice = ""
+	m.snapshotNotice = ""
+
+	if m.diagnostics != nil {
+		switch msg.String() {
+		case "ctrl+e":
+			m.showDiagnostics = !m.showDiagnostics
+			return m, nil
+		case "ctrl+y":
+			return m, copyToClipboard(m.diagnosticsReport(), DiagnosticsCopiedMsg{})
+		case "esc":
+			if m.showDiagnostics {
+				m.showDiagnostics = false
+				return m, nil
+			}
+		}
+	}
+
+	if courseTabIndex(m.currentView) != -1 && !m.editingFilter && !m.disputePicker && !m.recheckPicker {
+		switch msg.String() {
+		case "tab":
+			return m.switchCourseTab(1)
+		case "shift+tab":
+			return m.switchCourseTab(-1)
+		}
+	}
+
+	if msg.String() == "ctrl+f" && len(m.courses) > 0 && m.currentView != SearchView && m.currentView != LoadingView && m.currentView != LoginView && m.currentView != CaptchaView {
+		m.openSearch()
+		return m, nil
+	}
+
+	// Ctrl+S shows the password on the login screen, and the captcha is
+	// no use to anyone later.
+	if msg.String() == "ctrl+s" && m.currentView != LoginView && m.currentView != CaptchaView {
+		return m, saveSnapshotCmd(m.View(), viewName(m.currentView))
+	}
+
+	if msg.String() == "o" && !m.editingFilter && !m.disputePicker && !m.recheckPicker {
+		if url := m.portalURL(); url != "" {
+			return m, openBrowserCmd(url)
+		}
+	}
+
 	switch m.currentView {
 	case LoginView:
 		return m.handleLoginKeys(msg)
@@ -297,6 +851,24 @@ func (m model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleTranscriptKeys(msg)
 	case ChatView:
 		return m.handleChatKeys(msg)
+	case OutlineView:
+		return m.handleOutlineKeys(msg)
+	case ProvisionalResultView:
+		return m.handleProvisionalResultKeys(msg)
+	case FeesView:
+		return m.handleFeesKeys(msg)
+	case CaptchaView:
+		return m.handleCaptchaKeys(msg)
+	case JobsView:
+		return m.handleJobsKeys(msg)
+	case RetakeView:
+		return m.handleRetakeKeys(msg)
+	case GradeScaleView:
+		return m.handleGradeScaleKeys(msg)
+	case LatencyView:
+		return m.handleLatencyKeys(msg)
+	case SearchView:
+		return m.handleSearchKeys(msg)
 	default:
 		return m, nil
 	}
@@ -306,16 +878,12 @@ func (m model) handleLoadingKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "ctrl+c", "q":
 		if !m.rememberMe {
-			deleteTranscriptCache()
+			deleteCaches()
 		}
 		return m, tea.Quit
 	case "esc":
-		if strings.Contains(m.loadingState.Reason, "transcript") ||
-			strings.Contains(m.loadingState.Reason, "attendance") ||
-			strings.Contains(m.loadingState.Reason, "assessments") {
-			if m.session != nil && m.session.loggedIn {
-				m.currentView = CoursesView
-			}
+		if m.session != nil && m.session.loggedIn {
+			m.goBack()
 		}
 	}
 	return m, nil
@@ -325,17 +893,19 @@ func (m model) handleLoginKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "ctrl+c", "q":
 		if !m.rememberMe {
-			deleteTranscriptCache()
+			deleteCaches()
 		}
 		return m, tea.Quit
 
-	case "esc":
+	case "ctrl+s":
 		m.showPassword = !m.showPassword
 
 	case "tab", "down":
+		m.leaveLoginField()
 		m.focusedField = (m.focusedField + 1) % 4
 
 	case "shift+tab", "up":
+		m.leaveLoginField()
 		m.focusedField = (m.focusedField - 1 + 4) % 4
 
 	case "enter":
@@ -343,21 +913,19 @@ func (m model) handleLoginKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		case fieldRememberMe:
 			m.rememberMe = !m.rememberMe
 		case fieldLoginButton:
-			if m.Credentials.StudentID == "" || m.Credentials.Password == "" {
+			m.studentIDError = studentIDProblem(m.Credentials.StudentID, time.Now())
+			m.passwordError = ""
+			if m.Credentials.Password == "" {
+				m.passwordError = T("login.password_required")
+			}
+			if m.studentIDError != "" || m.passwordError != "" {
 				return m, nil
 			}
 			m.submitted = true
-			m.setLoadingState("🔐 Logging in, please wait", "Authenticating your credentials with the UMT portal", "• Q: Cancel and quit")
+			m.setLoadingState(T("loading.login"), T("loading.login_help"), T("loading.help_quit"))
 			m.currentView = LoadingView
 
-			return m, tea.Batch(
-				m.spinner.Tick,
-				func() tea.Msg {
-					session := NewSession()
-					code, str := session.Login(m.Credentials, m.rememberMe)
-					return LoginResultMsg{Code: code, Text: str, Session: session}
-				},
-			)
+			return m, tea.Batch(m.spinner.Tick, loginCmd(m.Credentials, m.rememberMe, false))
 		}
 
 	case " ":
@@ -368,38 +936,55 @@ func (m model) handleLoginKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "backspace":
 		if m.focusedField == fieldStudentID && len(m.Credentials.StudentID) > 0 {
 			m.Credentials.StudentID = m.Credentials.StudentID[:len(m.Credentials.StudentID)-1]
+			m.studentIDError = ""
 		} else if m.focusedField == fieldPassword && len(m.Credentials.Password) > 0 {
 			m.Credentials.Password = m.Credentials.Password[:len(m.Credentials.Password)-1]
+			m.passwordError = ""
 		}
 
 	default:
 		if m.focusedField == fieldStudentID && len(msg.String()) == 1 {
 			m.Credentials.StudentID += msg.String()
+			m.studentIDError = ""
 		} else if m.focusedField == fieldPassword && len(msg.String()) == 1 {
 			m.Credentials.Password += msg.String()
+			m.passwordError = ""
 		}
 	}
 	return m, nil
 }
 
+// leaveLoginField checks the student ID once it has been typed and focus
+// moves on, so a mistyped one is pointed out before the login is sent.
+func (m *model) leaveLoginField() {
+	if m.focusedField == fieldStudentID && m.Credentials.StudentID != "" {
+		m.studentIDError = studentIDProblem(m.Credentials.StudentID, time.Now())
+	}
+}
+
 func (m model) handleResultKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "ctrl+c", "q":
 		if !m.rememberMe {
-			deleteTranscriptCache()
+			deleteCaches()
 		}
 		return m, tea.Quit
 	case "enter", "c":
 		if m.loginResult != nil && m.loginResult.Code == ErrNone {
-			m.setLoadingState("📚 Loading courses, please wait", "Fetching your enrolled courses from the portal", "• Q: Cancel and quit")
+			if m.coursesPending {
+				m.awaitingCourses = true
+				m.setLoadingState(T("loading.courses"), T("loading.courses_help"), T("loading.help_quit"))
+				m.currentView = LoadingView
+				return m, m.spinner.Tick
+			}
+			if m.courses != nil {
+				m.stepCourse(0)
+				m.resetViews(CoursesView)
+				return m.restoreUIState()
+			}
+			m.setLoadingState(T("loading.courses"), T("loading.courses_help"), T("loading.help_quit"))
 			m.currentView = LoadingView
-			return m, tea.Batch(
-				m.spinner.Tick,
-				func() tea.Msg {
-					courses, err := m.session.GetCourses()
-					return CoursesLoadedMsg{Courses: courses, Error: err}
-				},
-			)
+			return m, tea.Batch(m.spinner.Tick, coursesCmd(m.session))
 		}
 	case "r":
 		m.resetToLogin()
@@ -407,72 +992,110 @@ func (m model) handleResultKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// On terminals at least SPLIT_MIN_WIDTH wide the course list shows the
+// highlighted course in a panel beside it.
+const (
+	SPLIT_MIN_WIDTH       = 140
+	SPLIT_PANEL_MAX_WIDTH = 60
+)
+
+// SHIFTED_DIGITS are Shift+1 to Shift+9 on a US layout, which is what the
+// terminal sends for them; they open a course's attendance directly.
+const SHIFTED_DIGITS = "!@#$%^&*("
+
 func (m model) handleCoursesKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "ctrl+c", "q":
 		if !m.rememberMe {
-			deleteTranscriptCache()
+			deleteCaches()
 		}
 		return m, tea.Quit
 
 	case "up", "k":
-		if m.selectedCourse > 0 {
-			m.selectedCourse--
-		}
+		m.stepCourse(-1)
 
 	case "down", "j":
-		if m.selectedCourse < len(m.courses)-1 {
-			m.selectedCourse++
-		}
+		m.stepCourse(1)
+
+	case "x":
+		m.toggleHiddenCourse()
+
+	case "H":
+		m.showHidden = !m.showHidden
+		m.stepCourse(0)
 
 	case "enter":
-		if len(m.courses) > 0 {
-			m.currentView = CourseDetailView
-			m.lastView = CoursesView
+		if len(m.listedCourses()) > 0 {
+			cmd := m.openCourseDetail()
+			return m, cmd
 		}
 
 	case "r":
-		m.setLoadingState("🔄 Refreshing courses, please wait", "Refreshing course information from the portal", "• Esc: Back to courses • Q: Cancel and quit")
-		m.currentView = LoadingView
-		return m, tea.Batch(
-			m.spinner.Tick,
-			func() tea.Msg {
-				courses, err := m.session.GetCourses()
-				return CoursesLoadedMsg{Courses: courses, Error: err}
-			},
-		)
+		return m.startRefresh(TASK_COURSES, func(s *Session) error {
+			_, err := s.GetCourses()
+			return err
+		})
+
+	case "A":
+		var cmds []tea.Cmd
+		for _, i := range m.listedCourses() {
+			courseID := m.courses[i].ID
+			cmds = append(cmds, m.enqueueJob(attendanceTask(courseID), func(s *Session) error {
+				return s.GetCourseAttendance(true, courseID)
+			}))
+		}
+		m.pushView(JobsView)
+		return m, tea.Batch(cmds...)
+
+	case "J":
+		m.pushView(JobsView)
+
+	case "N":
+		m.pushView(LatencyView)
+
+	case "a", "s":
+		if len(m.listedCourses()) == 0 {
+			break
+		}
+		m.pushView(CourseDetailView)
+		if msg.String() == "a" {
+			return m.openCourseTab(AttendanceView)
+		}
+		return m.openCourseTab(AssessmentView)
+
+	case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		if listed, i := m.listedCourses(), int(msg.String()[0]-'1'); i < len(listed) {
+			m.selectedCourse = listed[i]
+			cmd := m.openCourseDetail()
+			return m, cmd
+		}
+
+	default:
+		if listed, i := m.listedCourses(), strings.Index(SHIFTED_DIGITS, msg.String()); i != -1 && i < len(listed) {
+			m.selectedCourse = listed[i]
+			m.pushView(CourseDetailView)
+			return m.openCourseTab(AttendanceView)
+		}
 
 	case "l":
 		m.resetToLogin()
 
 	case "t":
-		m.setLoadingState("📄 Getting transcript, please wait", "Fetching your complete academic transcript", "• Esc: Back to courses • Q: Cancel and quit")
-		m.currentView = LoadingView
-		m.lastView = CoursesView
-		return m, tea.Batch(
-			m.spinner.Tick,
-			func() tea.Msg {
-				err := m.session.GetTranscript(false)
-				if err != nil {
-					m.session.Student.CgpaEarned = m.session.Student.Transcript.TotalCGPA
-					return CourseActionMsg{
-						Action:  "transcript",
-						Error:   err,
-						Success: false,
-					}
-				}
-				return CourseActionMsg{
-					Action:         "transcript",
-					Error:          nil,
-					Success:        true,
-					UpdatedCourses: m.session.Student.Courses,
-				}
-			},
-		)
+		return m.openTranscript()
+
+	case "g":
+		m.setLoadingState(T("loading.results"), T("loading.results_help"), T("loading.help_back_courses"))
+		m.pushView(LoadingView)
+		return m, tea.Batch(m.spinner.Tick, resultsCmd(m.session))
+
+	case "f":
+		m.setLoadingState(T("loading.fees"), T("loading.fees_help"), T("loading.help_back_courses"))
+		m.pushView(LoadingView)
+		return m, tea.Batch(m.spinner.Tick, feesCmd(m.session))
 
 	case "c":
 		// Open AI chat assistant
-		m.currentView = ChatView
+		m.pushView(ChatView)
 	}
 	return m, nil
 }
@@ -481,76 +1104,78 @@ func (m model) handleCourseDetailKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "ctrl+c", "q":
 		if !m.rememberMe {
-			deleteTranscriptCache()
+			deleteCaches()
 		}
 		return m, tea.Quit
-	case "esc":
-		if m.lastView == ChatView {
-			m.currentView = ChatView
-			m.lastView = 0 // Reset
-		} else {
-			m.currentView = CoursesView
-		}
-	case "enter":
-		m.currentView = CoursesView
-	case "a":
+	case "esc", "enter":
+		m.outlineStatus = ""
+		m.goBack()
+	case "o":
+		return m.openCourseTab(OutlineView)
+	case "d":
 		if len(m.courses) > 0 && m.selectedCourse < len(m.courses) {
-			courseID := m.courses[m.selectedCourse].ID
-			courseName := m.courses[m.selectedCourse].Code
-			m.setLoadingState(fmt.Sprintf("📊 Getting attendance for %s...", courseName), "Fetching attendance records", "• Esc: Back to courses • Q: Cancel and quit")
-			m.currentView = LoadingView
-			m.lastView = CourseDetailView
-			return m, tea.Batch(
-				m.spinner.Tick,
-				func() tea.Msg {
-					err := m.session.GetCourseAttendance(false, courseID)
-					if err != nil {
-						return CourseActionMsg{
-							Action:   "attendance",
-							CourseID: courseID,
-							Error:    err,
-							Success:  false,
-						}
-					}
-					return CourseActionMsg{
-						Action:         "attendance",
-						CourseID:       courseID,
-						Error:          nil,
-						Success:        true,
-						UpdatedCourses: m.session.Student.Courses,
-					}
-				},
-			)
+			course := m.courses[m.selectedCourse]
+			m.setLoadingState(T("loading.outline", course.Code), T("loading.outline_help"), T("loading.help_back"))
+			m.pushView(LoadingView)
+			return m, tea.Batch(m.spinner.Tick, outlineCmd(m.session, course.ID, true))
 		}
+	case "a":
+		return m.openCourseTab(AttendanceView)
 	case "s":
-		if len(m.courses) > 0 && m.selectedCourse < len(m.courses) {
-			courseID := m.courses[m.selectedCourse].ID
-			courseName := m.courses[m.selectedCourse].Code
-			m.setLoadingState(fmt.Sprintf("📝 Getting assessments for %s...", courseName), "Fetching detailed assessment information", "• Esc: Back to courses • Q: Cancel and quit")
-			m.currentView = LoadingView
-			m.lastView = CourseDetailView
-			return m, tea.Batch(
-				m.spinner.Tick,
-				func() tea.Msg {
-					err := m.session.GetCourseAssessments(courseID)
-					if err != nil {
-						return CourseActionMsg{
-							Action:   "assessments",
-							CourseID: courseID,
-							Error:    err,
-							Success:  false,
-						}
-					}
-					return CourseActionMsg{
-						Action:         "assessments",
-						CourseID:       courseID,
-						Error:          nil,
-						Success:        true,
-						UpdatedCourses: m.session.Student.Courses,
-					}
-				},
-			)
-		}
+		return m.openCourseTab(AssessmentView)
+	}
+	return m, nil
+}
+
+// openTranscript shows the transcript, fetching it first unless the
+// prefetch already has.
+func (m model) openTranscript() (tea.Model, tea.Cmd) {
+	if m.transcriptReady {
+		m.pushView(TranscriptView)
+		return m, nil
+	}
+	m.setLoadingState(T("loading.transcript"), T("loading.transcript_help"), T("loading.help_back_courses"))
+	m.loadingTask = TASK_TRANSCRIPT
+	m.pushView(LoadingView)
+	return m, tea.Batch(m.spinner.Tick, transcriptCmd(m.session))
+}
+
+// openAttendance shows the selected course's attendance, fetching it first
+// unless it is cached.
+// openCourseDetail shows the selected course and, unless its attendance is
+// already loaded, warms up the attendance report for it.
+func (m *model) openCourseDetail() tea.Cmd {
+	m.pushView(CourseDetailView)
+	if m.session == nil || !m.session.IsLoggedIn() || m.selectedCourse >= len(m.courses) {
+		return nil
+	}
+	course := m.courses[m.selectedCourse]
+	if len(course.Attendance) > 0 {
+		return nil
+	}
+	return warmAttendanceCmd(m.session, course.ID)
+}
+
+func (m model) openAttendance() (tea.Model, tea.Cmd) {
+	if len(m.courses) > 0 && m.selectedCourse < len(m.courses) {
+		courseID := m.courses[m.selectedCourse].ID
+		courseName := m.courses[m.selectedCourse].Code
+		m.setLoadingState(T("loading.attendance", courseName), T("loading.attendance_help"), T("loading.help_back_courses"))
+		m.loadingTask = attendanceTask(courseID)
+		m.pushView(LoadingView)
+		return m, tea.Batch(m.spinner.Tick, attendanceCmd(m.session, courseID))
+	}
+	return m, nil
+}
+
+// openAssessments fetches and shows the selected course's assessments.
+func (m model) openAssessments() (tea.Model, tea.Cmd) {
+	if len(m.courses) > 0 && m.selectedCourse < len(m.courses) {
+		courseID := m.courses[m.selectedCourse].ID
+		courseName := m.courses[m.selectedCourse].Code
+		m.setLoadingState(T("loading.assessments", courseName), T("loading.assessments_help"), T("loading.help_back_courses"))
+		m.pushView(LoadingView)
+		return m, tea.Batch(m.spinner.Tick, assessmentsCmd(m.session, courseID))
 	}
 	return m, nil
 }
@@ -561,13 +1186,16 @@ func (m *model) setLoadingState(reason, helpText, bottomText string) {
 		HelpText:   helpText,
 		BottomText: bottomText,
 	}
+	m.loadingTask = ""
+	m.loadingProgress = FetchProgress{}
+	m.loadingSince = time.Now()
 }
 
 func (m *model) resetToLogin() {
 	deleteCreds()
-	deleteTranscriptCache()
+	deleteCaches()
 	m.rememberMe = false
-	m.currentView = LoginView
+	m.resetViews(LoginView)
 	m.loginResult = nil
 	m.Credentials.StudentID = ""
 	m.Credentials.Password = ""
@@ -576,17 +1204,84 @@ func (m *model) resetToLogin() {
 	m.selectedCourse = 0
 	m.courseError = nil
 	m.session = nil
+	m.sessionNotice = ""
+	m.captchaSession = nil
+	m.coursesPending = false
+	m.awaitingCourses = false
+	m.transcriptReady = false
+	m.outlineCourse = ""
+	m.restore = nil
+	m.recovery = nil
+	deleteUIState()
+	m.jobs = nil
+	m.selectedJob = 0
+	m.refreshNotice = ""
 }
 
 func (m model) View() string {
-	switch m.currentView {
-	case LoginView:
-		return m.renderLogin()
-	case LoadingView:
-		return m.renderLoading()
-	case ResultView:
-		return m.renderResult()
-	case CoursesView:
+	top := m.header()
+	if top == "" {
+		return m.renderView()
+	}
+	m.height -= lipgloss.Height(top)
+	return lipgloss.JoinVertical(lipgloss.Left, top, m.renderView())
+}
+
+// header is the notices, breadcrumbs and tabs above the current view.
+func (m model) header() string {
+	var header []string
+	if m.updateNotice != "" {
+		header = append(header, lipgloss.NewStyle().Foreground(WARN).Render(m.updateNotice))
+	}
+	if m.sessionNotice != "" {
+		header = append(header, lipgloss.NewStyle().Foreground(DANGER).Render(m.sessionNotice))
+	}
+	if m.cgpaWarning != "" {
+		header = append(header, lipgloss.NewStyle().Foreground(DANGER).Bold(true).Render(m.cgpaWarning))
+	}
+	if m.browserNotice != "" {
+		header = append(header, lipgloss.NewStyle().Foreground(GREY).Render(m.browserNotice))
+	}
+	if m.snapshotNotice != "" {
+		header = append(header, lipgloss.NewStyle().Foreground(GREY).Render(m.snapshotNotice))
+	}
+	if banner := m.recoveryBanner(); banner != "" {
+		header = append(header, lipgloss.NewStyle().Foreground(WARN).Bold(true).Render(banner))
+	}
+	if diagnostics := m.renderDiagnostics(); diagnostics != "" {
+		header = append(header, diagnostics)
+	}
+	if active := m.activeJobs(); active > 0 {
+		header = append(header, lipgloss.NewStyle().Foreground(ACCENT).Render(m.spinner.View()+" "+T("refresh.active", active)))
+	} else if m.refreshNotice != "" {
+		header = append(header, lipgloss.NewStyle().Foreground(GREY).Render(m.refreshNotice))
+	}
+	if breadcrumbs := m.renderBreadcrumbs(); breadcrumbs != "" {
+		header = append(header, breadcrumbs)
+	}
+	if tabs := m.renderCourseTabs(); tabs != "" {
+		header = append(header, tabs)
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, header...)
+}
+
+// bodyHeight is the height left for the current view under the header.
+func (m model) bodyHeight() int {
+	if top := m.header(); top != "" {
+		return m.height - lipgloss.Height(top)
+	}
+	return m.height
+}
+
+func (m model) renderView() string {
+	switch m.currentView {
+	case LoginView:
+		return m.renderLogin()
+	case LoadingView:
+		return m.renderLoading()
+	case ResultView:
+		return m.renderResult()
+	case CoursesView:
 		return m.renderCourses()
 	case CourseDetailView:
 		return m.renderCourseDetail()
@@ -598,15 +1293,33 @@ func (m model) View() string {
 		return m.renderTranscript()
 	case ChatView:
 		return m.renderChat()
+	case OutlineView:
+		return m.renderOutline()
+	case ProvisionalResultView:
+		return m.renderProvisionalResult()
+	case FeesView:
+		return m.renderFees()
+	case CaptchaView:
+		return m.renderCaptcha()
+	case JobsView:
+		return m.renderJobs()
+	case RetakeView:
+		return m.renderRetake()
+	case GradeScaleView:
+		return m.renderGradeScale()
+	case LatencyView:
+		return m.renderLatency()
+	case SearchView:
+		return m.renderSearch()
 	default:
-		return "Unknown view"
+		return T("view.unknown")
 	}
 }
 
 func (m model) renderLogin() string {
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(LIGHT_BLUE).
+		Foreground(ACCENT).
 		MarginBottom(2)
 
 	labelStyle := lipgloss.NewStyle().
@@ -643,25 +1356,45 @@ func (m model) renderLogin() string {
 	helpStyle := lipgloss.NewStyle().
 		Foreground(GREY)
 
-	title := titleStyle.Render("UMT Portal TUI by Sunbreeze")
+	title := titleStyle.Render(T("login.title"))
+
+	errorStyle := lipgloss.NewStyle().
+		Foreground(DANGER).
+		Width(32).
+		MarginBottom(1)
+
+	// A field with an error shows it under the input, in place of the
+	// input's margin.
+	fieldStyles := func(problem string) (lipgloss.Style, lipgloss.Style) {
+		if problem == "" {
+			return inputStyle, focusedInputStyle
+		}
+		return inputStyle.UnsetMarginBottom().BorderForeground(DANGER), focusedInputStyle.UnsetMarginBottom()
+	}
+	field := func(label, input, problem string) string {
+		if problem == "" {
+			return lipgloss.JoinVertical(lipgloss.Left, labelStyle.Render(label), input)
+		}
+		return lipgloss.JoinVertical(lipgloss.Left, labelStyle.Render(label), input, errorStyle.Render("✗ "+problem))
+	}
 
 	var studentIDInput string
+	idStyle, focusedIDStyle := fieldStyles(m.studentIDError)
 	studentIDValue := m.Credentials.StudentID
 	if m.focusedField == fieldStudentID {
 		studentIDValue += "│"
-		studentIDInput = focusedInputStyle.Render(studentIDValue)
+		studentIDInput = focusedIDStyle.Render(studentIDValue)
 	} else {
 		if studentIDValue == "" {
-			studentIDValue = "Enter your student ID"
+			studentIDValue = T("login.student_id_placeholder")
 		}
-		studentIDInput = inputStyle.Render(studentIDValue)
+		studentIDInput = idStyle.Render(studentIDValue)
 	}
-
-	studentIDLabel := labelStyle.Render("Student ID:")
-	studentIDField := lipgloss.JoinVertical(lipgloss.Left, studentIDLabel, studentIDInput)
+	studentIDField := field(T("login.student_id"), studentIDInput, m.studentIDError)
 
 	var passwordInput string
 	var passwordValue string
+	passwordStyle, focusedPasswordStyle := fieldStyles(m.passwordError)
 	if m.showPassword {
 		passwordValue = m.Credentials.Password
 	} else {
@@ -669,16 +1402,14 @@ func (m model) renderLogin() string {
 	}
 	if m.focusedField == fieldPassword {
 		passwordValue += "│"
-		passwordInput = focusedInputStyle.Render(passwordValue)
+		passwordInput = focusedPasswordStyle.Render(passwordValue)
 	} else {
 		if len(m.Credentials.Password) == 0 {
-			passwordValue = "Enter your password"
+			passwordValue = T("login.password_placeholder")
 		}
-		passwordInput = inputStyle.Render(passwordValue)
+		passwordInput = passwordStyle.Render(passwordValue)
 	}
-
-	passwordLabel := labelStyle.Render("Password:")
-	passwordField := lipgloss.JoinVertical(lipgloss.Left, passwordLabel, passwordInput)
+	passwordField := field(T("login.password"), passwordInput, m.passwordError)
 
 	checkboxChar := "○"
 	if m.rememberMe {
@@ -687,26 +1418,42 @@ func (m model) renderLogin() string {
 
 	var rememberMeField string
 	if m.focusedField == fieldRememberMe {
-		rememberMeField = focusedStyle.Render(fmt.Sprintf("%s Remember me", checkboxChar))
+		if plainOutput {
+			checkboxChar = "→ " + checkboxChar
+		}
+		rememberMeField = focusedStyle.Render(T("login.remember_me", checkboxChar))
 	} else {
-		rememberMeField = checkboxStyle.Render(fmt.Sprintf("%s Remember me", checkboxChar))
+		rememberMeField = checkboxStyle.Render(T("login.remember_me", checkboxChar))
 	}
 
 	var loginButton string
 	if m.focusedField == fieldLoginButton {
-		loginButton = focusedButtonStyle.Render("Login")
+		label := T("login.button")
+		if plainOutput {
+			label = "→ " + label
+		}
+		loginButton = focusedButtonStyle.Render(label)
 	} else {
-		loginButton = buttonStyle.Render("Login")
+		loginButton = buttonStyle.Render(T("login.button"))
 	}
 
-	helpText := helpStyle.Render("• ↑/↓: Navigate • Esc: Show password • Enter/Space: Select • Ctrl+C/Q: Quit")
+	helpText := helpStyle.Render(T("login.help"))
 
 	content := lipgloss.JoinVertical(lipgloss.Center, title, studentIDField, passwordField, rememberMeField, loginButton, "", helpText)
 
+	if appConfig.InsecureSkipVerify {
+		warningStyle := lipgloss.NewStyle().Bold(true).Foreground(DANGER).MarginTop(1)
+		content = lipgloss.JoinVertical(lipgloss.Center, content, warningStyle.Render(T("login.insecure_warning")))
+	}
+
 	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, content)
 }
 
 func (m model) renderLoading() string {
+	if m.loadingProgress.Partial != nil {
+		return m.renderPartialAttendance()
+	}
+
 	reasonStyle := lipgloss.NewStyle().
 		Foreground(WHITE).
 		Bold(true).
@@ -720,14 +1467,33 @@ func (m model) renderLoading() string {
 		Foreground(GREY).
 		MarginTop(1)
 
+	progressStyle := lipgloss.NewStyle().
+		Foreground(ACCENT).
+		MarginTop(1)
+
 	spinnerView := m.spinner.View()
 
-	content := lipgloss.JoinVertical(lipgloss.Center,
+	parts := []string{
 		reasonStyle.Render(m.loadingState.Reason),
 		spinnerView,
 		helpStyle.Render(m.loadingState.HelpText),
-		quitStyle.Render(m.loadingState.BottomText),
-	)
+	}
+	if !m.loadingSince.IsZero() {
+		var status []string
+		if p := m.loadingProgress; p.RetryAt.After(time.Now()) {
+			status = append(status, T("progress.busy", p.RetryAt.Format("15:04:05")))
+		} else if p.Stage != "" {
+			status = append(status, T("progress.stage", T(p.Stage), p.Step, p.Steps))
+			if p.Attempt > 1 {
+				status = append(status, T("progress.attempt", p.Attempt, p.MaxAttempts))
+			}
+		}
+		status = append(status, formatElapsed(time.Since(m.loadingSince)))
+		parts = append(parts, progressStyle.Render(strings.Join(status, " • ")))
+	}
+	parts = append(parts, quitStyle.Render(m.loadingState.BottomText))
+
+	content := lipgloss.JoinVertical(lipgloss.Center, parts...)
 
 	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, content)
 }
@@ -737,26 +1503,34 @@ func (m model) renderResult() string {
 	var color lipgloss.Color
 
 	if m.courseError != nil {
-		color = RED
-		statusText = fmt.Sprintf("❌ Error: %s", m.courseError.Error())
+		color = DANGER
+		statusText = T("error", m.courseError)
 	} else if m.loginResult != nil {
 		switch m.loginResult.Code {
 		case ErrNone:
-			color = GREEN
-			statusText = "✅ You have successfully logged in to the UMT portal!\n"
+			color = SUCCESS
+			statusText = T("result.success")
 			m.session.loggedIn = true
 		case ErrNetworkIssue:
-			color = RED
-			statusText = "🌐 Network issue encountered! Please check your internet.\n"
+			color = DANGER
+			statusText = T("result.network")
 		case ErrInvalidCredentials:
-			color = RED
-			statusText = "❌ Invalid credentials! Please check your student ID and password.\n"
+			color = DANGER
+			statusText = T("result.invalid")
 		case ErrParsingError:
-			color = RED
-			statusText = "❓ Error parsing the response! Please try again later.\n"
+			color = DANGER
+			statusText = T("result.parse")
+		case ErrLockedOut:
+			if wait := time.Until(m.lockedUntil); wait > 0 {
+				color = WARN
+				statusText = T("result.locked", MAX_FAILED_LOGINS, formatCooldown(wait))
+			} else {
+				color = SUCCESS
+				statusText = T("result.unlocked")
+			}
 		default:
-			color = RED
-			statusText = "❓ An unknown error occurred! Please try again later.\n"
+			color = DANGER
+			statusText = T("result.unknown")
 		}
 	}
 
@@ -768,12 +1542,21 @@ func (m model) renderResult() string {
 
 	var helpText string
 	if m.loginResult != nil && m.loginResult.Code == ErrNone && m.courseError == nil {
-		helpText = helpStyle.Render("• Enter: Continue to courses • R: Retry • Q: Quit")
+		helpText = helpStyle.Render(T("result.help_success"))
 	} else {
-		helpText = helpStyle.Render("• R: Retry • Q: Quit")
+		helpText = helpStyle.Render(T("result.help_failure"))
 	}
 
-	content := lipgloss.JoinVertical(lipgloss.Center, responseStyle.Render(statusText), helpText)
+	parts := []string{responseStyle.Render(statusText)}
+	if m.loginResult != nil && m.loginResult.Code == ErrNone && m.courseError == nil {
+		if m.coursesPending {
+			parts = append(parts, helpStyle.Render(T("result.prefetching")))
+		} else if m.courses != nil {
+			parts = append(parts, lipgloss.NewStyle().Foreground(LIGHT_GREEN).Render(T("result.courses_ready", len(m.courses))))
+		}
+	}
+	parts = append(parts, helpText)
+	content := lipgloss.JoinVertical(lipgloss.Center, parts...)
 
 	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, content)
 }
@@ -781,7 +1564,7 @@ func (m model) renderResult() string {
 func (m model) renderCourses() string {
 
 	headerStyle := lipgloss.NewStyle().
-		Bold(true).Foreground(LIGHT_BLUE)
+		Bold(true).Foreground(ACCENT)
 
 	creditHoursStyle := headerStyle.Foreground(WHITE).UnsetBold()
 
@@ -808,10 +1591,10 @@ func (m model) renderCourses() string {
 	var studentInfo string
 	if m.session != nil {
 		studentInfo = fmt.Sprintf("%s, %s | %s | %s: %s",
-			headerStyle.Render("Welcome"),
+			headerStyle.Render(T("courses.welcome")),
 			turquoiseStyle.Render(student.Name),
 			lavenderStyle.Render(student.Program),
-			headerStyle.Render("CGPA"),
+			headerStyle.Render(T("courses.cgpa")),
 			lightGreenStyle.MarginBottom(1).Render(student.CgpaEarned),
 		)
 	}
@@ -820,10 +1603,10 @@ func (m model) renderCourses() string {
 	if m.session != nil {
 		creditHoursInfo = fmt.Sprintf(
 			"%s %s/%s | %s %s/%s",
-			creditHoursStyle.Render("C.Hrs. Registered:"),
+			creditHoursStyle.Render(T("courses.ch_registered")),
 			turquoiseStyle.UnsetBold().Render(student.RequestedCreditHours),
 			pinkStyle.UnsetBold().Render(student.MaxAllowedCreditHours),
-			creditHoursStyle.Render("C.Hrs. Earned:"),
+			creditHoursStyle.Render(T("courses.ch_earned")),
 			lightGreenStyle.UnsetBold().Render(student.CompletedCreditHours),
 			lavenderStyle.UnsetBold().MarginBottom(1).Render(student.RequiredCreditHours),
 		)
@@ -831,37 +1614,62 @@ func (m model) renderCourses() string {
 
 	if len(m.courses) == 0 {
 		noCoursesStyle := lipgloss.NewStyle().
-			Foreground(YELLOW)
+			Foreground(WARN)
 
 		content := lipgloss.JoinVertical(lipgloss.Center,
 			studentInfo,
 			creditHoursInfo,
-			noCoursesStyle.Render("No courses found."),
-			helpStyle.Render("• T: Transcript • C: AI Chat • R: Refresh • L: Log out • Q: Quit"),
+			noCoursesStyle.Render(T("courses.none")),
+			helpStyle.Render(T("courses.help_empty")),
 		)
 		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, content)
 	}
 
+	hiddenStyle := normalStyle.Foreground(GREY).Faint(true)
+
 	var courseList []string
-	for i, course := range m.courses {
-		courseText := fmt.Sprintf("%s - %s (%s CH)", course.Code, course.Title, course.CreditHours)
-		if i == m.selectedCourse {
-			courseList = append(courseList, selectedStyle.Render(fmt.Sprintf("→ %s", courseText)))
+	for n, i := range m.listedCourses() {
+		course := m.courses[i]
+		courseText := T("courses.item", course.Code, course.Title, course.CreditHours)
+		if n < len(SHIFTED_DIGITS) {
+			courseText = fmt.Sprintf("%d. %s", n+1, courseText)
 		} else {
+			courseText = "   " + courseText
+		}
+		hidden := m.hiddenCourses[course.ID]
+		if hidden {
+			courseText += " " + T("courses.hidden_tag")
+		}
+		switch {
+		case i == m.selectedCourse:
+			courseList = append(courseList, selectedStyle.Render(fmt.Sprintf("→ %s", courseText)))
+		case hidden:
+			courseList = append(courseList, hiddenStyle.Render(fmt.Sprintf("  %s", courseText)))
+		default:
 			courseList = append(courseList, normalStyle.Render(fmt.Sprintf("  %s", courseText)))
 		}
 	}
+	if len(courseList) == 0 {
+		courseList = append(courseList, lipgloss.NewStyle().Foreground(WARN).Render(T("courses.all_hidden", len(m.courses))))
+	} else if hidden := len(m.courses) - len(m.listedCourses()); hidden > 0 {
+		courseList = append(courseList, lipgloss.NewStyle().Foreground(GREY).Padding(0, 1).Render(T("courses.hidden_count", hidden)))
+	}
 
 	coursesDisplay := strings.Join(courseList, "\n")
+	if m.width >= SPLIT_MIN_WIDTH && len(m.listedCourses()) > 0 {
+		listWidth := lipgloss.Width(coursesDisplay)
+		panelWidth := min(m.width-listWidth-4, SPLIT_PANEL_MAX_WIDTH)
+		coursesDisplay = lipgloss.JoinHorizontal(lipgloss.Top, coursesDisplay, "  ", m.renderCoursePanel(panelWidth))
+	}
 
-	helpText := helpStyle.Render("• ↑/↓: Navigate • Enter: Details • T: Transcript • C: AI Chat • R: Refresh • L: Log out • Q: Quit")
+	helpText := helpStyle.Render(T("courses.help"))
 
-	content := lipgloss.JoinVertical(lipgloss.Center,
-		studentInfo,
-		creditHoursInfo,
-		coursesDisplay,
-		helpText,
-	)
+	parts := []string{studentInfo, creditHoursInfo, coursesDisplay}
+	if deadlines := m.renderDeadlines(5); deadlines != "" {
+		parts = append(parts, deadlines)
+	}
+	parts = append(parts, helpText)
+	content := lipgloss.JoinVertical(lipgloss.Center, parts...)
 
 	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, content)
 }
@@ -875,12 +1683,12 @@ func (m model) renderCourseDetail() string {
 
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(LIGHT_BLUE).
+		Foreground(ACCENT).
 		MarginBottom(1)
 
 	labelStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(LIGHT_BLUE)
+		Foreground(ACCENT)
 
 	valueStyle := lipgloss.NewStyle().
 		Foreground(WHITE)
@@ -888,46 +1696,214 @@ func (m model) renderCourseDetail() string {
 	helpStyle := lipgloss.NewStyle().
 		Foreground(GREY).MarginTop(1)
 
-	title := titleStyle.Render(fmt.Sprintf("📖 Course Details: %s", course.Code))
+	title := titleStyle.Render(T("detail.title", course.Code))
 
-	details := []string{
-		fmt.Sprintf("%s %s", labelStyle.Render("Title:"), valueStyle.Render(course.Title)),
-		fmt.Sprintf("%s %s", labelStyle.Render("Credit Hours:"), valueStyle.Render(course.CreditHours)),
-		fmt.Sprintf("%s %s", labelStyle.Render("Type:"), valueStyle.Render(course.CourseType)),
-		fmt.Sprintf("%s %s", labelStyle.Render("Faculty:"), valueStyle.Render(course.FacultyName)),
-		fmt.Sprintf("%s %s", labelStyle.Render("Email:"), valueStyle.Render(course.FacultyEmail)),
-		fmt.Sprintf("%s %s", labelStyle.Render("Mode:"), valueStyle.Render(course.Mode)),
-		fmt.Sprintf("%s %s", labelStyle.Render("Section:"), valueStyle.Render(course.Section)),
-		fmt.Sprintf("%s %s", labelStyle.Render("Semester:"), valueStyle.Render(course.Semester)),
+	detailsDisplay := strings.Join(courseDetailLines(course, labelStyle, valueStyle, m.width-4), "\n")
+
+	helpText := helpStyle.Render(wrapHelp(T("detail.help"), m.width-4))
+
+	parts := []string{title, detailsDisplay}
+	if appConfig.LMS.configured() && m.lmsError == nil {
+		deadlineStyle := lipgloss.NewStyle().Foreground(SILVER)
+		lines := []string{labelStyle.MarginTop(1).Render(T("detail.deadlines"))}
+		courseDeadlines := deadlinesFor(m.lmsDeadlines, course.Code)
+		for _, d := range courseDeadlines {
+			lines = append(lines, deadlineStyle.Render("• "+d.Name+" · "+formatDue(d.Due)))
+		}
+		if len(courseDeadlines) == 0 {
+			lines = append(lines, deadlineStyle.Render(T("detail.no_deadlines")))
+		}
+		parts = append(parts, strings.Join(lines, "\n"))
 	}
+	if m.outlineStatus != "" {
+		parts = append(parts, lipgloss.NewStyle().Foreground(WARN).MarginTop(1).Render(m.outlineStatus))
+	}
+	parts = append(parts, helpText)
+	content := lipgloss.JoinVertical(lipgloss.Center, parts...)
 
-	detailsDisplay := strings.Join(details, "\n")
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, content)
+}
 
-	helpText := helpStyle.Render("• A: Get Attendance • S: Get Assessments • Esc: Back to courses • Q: Quit")
+// DETAIL_MIN_VALUE_WIDTH is as narrow as a wrapped detail value gets, even
+// if that overflows the terminal.
+const DETAIL_MIN_VALUE_WIDTH = 10
+
+// courseDetailLines lists the course's details, wrapping values to width.
+// A width of zero or less never wraps.
+func courseDetailLines(course Course, labelStyle, valueStyle lipgloss.Style, width int) []string {
+	return []string{
+		detailLine(T("detail.course_title"), course.Title, labelStyle, valueStyle, width),
+		detailLine(T("detail.credit_hours"), course.CreditHours, labelStyle, valueStyle, width),
+		detailLine(T("detail.type"), course.CourseType, labelStyle, valueStyle, width),
+		detailLine(T("detail.faculty"), course.FacultyName, labelStyle, valueStyle, width),
+		detailLine(T("detail.email"), course.FacultyEmail, labelStyle, valueStyle, width),
+		detailLine(T("detail.mode"), course.Mode, labelStyle, valueStyle, width),
+		detailLine(T("detail.section"), course.Section, labelStyle, valueStyle, width),
+		detailLine(T("detail.semester"), course.Semester, labelStyle, valueStyle, width),
+	}
+}
 
-	content := lipgloss.JoinVertical(lipgloss.Center,
-		title,
-		detailsDisplay,
-		helpText,
-	)
+// detailLine renders a label and its value, wrapping a value too long for
+// width onto lines indented to line up under its start.
+func detailLine(label, value string, labelStyle, valueStyle lipgloss.Style, width int) string {
+	label = labelStyle.Render(label) + " "
+	indent := lipgloss.Width(label)
+	if width <= 0 || indent+lipgloss.Width(value) <= width {
+		return label + valueStyle.Render(value)
+	}
+	lines := strings.Split(valueStyle.Width(max(width-indent, DETAIL_MIN_VALUE_WIDTH)).Render(value), "\n")
+	for i := 1; i < len(lines); i++ {
+		lines[i] = strings.Repeat(" ", indent) + lines[i]
+	}
+	return label + strings.Join(lines, "\n")
+}
 
-	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, content)
+// renderCoursePanel is the right-hand panel of the split course list: the
+// highlighted course's details and its cached attendance.
+func (m model) renderCoursePanel(width int) string {
+	course := m.courses[m.selectedCourse]
+
+	panelStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(BLUE).
+		Padding(0, 1).
+		Width(width)
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(ACCENT).
+		MarginBottom(1)
+
+	labelStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(ACCENT)
+
+	valueStyle := lipgloss.NewStyle().
+		Foreground(WHITE)
+
+	lines := []string{titleStyle.Render(course.Code)}
+	lines = append(lines, courseDetailLines(course, labelStyle, valueStyle, width-2)...)
+	lines = append(lines, "")
+
+	if course.TotalLectures == 0 {
+		lines = append(lines, lipgloss.NewStyle().Foreground(GREY).Render(T("courses.panel_no_attendance")))
+	} else {
+		color := SUCCESS
+		summary := T("report.attendance_summary", course.TotalLectures, course.AttendancePercentage)
+		if appConfig.belowAttendanceThreshold(course) {
+			color = DANGER
+			summary += " " + T("report.below_threshold", appConfig.attendanceThreshold(course.Code))
+		}
+		absences := 0
+		for _, a := range course.Attendance {
+			if !a.Attendance {
+				absences++
+			}
+		}
+		lines = append(lines,
+			lipgloss.NewStyle().Bold(true).Foreground(color).Render(summary),
+			valueStyle.Render(T("courses.panel_absences", absences)),
+		)
+	}
+
+	return panelStyle.Render(strings.Join(lines, "\n"))
 }
 
 const attendancePageSize = 10
 const assessmentPageSize = 10
 
+// The attendance and assessment reports are drawn at their full size when
+// the terminal allows and shrink with it: REPORT_CHROME_HEIGHT lines go to
+// the title, summary, borders and help around the rows, and the faculty and
+// assessment name columns narrow down to their minimum widths.
+const (
+	REPORT_CHROME_HEIGHT   = 14
+	REPORT_MIN_PAGE_SIZE   = 3
+	FACULTY_COLUMN_WIDTH   = 15
+	FACULTY_MIN_WIDTH      = 6
+	ATTENDANCE_FIXED_WIDTH = 34
+	ASSESSMENT_NAME_WIDTH  = 25
+	ASSESSMENT_NAME_MIN    = 10
+	ASSESSMENT_FIXED_WIDTH = 56
+)
+
+// reportPageSize is how many rows of a report fit in height, at most
+// pageSize. A zero height, before the first WindowSizeMsg, fits them all.
+func reportPageSize(pageSize, height int) int {
+	if height <= 0 {
+		return pageSize
+	}
+	return min(max(height-REPORT_CHROME_HEIGHT, REPORT_MIN_PAGE_SIZE), pageSize)
+}
+
+// fitWidth is full unless the terminal is narrower than the fixed part of a
+// table plus full, in which case the flexible column gets what is left.
+func fitWidth(full, minimum, fixed, width int) int {
+	if width <= 0 {
+		return full
+	}
+	return min(max(width-fixed, minimum), full)
+}
+
+// truncateText cuts s to width terminal cells, marking the cut with an
+// ellipsis. Wide characters count as two cells and combining marks as none.
+func truncateText(s string, width int) string {
+	if runewidth.StringWidth(s) <= width {
+		return s
+	}
+	if width <= 1 {
+		return runewidth.Truncate(s, max(width, 0), "")
+	}
+	return runewidth.Truncate(s, width, "…")
+}
+
+// padText pads s with spaces to width terminal cells, where fmt's %-*s
+// would count runes.
+func padText(s string, width int) string {
+	return runewidth.FillRight(s, width)
+}
+
+// fitText truncates or pads s to exactly width terminal cells.
+func fitText(s string, width int) string {
+	return padText(truncateText(s, width), width)
+}
+
+// wrapHelp breaks a "• key: action" help line between its entries so no
+// line is wider than width.
+func wrapHelp(help string, width int) string {
+	if width <= 0 || lipgloss.Width(help) <= width {
+		return help
+	}
+	var lines []string
+	line := ""
+	for _, entry := range strings.SplitAfter(help, " • ") {
+		if line != "" && lipgloss.Width(strings.TrimSpace(line+entry)) > width {
+			lines = append(lines, strings.TrimSuffix(line, " • "))
+			line = "• "
+		}
+		line += entry
+	}
+	return strings.Join(append(lines, line), "\n")
+}
+
 // view true = attendance view false = assessment
 func (m model) renderTable(view bool) string {
 	if len(m.courses) == 0 || m.selectedCourse >= len(m.courses) {
 		return m.renderCourses()
 	}
 
+	if view && m.disputePicker {
+		return m.renderDisputePicker()
+	}
+	if !view && m.recheckPicker && m.pickerAssessment < len(m.courses[m.selectedCourse].Assessment) {
+		return m.renderRecheckPicker()
+	}
+
 	course := m.courses[m.selectedCourse]
 
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(LIGHT_BLUE).
+		Foreground(ACCENT).
 		MarginBottom(1)
 
 	summaryStyle := lipgloss.NewStyle().
@@ -941,10 +1917,10 @@ func (m model) renderTable(view bool) string {
 		Padding(0, 1)
 
 	presentStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color(GREEN))
+		Foreground(SUCCESS)
 
 	absentStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color(PINK))
+		Foreground(DANGER)
 
 	neutralStyle := lipgloss.NewStyle().
 		Foreground(WHITE)
@@ -961,25 +1937,34 @@ func (m model) renderTable(view bool) string {
 		summaryColor lipgloss.Color
 	)
 
+	records := m.attendanceFilter.apply(course.Attendance)
+	grading := classGrading(appConfig.courseGrading(course.Code), course.Assessment)
+	grouped := assessmentRows(course.Assessment, grading)
 	if view {
-		titleString = "📊 Attendance"
-		totalRecords = len(course.Attendance)
+		titleString = T("report.attendance")
+		totalRecords = len(records)
 
+		below := appConfig.belowAttendanceThreshold(course)
 		switch {
+		case below:
+			summaryColor = DANGER
 		case course.AttendancePercentage >= 85:
-			summaryColor = lipgloss.Color(GREEN)
-		case course.AttendancePercentage >= 70:
-			summaryColor = lipgloss.Color(YELLOW)
+			summaryColor = SUCCESS
 		default:
-			summaryColor = lipgloss.Color(PINK)
+			summaryColor = WARN
 		}
 
-		summaryText = fmt.Sprintf("Total Lectures: %d | Attendance: %d%%",
-			course.TotalLectures, course.AttendancePercentage)
-		noDataText = "No attendance records available"
+		summaryText = T("report.attendance_summary", course.TotalLectures, course.AttendancePercentage)
+		if below {
+			summaryText += " " + T("report.below_threshold", appConfig.attendanceThreshold(course.Code))
+		}
+		noDataText = T("report.attendance_empty")
+		if len(course.Attendance) > 0 {
+			noDataText = T("attendance.filter_none")
+		}
 	} else {
-		titleString = "📝 Assessment"
-		totalRecords = len(course.Assessment)
+		titleString = T("report.assessment")
+		totalRecords = len(grouped)
 
 		var totalObtained, totalPossible float32
 		for _, assessment := range course.Assessment {
@@ -994,20 +1979,30 @@ func (m model) renderTable(view bool) string {
 
 		switch {
 		case percentage >= 85:
-			summaryColor = lipgloss.Color(GREEN)
+			summaryColor = SUCCESS
 		case percentage >= 70:
-			summaryColor = lipgloss.Color(YELLOW)
+			summaryColor = WARN
 		default:
-			summaryColor = lipgloss.Color(PINK)
+			summaryColor = DANGER
 		}
 
-		summaryText = fmt.Sprintf("Total Assessments: %d | Obtained: %.1f/%.1f (%.1f%%)",
-			len(course.Assessment), totalObtained, totalPossible, percentage)
-		noDataText = "No assessment records available"
+		summaryText = T("report.assessment_summary", len(course.Assessment), totalObtained, totalPossible, percentage)
+		if p, ok := grading.project(course.Assessment); ok {
+			summaryText += "\n" + T("assessment.weighted", p.Earned, p.Assessed, p.Percentage())
+			if letter, ok := grading.letterGrade(p.Percentage()); ok {
+				summaryText += " " + T("assessment.projected_grade", letter)
+			}
+		}
+		noDataText = T("report.assessment_empty")
 	}
 
-	title := titleStyle.Render(fmt.Sprintf("%s Report: %s", titleString, course.Code))
+	title := titleStyle.Render(T("report.title", titleString, course.Code))
 	summary := summaryStyle.Foreground(summaryColor).Render(summaryText)
+	if view {
+		if bar := m.renderAttendanceFilter(len(records), len(course.Attendance)); bar != "" {
+			summary = lipgloss.JoinVertical(lipgloss.Center, summary, bar)
+		}
+	}
 
 	if totalRecords == 0 {
 		noDataStyle := lipgloss.NewStyle().
@@ -1016,7 +2011,11 @@ func (m model) renderTable(view bool) string {
 			MarginBottom(2)
 
 		noData := noDataStyle.Render(noDataText)
-		helpText := helpStyle.Render("• Esc/Enter: Back • R: Refresh • Q: Quit")
+		helpText := helpStyle.Render(T("report.help_empty"))
+		if view && len(course.Attendance) > 0 {
+			title = lipgloss.JoinVertical(lipgloss.Center, title, summary)
+			helpText = helpStyle.Render(wrapHelp(T("attendance.help"), m.width-4))
+		}
 
 		content := lipgloss.JoinVertical(lipgloss.Center,
 			title,
@@ -1031,9 +2030,9 @@ func (m model) renderTable(view bool) string {
 	currentPage := m.currentAttendancePage
 
 	if view {
-		pageSize = attendancePageSize
+		pageSize = reportPageSize(attendancePageSize, m.height)
 	} else {
-		pageSize = assessmentPageSize
+		pageSize = reportPageSize(assessmentPageSize, m.height)
 	}
 
 	totalPages := (totalRecords + pageSize - 1) / pageSize
@@ -1049,27 +2048,27 @@ func (m model) renderTable(view bool) string {
 	var widths []int
 
 	if view {
-		headers := []string{headerStyle.Render("#") + strings.Repeat(" ", 3), headerStyle.Render("Date") + strings.Repeat(" ", 3), headerStyle.Render("Status") + strings.Repeat(" ", 2), headerStyle.Render("Faculty")}
+		headers := []string{headerStyle.Render(T("report.col_number")) + strings.Repeat(" ", 3), headerStyle.Render(T("report.col_date")) + strings.Repeat(" ", 3), headerStyle.Render(T("report.col_status")) + strings.Repeat(" ", 2), headerStyle.Render(T("report.col_faculty"))}
 
 		rows = append(rows, strings.Join(headers, " "))
 
-		widths = []int{3, 12, 8, 15}
+		widths = []int{3, 12, 8, fitWidth(FACULTY_COLUMN_WIDTH, FACULTY_MIN_WIDTH, ATTENDANCE_FIXED_WIDTH, m.width)}
 
 		separator := strings.Repeat("─", widths[0]+widths[1]+widths[2]+widths[3]+3)
 		rows = append(rows, neutralStyle.Render(separator))
 
-		for _, record := range course.Attendance[startIndex:endIndex] {
+		for _, record := range records[startIndex:endIndex] {
 			lectureNum := fmt.Sprintf("%-*d", widths[0], record.LectureNumber)
 			date := fmt.Sprintf("%-*s", widths[1], record.LectureDate)
 
 			var status string
 			if record.Attendance {
-				status = presentStyle.Render(fmt.Sprintf("%-*s", widths[2], "Present"))
+				status = presentStyle.Render(padText(T("report.present"), widths[2]))
 			} else {
-				status = absentStyle.Render(fmt.Sprintf("%-*s", widths[2], "Absent"))
+				status = absentStyle.Render(padText(T("report.absent"), widths[2]))
 			}
 
-			faculty := neutralStyle.Render(fmt.Sprintf("%-*s", widths[3], record.Faculty))
+			faculty := neutralStyle.Render(fitText(record.Faculty, widths[3]))
 
 			rows = append(rows, fmt.Sprintf("%s %s %s %s",
 				neutralStyle.Render(lectureNum),
@@ -1079,27 +2078,59 @@ func (m model) renderTable(view bool) string {
 			))
 		}
 	} else {
+		nameWidth := fitWidth(ASSESSMENT_NAME_WIDTH, ASSESSMENT_NAME_MIN, ASSESSMENT_FIXED_WIDTH, m.width)
 		headers := []string{
-			headerStyle.Render("Name") + strings.Repeat(" ", 15),
-			headerStyle.Render("Obtained") + strings.Repeat(" ", 3),
-			headerStyle.Render("Total") + strings.Repeat(" ", 2),
-			headerStyle.Render("Percentage") + strings.Repeat(" ", 4),
-			headerStyle.Render("Date"),
+			headerStyle.Render(T("report.col_name")) + strings.Repeat(" ", nameWidth-10),
+			headerStyle.Render(T("report.col_obtained")) + strings.Repeat(" ", 3),
+			headerStyle.Render(T("report.col_total")) + strings.Repeat(" ", 2),
+			headerStyle.Render(T("report.col_percentage")) + strings.Repeat(" ", 4),
+			headerStyle.Render(T("report.col_date")),
 		}
 
-		widths = []int{25, 15, 20, 10, 5}
+		widths = []int{nameWidth, 15, 20, 10, 5}
 
-		rows = append(rows, fmt.Sprintf("%-*s %-*s %-*s %-*s %-*s",
+		showClass := hasClassStats(course.Assessment)
+		header := fmt.Sprintf("%-*s %-*s %-*s %-*s %-*s",
 			widths[0], headers[0], widths[1], headers[1],
-			widths[2], headers[2], widths[3], headers[3], widths[4], headers[4]))
+			widths[2], headers[2], widths[3], headers[3], widths[4], headers[4])
+		if showClass {
+			header += strings.Repeat(" ", 8) + headerStyle.Render(T("report.col_class"))
+		}
+		rows = append(rows, header)
 
 		separator := strings.Repeat("─", widths[0]+widths[1]+widths[2]+widths[3]+widths[4])
 		rows = append(rows, neutralStyle.Render(separator))
 
-		for _, record := range course.Assessment[startIndex:endIndex] {
-			name := record.name
-			if len(name) > 20 {
-				name = name[:17] + "..."
+		groupStyle := lipgloss.NewStyle().
+			Bold(true).
+			Foreground(ACCENT)
+
+		for _, row := range grouped[startIndex:endIndex] {
+			if g := row.group; g != nil {
+				var percentage float32
+				if g.Total > 0 {
+					percentage = g.Obtained / g.Total * 100
+				}
+				label := T("assessment.category", T("assessment.category_"+g.Category), len(g.Assessments))
+				if n, ok := grading.Best[g.Category]; ok && n < len(g.Assessments) {
+					label = T("assessment.category_best", T("assessment.category_"+g.Category), n, len(g.Assessments))
+				}
+				rows = append(rows, groupStyle.Render(fmt.Sprintf("%s %-*s %-*s %-*s",
+					padText(truncateText(label, nameWidth-1), nameWidth),
+					10, fmt.Sprintf("%.1f", g.Obtained),
+					10, fmt.Sprintf("%.1f", g.Total),
+					12, fmt.Sprintf("%.1f%%", percentage))))
+				continue
+			}
+
+			record := row.assessment
+			name := padText("  "+truncateText(record.name, nameWidth-7), nameWidth)
+			if row.dropped {
+				droppedStyle := lipgloss.NewStyle().Foreground(GREY).Strikethrough(true)
+				rows = append(rows, droppedStyle.Render(fmt.Sprintf("%s %-*s %-*s %-*s", name,
+					10, fmt.Sprintf("%.1f", record.obtainedMarks), 10, fmt.Sprintf("%.1f", record.totalMarks), 12, T("assessment.dropped")))+
+					strings.Repeat(" ", 3)+record.assignedDate)
+				continue
 			}
 
 			obtained := fmt.Sprintf("%.1f", record.obtainedMarks)
@@ -1114,20 +2145,23 @@ func (m model) renderTable(view bool) string {
 			if percentage >= 85 {
 				percentageStr = presentStyle.Render(fmt.Sprintf("%-*s", widths[3], fmt.Sprintf("%.1f%%", percentage)))
 			} else if percentage >= 75 {
-				percentageStr = lipgloss.NewStyle().Foreground(YELLOW).Render(fmt.Sprintf("%-*s", widths[3], fmt.Sprintf("%.1f%%", percentage)))
+				percentageStr = lipgloss.NewStyle().Foreground(WARN).Render(fmt.Sprintf("%-*s", widths[3], fmt.Sprintf("%.1f%%", percentage)))
 			} else {
 				percentageStr = absentStyle.Render(fmt.Sprintf("%-*s", widths[3], fmt.Sprintf("%.1f%%", percentage)))
 			}
 
-			widths2 := []int{25, 10, 10, 12}
+			widths2 := []int{nameWidth, 10, 10, 12}
 
 			rowData := []string{
-				neutralStyle.Render(fmt.Sprintf("%-*s", widths2[0], name)),
+				neutralStyle.Render(name),
 				neutralStyle.Render(fmt.Sprintf("%-*s", widths2[1], obtained)),
 				neutralStyle.Render(fmt.Sprintf("%-*s", widths2[2], total)),
 				neutralStyle.Render(fmt.Sprintf("%-*s", widths2[3], percentageStr) + strings.Repeat(" ", 3)),
 				record.assignedDate,
 			}
+			if showClass {
+				rowData = append(rowData, "  "+neutralStyle.Render(classBar(record, CLASS_BAR_WIDTH))+" "+lipgloss.NewStyle().Foreground(GREY).Render(classComparison(record)))
+			}
 
 			rows = append(rows, strings.Join(rowData, " "))
 		}
@@ -1140,8 +2174,11 @@ func (m model) renderTable(view bool) string {
 
 	table := tableStyle.Render(strings.Join(rows, "\n"))
 
-	pageIndicator := helpStyle.Render(fmt.Sprintf("Page %d/%d • ←/→ to navigate", currentPage+1, totalPages))
-	helpText := helpStyle.Render("• Esc: Back • R: Refresh • Q: Quit")
+	pageIndicator := helpStyle.Render(T("report.page", currentPage+1, totalPages))
+	helpText := helpStyle.Render(wrapHelp(T("assessment.help"), m.width-4))
+	if view {
+		helpText = helpStyle.Render(wrapHelp(T("attendance.help"), m.width-4))
+	}
 
 	content := lipgloss.JoinVertical(lipgloss.Center,
 		title,
@@ -1150,54 +2187,70 @@ func (m model) renderTable(view bool) string {
 		pageIndicator,
 		helpText,
 	)
+	if !view && m.assessmentStatus != "" {
+		content = lipgloss.JoinVertical(lipgloss.Center, content, lipgloss.NewStyle().Foreground(WARN).MarginTop(1).Render(m.assessmentStatus))
+	}
+	if view && m.attendanceStatus != "" {
+		content = lipgloss.JoinVertical(lipgloss.Center, content, lipgloss.NewStyle().Foreground(WARN).MarginTop(1).Render(m.attendanceStatus))
+	}
 
 	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, content)
 }
 
 func (m *model) setTranscriptTable(t Transcript) {
+	m.updateCGPAWarning(t)
 	m.transcriptSemesters = parseAndSortSemesters(t.Semester)
 	m.table = m.initTranscriptTable(t)
 	m.currentSemester = 0
+	for i, key := range m.transcriptSemesters {
+		if m.restoreSemester != "" && key.semester.Name == m.restoreSemester {
+			m.currentSemester = i
+			break
+		}
+	}
+	m.restoreSemester = ""
 }
 
 func (m model) handleTranscriptKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.semesterPicker {
+		return m.handleSemesterPickerKeys(msg)
+	}
 	switch msg.String() {
 	case "ctrl+c", "q":
 		if !m.rememberMe {
-			deleteTranscriptCache()
+			deleteCaches()
 		}
 		return m, tea.Quit
 	case "esc":
-		if m.lastView == ChatView {
-			m.currentView = ChatView
-			m.lastView = 0 // Reset
-		} else {
-			m.currentView = CoursesView
-		}
+		m.goBack()
 
 	case "r":
-		m.setLoadingState("📄 Getting transcript, please wait", "Refreshing your transcript from the portal", "Esc: Back to courses• Q: Cancel and quit")
-		m.currentView = LoadingView
-		return m, tea.Batch(
-			m.spinner.Tick,
-			func() tea.Msg {
-				err := m.session.GetTranscript(true)
-				if err != nil {
-					m.session.Student.CgpaEarned = m.session.Student.Transcript.TotalCGPA
-					return CourseActionMsg{
-						Action:  "transcript",
-						Error:   err,
-						Success: false,
-					}
-				}
-				return CourseActionMsg{
-					Action:         "transcript",
-					Error:          nil,
-					Success:        true,
-					UpdatedCourses: m.session.Student.Courses,
-				}
-			},
-		)
+		return m.startRefresh(TASK_TRANSCRIPT, func(s *Session) error {
+			return s.GetTranscript(true)
+		})
+
+	case "s":
+		if len(m.transcriptSemesters) > 0 {
+			m.openSemesterPicker()
+		}
+	case "i":
+		if len(m.transcriptSemesters) > 0 {
+			if m.retakeTarget == "" {
+				m.retakeTarget = RETAKE_TARGETS[0]
+			}
+			m.selectedRetake = 0
+			m.pushView(RetakeView)
+		}
+	case "G":
+		m.openGradeScale("", appConfig.courseGrading(""))
+	case "p":
+		m.transcriptStatus = T("transcript.pdf_downloading")
+		return m, transcriptPDFCmd(m.session, downloadDir())
+	case "c":
+		if len(m.transcriptSemesters) > 0 {
+			st := m.session.GetStudent()
+			return m, copyToClipboard(transcriptText(st, st.Transcript), TranscriptCopiedMsg{})
+		}
 
 	case "left", "h":
 		if m.currentSemester > 0 {
@@ -1227,23 +2280,26 @@ func (m model) handleTranscriptKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 func (m model) renderTranscript() string {
 	if len(m.table) == 0 || len(m.transcriptSemesters) == 0 {
-		errorStyle := lipgloss.NewStyle().Foreground(RED)
-		content := errorStyle.Render("No transcript data available")
+		errorStyle := lipgloss.NewStyle().Foreground(DANGER)
+		content := errorStyle.Render(T("transcript.empty"))
 		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, content)
 	}
 
 	if m.currentSemester >= len(m.table) || m.currentSemester >= len(m.transcriptSemesters) {
 		m.currentSemester = 0
 	}
+	if m.semesterPicker {
+		return m.renderSemesterPicker()
+	}
 
 	headerStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(LIGHT_BLUE).
+		Foreground(ACCENT).
 		MarginBottom(1).
 		Align(lipgloss.Center)
 
 	currentSem := m.transcriptSemesters[m.currentSemester].semester
-	semesterInfo := fmt.Sprintf("📄 Academic Transcript - %s", currentSem.Name)
+	semesterInfo := T("transcript.title", currentSem.Name)
 
 	statsStyle := lipgloss.NewStyle().
 		Foreground(WHITE).
@@ -1261,24 +2317,25 @@ func (m model) renderTranscript() string {
 	cgpaStr := fmt.Sprintf("%.2f", currentSem.CGPA)
 
 	stats := fmt.Sprintf("%s %s | %s %s | %s %s",
-		statsStyle.Render("C.Hrs. Earned:"),
+		statsStyle.Render(T("transcript.ch_earned")),
 		turquoiseStyle.Render(creditHoursStr),
-		statsStyle.Render("SGPA:"),
+		statsStyle.Render(T("transcript.sgpa")),
 		lavenderStyle.Render(sgpaStr),
-		statsStyle.Render("CGPA:"),
+		statsStyle.Render(T("transcript.cgpa")),
 		lightGreenStyle.MarginBottom(1).Render(cgpaStr),
 	)
 
+	transcript := m.session.GetStudent().Transcript
 	totalStats := fmt.Sprintf(
 		"%s %s | %s %s | %s %s | %s %s/%s",
-		statsStyle.Render("C.Hrs. Earned:"),
-		turquoiseStyle.Render(m.session.Student.Transcript.CreditHoursEarned),
-		statsStyle.Render("C.Hrs. for GPA:"),
-		lavenderStyle.Render(m.session.Student.Transcript.CreditHoursForGPA),
-		statsStyle.Render("Total G.P:"),
-		turquoiseStyle.Render(m.session.Student.Transcript.TotalGradePoints),
-		statsStyle.Render("CGPA:"),
-		lightGreenStyle.Render(m.session.Student.Transcript.TotalCGPA),
+		statsStyle.Render(T("transcript.ch_earned")),
+		turquoiseStyle.Render(transcript.CreditHoursEarned),
+		statsStyle.Render(T("transcript.ch_gpa")),
+		lavenderStyle.Render(transcript.CreditHoursForGPA),
+		statsStyle.Render(T("transcript.total_gp")),
+		turquoiseStyle.Render(transcript.TotalGradePoints),
+		statsStyle.Render(T("transcript.cgpa")),
+		lightGreenStyle.Render(transcript.TotalCGPA),
 		pinkStyle.Render("4.00"),
 	)
 
@@ -1287,17 +2344,48 @@ func (m model) renderTranscript() string {
 		MarginBottom(1).
 		Align(lipgloss.Center)
 
-	navIndicator := fmt.Sprintf("Semester %d of %d", m.currentSemester+1, len(m.transcriptSemesters))
+	navIndicator := T("transcript.semester_of", m.currentSemester+1, len(m.transcriptSemesters))
+	if !m.transcriptSemesters[m.currentSemester].parsed {
+		navIndicator += lipgloss.NewStyle().Foreground(WARN).Render(T("transcript.unrecognized"))
+	}
 
 	helpStyle := lipgloss.NewStyle().
 		Foreground(GREY).
 		MarginTop(1).
 		Align(lipgloss.Center)
 
-	helpText := "• ← →: Switch semesters • ↑ ↓: Navigate • Esc: Back • R: Refresh • Q: Quit"
+	helpText := wrapHelp(T("transcript.help"), m.width-4)
 
 	currentTable := m.table[m.currentSemester].View()
 
+	// The table styles no single row but the selected one, so the other
+	// rows without grade points are dimmed in its output.
+	dimStyle := lipgloss.NewStyle().Foreground(GREY).Faint(true)
+	tableLines := strings.Split(currentTable, "\n")
+	for i, line := range tableLines {
+		if strings.Contains(line, NO_GPA_MARK) && !strings.Contains(line, "\x1b[") {
+			tableLines[i] = dimStyle.Render(line)
+		}
+	}
+	currentTable = strings.Join(tableLines, "\n")
+
+	var repeats, noPoints bool
+	for _, c := range transcript.Semester[currentSem] {
+		repeats = repeats || c.Retake || c.Superseded
+		noPoints = noPoints || noGradePoints(c.Grade)
+	}
+	var legend []string
+	if repeats {
+		legend = append(legend, T("transcript.legend"))
+	}
+	if noPoints {
+		legend = append(legend, T("transcript.legend_no_gpa", NO_GPA_MARK))
+	}
+	if len(legend) > 0 {
+		legendStyle := lipgloss.NewStyle().Foreground(GREY)
+		currentTable = lipgloss.JoinVertical(lipgloss.Left, currentTable, legendStyle.Render(wrapHelp(strings.Join(legend, " • "), m.width-4)))
+	}
+
 	content := lipgloss.JoinVertical(lipgloss.Center,
 		headerStyle.Render(semesterInfo),
 		statsStyle.Render(stats),
@@ -1306,66 +2394,59 @@ func (m model) renderTranscript() string {
 		totalStatsStyle.Render(totalStats),
 		helpStyle.Render(helpText),
 	)
+	if m.transcriptStatus != "" {
+		content = lipgloss.JoinVertical(lipgloss.Center, content, lipgloss.NewStyle().Foreground(WARN).MarginTop(1).Render(m.transcriptStatus))
+	}
 
 	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, content)
 }
 
 func (m model) handleAttendanceKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.editingFilter {
+		return m.handleAttendanceFilterKeys(msg)
+	}
+	if m.disputePicker {
+		return m.handleDisputeKeys(msg)
+	}
 	switch msg.String() {
 	case "ctrl+c", "q":
 		if !m.rememberMe {
-			deleteTranscriptCache()
+			deleteCaches()
 		}
 		return m, tea.Quit
 	case "esc":
-		if m.lastView == ChatView {
-			m.currentView = ChatView
-			m.lastView = 0 // Reset
-		} else {
-			m.currentView = CourseDetailView
-		}
+		m.goBack()
 	case "r":
 		if len(m.courses) > 0 && m.selectedCourse < len(m.courses) {
 			courseID := m.courses[m.selectedCourse].ID
-			courseName := m.courses[m.selectedCourse].Code
-			m.setLoadingState(fmt.Sprintf("📊 Getting attendance for %s...", courseName), "Refreshing attendance record", "• Esc: Back to courses • Q: Cancel and quit")
-			m.currentView = LoadingView
-			return m, tea.Batch(
-				m.spinner.Tick,
-				func() tea.Msg {
-					err := m.session.GetCourseAttendance(true, courseID)
-					if err != nil {
-						return CourseActionMsg{
-							Action:   "attendance",
-							CourseID: courseID,
-							Error:    err,
-							Success:  false,
-						}
-					}
-					for i, c := range m.session.Student.Courses {
-						if c.ID == courseID {
-							m.selectedCourse = i
-							break
-						}
-					}
-					return CourseActionMsg{
-						Action:         "attendance",
-						CourseID:       courseID,
-						Error:          nil,
-						Success:        true,
-						UpdatedCourses: m.session.Student.Courses,
-					}
-				},
-			)
+			return m.startRefresh(attendanceTask(courseID), func(s *Session) error {
+				return s.GetCourseAttendance(true, courseID)
+			})
 		}
 
-	case "right", "l":
+	case "/":
+		m.editingFilter = true
+		m.filterInput = m.attendanceFilter.input
+		m.filterError = ""
+	case "a":
+		m.attendanceFilter.absentOnly = !m.attendanceFilter.absentOnly
+		m.currentAttendancePage = 0
+	case "m":
 		if len(m.courses) > 0 && m.selectedCourse < len(m.courses) {
-			course := m.courses[m.selectedCourse]
-			totalPages := (len(course.Attendance) + attendancePageSize - 1) / attendancePageSize
-			if m.currentAttendancePage < totalPages-1 {
-				m.currentAttendancePage++
-			}
+			m.attendanceFilter = m.attendanceFilter.nextMonth(m.courses[m.selectedCourse].Attendance)
+			m.currentAttendancePage = 0
+		}
+	case "x":
+		m.attendanceFilter = attendanceFilter{}
+		m.currentAttendancePage = 0
+	case "d":
+		m.openDisputePicker()
+
+	case "right", "l":
+		pageSize := reportPageSize(attendancePageSize, m.bodyHeight())
+		totalPages := (len(m.filteredAttendance()) + pageSize - 1) / pageSize
+		if m.currentAttendancePage < totalPages-1 {
+			m.currentAttendancePage++
 		}
 	case "left", "h":
 		if m.currentAttendancePage > 0 {
@@ -1377,58 +2458,48 @@ func (m model) handleAttendanceKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m model) handleAssessmentKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.recheckPicker {
+		return m.handleRecheckKeys(msg)
+	}
 	switch msg.String() {
 	case "ctrl+c", "q":
 		if !m.rememberMe {
-			deleteTranscriptCache()
+			deleteCaches()
 		}
 		return m, tea.Quit
 	case "esc":
-		if m.lastView == ChatView {
-			m.currentView = ChatView
-			m.lastView = 0 // Reset
-		} else {
-			m.currentView = CourseDetailView
-		}
+		m.goBack()
 	case "r":
 		if len(m.courses) > 0 && m.selectedCourse < len(m.courses) {
 			courseID := m.courses[m.selectedCourse].ID
-			courseName := m.courses[m.selectedCourse].Code
-			m.setLoadingState(fmt.Sprintf("📝 Getting assessments for %s...", courseName), "Refreshing assessment records", "• Esc: Back to courses • Q: Cancel and quit")
-			m.currentView = LoadingView
-			return m, tea.Batch(
-				m.spinner.Tick,
-				func() tea.Msg {
-					err := m.session.GetCourseAssessments(courseID)
-					if err != nil {
-						return CourseActionMsg{
-							Action:   "assessments",
-							CourseID: courseID,
-							Error:    err,
-							Success:  false,
-						}
-					}
-					for i, c := range m.session.Student.Courses {
-						if c.ID == courseID {
-							m.selectedCourse = i
-							break
-						}
-					}
-					return CourseActionMsg{
-						Action:         "assessments",
-						CourseID:       courseID,
-						Error:          nil,
-						Success:        true,
-						UpdatedCourses: m.session.Student.Courses,
-					}
-				},
-			)
+			return m.startRefresh(assessmentsTask(courseID), func(s *Session) error {
+				return s.GetCourseAssessments(courseID)
+			})
 		}
 
+	case "e":
+		if len(m.courses) > 0 && m.selectedCourse < len(m.courses) {
+			course := m.courses[m.selectedCourse]
+			export := buildAssessmentExport(course, classGrading(appConfig.courseGrading(course.Code), course.Assessment))
+			return m, copyToClipboard(export.Markdown(), AssessmentsCopiedMsg{})
+		}
+	case "E":
+		if len(m.courses) > 0 && m.selectedCourse < len(m.courses) {
+			return m, assessmentsHTMLCmd(m.courses[m.selectedCourse], downloadDir())
+		}
+	case "G":
+		if len(m.courses) > 0 && m.selectedCourse < len(m.courses) {
+			course := m.courses[m.selectedCourse]
+			m.openGradeScale(course.Code, classGrading(appConfig.courseGrading(course.Code), course.Assessment))
+		}
+	case "d":
+		m.openRecheckPicker()
+
 	case "right", "l":
 		if len(m.courses) > 0 && m.selectedCourse < len(m.courses) {
 			course := m.courses[m.selectedCourse]
-			totalPages := (len(course.Assessment) + assessmentPageSize - 1) / assessmentPageSize
+			pageSize := reportPageSize(assessmentPageSize, m.bodyHeight())
+			totalPages := (len(assessmentRows(course.Assessment, appConfig.courseGrading(course.Code))) + pageSize - 1) / pageSize
 			if m.currentAttendancePage < totalPages-1 {
 				m.currentAttendancePage++
 			}
@@ -1447,13 +2518,8 @@ func (m model) initTranscriptTable(t Transcript) []table.Model {
 
 	semesterKeys := parseAndSortSemesters(t.Semester)
 
-	columns := []table.Column{
-		{Title: "Code", Width: 8},
-		{Title: "Course Title", Width: 62},
-		{Title: "Cr. Hrs", Width: 7},
-		{Title: "Grade", Width: 6},
-		{Title: "G.P.", Width: 6},
-	}
+	columns := transcriptColumns(m.width)
+	height := m.bodyHeight()
 
 	for _, sk := range semesterKeys {
 		sem := sk.semester
@@ -1461,21 +2527,32 @@ func (m model) initTranscriptTable(t Transcript) []table.Model {
 
 		courses := t.Semester[sem]
 		for _, c := range courses {
+			title := c.Title
+			switch {
+			case c.Superseded:
+				title = "⊘ " + title
+			case noGradePoints(c.Grade):
+				title = NO_GPA_MARK + " " + title
+			case c.Retake:
+				title = "↻ " + title
+			}
+			gradePoint := fmt.Sprintf("%.2f", c.GradePoint)
+			if noGradePoints(c.Grade) {
+				gradePoint = "—"
+			}
 			rows = append(rows, table.Row{
 				c.Code,
-				c.Title,
+				title,
 				fmt.Sprintf("%d", c.CreditHours),
 				c.Grade,
-				fmt.Sprintf("%.2f", c.GradePoint),
+				gradePoint,
 			})
 		}
 
-		tableHeight := min(max(len(rows)+1, 5), 15)
-
 		tbl := table.New(
 			table.WithColumns(columns),
 			table.WithRows(rows),
-			table.WithHeight(tableHeight),
+			table.WithHeight(transcriptTableHeight(len(rows), height)),
 			table.WithFocused(true),
 		)
 
@@ -1496,3 +2573,731 @@ func (m model) initTranscriptTable(t Transcript) []table.Model {
 
 	return tables
 }
+
+// The transcript's title column takes what the code, credit, grade and
+// G.P. columns (TRANSCRIPT_FIXED_WIDTH with cell padding) leave of the
+// terminal, up to TRANSCRIPT_TITLE_WIDTH; TRANSCRIPT_CHROME_HEIGHT lines go
+// to the semester stats and help around the table.
+const (
+	TRANSCRIPT_TITLE_WIDTH   = 62
+	TRANSCRIPT_TITLE_MIN     = 12
+	TRANSCRIPT_FIXED_WIDTH   = 39
+	TRANSCRIPT_CHROME_HEIGHT = 12
+)
+
+func transcriptColumns(width int) []table.Column {
+	return []table.Column{
+		{Title: T("transcript.col_code"), Width: 8},
+		{Title: T("transcript.col_title"), Width: fitWidth(TRANSCRIPT_TITLE_WIDTH, TRANSCRIPT_TITLE_MIN, TRANSCRIPT_FIXED_WIDTH, width)},
+		{Title: T("transcript.col_credits"), Width: 7},
+		{Title: T("transcript.col_grade"), Width: 6},
+		{Title: T("transcript.col_gp"), Width: 6},
+	}
+}
+
+func transcriptTableHeight(rows, height int) int {
+	h := min(max(rows+1, 5), 15)
+	if height > 0 {
+		h = min(h, max(height-TRANSCRIPT_CHROME_HEIGHT, 5))
+	}
+	return h
+}
+
+// resizeTables fits the transcript tables to the terminal after a resize;
+// the reports are laid out on every render and need nothing.
+func (m *model) resizeTables() {
+	columns, height := transcriptColumns(m.width), m.bodyHeight()
+	for i := range m.table {
+		m.table[i].SetColumns(columns)
+		m.table[i].SetHeight(transcriptTableHeight(len(m.table[i].Rows()), height))
+	}
+}
+
+const outlineMaxWidth = 100
+
+// outlineWrapped wraps the outline text to the view width so scrolling moves
+// by screen lines.
+func (m model) outlineWrapped() []string {
+	width := min(m.width-8, outlineMaxWidth)
+	wrapStyle := lipgloss.NewStyle().Width(max(width, 20))
+	var lines []string
+	for _, line := range m.outlineLines {
+		lines = append(lines, strings.Split(wrapStyle.Render(line), "\n")...)
+	}
+	return lines
+}
+
+func (m model) outlinePageSize() int {
+	return max(m.height-10, 5)
+}
+
+func (m model) handleOutlineKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	maxOffset := max(len(m.outlineWrapped())-m.outlinePageSize(), 0)
+	switch msg.String() {
+	case "ctrl+c", "q":
+		if !m.rememberMe {
+			deleteCaches()
+		}
+		return m, tea.Quit
+	case "esc":
+		m.outlineStatus = ""
+		m.goBack()
+	case "up", "k":
+		m.outlineOffset--
+	case "down", "j":
+		m.outlineOffset++
+	case "pgup", "b":
+		m.outlineOffset -= m.outlinePageSize()
+	case "pgdown", "f", " ":
+		m.outlineOffset += m.outlinePageSize()
+	case "home", "g":
+		m.outlineOffset = 0
+	case "end", "G":
+		m.outlineOffset = maxOffset
+	case "d":
+		path, err := saveCourseOutline(m.outline, downloadDir())
+		if err != nil {
+			m.outlineStatus = T("error", err)
+		} else {
+			m.outlineStatus = T("outline.saved", path)
+		}
+	}
+	m.outlineOffset = min(max(m.outlineOffset, 0), maxOffset)
+	return m, nil
+}
+
+func (m model) renderOutline() string {
+	if len(m.courses) == 0 || m.selectedCourse >= len(m.courses) {
+		return m.renderCourses()
+	}
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(ACCENT).
+		MarginBottom(1)
+
+	textStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(BLUE).
+		Padding(0, 2).
+		Foreground(WHITE)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(GREY).
+		MarginTop(1)
+
+	title := titleStyle.Render(T("outline.title", m.courses[m.selectedCourse].Code))
+
+	lines := m.outlineWrapped()
+	start := min(m.outlineOffset, len(lines))
+	end := min(start+m.outlinePageSize(), len(lines))
+	text := textStyle.Render(strings.Join(lines[start:end], "\n"))
+
+	position := lipgloss.NewStyle().Foreground(GREY).Render(T("outline.position", start+1, end, len(lines)))
+
+	parts := []string{title, text, position}
+	if m.outlineStatus != "" {
+		parts = append(parts, lipgloss.NewStyle().Foreground(WARN).Render(m.outlineStatus))
+	}
+	parts = append(parts, helpStyle.Render(T("outline.help")))
+
+	content := lipgloss.JoinVertical(lipgloss.Center, parts...)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, content)
+}
+
+func formatDue(due time.Time) string {
+	return due.Format("Mon 02 Jan 15:04")
+}
+
+// renderDeadlines lists the next few LMS deadlines for the course list. It
+// is empty when the LMS is not configured or nothing is pending.
+func (m model) renderDeadlines(limit int) string {
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(ACCENT).
+		MarginTop(1)
+
+	itemStyle := lipgloss.NewStyle().
+		Foreground(SILVER)
+
+	soonStyle := lipgloss.NewStyle().
+		Foreground(WARN)
+
+	if m.lmsError != nil {
+		return lipgloss.NewStyle().Foreground(DANGER).MarginTop(1).Render(T("courses.lms_error", m.lmsError))
+	}
+	if len(m.lmsDeadlines) == 0 {
+		return ""
+	}
+
+	lines := []string{titleStyle.Render(T("courses.deadlines"))}
+	for i, d := range m.lmsDeadlines {
+		if i == limit {
+			break
+		}
+		course := d.CourseCode
+		if course == "" {
+			course = d.Course
+		}
+		style := itemStyle
+		if time.Until(d.Due) < 48*time.Hour {
+			style = soonStyle
+		}
+		lines = append(lines, style.Render(T("lms.deadline", course, d.Name, formatDue(d.Due))))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m model) handleProvisionalResultKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		if !m.rememberMe {
+			deleteCaches()
+		}
+		return m, tea.Quit
+	case "esc":
+		m.goBack()
+	case "r":
+		m.setLoadingState(T("loading.results"), T("loading.results_help"), T("loading.help_back"))
+		m.pushView(LoadingView)
+		return m, tea.Batch(m.spinner.Tick, resultsCmd(m.session))
+	}
+	return m, nil
+}
+
+func (m model) renderProvisionalResult() string {
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(ACCENT).
+		MarginBottom(1)
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(WHITE).
+		Background(BLUE)
+
+	rowStyle := lipgloss.NewStyle().
+		Foreground(SILVER)
+
+	pendingStyle := lipgloss.NewStyle().
+		Foreground(GREY).
+		Italic(true)
+
+	summaryStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(LIGHT_GREEN).
+		MarginTop(1)
+
+	noteStyle := lipgloss.NewStyle().
+		Foreground(WARN)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(GREY).
+		MarginTop(1)
+
+	result := m.provisionalResult
+	semester := result.Semester
+	if semester == "" {
+		semester = T("nav.results")
+	}
+	title := titleStyle.Render(T("results.title", semester))
+
+	if !result.Posted() {
+		content := lipgloss.JoinVertical(lipgloss.Center,
+			title,
+			noteStyle.Render(T("results.not_posted")),
+			helpStyle.Render(T("results.help")),
+		)
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, content)
+	}
+
+	format := "%-10s %s %7s %6s %6s"
+	rows := []string{headerStyle.Render(fmt.Sprintf(format,
+		T("transcript.col_code"), fitText(T("transcript.col_title"), 36), T("transcript.col_credits"), T("transcript.col_grade"), T("transcript.col_gp")))}
+	graded := 0
+	for _, c := range result.Courses {
+		title := fitText(c.Title, 36)
+		if c.Grade == "" {
+			rows = append(rows, pendingStyle.Render(fmt.Sprintf(format, c.Code, title, strconv.Itoa(c.CreditHours), T("results.pending"), "")))
+			continue
+		}
+		graded++
+		rows = append(rows, rowStyle.Render(fmt.Sprintf(format, c.Code, title, strconv.Itoa(c.CreditHours), c.Grade, fmt.Sprintf("%.2f", c.GradePoint))))
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Center,
+		title,
+		strings.Join(rows, "\n"),
+		summaryStyle.Render(T("results.sgpa", result.SGPA(), graded, len(result.Courses))),
+		noteStyle.Render(T("results.note")),
+		helpStyle.Render(T("results.help")),
+	)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, content)
+}
+
+func (m model) handleFeesKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.savingChallan {
+		switch msg.Type {
+		case tea.KeyEsc:
+			m.savingChallan = false
+		case tea.KeyEnter:
+			m.savingChallan = false
+			if m.selectedFee >= len(m.fees) {
+				break
+			}
+			challan, dir := m.fees[m.selectedFee], strings.TrimSpace(m.challanDir)
+			m.feeStatus = T("fees.downloading", challan.Number)
+			return m, challanCmd(m.session, challan, dir)
+		case tea.KeyBackspace:
+			if len(m.challanDir) > 0 {
+				runes := []rune(m.challanDir)
+				m.challanDir = string(runes[:len(runes)-1])
+			}
+		case tea.KeyRunes, tea.KeySpace:
+			m.challanDir += string(msg.Runes)
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "ctrl+c", "q":
+		if !m.rememberMe {
+			deleteCaches()
+		}
+		return m, tea.Quit
+	case "esc":
+		m.feeStatus = ""
+		m.goBack()
+	case "up", "k":
+		if m.selectedFee > 0 {
+			m.selectedFee--
+		}
+	case "down", "j":
+		if m.selectedFee < len(m.fees)-1 {
+			m.selectedFee++
+		}
+	case "p":
+		switch {
+		case m.showQR || m.selectedFee >= len(m.fees):
+			m.showQR = false
+		case m.fees[m.selectedFee].Paid():
+			m.feeStatus = T("fees.qr_paid", m.fees[m.selectedFee].Number)
+		default:
+			m.showQR = true
+			m.feeStatus = ""
+		}
+	case "d":
+		if m.selectedFee < len(m.fees) {
+			m.savingChallan = true
+			m.challanDir = downloadDir()
+			m.feeStatus = ""
+		}
+	case "r":
+		m.setLoadingState(T("loading.fees"), T("loading.fees_help"), T("loading.help_back"))
+		m.pushView(LoadingView)
+		return m, tea.Batch(m.spinner.Tick, feesCmd(m.session))
+	}
+	return m, nil
+}
+
+func (m model) renderFees() string {
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(ACCENT).
+		MarginBottom(1)
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(WHITE).
+		Background(BLUE)
+
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(WHITE).
+		Background(BLUE)
+
+	normalStyle := lipgloss.NewStyle().
+		Foreground(SILVER)
+
+	paidStyle := lipgloss.NewStyle().
+		Foreground(SUCCESS)
+
+	unpaidStyle := lipgloss.NewStyle().
+		Foreground(DANGER)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(GREY).
+		MarginTop(1)
+
+	title := titleStyle.Render(T("fees.title"))
+
+	if len(m.fees) == 0 {
+		content := lipgloss.JoinVertical(lipgloss.Center,
+			title,
+			lipgloss.NewStyle().Foreground(WARN).Render(T("fees.none")),
+			helpStyle.Render(T("fees.help")),
+		)
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, content)
+	}
+
+	var summary string
+	if dues := outstandingDues(m.fees); dues > 0 {
+		summary = unpaidStyle.Bold(true).Render(T("fees.outstanding", formatAmount(dues)))
+		var plan []string
+		for _, in := range splitInstallments(dues, appConfig.installmentDates(), time.Now()) {
+			plan = append(plan, T("fees.installment", formatAmount(in.Amount), in.Due.Format("2 Jan 2006")))
+		}
+		if len(plan) > 0 {
+			summary += "\n" + normalStyle.Render(wrapHelp(T("fees.installments", strings.Join(plan, " • ")), m.width-4))
+		}
+	} else {
+		summary = paidStyle.Bold(true).Render(T("fees.clear"))
+	}
+
+	format := "  %-14s %s %s %12s %-12s %s"
+	rows := []string{headerStyle.Render(fmt.Sprintf(format,
+		T("fees.col_challan"), fitText(T("fees.col_semester"), 12), fitText(T("fees.col_desc"), 30), T("fees.col_amount"), T("fees.col_due"), fitText(T("fees.col_status"), 8)))}
+	for i, c := range m.fees {
+		line := fmt.Sprintf(format, c.Number, fitText(c.Semester, 12), fitText(c.Description, 30), formatAmount(c.Amount), c.DueDate, fitText(c.Status, 8))
+		switch {
+		case i == m.selectedFee:
+			rows = append(rows, selectedStyle.Render("→"+line[1:]))
+		case c.Paid():
+			rows = append(rows, normalStyle.Render(line))
+		default:
+			rows = append(rows, unpaidStyle.Render(line))
+		}
+	}
+
+	parts := []string{title, summary, "", strings.Join(rows, "\n")}
+	if c := m.fees[min(m.selectedFee, len(m.fees)-1)]; m.showQR && !c.Paid() {
+		if code, err := renderQR(c.PaymentReference()); err != nil {
+			parts = append(parts, unpaidStyle.MarginTop(1).Render(err.Error()))
+		} else {
+			parts = append(parts,
+				lipgloss.NewStyle().Foreground(WHITE).MarginTop(1).Render(code),
+				normalStyle.Render(T("fees.qr_caption", c.PaymentReference(), formatAmount(c.Amount))))
+		}
+	}
+	if m.savingChallan {
+		inputStyle := lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(BLUE).
+			Padding(0, 1)
+		parts = append(parts, inputStyle.Render(T("fees.save_prompt")+m.challanDir+"│"), helpStyle.Render(T("fees.help_prompt")))
+	} else {
+		if m.feeStatus != "" {
+			parts = append(parts, lipgloss.NewStyle().Foreground(WARN).MarginTop(1).Render(m.feeStatus))
+		}
+		parts = append(parts, helpStyle.Render(T("fees.help")))
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Center, parts...)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, content)
+}
+
+func (m model) handleJobsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		if !m.rememberMe {
+			deleteCaches()
+		}
+		return m, tea.Quit
+	case "esc":
+		m.goBack()
+	case "up", "k":
+		if m.selectedJob > 0 {
+			m.selectedJob--
+		}
+	case "down", "j":
+		if m.selectedJob < len(m.jobs)-1 {
+			m.selectedJob++
+		}
+	case "x":
+		if m.selectedJob < len(m.jobs) {
+			m.cancelJob(m.jobs[m.selectedJob].ID)
+		}
+	case "r":
+		if m.selectedJob < len(m.jobs) {
+			queued := len(m.jobs)
+			cmd := m.retryJob(m.jobs[m.selectedJob].ID)
+			if len(m.jobs) > queued {
+				m.selectedJob = len(m.jobs) - 1
+			}
+			return m, cmd
+		}
+	case "c":
+		m.clearFinishedJobs()
+	}
+	return m, nil
+}
+
+func (m model) renderJobs() string {
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(ACCENT).
+		MarginBottom(1)
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(WHITE).
+		Background(BLUE)
+
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(WHITE).
+		Background(BLUE)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(GREY).
+		MarginTop(1)
+
+	title := titleStyle.Render(T("jobs.title"))
+
+	if len(m.jobs) == 0 {
+		content := lipgloss.JoinVertical(lipgloss.Center,
+			title,
+			lipgloss.NewStyle().Foreground(WARN).Render(T("jobs.none")),
+			helpStyle.Render(T("jobs.help")),
+		)
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, content)
+	}
+
+	format := "  %s %s %8s  %s"
+	rows := []string{headerStyle.Render(fmt.Sprintf(format,
+		fitText(T("jobs.col_job"), 28), fitText(T("jobs.col_state"), 10), T("jobs.col_time"), fitText(T("jobs.col_detail"), 40)))}
+	for i, j := range m.jobs {
+		var state, elapsed, detail string
+		color := SILVER
+		switch j.State {
+		case JobQueued:
+			state, color = T("jobs.queued"), GREY
+		case JobRunning:
+			state, color = T("jobs.running"), ACCENT
+			elapsed = formatElapsed(time.Since(j.Started))
+		case JobDone:
+			state, color = T("jobs.done"), SUCCESS
+			elapsed = formatElapsed(j.Finished.Sub(j.Started))
+		case JobFailed:
+			state, color = T("jobs.failed"), DANGER
+			elapsed = formatElapsed(j.Finished.Sub(j.Started))
+			detail = j.Err.Error()
+		case JobCancelled:
+			state, color = T("jobs.cancelled"), WARN
+		}
+		line := fmt.Sprintf(format, fitText(m.jobLabel(j), 28), fitText(state, 10), elapsed, fitText(detail, 40))
+		if i == m.selectedJob {
+			rows = append(rows, selectedStyle.Render("→"+line[1:]))
+		} else {
+			rows = append(rows, lipgloss.NewStyle().Foreground(color).Render(line))
+		}
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Center,
+		title,
+		strings.Join(rows, "\n"),
+		helpStyle.Render(T("jobs.help")),
+	)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, content)
+}
+
+func lockoutTick() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg { return lockoutTickMsg{} })
+}
+
+func keepaliveTick(id int) tea.Cmd {
+	return tea.Tick(keepaliveInterval, func(time.Time) tea.Msg { return keepaliveDueMsg{ID: id} })
+}
+
+func (m model) handleCaptchaKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.captchaSession = nil
+		m.goBack()
+	case tea.KeyEnter, tea.KeyCtrlR:
+		session, creds, code := m.captchaSession, m.Credentials, strings.TrimSpace(m.captchaInput)
+		if session == nil || (msg.Type == tea.KeyEnter && code == "") {
+			return m, nil
+		}
+		if msg.Type == tea.KeyCtrlR {
+			code = ""
+		}
+		m.submitted = true
+		m.setLoadingState(T("loading.login"), T("loading.login_help"), T("loading.help_quit"))
+		m.currentView = LoadingView
+		return m, tea.Batch(m.spinner.Tick, captchaLoginCmd(session, creds, code, m.rememberMe))
+	case tea.KeyBackspace:
+		if len(m.captchaInput) > 0 {
+			m.captchaInput = m.captchaInput[:len(m.captchaInput)-1]
+		}
+	case tea.KeyRunes:
+		m.captchaInput += string(msg.Runes)
+	}
+	return m, nil
+}
+
+func (m model) renderCaptcha() string {
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(ACCENT).
+		MarginBottom(1)
+
+	labelStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(WHITE).
+		MarginTop(1)
+
+	inputStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(BLUE).
+		Padding(0, 1).
+		Width(30)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(GREY).
+		MarginTop(1)
+
+	var image string
+	if m.captchaSession != nil && m.captchaSession.Captcha() != nil {
+		width := CAPTCHA_WIDTH
+		if m.width > 4 {
+			width = min(width, m.width-4)
+		}
+		color := !plainOutput && lipgloss.ColorProfile() != termenv.Ascii
+		image = renderCaptcha(m.captchaSession.Captcha(), width, color)
+	} else {
+		image = lipgloss.NewStyle().Foreground(WARN).Render(T("captcha.unreadable"))
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Center,
+		titleStyle.Render(T("captcha.title")),
+		image,
+		labelStyle.Render(T("captcha.prompt")),
+		inputStyle.Render(m.captchaInput+"│"),
+		helpStyle.Render(T("captcha.help")),
+	)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, content)
+}
+
+// startRefresh queues fetch as a background job while the current view
+// keeps showing cached data.
+func (m model) startRefresh(task string, fetch func(s *Session) error) (tea.Model, tea.Cmd) {
+	cmd := m.enqueueJob(task, fetch)
+	return m, cmd
+}
+
+// RETAKE_TARGETS are the grades the retake analysis can assume, best first.
+var RETAKE_TARGETS = []string{"A", "A-", "B+", "B", "B-", "C+", "C"}
+
+func (m model) handleRetakeKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	target := max(slices.Index(RETAKE_TARGETS, m.retakeTarget), 0)
+	switch msg.String() {
+	case "ctrl+c", "q":
+		if !m.rememberMe {
+			deleteCaches()
+		}
+		return m, tea.Quit
+	case "esc":
+		m.goBack()
+	case "up", "k":
+		if m.selectedRetake > 0 {
+			m.selectedRetake--
+		}
+	case "down", "j":
+		m.selectedRetake++
+	case "left", "h":
+		m.retakeTarget = RETAKE_TARGETS[max(target-1, 0)]
+	case "right", "l":
+		m.retakeTarget = RETAKE_TARGETS[min(target+1, len(RETAKE_TARGETS)-1)]
+	}
+	if m.session != nil {
+		options := retakeOptions(m.session.GetStudent().Transcript, m.retakeTarget)
+		m.selectedRetake = max(min(m.selectedRetake, len(options)-1), 0)
+	}
+	return m, nil
+}
+
+func (m model) renderRetake() string {
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(ACCENT).
+		MarginBottom(1)
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(WHITE).
+		Background(BLUE)
+
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(WHITE).
+		Background(BLUE)
+
+	normalStyle := lipgloss.NewStyle().
+		Foreground(SILVER)
+
+	summaryStyle := lipgloss.NewStyle().
+		Foreground(WHITE).
+		MarginBottom(1)
+
+	totalStyle := lipgloss.NewStyle().
+		Foreground(LIGHT_GREEN).
+		Bold(true).
+		MarginTop(1)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(GREY).
+		MarginTop(1)
+
+	title := titleStyle.Render(T("retake.title"))
+
+	var transcript Transcript
+	if m.session != nil {
+		transcript = m.session.GetStudent().Transcript
+	}
+	creditHours, points := transcript.gpaTotals()
+	var cgpa float64
+	if creditHours > 0 {
+		cgpa = points / float64(creditHours)
+	}
+	summary := summaryStyle.Render(T("retake.summary", cgpa, m.retakeTarget))
+
+	options := retakeOptions(transcript, m.retakeTarget)
+	if len(options) == 0 {
+		content := lipgloss.JoinVertical(lipgloss.Center,
+			title,
+			summary,
+			lipgloss.NewStyle().Foreground(SUCCESS).Render(T("retake.none")),
+			helpStyle.Render(T("retake.help")),
+		)
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, content)
+	}
+
+	format := "  %-8s %s %s %4s %-5s %8s %7s %8s"
+	rows := []string{headerStyle.Render(fmt.Sprintf(format,
+		T("retake.col_code"), fitText(T("retake.col_title"), 30), fitText(T("retake.col_semester"), 12), T("retake.col_credits"),
+		T("retake.col_grade"), T("retake.col_cgpa"), T("retake.col_delta"), T("retake.col_per_credit")))}
+	gained := 0.0
+	for i, o := range options {
+		gained += o.Delta
+		line := fmt.Sprintf(format, o.Course.Code, fitText(o.Course.Title, 30), fitText(o.Semester, 12),
+			strconv.Itoa(o.Course.CreditHours), o.Course.Grade,
+			fmt.Sprintf("%.2f", o.NewCGPA), fmt.Sprintf("+%.3f", o.Delta), fmt.Sprintf("+%.4f", o.DeltaPerCreditHour))
+		if i == m.selectedRetake {
+			rows = append(rows, selectedStyle.Render("→"+line[1:]))
+		} else {
+			rows = append(rows, normalStyle.Render(line))
+		}
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Center,
+		title,
+		summary,
+		strings.Join(rows, "\n"),
+		totalStyle.Render(T("retake.all", len(options), cgpa+gained, gained)),
+		helpStyle.Render(T("retake.help")),
+	)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, content)
+}
@@ -0,0 +1,654 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+func TestPlainOutputHasNoEscapes(t *testing.T) {
+	prevProfile, prevPlain := lipgloss.ColorProfile(), plainOutput
+	t.Cleanup(func() {
+		lipgloss.SetColorProfile(prevProfile)
+		plainOutput = prevPlain
+	})
+
+	m := model{currentView: LoginView, focusedField: fieldLoginButton, width: 80, height: 24}
+
+	lipgloss.SetColorProfile(termenv.TrueColor)
+	if styled := m.View(); !strings.Contains(styled, "\x1b[") {
+		t.Fatal("expected ANSI escapes with a TrueColor profile")
+	}
+
+	enablePlainOutput()
+	plain := m.View()
+	if strings.Contains(plain, "\x1b") {
+		t.Errorf("plain output contains escape sequences:\n%q", plain)
+	}
+	if !strings.Contains(plain, "→ "+T("login.button")) {
+		t.Errorf("plain output does not mark the focused login button:\n%s", plain)
+	}
+}
+
+func TestProvisionalResultView(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	useMockPortal(t, portal)
+
+	s := NewSession()
+	if code, text := s.Login(Credentials{StudentID: "F2023000000", Password: "hunter2"}, false); code != ErrNone {
+		t.Fatalf("login failed: %v %s", code, text)
+	}
+	s.loggedIn = true
+
+	m := model{session: s, currentView: CoursesView, width: 120, height: 40}
+	next, _ := m.handleCoursesKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")})
+	m = next.(model)
+	if m.currentView != LoadingView {
+		t.Fatalf("expected loading view, got %v", m.currentView)
+	}
+	next, _ = m.Update(resultsCmd(s)())
+	m = next.(model)
+	if m.currentView != ProvisionalResultView {
+		t.Fatalf("expected results view, got %v", m.currentView)
+	}
+
+	view := m.View()
+	for _, want := range []string{"Fall 2025", "A-", T("results.pending"), "3.50"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("results view is missing %q:\n%s", want, view)
+		}
+	}
+
+	next, _ = m.handleProvisionalResultKeys(tea.KeyMsg{Type: tea.KeyEsc})
+	if next.(model).currentView != CoursesView {
+		t.Errorf("esc should return to the course list")
+	}
+}
+
+func TestPostLoginPrefetch(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	useMockPortal(t, portal)
+
+	s := NewSession()
+	if code, text := s.Login(Credentials{StudentID: "F2023000000", Password: "hunter2"}, false); code != ErrNone {
+		t.Fatalf("login failed: %v %s", code, text)
+	}
+
+	m := model{width: 120, height: 40}
+	next, _ := m.Update(LoginResultMsg{Code: ErrNone, Session: s})
+	m = next.(model)
+
+	// Both fetches run at once, as the batched commands would.
+	msgs := make(chan tea.Msg, 2)
+	go func() { msgs <- prefetchCoursesCmd(s)() }()
+	go func() { msgs <- prefetchTranscriptCmd(s)() }()
+
+	// Continuing before the courses arrive waits on the prefetch instead of
+	// starting another fetch.
+	next, cmd := m.handleResultKeys(tea.KeyMsg{Type: tea.KeyEnter})
+	m = next.(model)
+	if m.currentView != LoadingView || !m.awaitingCourses || cmd == nil {
+		t.Fatalf("expected to wait on the prefetch, got view %v", m.currentView)
+	}
+
+	for range 2 {
+		next, _ = m.Update(<-msgs)
+		m = next.(model)
+	}
+	if m.currentView != CoursesView || len(m.courses) != 3 {
+		t.Fatalf("expected the courses view with 3 courses, got view %v with %d", m.currentView, len(m.courses))
+	}
+	if !m.transcriptReady {
+		t.Error("transcript was not prefetched")
+	}
+	if got := portal.Hits("GET /MyCourses"); got != 1 {
+		t.Errorf("courses fetched %d times, want 1", got)
+	}
+}
+
+func TestAttendanceCmdFailures(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	portal.incompleteReports = 100
+	useMockPortal(t, portal)
+	s := NewSession()
+	if code, text := s.Login(Credentials{StudentID: "F2023000000", Password: "hunter2"}, false); code != ErrNone {
+		t.Fatalf("login failed: %v %s", code, text)
+	}
+	courses, err := s.GetCourses()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A report that never finishes loading opens an empty view.
+	if msg, ok := attendanceCmd(s, courses[0].ID)().(CourseActionMsg); !ok || !msg.Success {
+		t.Errorf("incomplete report: got %#v, want success", msg)
+	}
+	if msg, ok := attendanceCmd(s, "no-such-course")().(CourseActionMsg); !ok || msg.Error == nil {
+		t.Errorf("unknown course: got %#v, want an error", msg)
+	}
+	if msg, ok := assessmentsCmd(s, "no-such-course")().(CourseActionMsg); !ok || msg.Error == nil {
+		t.Errorf("unknown course: got %#v, want an error", msg)
+	}
+}
+
+func TestBackgroundRefresh(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	useMockPortal(t, portal)
+
+	s := NewSession()
+	if code, text := s.Login(Credentials{StudentID: "F2023000000", Password: "hunter2"}, false); code != ErrNone {
+		t.Fatalf("login failed: %v %s", code, text)
+	}
+	courses, err := s.GetCourses()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := model{session: s, courses: courses, selectedCourse: 1, currentView: AttendanceView, viewStack: []ViewType{CoursesView, CourseDetailView}, width: 120, height: 40}
+	next, cmd := m.handleAttendanceKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	m = next.(model)
+	if m.currentView != AttendanceView {
+		t.Fatalf("refresh left the attendance view for %v", m.currentView)
+	}
+	if !strings.Contains(m.View(), T("refresh.active", 1)) {
+		t.Error("no refreshing indicator")
+	}
+
+	// Pressing R again while the refresh runs does not start another.
+	if _, again := m.handleAttendanceKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")}); again != nil {
+		t.Error("a second refresh was started")
+	}
+
+	var refreshed tea.Msg
+	for _, c := range cmd().(tea.BatchMsg) {
+		if msg, ok := c().(JobFinishedMsg); ok {
+			refreshed = msg
+		}
+	}
+	next, _ = m.Update(refreshed)
+	m = next.(model)
+	if m.activeJobs() != 0 || m.currentView != AttendanceView {
+		t.Fatalf("refresh did not finish in place: %v %v", m.jobs, m.currentView)
+	}
+	if got := m.courses[m.selectedCourse]; got.ID != courses[1].ID || len(got.Attendance) == 0 {
+		t.Errorf("attendance of %s not updated", courses[1].Code)
+	}
+}
+
+func TestQuickSelectCourses(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	useMockPortal(t, portal)
+
+	s := NewSession()
+	if code, text := s.Login(Credentials{StudentID: "F2023000000", Password: "hunter2"}, false); code != ErrNone {
+		t.Fatalf("login failed: %v %s", code, text)
+	}
+	courses, err := s.GetCourses()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := model{session: s, courses: courses, currentView: CoursesView, width: 120, height: 40}
+	key := func(k string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)} }
+
+	if next, _ := m.handleCoursesKeys(key("9")); next.(model).currentView != CoursesView {
+		t.Error("a number past the last course opened a view")
+	}
+
+	next, _ := m.handleCoursesKeys(key("2"))
+	if got := next.(model); got.currentView != CourseDetailView || got.selectedCourse != 1 {
+		t.Fatalf("2 opened view %v for course %d", got.currentView, got.selectedCourse)
+	}
+
+	next, cmd := m.handleCoursesKeys(key("@"))
+	for _, c := range cmd().(tea.BatchMsg) {
+		if msg, ok := c().(CourseActionMsg); ok {
+			next, _ = next.(model).Update(msg)
+		}
+	}
+	m = next.(model)
+	if m.currentView != AttendanceView || m.selectedCourse != 1 || len(m.courses[1].Attendance) == 0 {
+		t.Fatalf("Shift+2 opened view %v for course %d", m.currentView, m.selectedCourse)
+	}
+	if crumbs := m.renderBreadcrumbs(); !strings.Contains(crumbs, courses[1].Code) {
+		t.Errorf("breadcrumbs do not lead through the course: %s", crumbs)
+	}
+
+	// S on the highlighted course skips its detail view.
+	m.goBack()
+	m.goBack()
+	next, cmd = m.handleCoursesKeys(key("s"))
+	for _, c := range cmd().(tea.BatchMsg) {
+		if msg, ok := c().(CourseActionMsg); ok {
+			next, _ = next.(model).Update(msg)
+		}
+	}
+	if m = next.(model); m.currentView != AssessmentView || m.selectedCourse != 1 {
+		t.Fatalf("S opened view %v for course %d", m.currentView, m.selectedCourse)
+	}
+}
+
+func TestSplitCourseList(t *testing.T) {
+	s := NewSession()
+	s.Student.Courses = []Course{
+		{ID: "1", Code: "CC1021", Title: "Programming Fundamentals", FacultyName: "Dr. Ayesha Khan"},
+		{ID: "2", Code: "CC2042", Title: "Database Systems", FacultyName: "Mr. Bilal Ahmed", TotalLectures: 4, AttendancePercentage: 75,
+			Attendance: []Attendance{{Attendance: true}, {Attendance: true}, {Attendance: true}, {}}},
+	}
+	m := model{session: s, courses: s.Student.Courses, currentView: CoursesView, width: 100, height: 40}
+
+	if view := m.View(); strings.Contains(view, "Dr. Ayesha Khan") {
+		t.Error("narrow terminal shows the detail panel")
+	}
+
+	m.width = 180
+	if view := m.View(); !strings.Contains(view, "Dr. Ayesha Khan") || !strings.Contains(view, T("courses.panel_no_attendance")) {
+		t.Errorf("panel does not show the highlighted course:\n%s", view)
+	}
+
+	next, _ := m.handleCoursesKeys(tea.KeyMsg{Type: tea.KeyDown})
+	view := next.(model).View()
+	for _, want := range []string{"Mr. Bilal Ahmed", T("courses.panel_absences", 1)} {
+		if !strings.Contains(view, want) {
+			t.Errorf("panel did not follow the cursor, missing %q:\n%s", want, view)
+		}
+	}
+}
+
+func TestCourseDetailWraps(t *testing.T) {
+	course := Course{ID: "1", Code: "CC1021", Title: "Introduction to Information and Communication Technologies Lab",
+		FacultyName: "Dr. Muhammad Abdullah Bin Tariq Siddiqui", Semester: "Fall 2024"}
+
+	indent := lipgloss.Width(T("detail.course_title")) + 1
+	for _, detail := range courseDetailLines(course, lipgloss.NewStyle(), lipgloss.NewStyle(), 36) {
+		for i, line := range strings.Split(detail, "\n") {
+			if w := lipgloss.Width(line); w > 36 {
+				t.Errorf("detail line is %d wide at 36 columns: %q", w, line)
+			}
+			if i > 0 && strings.HasPrefix(detail, T("detail.course_title")) && (!strings.HasPrefix(line, strings.Repeat(" ", indent)) || line[indent] == ' ') {
+				t.Errorf("continuation %q is not indented %d under the value", line, indent)
+			}
+		}
+	}
+
+	m := model{courses: []Course{course}, currentView: CourseDetailView, width: 40, height: 40}
+	view := m.View()
+	for _, want := range []string{"Technologies Lab", "Tariq Siddiqui"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("%q was clipped:\n%s", want, view)
+		}
+	}
+}
+
+func TestCourseTabs(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	useMockPortal(t, portal)
+
+	s := NewSession()
+	if code, text := s.Login(Credentials{StudentID: "F2023000000", Password: "hunter2"}, false); code != ErrNone {
+		t.Fatalf("login failed: %v %s", code, text)
+	}
+	courses, err := s.GetCourses()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := model{session: s, courses: courses, currentView: CoursesView, width: 120, height: 40}
+
+	press := func(key tea.KeyMsg) {
+		t.Helper()
+		next, cmd := m.handleKeyPress(key)
+		m = next.(model)
+		if cmd == nil {
+			return
+		}
+		msg := cmd()
+		batch, ok := msg.(tea.BatchMsg)
+		if !ok {
+			batch = tea.BatchMsg{func() tea.Msg { return msg }}
+		}
+		for _, c := range batch {
+			if msg, ok := c().(CourseActionMsg); ok {
+				next, _ = m.Update(msg)
+				m = next.(model)
+			}
+		}
+	}
+	tab := tea.KeyMsg{Type: tea.KeyTab}
+
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	press(tab)
+	if m.currentView != AttendanceView || len(m.courses[0].Attendance) == 0 {
+		t.Fatalf("Tab from details opened %v", m.currentView)
+	}
+	press(tab)
+	if m.currentView != AssessmentView || len(m.viewStack) != 1 {
+		t.Fatalf("second Tab opened %v with stack %v", m.currentView, m.viewStack)
+	}
+	if view := m.View(); !strings.Contains(view, T("nav.details")) || !strings.Contains(view, T("nav.outline")) {
+		t.Errorf("no tab bar:\n%s", view)
+	}
+
+	// Tabs already loaded switch without fetching again.
+	hits := portal.Hits("POST /Reports/Attendance.aspx")
+	press(tea.KeyMsg{Type: tea.KeyShiftTab})
+	if m.currentView != AttendanceView || portal.Hits("POST /Reports/Attendance.aspx") != hits {
+		t.Errorf("Shift+Tab opened %v, refetching attendance", m.currentView)
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.currentView != CoursesView {
+		t.Errorf("Esc from a tab went to %v, want the course list", m.currentView)
+	}
+}
+
+func TestRestoreUIState(t *testing.T) {
+	portal := newMockPortal(t, "F2023000000", "hunter2")
+	useMockPortal(t, portal)
+
+	s := NewSession()
+	if code, text := s.Login(Credentials{StudentID: "F2023000000", Password: "hunter2"}, false); code != ErrNone {
+		t.Fatalf("login failed: %v %s", code, text)
+	}
+	s.loggedIn = true
+	courses, err := s.GetCourses()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := model{session: s, courses: courses, Credentials: Credentials{StudentID: "F2023000000"}, rememberMe: true,
+		currentView: LoadingView, viewStack: []ViewType{CoursesView, AssessmentView}, selectedCourse: 2, currentAttendancePage: 1}
+	if err := saveUIState(m); err != nil {
+		t.Fatal(err)
+	}
+	if readUIState("F2023999999") != nil {
+		t.Error("state restored for another student")
+	}
+
+	m = model{session: s, courses: courses, currentView: CoursesView, restore: readUIState("F2023000000")}
+	next, cmd := m.restoreUIState()
+	for _, c := range cmd().(tea.BatchMsg) {
+		if msg, ok := c().(CourseActionMsg); ok {
+			next, _ = next.(model).Update(msg)
+		}
+	}
+	m = next.(model)
+	if m.currentView != AssessmentView || m.selectedCourse != 2 || m.currentAttendancePage != 1 {
+		t.Fatalf("restored view %v, course %d, page %d", m.currentView, m.selectedCourse, m.currentAttendancePage)
+	}
+	if m.restore != nil {
+		t.Error("state is restored more than once")
+	}
+
+	m.rememberMe = false
+	saveUIState(m)
+	if readUIState("F2023000000") != nil {
+		t.Error("state kept for a login that is not remembered")
+	}
+}
+
+func TestNetworkRecovery(t *testing.T) {
+	drop := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	calls := 0
+	fetch := recoverable(func() tea.Msg {
+		calls++
+		if calls == 1 {
+			return ResultsLoadedMsg{Error: fmt.Errorf("failed to get results page: %w", drop)}
+		}
+		return ResultsLoadedMsg{Result: ProvisionalResult{Semester: "Fall 2025"}}
+	})
+
+	m := model{currentView: LoadingView, viewStack: []ViewType{CoursesView}, width: 120, height: 40}
+	next, _ := m.Update(fetch())
+	m = next.(model)
+	if m.currentView != LoadingView || m.courseError != nil {
+		t.Fatalf("network drop left the view for %v (%v)", m.currentView, m.courseError)
+	}
+	if view := m.View(); !strings.Contains(view, T("recovery.lost", "10s")) {
+		t.Errorf("no recovery banner:\n%s", view)
+	}
+
+	m.recovery.due = time.Now()
+	next, retry := m.Update(recoveryTickMsg{ID: m.recoveryID})
+	next, _ = next.(model).Update(retry())
+	m = next.(model)
+	if m.recovery != nil || m.currentView != ProvisionalResultView {
+		t.Fatalf("retry did not recover: view %v, recovery %+v", m.currentView, m.recovery)
+	}
+
+	// Once the retries run out, the failure is handled as before.
+	calls = 0
+	m = model{currentView: LoadingView, viewStack: []ViewType{CoursesView}, recovery: &networkRecovery{attempt: RECOVERY_MAX_ATTEMPTS}}
+	next, _ = m.Update(fetch())
+	if m = next.(model); m.currentView != CoursesView || m.courseError == nil || m.recovery != nil {
+		t.Errorf("gave up into view %v with error %v", m.currentView, m.courseError)
+	}
+}
+
+func TestResizeTables(t *testing.T) {
+	var courses []TranscriptCourse
+	for i := range 20 {
+		courses = append(courses, TranscriptCourse{Code: fmt.Sprintf("CS%d", 100+i), Title: strings.Repeat("Course title ", 6), CreditHours: 3, Grade: "A", GradePoint: 4})
+	}
+	transcript := Transcript{Semester: map[Semester][]TranscriptCourse{{Name: "Fall 2024"}: courses}}
+
+	m := model{currentView: TranscriptView, width: 120, height: 40}
+	m.setTranscriptTable(transcript)
+	if got := m.table[0].Columns()[1].Width; got != TRANSCRIPT_TITLE_WIDTH {
+		t.Fatalf("title column is %d wide at 120 columns", got)
+	}
+
+	next, _ := m.Update(tea.WindowSizeMsg{Width: 60, Height: 20})
+	m = next.(model)
+	if got := m.table[0].Columns()[1].Width; got != 60-TRANSCRIPT_FIXED_WIDTH {
+		t.Errorf("title column is %d wide at 60 columns", got)
+	}
+	if got := m.table[0].Height(); got > 20-TRANSCRIPT_CHROME_HEIGHT {
+		t.Errorf("table is %d rows high in a 20 row terminal", got)
+	}
+	for _, line := range strings.Split(m.table[0].View(), "\n") {
+		if w := lipgloss.Width(line); w > 60 {
+			t.Errorf("table line is %d wide in a 60 column terminal: %q", w, line)
+		}
+	}
+
+	if got := reportPageSize(attendancePageSize, 20); got != 20-REPORT_CHROME_HEIGHT {
+		t.Errorf("attendance shows %d rows in a 20 row terminal", got)
+	}
+}
+
+func TestNoGradePointRows(t *testing.T) {
+	transcript := Transcript{Semester: map[Semester][]TranscriptCourse{{Name: "Fall 2024"}: {
+		{Code: "CC1021", Title: "Programming Fundamentals", CreditHours: 3, Grade: "A", GradePoint: 4},
+		{Code: "MA1011", Title: "Calculus", CreditHours: 3, Grade: "w"},
+		{Code: "CS1002", Title: "Discrete Structures", CreditHours: 3, Grade: "I"},
+		{Code: "HU1001", Title: "Islamic Studies", CreditHours: 2, Grade: "P"},
+		{Code: "SS1012", Title: "Pakistan Studies", CreditHours: 2, Grade: "F"},
+	}}}
+	if creditHours, points := transcript.gpaTotals(); creditHours != 5 || points != 12 {
+		t.Errorf("GPA totals %d credit hours, %.2f points; want 5 and 12", creditHours, points)
+	}
+
+	s := NewSession()
+	s.Student.Transcript = transcript
+	m := model{session: s, currentView: TranscriptView, width: 120, height: 40}
+	m.setTranscriptTable(transcript)
+	rows := m.table[0].Rows()
+	for i, want := range []bool{false, true, true, true, false} {
+		if got := strings.HasPrefix(rows[i][1], NO_GPA_MARK); got != want {
+			t.Errorf("%s tagged %v", rows[i][0], got)
+		}
+		if got := rows[i][4] == "—"; got != want {
+			t.Errorf("%s grade point %q", rows[i][0], rows[i][4])
+		}
+	}
+	if view := m.View(); !strings.Contains(view, T("transcript.legend_no_gpa", NO_GPA_MARK)) {
+		t.Errorf("legend missing:\n%s", view)
+	}
+}
+
+func TestFitTextDisplayWidth(t *testing.T) {
+	for _, s := range []string{
+		"Calculus",
+		"Introducción a la Programación",
+		"Cafe\u0301 Culture and Society",
+		"日本語と文化の入門コース",
+		"اسلامیات اور مطالعہ پاکستان",
+	} {
+		for _, width := range []int{8, 15, 36} {
+			got := fitText(s, width)
+			if w := lipgloss.Width(got); w != width {
+				t.Errorf("fitText(%q, %d) = %q, %d cells wide", s, width, got, w)
+			}
+			if lipgloss.Width(s) > width && !strings.HasSuffix(strings.TrimRight(got, " "), "…") {
+				t.Errorf("fitText(%q, %d) = %q, cut without an ellipsis", s, width, got)
+			}
+		}
+	}
+}
+
+func TestSemesterPicker(t *testing.T) {
+	transcript := Transcript{Semester: map[Semester][]TranscriptCourse{}}
+	for i, name := range []string{"Fall 2021", "Spring 2022", "Fall 2022", "Spring 2023"} {
+		transcript.Semester[Semester{Name: name, SGPA: 3 + float32(i)/10, CGPA: 3.05}] = []TranscriptCourse{{Code: "CS100", Title: "Course", CreditHours: 3, Grade: "B", GradePoint: 3}}
+	}
+	m := model{currentView: TranscriptView, width: 120, height: 40}
+	m.setTranscriptTable(transcript)
+	m.currentSemester = 3
+
+	key := func(k string) {
+		t.Helper()
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		switch k {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "up":
+			msg = tea.KeyMsg{Type: tea.KeyUp}
+		}
+		next, _ := m.Update(msg)
+		m = next.(model)
+	}
+
+	key("s")
+	view := m.View()
+	for _, want := range []string{T("picker.title"), "Fall 2022", "3.20", "Spring 2023"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("picker is missing %q:\n%s", want, view)
+		}
+	}
+
+	key("up")
+	key("enter")
+	if m.semesterPicker || m.transcriptSemesters[m.currentSemester].semester.Name != "Fall 2022" {
+		t.Errorf("picked %q, picker open: %v", m.transcriptSemesters[m.currentSemester].semester.Name, m.semesterPicker)
+	}
+
+	if start, end := pickerWindow(30, 40, 12); start != 24 || end != 36 {
+		t.Errorf("window around 30 of 40 is %d-%d", start, end)
+	}
+}
+
+func TestAttendanceFilter(t *testing.T) {
+	course := Course{Code: "CS1001", Attendance: []Attendance{
+		{LectureNumber: 1, LectureDate: "26-Feb-2025", Attendance: true},
+		{LectureNumber: 2, LectureDate: "05-Mar-2025", Attendance: true},
+		{LectureNumber: 3, LectureDate: "12-Mar-2025", Attendance: false},
+		{LectureNumber: 4, LectureDate: "19-Mar-2025", Attendance: false},
+		{LectureNumber: 5, LectureDate: "02-Apr-2025", Attendance: false},
+	}}
+	lectures := func(records []Attendance) []int {
+		var numbers []int
+		for _, a := range records {
+			numbers = append(numbers, a.LectureNumber)
+		}
+		return numbers
+	}
+
+	for input, want := range map[string][]int{
+		"12-Mar-2025":              {3},
+		"2025-03-12":               {3},
+		"Mar 2025":                 {2, 3, 4},
+		"1-Mar-2025..15-Mar-2025":  {2, 3},
+		"Mar 2025 to Apr 2025":     {2, 3, 4, 5},
+		"..2025-03-05":             {1, 2},
+		"15-Mar-2025..1-Mar-2025 ": {2, 3},
+	} {
+		from, to, _, err := parseDateFilter(input)
+		if err != nil {
+			t.Errorf("parseDateFilter(%q): %v", input, err)
+			continue
+		}
+		if got := lectures(attendanceFilter{from: from, to: to}.apply(course.Attendance)); fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("%q matched lectures %v, want %v", input, got, want)
+		}
+	}
+	if _, _, _, err := parseDateFilter("yesterday"); !errors.Is(err, errFilterDate) {
+		t.Errorf("parseDateFilter(yesterday) = %v", err)
+	}
+
+	m := model{currentView: AttendanceView, courses: []Course{course}, width: 120, height: 40}
+	press := func(keys ...tea.KeyMsg) {
+		for _, key := range keys {
+			next, _ := m.Update(key)
+			m = next.(model)
+		}
+	}
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")}, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("m")})
+	if got := lectures(m.filteredAttendance()); fmt.Sprint(got) != "[]" {
+		t.Errorf("absences in February: %v", got)
+	}
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("m")})
+	if got := lectures(m.filteredAttendance()); fmt.Sprint(got) != "[3 4]" {
+		t.Errorf("absences in March: %v", got)
+	}
+	if view := m.View(); !strings.Contains(view, "(2 of 5 lectures)") {
+		t.Errorf("filter bar missing:\n%s", view)
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	for _, r := range "tab" {
+		press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.editingFilter || m.filterError == "" || m.currentView != AttendanceView {
+		t.Fatalf("an invalid filter was accepted: %+v", m.attendanceFilter)
+	}
+	press(tea.KeyMsg{Type: tea.KeyEsc}, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	if m.attendanceFilter.active() || len(m.filteredAttendance()) != 5 {
+		t.Errorf("x did not clear the filter: %+v", m.attendanceFilter)
+	}
+}
+
+func TestGroupAssessments(t *testing.T) {
+	for name, want := range map[string]string{
+		"Quiz 3":            CATEGORY_QUIZ,
+		"Assignment #2":     CATEGORY_ASSIGNMENT,
+		"HW 1":              CATEGORY_ASSIGNMENT,
+		"Mid Term Exam":     CATEGORY_MIDTERM,
+		"Mids":              CATEGORY_MIDTERM,
+		"Final Exam":        CATEGORY_FINAL,
+		"Final Project":     CATEGORY_PROJECT,
+		"Class Performance": CATEGORY_OTHER,
+	} {
+		if got := assessmentCategory(name); got != want {
+			t.Errorf("assessmentCategory(%q) = %s, want %s", name, got, want)
+		}
+	}
+
+	groups := groupAssessments([]Assessment{
+		{name: "Mid Term", obtainedMarks: 20, totalMarks: 30},
+		{name: "Quiz 1", obtainedMarks: 8, totalMarks: 10},
+		{name: "Quiz 2", obtainedMarks: 6.5, totalMarks: 10},
+	})
+	if len(groups) != 2 || groups[0].Category != CATEGORY_QUIZ || groups[1].Category != CATEGORY_MIDTERM {
+		t.Fatalf("groups = %+v", groups)
+	}
+	if g := groups[0]; len(g.Assessments) != 2 || g.Obtained != 14.5 || g.Total != 20 {
+		t.Errorf("quiz subtotal = %d, %.1f/%.1f", len(g.Assessments), g.Obtained, g.Total)
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// uiState is where the TUI was left: saved on exit when the login is
+// remembered, and restored once the course list loads on the next launch.
+// Views are stored by name so the file survives reordering of ViewType.
+type uiState struct {
+	StudentID string `json:"student_id"`
+	View      string `json:"view"`
+	CourseID  string `json:"course_id,omitempty"`
+	Semester  string `json:"semester,omitempty"`
+	Page      int    `json:"page,omitempty"`
+}
+
+// restorableViews are the views a launch can return to without asking for
+// anything; the rest (chat, fees, jobs, ...) restore to the view below them.
+var restorableViews = map[ViewType]string{
+	CoursesView:      "courses",
+	CourseDetailView: "details",
+	AttendanceView:   "attendance",
+	AssessmentView:   "assessments",
+	OutlineView:      "outline",
+	TranscriptView:   "transcript",
+}
+
+func uiStatePath() (string, error) {
+	dir, err := stateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "ui_state.json"), nil
+}
+
+// readUIState returns the saved state of studentID, or nil when there is
+// none.
+func readUIState(studentID string) *uiState {
+	path, err := uiStatePath()
+	if err != nil || studentID == "" {
+		return nil
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var st uiState
+	if json.Unmarshal(raw, &st) != nil || !strings.EqualFold(st.StudentID, studentID) {
+		return nil
+	}
+	return &st
+}
+
+func writeUIState(st uiState) error {
+	path, err := uiStatePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create app cache dir: %w", err)
+	}
+	raw, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, raw, 0600)
+}
+
+func deleteUIState() {
+	if path, err := uiStatePath(); err == nil {
+		os.Remove(path)
+	}
+}
+
+// saveUIState records where m was left. Without a remembered login the next
+// launch starts at the login form, so any saved state is removed instead.
+func saveUIState(m model) error {
+	if !m.rememberMe || m.session == nil || !m.session.loggedIn {
+		deleteUIState()
+		return nil
+	}
+
+	view := ""
+	for i := len(m.viewStack); i >= 0 && view == ""; i-- {
+		v := m.currentView
+		if i < len(m.viewStack) {
+			v = m.viewStack[i]
+		}
+		view = restorableViews[v]
+	}
+	if view == "" {
+		deleteUIState()
+		return nil
+	}
+
+	st := uiState{StudentID: m.Credentials.StudentID, View: view, Page: m.currentAttendancePage}
+	if m.selectedCourse < len(m.courses) {
+		st.CourseID = m.courses[m.selectedCourse].ID
+	}
+	if m.currentSemester < len(m.transcriptSemesters) {
+		st.Semester = m.transcriptSemesters[m.currentSemester].semester.Name
+	}
+	return writeUIState(st)
+}
+
+// restoreUIState returns to the view saved by the last run, once, when the
+// course list first shows.
+func (m model) restoreUIState() (tea.Model, tea.Cmd) {
+	st := m.restore
+	if st == nil || m.currentView != CoursesView {
+		return m, nil
+	}
+	m.restore = nil
+
+	course := -1
+	for i, c := range m.courses {
+		if c.ID == st.CourseID {
+			course = i
+			break
+		}
+	}
+	if course != -1 {
+		m.selectedCourse = course
+	}
+	m.currentAttendancePage = max(st.Page, 0)
+	m.restoreSemester = st.Semester
+
+	switch st.View {
+	case "transcript":
+		if m.transcriptReady {
+			m.setTranscriptTable(m.session.GetStudent().Transcript)
+		}
+		return m.openTranscript()
+	case "details", "attendance", "assessments", "outline":
+		if course == -1 {
+			return m, nil
+		}
+		m.pushView(CourseDetailView)
+		for v, name := range restorableViews {
+			if name == st.View {
+				return m.openCourseTab(v)
+			}
+		}
+	}
+	return m, nil
+}
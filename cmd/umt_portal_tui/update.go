@@ -0,0 +1,279 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	GITHUB_LATEST_RELEASE_URL = "https://api.github.com/repos/feelsunbreeze/umt_portal_tui/releases/latest"
+	CHECKSUMS_ASSET_NAME      = "checksums.txt"
+)
+
+var errNoReleaseAsset = errors.New("no release binary for this platform")
+
+type githubAsset struct {
+	Name               string `json:"name"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+}
+
+type githubRelease struct {
+	TagName string        `json:"tag_name"`
+	HTMLURL string        `json:"html_url"`
+	Assets  []githubAsset `json:"assets"`
+}
+
+type UpdateAvailableMsg struct {
+	Version string
+	URL     string
+}
+
+func releaseClient() *http.Client {
+	return &http.Client{Transport: sharedTransport, Timeout: 5 * time.Minute}
+}
+
+func fetchLatestRelease(ctx context.Context) (githubRelease, error) {
+	var release githubRelease
+
+	req, err := http.NewRequestWithContext(ctx, "GET", GITHUB_LATEST_RELEASE_URL, nil)
+	if err != nil {
+		return release, fmt.Errorf("failed to create release request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := releaseClient().Do(req)
+	if err != nil {
+		return release, fmt.Errorf("failed to fetch latest release: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return release, fmt.Errorf("failed to fetch latest release: %s", resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return release, fmt.Errorf("failed to parse latest release: %w", err)
+	}
+	return release, nil
+}
+
+// parseVersion accepts "v1.2.3" or "1.2" and ignores any pre-release or
+// build suffix.
+func parseVersion(v string) ([3]int, bool) {
+	var parts [3]int
+
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	fields := strings.Split(v, ".")
+	if len(fields) == 0 || len(fields) > 3 {
+		return parts, false
+	}
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 0 {
+			return parts, false
+		}
+		parts[i] = n
+	}
+	return parts, true
+}
+
+// isNewerVersion reports whether latest is a higher release than current.
+// Development builds are never considered out of date.
+func isNewerVersion(latest, current string) bool {
+	l, ok := parseVersion(latest)
+	if !ok {
+		return false
+	}
+	c, ok := parseVersion(current)
+	if !ok {
+		return false
+	}
+	for i := range l {
+		if l[i] != c[i] {
+			return l[i] > c[i]
+		}
+	}
+	return false
+}
+
+// releaseAssetName is the binary name published for each platform, e.g.
+// umt_portal_tui_linux_amd64 or umt_portal_tui_windows_amd64.exe.
+func releaseAssetName(goos, goarch string) string {
+	name := fmt.Sprintf("umt_portal_tui_%s_%s", goos, goarch)
+	if goos == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+func (r githubRelease) asset(name string) (githubAsset, bool) {
+	for _, a := range r.Assets {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return githubAsset{}, false
+}
+
+func downloadAsset(ctx context.Context, asset githubAsset) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", asset.BrowserDownloadURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create download request: %w", err)
+	}
+
+	resp, err := releaseClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", asset.Name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: %s", asset.Name, resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", asset.Name, err)
+	}
+	return data, nil
+}
+
+// verifyChecksum checks data against the entry for name in a sha256sum
+// style checksums file.
+func verifyChecksum(data []byte, name string, checksums []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || strings.TrimPrefix(fields[1], "*") != name {
+			continue
+		}
+		sum := sha256.Sum256(data)
+		if !strings.EqualFold(fields[0], hex.EncodeToString(sum[:])) {
+			return fmt.Errorf("checksum mismatch for %s", name)
+		}
+		return nil
+	}
+	return fmt.Errorf("no checksum listed for %s", name)
+}
+
+// replaceExecutable writes data next to path and renames it into place. A
+// running executable cannot be overwritten on Windows, so it is moved aside
+// first and cleaned up on the next update.
+func replaceExecutable(path string, data []byte) error {
+	dir := filepath.Dir(path)
+
+	tmp, err := os.CreateTemp(dir, ".umt_portal_tui-update-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write new binary: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write new binary: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0755); err != nil {
+		return fmt.Errorf("failed to make new binary executable: %w", err)
+	}
+
+	if runtime.GOOS == "windows" {
+		old := path + ".old"
+		os.Remove(old)
+		if err := os.Rename(path, old); err != nil {
+			return fmt.Errorf("failed to move current binary aside: %w", err)
+		}
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace binary: %w", err)
+	}
+	return nil
+}
+
+// runUpdate downloads the latest release for this platform, verifies it
+// against the published checksums and replaces the running binary.
+func runUpdate(out io.Writer) error {
+	ctx := context.Background()
+	current := readBuildInfo().Version
+
+	release, err := fetchLatestRelease(ctx)
+	if err != nil {
+		return err
+	}
+	if !isNewerVersion(release.TagName, current) && current != "dev" {
+		fmt.Fprintf(out, "Already up to date (%s).\n", current)
+		return nil
+	}
+
+	name := releaseAssetName(runtime.GOOS, runtime.GOARCH)
+	asset, ok := release.asset(name)
+	if !ok {
+		return fmt.Errorf("%w (%s) in release %s", errNoReleaseAsset, name, release.TagName)
+	}
+	checksumAsset, ok := release.asset(CHECKSUMS_ASSET_NAME)
+	if !ok {
+		return fmt.Errorf("release %s has no %s, refusing to install an unverified binary", release.TagName, CHECKSUMS_ASSET_NAME)
+	}
+
+	fmt.Fprintf(out, "Downloading %s %s...\n", name, release.TagName)
+	data, err := downloadAsset(ctx, asset)
+	if err != nil {
+		return err
+	}
+	checksums, err := downloadAsset(ctx, checksumAsset)
+	if err != nil {
+		return err
+	}
+	if err := verifyChecksum(data, name, checksums); err != nil {
+		return err
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate current binary: %w", err)
+	}
+	if exe, err = filepath.EvalSymlinks(exe); err != nil {
+		return fmt.Errorf("failed to locate current binary: %w", err)
+	}
+	os.Remove(exe + ".old")
+
+	if err := replaceExecutable(exe, data); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Updated %s from %s to %s.\n", exe, current, release.TagName)
+	return nil
+}
+
+// checkForUpdateCmd looks for a newer release in the background. Failures
+// are silent; the check is a convenience and must not get in the way.
+func checkForUpdateCmd() tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		release, err := fetchLatestRelease(ctx)
+		if err != nil || !isNewerVersion(release.TagName, readBuildInfo().Version) {
+			return nil
+		}
+		return UpdateAvailableMsg{Version: release.TagName, URL: release.HTMLURL}
+	}
+}
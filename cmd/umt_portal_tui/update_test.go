@@ -0,0 +1,73 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIsNewerVersion(t *testing.T) {
+	tests := []struct {
+		latest, current string
+		want            bool
+	}{
+		{"v1.2.0", "v1.1.9", true},
+		{"v1.10.0", "v1.9.0", true},
+		{"v2.0", "v1.9.9", true},
+		{"v1.2.0", "v1.2.0", false},
+		{"v1.2.0", "v1.3.0", false},
+		{"v1.2.1-rc1", "v1.2.0", true},
+		{"v1.2.0", "dev", false},
+		{"nightly", "v1.2.0", false},
+	}
+
+	for _, tt := range tests {
+		if got := isNewerVersion(tt.latest, tt.current); got != tt.want {
+			t.Errorf("isNewerVersion(%q, %q) = %v, want %v", tt.latest, tt.current, got, tt.want)
+		}
+	}
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	data := []byte("binary")
+	sum := sha256.Sum256(data)
+	checksums := []byte(hex.EncodeToString(sum[:]) + "  umt_portal_tui_linux_amd64\n" +
+		"0000  umt_portal_tui_windows_amd64.exe\n")
+
+	if err := verifyChecksum(data, "umt_portal_tui_linux_amd64", checksums); err != nil {
+		t.Errorf("valid checksum rejected: %v", err)
+	}
+	if err := verifyChecksum(data, "umt_portal_tui_windows_amd64.exe", checksums); err == nil {
+		t.Error("mismatched checksum accepted")
+	}
+	if err := verifyChecksum(data, "umt_portal_tui_darwin_arm64", checksums); err == nil {
+		t.Error("missing checksum accepted")
+	}
+}
+
+func TestReplaceExecutable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "umt_portal_tui")
+	if err := os.WriteFile(path, []byte("old"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := replaceExecutable(path, []byte("new")); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "new" {
+		t.Errorf("binary contains %q, want %q", data, "new")
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	for _, e := range entries {
+		if e.Name() != "umt_portal_tui" && e.Name() != "umt_portal_tui.old" {
+			t.Errorf("leftover file %s", e.Name())
+		}
+	}
+}
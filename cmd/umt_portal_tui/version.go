@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+// version is stamped by release builds:
+//
+//	go build -ldflags "-X main.version=v1.2.0" ./cmd/umt_portal_tui
+var version = "dev"
+
+type BuildInfo struct {
+	Version  string
+	Commit   string
+	Date     string
+	Modified bool
+}
+
+func readBuildInfo() BuildInfo {
+	info := BuildInfo{Version: version}
+
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	if info.Version == "dev" && bi.Main.Version != "" && bi.Main.Version != "(devel)" {
+		info.Version = bi.Main.Version
+	}
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			info.Commit = s.Value
+			if len(info.Commit) > 12 {
+				info.Commit = info.Commit[:12]
+			}
+		case "vcs.time":
+			info.Date = s.Value
+		case "vcs.modified":
+			info.Modified = s.Value == "true"
+		}
+	}
+	return info
+}
+
+func (b BuildInfo) String() string {
+	s := b.Version
+	if b.Commit != "" {
+		s += " (" + b.Commit
+		if b.Modified {
+			s += "-dirty"
+		}
+		if b.Date != "" {
+			s += ", " + b.Date
+		}
+		s += ")"
+	}
+	return fmt.Sprintf("%s %s/%s %s", s, runtime.GOOS, runtime.GOARCH, runtime.Version())
+}
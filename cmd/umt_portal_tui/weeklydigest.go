@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// weeklyCourse is what the weekly digest remembers of a course, to report
+// the change since the last one.
+type weeklyCourse struct {
+	Attendance  float64  `json:"attendance"`
+	Assessments []string `json:"assessments"`
+}
+
+// weeklySnapshot is the state of every course when the last weekly digest
+// was sent, keyed by course code.
+type weeklySnapshot struct {
+	StudentID string                  `json:"student_id"`
+	SentAt    time.Time               `json:"sent_at"`
+	Courses   map[string]weeklyCourse `json:"courses"`
+}
+
+func weeklySnapshotPath() (string, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "weekly_digest.json"), nil
+}
+
+// readWeeklySnapshot returns the zero snapshot before the first weekly
+// digest.
+func readWeeklySnapshot() (weeklySnapshot, error) {
+	var snapshot weeklySnapshot
+	path, err := weeklySnapshotPath()
+	if err != nil {
+		return snapshot, err
+	}
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return snapshot, nil
+	}
+	if err != nil {
+		return snapshot, fmt.Errorf("failed to read weekly digest snapshot: %w", err)
+	}
+	if err := json.Unmarshal(raw, &snapshot); err != nil {
+		return snapshot, fmt.Errorf("failed to parse weekly digest snapshot: %w", err)
+	}
+	return snapshot, nil
+}
+
+func writeWeeklySnapshot(snapshot weeklySnapshot) error {
+	path, err := weeklySnapshotPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create app cache dir: %w", err)
+	}
+	raw, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal weekly digest snapshot: %w", err)
+	}
+	if err := os.WriteFile(path, raw, 0600); err != nil {
+		return fmt.Errorf("failed to write weekly digest snapshot: %w", err)
+	}
+	return nil
+}
+
+// weeklyDay is the configured weekday of the weekly digest; LoadConfig has
+// validated it.
+func (c DigestConfig) weeklyDay() (time.Weekday, bool) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(c.Weekly, day.String()) {
+			return day, true
+		}
+	}
+	return 0, false
+}
+
+// weeklyDigest writes the weekly email from courses whose attendance and
+// assessments are loaded, and the snapshot to compare the next one with.
+func weeklyDigest(student Student, previous weeklySnapshot, now time.Time) (mailMessage, weeklySnapshot) {
+	if previous.StudentID != student.ID {
+		previous = weeklySnapshot{}
+	}
+	next := weeklySnapshot{StudentID: student.ID, SentAt: now, Courses: map[string]weeklyCourse{}}
+
+	var b strings.Builder
+	if previous.SentAt.IsZero() {
+		fmt.Fprintf(&b, "Your courses as of %s.\n", now.Format("Mon 2 Jan"))
+	} else {
+		fmt.Fprintf(&b, "Your courses since %s.\n", previous.SentAt.Format("Mon 2 Jan"))
+	}
+	for _, c := range student.Courses {
+		before, seen := previous.Courses[c.Code]
+		current := weeklyCourse{Attendance: c.AttendancePercentage}
+
+		fmt.Fprintf(&b, "\n%s %s\n", c.Code, c.Title)
+		if seen {
+			fmt.Fprintf(&b, "  Attendance %.1f%% (%+.1f)\n", c.AttendancePercentage, c.AttendancePercentage-before.Attendance)
+		} else {
+			fmt.Fprintf(&b, "  Attendance %.1f%%\n", c.AttendancePercentage)
+		}
+		known := make(map[string]bool, len(before.Assessments))
+		for _, name := range before.Assessments {
+			known[name] = true
+		}
+		for _, a := range c.Assessment {
+			current.Assessments = append(current.Assessments, a.name)
+			if seen && !known[a.name] {
+				fmt.Fprintf(&b, "  New: %s %.1f/%.1f\n", a.name, a.obtainedMarks, a.totalMarks)
+			}
+		}
+		grading := appConfig.courseGrading(c.Code)
+		if p, ok := grading.project(c.Assessment); ok {
+			if letter, ok := grading.letterGrade(p.Percentage()); ok {
+				fmt.Fprintf(&b, "  Projected %.1f%% (%s)\n", p.Percentage(), letter)
+			} else {
+				fmt.Fprintf(&b, "  Projected %.1f%%\n", p.Percentage())
+			}
+		}
+		next.Courses[c.Code] = current
+	}
+
+	subject := "UMT Portal weekly digest for " + now.Format("Mon 2 Jan")
+	return mailMessage{Subject: subject, Text: b.String()}, next
+}
+
+// sendWeeklyDigest emails the weekly digest and remembers what it covered.
+func sendWeeklyDigest(s *Session, now time.Time) error {
+	previous, err := readWeeklySnapshot()
+	if err != nil {
+		return err
+	}
+	msg, next := weeklyDigest(s.Student, previous, now)
+	if err := sendMail(appConfig.SMTP, msg); err != nil {
+		return fmt.Errorf("failed to send weekly digest: %w", err)
+	}
+	return writeWeeklySnapshot(next)
+}
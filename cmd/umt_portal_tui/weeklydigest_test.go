@@ -0,0 +1,51 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWeeklyDigest(t *testing.T) {
+	prevConfig := appConfig
+	t.Cleanup(func() { appConfig = prevConfig })
+	appConfig.Grading = map[string]CourseGrading{
+		"CC2042": {Weights: map[string]float64{CATEGORY_QUIZ: 20, CATEGORY_MIDTERM: 30, CATEGORY_FINAL: 50}},
+	}
+
+	student := Student{ID: "F2023000000", Courses: []Course{{
+		Code:                 "CC2042",
+		Title:                "Database Systems",
+		AttendancePercentage: 85,
+		Assessment: []Assessment{
+			{name: "Quiz 1", obtainedMarks: 8, totalMarks: 10},
+			{name: "Mid Term", obtainedMarks: 21, totalMarks: 30},
+		},
+	}}}
+	lastWeek := time.Date(2026, 3, 2, 20, 0, 0, 0, time.UTC)
+	previous := weeklySnapshot{StudentID: "F2023000000", SentAt: lastWeek, Courses: map[string]weeklyCourse{
+		"CC2042": {Attendance: 90, Assessments: []string{"Quiz 1"}},
+	}}
+
+	msg, next := weeklyDigest(student, previous, lastWeek.AddDate(0, 0, 7))
+	for _, want := range []string{"since Mon 2 Mar", "Attendance 85.0% (-5.0)", "New: Mid Term 21.0/30.0", "Projected 74.0% (B)"} {
+		if !strings.Contains(msg.Text, want) {
+			t.Errorf("weekly digest is missing %q:\n%s", want, msg.Text)
+		}
+	}
+	if strings.Contains(msg.Text, "New: Quiz 1") {
+		t.Errorf("an assessment from last week was reported as new:\n%s", msg.Text)
+	}
+	if got := next.Courses["CC2042"]; got.Attendance != 85 || len(got.Assessments) != 2 {
+		t.Errorf("next snapshot = %+v", got)
+	}
+
+	// Another student's snapshot is not compared against.
+	previous.StudentID = "F2023999999"
+	if msg, _ := weeklyDigest(student, previous, lastWeek.AddDate(0, 0, 7)); strings.Contains(msg.Text, "New:") || strings.Contains(msg.Text, "(-5.0)") {
+		t.Errorf("compared against another student's snapshot:\n%s", msg.Text)
+	}
+	if _, ok := (DigestConfig{Weekly: "Sunday"}).weeklyDay(); !ok {
+		t.Error("Sunday was not taken as a weekday")
+	}
+}